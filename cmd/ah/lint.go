@@ -16,6 +16,7 @@ import (
 	"github.com/Masterminds/semver/v3"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tracker/source"
 	"github.com/artifacthub/hub/internal/tracker/source/generic"
 	"github.com/artifacthub/hub/internal/tracker/source/helm"
 	"github.com/artifacthub/hub/internal/tracker/source/helmplugin"
@@ -204,6 +205,8 @@ func lintGeneric(basePath string, kind hub.RepositoryKind) *lintReport {
 			e.pkg, err = generic.PreparePackage(&hub.Repository{Kind: kind}, md, pkgPath)
 			if err != nil {
 				e.result = multierror.Append(e.result, err)
+			} else if err := source.EnrichPackageFromChangelogFile(e.pkg, pkgPath); err != nil {
+				e.result = multierror.Append(e.result, err)
 			}
 		}
 
@@ -257,6 +260,8 @@ func lintHelm(basePath string, kind hub.RepositoryKind) *lintReport {
 		helm.EnrichPackageFromChart(e.pkg, chrt)
 		err = helm.EnrichPackageFromAnnotations(e.pkg, chrt.Metadata.Annotations)
 		e.result = multierror.Append(e.result, err)
+		err = helm.EnrichPackageFromChangelog(e.pkg, chrt)
+		e.result = multierror.Append(e.result, err)
 
 		report.entries = append(report.entries, e)
 		return nil
@@ -301,6 +306,8 @@ func lintHelmPlugin(basePath string) *lintReport {
 			e.pkg, err = helmplugin.PreparePackage(repo, md, pkgPath)
 			if err != nil {
 				e.result = multierror.Append(e.result, err)
+			} else if err := source.EnrichPackageFromChangelogFile(e.pkg, pkgPath); err != nil {
+				e.result = multierror.Append(e.result, err)
 			}
 		}
 
@@ -473,6 +480,8 @@ func lintTektonDirBasedCatalog(basePath string, kind hub.RepositoryKind) (*lintR
 				})
 				if err != nil {
 					e.result = multierror.Append(e.result, err)
+				} else if err := source.EnrichPackageFromChangelogFile(e.pkg, pkgPath); err != nil {
+					e.result = multierror.Append(e.result, err)
 				}
 			}
 
@@ -551,6 +560,8 @@ func lintTektonGitBasedCatalog(basePath string, kind hub.RepositoryKind) (*lintR
 				})
 				if err != nil {
 					e.result = multierror.Append(e.result, err)
+				} else if err := source.EnrichPackageFromChangelogFile(e.pkg, pkgPath); err != nil {
+					e.result = multierror.Append(e.result, err)
 				}
 			}
 
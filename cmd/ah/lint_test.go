@@ -127,6 +127,18 @@ func TestLintCmd(t *testing.T) {
 			"four packages found, two with errors",
 			errLintFailed,
 		},
+		{
+			"opa",
+			"test19",
+			"one package found, no errors (changes from changelog)",
+			nil,
+		},
+		{
+			"helm",
+			"test20",
+			"one package found, no errors (changelog with unknown sections)",
+			nil,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1 @@
+policy content
@@ -21,9 +21,11 @@ import (
 	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/org"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/ratelimit"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/stats"
 	"github.com/artifacthub/hub/internal/subscription"
+	"github.com/artifacthub/hub/internal/tracker/source/helm"
 	"github.com/artifacthub/hub/internal/user"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/artifacthub/hub/internal/webhook"
@@ -33,6 +35,16 @@ import (
 )
 
 func main() {
+	// Run as a chart renderer process when requested. This must be handled
+	// before any setup, as it's launched by the packages handlers to render
+	// charts in isolation.
+	if len(os.Args) > 1 && os.Args[1] == helm.RenderChartCmd {
+		if err := helm.ServeRenderChart(os.Stdin, os.Stdout); err != nil {
+			log.Fatal().Err(err).Msg("chart rendering failed")
+		}
+		return
+	}
+
 	// Setup configuration and logger
 	cfg, err := util.SetupConfig("hub")
 	if err != nil {
@@ -49,6 +61,17 @@ func main() {
 	if err != nil {
 		log.Fatal().Err(err).Msg("database setup failed")
 	}
+	kr, err := util.SetupKeyring(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("keyring setup failed")
+	}
+
+	// Re-encrypt secrets stored in the database when requested
+	if len(os.Args) > 1 && os.Args[1] == reencryptSecretsCmd {
+		reencryptSecrets(db, kr)
+		return
+	}
+
 	var es hub.EmailSender
 	if s := email.NewSender(cfg); s != nil {
 		es = s
@@ -59,16 +82,17 @@ func main() {
 	}
 	hc := util.SetupHTTPClient(cfg.GetBool("restrictedHTTPClient"), util.HTTPClientDefaultTimeout)
 	vt := pkg.NewViewsTracker(db)
+	rl := ratelimit.NewLimiter(db)
 
 	// Setup and launch http server
 	ctx, stop := context.WithCancel(context.Background())
 	hSvc := &handlers.Services{
 		OrganizationManager: org.NewManager(cfg, db, es, az),
 		UserManager:         user.NewManager(cfg, db, es),
-		RepositoryManager:   repo.NewManager(cfg, db, az, hc),
+		RepositoryManager:   repo.NewManager(cfg, db, az, hc, repo.WithKeyring(kr)),
 		PackageManager:      pkg.NewManager(db),
 		SubscriptionManager: subscription.NewManager(db),
-		WebhookManager:      webhook.NewManager(db),
+		WebhookManager:      webhook.NewManager(db, webhook.WithKeyring(kr)),
 		APIKeyManager:       apikey.NewManager(db),
 		StatsManager:        stats.NewManager(db),
 		ImageStore:          pg.NewImageStore(cfg, db, hc),
@@ -76,6 +100,7 @@ func main() {
 		HTTPClient:          hc,
 		OCIPuller:           oci.NewPuller(cfg),
 		ViewsTracker:        vt,
+		RateLimiter:         rl,
 	}
 	h, err := handlers.Setup(ctx, cfg, hSvc)
 	if err != nil {
@@ -117,12 +142,16 @@ func main() {
 	wg.Add(1)
 	go vt.Flusher(ctx, &wg)
 
+	// Launch rate limiter expired counters cleaner
+	wg.Add(1)
+	go rl.Cleaner(ctx, &wg)
+
 	// Setup and launch events dispatcher
 	eSvc := &event.Services{
 		DB:                  db,
 		EventManager:        event.NewManager(),
 		SubscriptionManager: subscription.NewManager(db),
-		WebhookManager:      webhook.NewManager(db),
+		WebhookManager:      webhook.NewManager(db, webhook.WithKeyring(kr)),
 		NotificationManager: notification.NewManager(),
 	}
 	eventsDispatcher := event.NewDispatcher(eSvc)
@@ -136,9 +165,10 @@ func main() {
 		ES:                  es,
 		NotificationManager: notification.NewManager(),
 		SubscriptionManager: subscription.NewManager(db),
-		RepositoryManager:   repo.NewManager(cfg, db, az, hc),
+		RepositoryManager:   repo.NewManager(cfg, db, az, hc, repo.WithKeyring(kr)),
 		PackageManager:      pkg.NewManager(db),
 		HTTPClient:          util.SetupHTTPClient(cfg.GetBool("restrictedHTTPClient"), handlers.WebhooksHTTPClientTimeout),
+		Keyring:             kr,
 	}
 	notificationsDispatcher := notification.NewDispatcher(nSvc)
 	wg.Add(1)
@@ -162,6 +192,7 @@ func main() {
 
 // setCfgDefaults sets the default values for some configuration options.
 func setCfgDefaults(cfg *viper.Viper) {
+	cfg.SetDefault("server.accountLockout.duration", 30*time.Minute)
 	cfg.SetDefault("server.addr", "0.0.0.0:8000")
 	cfg.SetDefault("server.allowUserSignUp", true)
 	cfg.SetDefault("server.baseURL", "http://localhost:8000")
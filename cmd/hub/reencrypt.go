@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+// reencryptSecretsCmd represents the command used to re-encrypt the secrets
+// stored in the database using the active encryption key.
+const reencryptSecretsCmd = "reencrypt-secrets"
+
+// reencryptSecrets encrypts again, using the active key of the keyring
+// provided, the secrets stored in the database that are not encrypted yet or
+// were encrypted using a previous key. It's expected to be run after adding a
+// new encryption key, before removing the old one from the configuration.
+func reencryptSecrets(db hub.DB, kr *util.Keyring) {
+	count, err := util.ReencryptDBSecrets(context.Background(), db, kr)
+	if err != nil {
+		log.Fatal().Err(err).Int("reencrypted", count).Msg("secrets re-encryption failed")
+	}
+	log.Info().Int("reencrypted", count).Msg("secrets re-encryption completed")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/spf13/viper"
+)
+
+// evaluateCategoryClassifierCmd represents the command used to evaluate the
+// package category classifier configured against the current catalog or a set
+// of labelled packages.
+const evaluateCategoryClassifierCmd = "evaluate-category-classifier"
+
+// evaluateCategoryClassifier evaluates the package category classifier
+// provided, printing a report with the results to the standard output. When a
+// labelled packages file is given in the arguments the classifier is evaluated
+// against it. Otherwise, the packages in the catalog whose category was
+// provided by the publisher are used.
+func evaluateCategoryClassifier(
+	ctx context.Context,
+	cfg *viper.Viper,
+	args []string,
+	pcc hub.PackageCategoryClassifier,
+) error {
+	var e *tracker.CategoryClassifierEvaluation
+	switch len(args) {
+	case 0:
+		db, err := util.SetupDB(cfg)
+		if err != nil {
+			return fmt.Errorf("database setup failed: %w", err)
+		}
+		e, err = tracker.EvaluateCategoryClassifier(ctx, db, pcc)
+		if err != nil {
+			return err
+		}
+	case 1:
+		f, err := os.Open(args[0])
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		e, err = tracker.EvaluateCategoryClassifierFromCSV(f, pcc)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("usage: tracker %s [LABELLED_PACKAGES_CSV]", evaluateCategoryClassifierCmd)
+	}
+	printEvaluationReport(os.Stdout, e)
+	return nil
+}
+
+// printEvaluationReport writes a report of the category classifier evaluation
+// provided to the writer, including the stats per category and the confusion
+// matrix (rows represent the expected category and columns the predicted one).
+func printEvaluationReport(out io.Writer, e *tracker.CategoryClassifierEvaluation) {
+	categories := []hub.PackageCategory{hub.UnknownCategory}
+	for c := hub.AIMachineLearning; c <= hub.StreamingMessaging; c++ {
+		categories = append(categories, c)
+	}
+
+	fmt.Fprintf(out, "Packages evaluated: %d\n", e.Total)
+	fmt.Fprintf(out, "Accuracy: %.2f%% (%d/%d)\n\n", e.Accuracy()*100, e.Correct, e.Total)
+
+	// Stats per category
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CATEGORY\tPACKAGES\tPRECISION\tRECALL")
+	for _, c := range categories[1:] {
+		total, precision, recall := e.CategoryStats(c)
+		fmt.Fprintf(w, "%s\t%d\t%.2f\t%.2f\n", hub.GetPackageCategoryName(c), total, precision, recall)
+	}
+	w.Flush()
+	fmt.Fprintln(out)
+
+	// Confusion matrix
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprint(w, "EXPECTED \\ PREDICTED\t")
+	for _, c := range categories {
+		fmt.Fprintf(w, "%s\t", hub.GetPackageCategoryName(c))
+	}
+	fmt.Fprintln(w)
+	for _, expected := range categories {
+		fmt.Fprintf(w, "%s\t", hub.GetPackageCategoryName(expected))
+		for _, predicted := range categories {
+			fmt.Fprintf(w, "%d\t", e.Confusion[expected][predicted])
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+}
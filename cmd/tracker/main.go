@@ -17,12 +17,26 @@ import (
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker"
+	"github.com/artifacthub/hub/internal/tracker/scheduler"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 )
 
+const (
+	// trackerStopTimeout represents the maximum amount of time we'll wait for
+	// the tracker to stop once the repository tracking has timed out or the
+	// tracker is shutting down.
+	trackerStopTimeout = 1 * time.Minute
+
+	// gitCachePruneFrequency represents how often the local copies of the
+	// repositories that have been deleted will be removed from the cache in
+	// daemon mode.
+	gitCachePruneFrequency = 6 * time.Hour
+)
+
 var (
+	errPanic   = errors.New("repository tracking failed unexpectedly")
 	errTimeout = errors.New("repository tracking timed out")
 )
 
@@ -49,22 +63,40 @@ func main() {
 		log.Info().Msg("tracker shutting down..")
 	}()
 
-	// Check required external tools are available
-	if _, err := exec.LookPath("opm"); err != nil {
-		log.Fatal().Err(err).Msg("opm not found")
+	// Setup services
+	pcc, err := tracker.SetupPackageCategoryClassifier(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("package category classifier setup failed")
+	}
+
+	// Evaluate the package category classifier when requested
+	if len(os.Args) > 1 && os.Args[1] == evaluateCategoryClassifierCmd {
+		if err := evaluateCategoryClassifier(ctx, cfg, os.Args[2:], pcc); err != nil {
+			log.Fatal().Err(err).Msg("category classifier evaluation failed")
+		}
+		return
 	}
 
-	// Setup services
 	db, err := util.SetupDB(cfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("database setup failed")
 	}
+
+	// Check optional external tools are available (opm is only needed to
+	// process OLM catalog images that do not use the file-based catalog format)
+	if _, err := exec.LookPath("opm"); err != nil {
+		log.Warn().Err(err).Msg("opm not found")
+	}
 	az, err := authz.NewAuthorizer(db)
 	if err != nil {
 		log.Fatal().Err(err).Msg("authorizer setup failed")
 	}
+	kr, err := util.SetupKeyring(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("keyring setup failed")
+	}
 	hc := util.SetupHTTPClient(cfg.GetBool("restrictedHTTPClient"), util.HTTPClientDefaultTimeout)
-	rm := repo.NewManager(cfg, db, az, hc)
+	rm := repo.NewManager(cfg, db, az, hc, repo.WithKeyring(kr))
 	pm := pkg.NewManager(db)
 	is, err := util.SetupImageStore(cfg, db, hc)
 	if err != nil {
@@ -72,14 +104,21 @@ func main() {
 	}
 	ec := repo.NewErrorsCollector(rm, repo.Tracker)
 	op := oci.NewPuller(cfg)
-	pcc := tracker.NewPackageCategoryClassifierML(cfg.GetString("tracker.categoryModelPath"))
+	rc := repo.NewCloner(hc, repo.WithCacheDir(cfg.GetString("tracker.gitCacheDir")))
+	if cfg.GetBool("tracker.incrementalTracking") {
+		if rc.CacheDir() == "" {
+			log.Warn().Msg("git cache dir not available, repositories will be fully cloned")
+		} else {
+			pruneGitCache(ctx, rc, rm)
+		}
+	}
 	svc := &hub.TrackerServices{
 		Ctx:                ctx,
 		Cfg:                cfg,
 		Rm:                 rm,
 		Pm:                 pm,
-		Rc:                 &repo.Cloner{},
-		Oe:                 &repo.OLMOCIExporter{},
+		Rc:                 rc,
+		Oe:                 repo.NewOLMOCIExporter(cfg),
 		Ec:                 ec,
 		Hc:                 hc,
 		Op:                 op,
@@ -90,7 +129,29 @@ func main() {
 	}
 
 	// Track registered repositories
-	repos, err := tracker.GetRepositories(ctx, cfg, rm)
+	if cfg.GetBool("tracker.daemon.enabled") {
+		if cfg.GetBool("tracker.incrementalTracking") && rc.CacheDir() != "" {
+			go func() {
+				for {
+					select {
+					case <-time.After(gitCachePruneFrequency):
+						pruneGitCache(ctx, rc, rm)
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		runDaemon(ctx, cfg, db, svc)
+	} else {
+		runOnce(ctx, cfg, svc)
+	}
+	log.Info().Msg("tracker finished")
+}
+
+// runOnce tracks all the registered repositories once and returns.
+func runOnce(ctx context.Context, cfg *viper.Viper, svc *hub.TrackerServices) {
+	repos, err := tracker.GetRepositories(ctx, cfg, svc.Rm)
 	if err != nil {
 		log.Fatal().Err(err).Msg("error getting repositories")
 	}
@@ -137,13 +198,83 @@ L:
 		}(r)
 	}
 	wg.Wait()
+	svc.Ec.Flush()
+}
+
+// runDaemon keeps tracking the registered repositories, each of them on its
+// own schedule, until the context provided is done.
+func runDaemon(ctx context.Context, cfg *viper.Viper, db hub.DB, svc *hub.TrackerServices) {
+	s, err := scheduler.New(cfg, db, svc.Rm, func(ctx context.Context, r *hub.Repository) error {
+		return trackRepository(ctx, cfg, svc, r)
+	})
+	if err != nil {
+		log.Fatal().Err(err).Msg("scheduler setup failed")
+	}
+	log.Info().Msg("tracker running in daemon mode")
+	s.Run(ctx)
+}
+
+// trackRepository tracks the repository provided, flushing the errors found
+// once it's done. When the repository tracking times out, the tracker is
+// asked to stop and we wait for it to do so for a while, as the repository
+// should not be released while it's still being processed.
+func trackRepository(ctx context.Context, cfg *viper.Viper, svc *hub.TrackerServices, r *hub.Repository) error {
+	// Setup services for this repository, using its own errors collector
+	rCtx, cancel := context.WithTimeout(ctx, cfg.GetDuration("tracker.repositoryTimeout"))
+	defer cancel()
+	ec := repo.NewErrorsCollector(svc.Rm, repo.Tracker)
+	rSvc := *svc
+	rSvc.Ctx = rCtx
+	rSvc.Ec = ec
+
+	// Track repository
+	logger := log.With().Str("repo", r.Name).Str("kind", hub.GetKindName(r.Kind)).Logger()
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if rec := recover(); rec != nil {
+				logger.Error().Bytes("stacktrace", debug.Stack()).Interface("recover", rec).Send()
+				done <- errPanic
+			}
+		}()
+		done <- tracker.New(&rSvc, r, logger).Run()
+	}()
+	var err error
+	select {
+	case err = <-done:
+	case <-rCtx.Done():
+		select {
+		case <-done:
+		case <-time.After(trackerStopTimeout):
+			logger.Warn().Msg("tracker did not stop in time, releasing repository")
+		}
+		if errors.Is(rCtx.Err(), context.DeadlineExceeded) {
+			err = errTimeout
+		} else {
+			err = rCtx.Err()
+		}
+	}
+	if err != nil {
+		logger.Error().Err(err).Send()
+		ec.Append(r.RepositoryID, err.Error())
+	}
 	ec.Flush()
-	log.Info().Msg("tracker finished")
+	return err
+}
+
+// pruneGitCache removes from the cache the local copies of the repositories
+// that have been deleted.
+func pruneGitCache(ctx context.Context, rc *repo.Cloner, rm hub.RepositoryManager) {
+	if err := rc.PruneCache(ctx, rm); err != nil && ctx.Err() == nil {
+		log.Error().Err(err).Msg("error pruning git cache")
+	}
 }
 
 // setCfgDefaults sets the default values for some configuration options.
 func setCfgDefaults(cfg *viper.Viper) {
+	cfg.SetDefault("tracker.categoryClassifier", "ml")
 	cfg.SetDefault("tracker.categoryModelPath", "../../ml/category/model")
 	cfg.SetDefault("tracker.concurrency", 1)
+	cfg.SetDefault("tracker.daemon.enabled", false)
 	cfg.SetDefault("tracker.repositoryTimeout", 15*time.Minute)
 }
@@ -7,6 +7,10 @@
 {{ template "api_keys/get_user_api_keys.sql" }}
 {{ template "api_keys/update_api_key.sql" }}
 
+{{ template "audit/get_organization_audit_log.sql" }}
+{{ template "audit/get_user_audit_log.sql" }}
+{{ template "audit/register_audit_event.sql" }}
+
 {{ template "events/get_pending_event.sql" }}
 
 {{ template "images/get_image.sql" }}
@@ -40,6 +44,8 @@
 {{ template "packages/get_nova_dump.sql" }}
 {{ template "packages/get_package.sql" }}
 {{ template "packages/get_package_changelog.sql" }}
+{{ template "packages/get_package_dependencies.sql" }}
+{{ template "packages/get_package_dependents.sql" }}
 {{ template "packages/get_package_summary.sql" }}
 {{ template "packages/get_packages_starred_by_user.sql" }}
 {{ template "packages/get_package_stars.sql" }}
@@ -48,6 +54,7 @@
 {{ template "packages/get_production_usage.sql" }}
 {{ template "packages/get_random_packages.sql" }}
 {{ template "packages/get_snapshots_to_scan.sql" }}
+{{ template "packages/is_helm_dependency_repository.sql" }}
 {{ template "packages/is_latest.sql" }}
 {{ template "packages/register_package.sql" }}
 {{ template "packages/search_packages.sql" }}
@@ -59,16 +66,26 @@
 {{ template "packages/update_packages_views.sql" }}
 {{ template "packages/update_snapshot_security_report.sql" }}
 
+{{ template "rate_limits/check_rate_limit.sql" }}
+
 {{ template "repositories/add_repository.sql" }}
 {{ template "repositories/delete_repository.sql" }}
+{{ template "repositories/get_repositories_due_for_tracking.sql" }}
 {{ template "repositories/get_repository_by_name.sql" }}
 {{ template "repositories/get_repository_packages_digest.sql" }}
+{{ template "repositories/get_repository_packages_paths.sql" }}
+{{ template "repositories/get_repository_tracking_runs.sql" }}
+{{ template "repositories/get_repository_tracking_status.sql" }}
+{{ template "repositories/register_repository_tracking_run.sql" }}
+{{ template "repositories/request_repository_tracking.sql" }}
 {{ template "repositories/search_repositories.sql" }}
 {{ template "repositories/set_last_scanning_results.sql" }}
 {{ template "repositories/set_last_tracking_results.sql" }}
 {{ template "repositories/set_verified_publisher.sql" }}
 {{ template "repositories/transfer_repository.sql" }}
 {{ template "repositories/update_repository.sql" }}
+{{ template "repositories/update_repository_push_secret.sql" }}
+{{ template "repositories/update_repository_tracking_schedule.sql" }}
 
 {{ template "stats/get_stats.sql" }}
 
@@ -87,10 +104,12 @@
 {{ template "users/get_user_profile.sql" }}
 {{ template "users/get_user_tfa_config.sql" }}
 {{ template "users/register_delete_user_code.sql" }}
+{{ template "users/register_failed_login_attempt.sql" }}
 {{ template "users/register_password_reset_code.sql" }}
 {{ template "users/register_session.sql" }}
 {{ template "users/register_user.sql" }}
 {{ template "users/reset_user_password.sql" }}
+{{ template "users/sync_user_organizations_memberships.sql" }}
 {{ template "users/update_user_password.sql" }}
 {{ template "users/update_user_profile.sql" }}
 {{ template "users/verify_email.sql" }}
@@ -0,0 +1,62 @@
+-- get_organization_audit_log returns the audit log entries of the provided
+-- organization that match the input provided as a json array.
+create or replace function get_organization_audit_log(
+    p_requesting_user_id uuid,
+    p_org_name text,
+    p_input jsonb
+) returns table(data json, total_count bigint) as $$
+declare
+    v_organization_id uuid;
+begin
+    if not user_belongs_to_organization(p_requesting_user_id, p_org_name) then
+        raise insufficient_privilege;
+    end if;
+    select organization_id into v_organization_id
+    from organization
+    where name = p_org_name;
+
+    return query
+    with organization_audit_log as (
+        select
+            al.audit_log_id,
+            al.action,
+            u.alias as user_alias,
+            o.name as organization_name,
+            t.name as target_organization_name,
+            al.details,
+            al.created_at
+        from audit_log al
+        join "user" u on u.user_id = al.user_id
+        left join organization o on o.organization_id = al.organization_id
+        left join organization t on t.organization_id = al.target_organization_id
+        where (
+            al.organization_id = v_organization_id
+            or al.target_organization_id = v_organization_id
+        )
+        and (
+            p_input->'actions' is null
+            or al.action in (select jsonb_array_elements_text(p_input->'actions'))
+        )
+        and (p_input->>'from' is null or al.created_at >= (p_input->>'from')::timestamptz)
+        and (p_input->>'to' is null or al.created_at <= (p_input->>'to')::timestamptz)
+    )
+    select
+        coalesce(json_agg(json_strip_nulls(json_build_object(
+            'audit_log_id', audit_log_id,
+            'action', action,
+            'user_alias', user_alias,
+            'organization_name', organization_name,
+            'target_organization_name', target_organization_name,
+            'details', details,
+            'created_at', floor(extract(epoch from created_at))
+        ))), '[]'),
+        (select count(*) from organization_audit_log)
+    from (
+        select *
+        from organization_audit_log
+        order by created_at desc
+        limit (case when coalesce((p_input->>'limit')::int, 0) = 0 then null else (p_input->>'limit')::int end)
+        offset coalesce((p_input->>'offset')::int, 0)
+    ) al;
+end
+$$ language plpgsql;
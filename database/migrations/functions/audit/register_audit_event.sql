@@ -0,0 +1,17 @@
+-- register_audit_event registers the provided audit event in the audit log.
+create or replace function register_audit_event(p_event jsonb)
+returns void as $$
+    insert into audit_log (
+        action,
+        user_id,
+        organization_id,
+        target_organization_id,
+        details
+    ) values (
+        p_event->>'action',
+        (p_event->>'user_id')::uuid,
+        (select organization_id from organization where name = p_event->>'organization_name'),
+        (select organization_id from organization where name = p_event->>'target_organization_name'),
+        nullif(p_event->'details', 'null')
+    );
+$$ language sql;
@@ -0,0 +1,70 @@
+-- get_package_dependencies returns the dependencies of the package version
+-- identified by the input provided as a json array. Dependencies resolved to
+-- packages available in the database are expanded transitively (using their
+-- latest version) up to the depth provided.
+create or replace function get_package_dependencies(p_input jsonb)
+returns setof json as $$
+declare
+    v_package_id uuid;
+    v_version text;
+    v_max_depth int := coalesce(nullif((p_input->>'depth')::int, 0), 1);
+begin
+    -- Get package id and version to use
+    select p.package_id, coalesce(nullif(p_input->>'version', ''), p.latest_version)
+    into v_package_id, v_version
+    from package p
+    join repository r using (repository_id)
+    where p.normalized_name = p_input->>'package_name'
+    and r.name = p_input->>'repository_name';
+
+    -- Check the package version exists
+    perform from snapshot
+    where package_id = v_package_id
+    and version = v_version;
+    if not found then
+        return;
+    end if;
+
+    return query
+    with recursive dependencies as (
+        select
+            1 as depth,
+            sd.package_id as parent_package_id,
+            sd.name,
+            sd.version_constraint,
+            sd.repository_url,
+            sd.dependency_package_id,
+            array[sd.package_id] as path
+        from snapshot_dependency sd
+        where sd.package_id = v_package_id
+        and sd.version = v_version
+        union all
+        select
+            d.depth + 1,
+            sd.package_id,
+            sd.name,
+            sd.version_constraint,
+            sd.repository_url,
+            sd.dependency_package_id,
+            d.path || sd.package_id
+        from dependencies d
+        join package p on p.package_id = d.dependency_package_id
+        join snapshot_dependency sd on sd.package_id = p.package_id and sd.version = p.latest_version
+        where d.depth < v_max_depth
+        and sd.package_id <> all(d.path)
+    )
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'depth', d.depth,
+        'parent_package_id', d.parent_package_id,
+        'package_id', d.dependency_package_id,
+        'name', d.name,
+        'version_constraint', d.version_constraint,
+        'repository_url', d.repository_url,
+        'package', (select get_package_summary(jsonb_build_object('package_id', d.dependency_package_id)))
+    )) order by d.depth asc, d.name asc), '[]')
+    from (
+        select distinct depth, parent_package_id, name, version_constraint, repository_url, dependency_package_id
+        from dependencies
+    ) d;
+end
+$$ language plpgsql;
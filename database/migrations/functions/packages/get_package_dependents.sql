@@ -0,0 +1,64 @@
+-- get_package_dependents returns the packages that depend on the package
+-- identified by the input provided as a json array. Only the latest version of
+-- the dependent packages is considered. Dependents are expanded transitively
+-- up to the depth provided.
+create or replace function get_package_dependents(p_input jsonb)
+returns setof json as $$
+declare
+    v_package_id uuid;
+    v_max_depth int := coalesce(nullif((p_input->>'depth')::int, 0), 1);
+begin
+    -- Get package id
+    select p.package_id into v_package_id
+    from package p
+    join repository r using (repository_id)
+    where p.normalized_name = p_input->>'package_name'
+    and r.name = p_input->>'repository_name';
+    if not found then
+        return;
+    end if;
+
+    return query
+    with recursive dependents as (
+        select
+            1 as depth,
+            sd.dependency_package_id as parent_package_id,
+            sd.package_id,
+            sd.name,
+            sd.version_constraint,
+            sd.repository_url,
+            array[sd.dependency_package_id, sd.package_id] as path
+        from snapshot_dependency sd
+        join package p on p.package_id = sd.package_id and p.latest_version = sd.version
+        where sd.dependency_package_id = v_package_id
+        union all
+        select
+            d.depth + 1,
+            sd.dependency_package_id,
+            sd.package_id,
+            sd.name,
+            sd.version_constraint,
+            sd.repository_url,
+            d.path || sd.package_id
+        from dependents d
+        join snapshot_dependency sd on sd.dependency_package_id = d.package_id
+        join package p on p.package_id = sd.package_id and p.latest_version = sd.version
+        where d.depth < v_max_depth
+        and sd.package_id <> all(d.path)
+    )
+    select coalesce(json_agg(json_strip_nulls(json_build_object(
+        'depth', d.depth,
+        'parent_package_id', d.parent_package_id,
+        'package_id', d.package_id,
+        'name', d.name,
+        'version_constraint', d.version_constraint,
+        'repository_url', d.repository_url,
+        'package', (select get_package_summary(jsonb_build_object('package_id', d.package_id)))
+    )) order by d.depth asc, p.name asc), '[]')
+    from (
+        select distinct depth, parent_package_id, package_id, name, version_constraint, repository_url
+        from dependents
+    ) d
+    join package p on p.package_id = d.package_id;
+end
+$$ language plpgsql;
@@ -0,0 +1,20 @@
+-- is_helm_dependency_repository checks if the repository url provided matches
+-- the repository of a Helm chart dependency. Local dependencies (file://) are
+-- expected to be found in the same repository as the chart depending on them,
+-- whereas dependencies in OCI registries may match repositories whose url
+-- includes the chart name.
+create or replace function is_helm_dependency_repository(
+    p_dependency_name text,
+    p_dependency_repository_url text,
+    p_dependent_repository_url text,
+    p_repository_url text
+)
+returns boolean as $$
+    select case
+        when starts_with(p_dependency_repository_url, 'file://') then
+            p_repository_url = p_dependent_repository_url
+        else
+            rtrim(p_repository_url, '/') = rtrim(p_dependency_repository_url, '/')
+            or p_repository_url = rtrim(p_dependency_repository_url, '/') || '/' || p_dependency_name
+    end;
+$$ language sql immutable;
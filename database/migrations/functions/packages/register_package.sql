@@ -24,6 +24,7 @@ declare
     v_previous_latest_version_ts timestamptz;
     v_repository_disabled boolean;
     v_repository_kind_id integer;
+    v_repository_url text;
     v_ts timestamptz;
     v_ts_publisher text[];
     v_ts_repository text[];
@@ -35,8 +36,8 @@ begin
     end if;
 
     -- Get some repository information (some of it for tsdoc)
-    select r.disabled, array[r.name, r.display_name], array[u.alias, o.name, o.display_name, v_provider], repository_kind_id
-    into v_repository_disabled, v_ts_repository, v_ts_publisher, v_repository_kind_id
+    select r.disabled, array[r.name, r.display_name], array[u.alias, o.name, o.display_name, v_provider], repository_kind_id, r.url
+    into v_repository_disabled, v_ts_repository, v_ts_publisher, v_repository_kind_id, v_repository_url
     from repository r
     left join "user" u using (user_id)
     left join organization o using (organization_id)
@@ -67,6 +68,7 @@ begin
         channels,
         default_channel,
         package_category_id,
+        package_category_predicted,
         repository_id
     ) values (
         v_name,
@@ -77,6 +79,7 @@ begin
         nullif(p_pkg->'channels', 'null'),
         nullif(p_pkg->>'default_channel', ''),
         nullif((p_pkg->>'category')::int, 0),
+        coalesce((p_pkg->>'category_predicted')::boolean, false),
         v_repository_id
     )
     on conflict (repository_id, name) do update
@@ -88,7 +91,8 @@ begin
         is_operator = excluded.is_operator,
         channels = excluded.channels,
         default_channel = excluded.default_channel,
-        package_category_id = excluded.package_category_id
+        package_category_id = excluded.package_category_id,
+        package_category_predicted = excluded.package_category_predicted
     where is_latest(
         v_repository_kind_id,
         v_version,
@@ -250,6 +254,51 @@ begin
         relative_path = excluded.relative_path,
         ts = v_ts;
 
+    -- Helm chart dependencies
+    if v_repository_kind_id = 0 then
+        -- Register snapshot dependencies, resolving them to the packages
+        -- indexed when possible
+        delete from snapshot_dependency
+        where package_id = v_package_id
+        and version = v_version;
+        insert into snapshot_dependency (
+            package_id,
+            version,
+            name,
+            version_constraint,
+            repository_url,
+            dependency_package_id
+        )
+        select
+            v_package_id,
+            v_version,
+            dep->>'name',
+            nullif(dep->>'version', ''),
+            nullif(dep->>'repository', ''),
+            (
+                select dp.package_id
+                from package dp
+                join repository dr using (repository_id)
+                where dr.repository_kind_id = 0
+                and dp.name = dep->>'name'
+                and is_helm_dependency_repository(dep->>'name', dep->>'repository', v_repository_url, dr.url)
+                order by dr.verified_publisher desc
+                limit 1
+            )
+        from jsonb_array_elements(nullif(p_pkg->'data'->'dependencies', 'null'::jsonb)) as dep
+        where dep->>'name' <> '';
+
+        -- Resolve the dependencies on this package registered before it was
+        -- indexed
+        update snapshot_dependency sd set dependency_package_id = v_package_id
+        from package dp
+        join repository dr using (repository_id)
+        where sd.package_id = dp.package_id
+        and sd.dependency_package_id is null
+        and sd.name = v_name
+        and is_helm_dependency_repository(sd.name, sd.repository_url, dr.url, v_repository_url);
+    end if;
+
     -- Register new release event if package's latest version has been updated
     v_latest_version_updated := false;
     case v_repository_kind_id
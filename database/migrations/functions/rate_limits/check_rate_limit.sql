@@ -0,0 +1,70 @@
+-- check_rate_limit returns the status of the rate limit identified by the key
+-- provided, registering a new hit first when requested. Hits are counted per
+-- fixed window, and the number of hits in the sliding window is estimated by
+-- weighting the hits registered in the previous window.
+create or replace function check_rate_limit(
+    p_key text,
+    p_limit int,
+    p_window_seconds int,
+    p_register_hit boolean
+)
+returns json as $$
+declare
+    v_now double precision := extract(epoch from current_timestamp);
+    v_window_start double precision := floor(v_now / p_window_seconds) * p_window_seconds;
+    v_elapsed double precision := v_now - v_window_start;
+    v_current_hits int;
+    v_previous_hits int;
+    v_estimated_hits double precision;
+    v_allowed boolean;
+    v_reset double precision;
+begin
+    -- Register hit in the current window if requested
+    if p_register_hit then
+        insert into rate_limit_counter (key, window_start, hits, expires_at)
+        values (
+            p_key,
+            to_timestamp(v_window_start),
+            1,
+            to_timestamp(v_window_start + 2 * p_window_seconds)
+        )
+        on conflict (key, window_start) do update
+        set hits = rate_limit_counter.hits + 1;
+    end if;
+
+    -- Estimate the number of hits in the sliding window
+    select
+        coalesce(sum(hits) filter (where window_start = to_timestamp(v_window_start)), 0),
+        coalesce(sum(hits) filter (where window_start = to_timestamp(v_window_start - p_window_seconds)), 0)
+    into v_current_hits, v_previous_hits
+    from rate_limit_counter
+    where key = p_key
+    and window_start >= to_timestamp(v_window_start - p_window_seconds);
+    v_estimated_hits := v_previous_hits * (1 - v_elapsed / p_window_seconds) + v_current_hits;
+
+    -- A registered hit is allowed when it doesn't exceed the limit. When no
+    -- hit is registered, we check if there is still room for one more.
+    if p_register_hit then
+        v_allowed := v_estimated_hits <= p_limit;
+    else
+        v_allowed := v_estimated_hits < p_limit;
+    end if;
+
+    -- Calculate the seconds until the current window ends or, when the limit
+    -- has been reached, until the estimated hits drop below the limit
+    if v_allowed then
+        v_reset := p_window_seconds - v_elapsed;
+    elsif v_current_hits < p_limit then
+        v_reset := p_window_seconds * (1 - (p_limit - v_current_hits)::double precision / v_previous_hits) - v_elapsed;
+    else
+        v_reset := (p_window_seconds - v_elapsed) + p_window_seconds * (1 - p_limit::double precision / v_current_hits);
+    end if;
+
+    return json_build_object(
+        'allowed', v_allowed,
+        'limit', p_limit,
+        'remaining', greatest(p_limit - ceil(v_estimated_hits)::int, 0),
+        'reset', greatest(ceil(v_reset)::int, 1)
+    );
+end
+$$ language plpgsql;
@@ -22,11 +22,20 @@ begin
         display_name,
         url,
         branch,
+        git_url,
+        git_ref,
+        git_subdir,
         auth_user,
         auth_pass,
+        auth_ssh_key,
+        auth_ssh_known_hosts,
+        auth_github_app_id,
+        auth_github_app_installation_id,
+        auth_github_app_key,
         disabled,
         scanner_disabled,
         data,
+        tracking_interval,
         repository_kind_id,
         user_id,
         organization_id
@@ -35,11 +44,20 @@ begin
         nullif(p_repository->>'display_name', ''),
         p_repository->>'url',
         nullif(p_repository->>'branch', ''),
+        nullif(p_repository->>'git_url', ''),
+        nullif(p_repository->>'git_ref', ''),
+        nullif(p_repository->>'git_subdir', ''),
         nullif(p_repository->>'auth_user', ''),
         nullif(p_repository->>'auth_pass', ''),
+        nullif(p_repository->>'auth_ssh_key', ''),
+        nullif(p_repository->>'auth_ssh_known_hosts', ''),
+        nullif(p_repository->>'auth_github_app_id', ''),
+        nullif(p_repository->>'auth_github_app_installation_id', ''),
+        nullif(p_repository->>'auth_github_app_key', ''),
         (p_repository->>'disabled')::boolean,
         (p_repository->>'scanner_disabled')::boolean,
         nullif(p_repository->'data', 'null'),
+        nullif((p_repository->>'tracking_interval')::int, 0),
         (p_repository->>'kind')::int,
         v_owner_user_id,
         v_owner_organization_id
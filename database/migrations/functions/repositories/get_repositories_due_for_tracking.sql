@@ -0,0 +1,27 @@
+-- get_repositories_due_for_tracking returns the ids of the enabled
+-- repositories whose next tracking is due (or that have been requested to be
+-- tracked) as a json array, optionally filtered by the names and kinds
+-- provided. Repositories whose tracking has been requested are returned first,
+-- followed by the ones that have never been scheduled and the ones overdue the
+-- longest.
+create or replace function get_repositories_due_for_tracking(p_names text[], p_kinds int[])
+returns setof json as $$
+    select coalesce(json_agg(repository_id), '[]')
+    from (
+        select repository_id
+        from repository
+        where disabled = false
+        and (
+            next_tracking_at is null
+            or next_tracking_at <= current_timestamp
+            or tracking_requested_at is not null
+        )
+        and
+            case when cardinality(p_names) > 0
+            then name = any(p_names) else true end
+        and
+            case when cardinality(p_kinds) > 0
+            then repository_kind_id = any(p_kinds) else true end
+        order by tracking_requested_at asc nulls last, next_tracking_at asc nulls first
+    ) r;
+$$ language sql;
@@ -8,9 +8,25 @@ returns setof json as $$
         'display_name', r.display_name,
         'url', r.url,
         'branch', r.branch,
-        'private', (case when r.auth_user is not null or r.auth_pass is not null then true else null end),
+        'git_url', r.git_url,
+        'git_ref', r.git_ref,
+        'git_subdir', r.git_subdir,
+        'private', (
+            case when
+                r.auth_user is not null
+                or r.auth_pass is not null
+                or r.auth_ssh_key is not null
+                or r.auth_github_app_key is not null
+            then true else null end
+        ),
         'auth_user', (case when p_include_credentials then r.auth_user else null end),
         'auth_pass', (case when p_include_credentials then r.auth_pass else null end),
+        'auth_ssh_key', (case when p_include_credentials then r.auth_ssh_key else null end),
+        'auth_ssh_known_hosts', (case when p_include_credentials then r.auth_ssh_known_hosts else null end),
+        'auth_github_app_id', (case when p_include_credentials then r.auth_github_app_id else null end),
+        'auth_github_app_installation_id', (case when p_include_credentials then r.auth_github_app_installation_id else null end),
+        'auth_github_app_key', (case when p_include_credentials then r.auth_github_app_key else null end),
+        'push_secret', (case when p_include_credentials then r.push_secret else null end),
         'kind', r.repository_kind_id,
         'verified_publisher', r.verified_publisher,
         'official', r.official,
@@ -23,6 +39,8 @@ returns setof json as $$
         'last_tracking_errors', r.last_tracking_errors,
         'data', r.data,
         'packages_deletion_protection', r.packages_deletion_protection,
+        'tracking_interval', r.tracking_interval,
+        'tracking_requested', (case when r.tracking_requested_at is not null then true else null end),
         'user_alias', u.alias,
         'organization_name', o.name,
         'organization_display_name', o.display_name
@@ -9,8 +9,12 @@ returns setof json as $$
         'url', r.url,
         'branch', r.branch,
         'private', (
-            case when r.auth_user is not null or r.auth_pass is not null then true
-            else false end
+            case when
+                r.auth_user is not null
+                or r.auth_pass is not null
+                or r.auth_ssh_key is not null
+                or r.auth_github_app_key is not null
+            then true else false end
         ),
         'kind', r.repository_kind_id,
         'verified_publisher', verified_publisher,
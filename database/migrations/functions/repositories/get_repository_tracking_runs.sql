@@ -0,0 +1,60 @@
+-- get_repository_tracking_runs returns the tracking runs of the provided
+-- repository, most recent first, if the requesting user is the owner of the
+-- repository or belongs to the organization which owns it.
+create or replace function get_repository_tracking_runs(
+    p_user_id uuid,
+    p_repository_name text,
+    p_limit int,
+    p_offset int
+) returns table(data json, total_count bigint) as $$
+declare
+    v_repository_id uuid;
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+begin
+    -- Get user or organization owning the repository
+    select r.repository_id, r.user_id, o.name
+    into v_repository_id, v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    with repository_runs as (
+        select *
+        from repository_tracking_run
+        where repository_id = v_repository_id
+    )
+    select
+        coalesce(json_agg(json_strip_nulls(json_build_object(
+            'repository_tracking_run_id', repository_tracking_run_id,
+            'repository_id', repository_id,
+            'started_at', floor(extract(epoch from started_at)),
+            'finished_at', floor(extract(epoch from finished_at)),
+            'remote_digest', remote_digest,
+            'packages_added', packages_added,
+            'packages_updated', packages_updated,
+            'packages_removed', packages_removed,
+            'packages_ignored', packages_ignored,
+            'errors', errors
+        ))), '[]'),
+        (select count(*) from repository_runs)
+    from (
+        select *
+        from repository_runs
+        order by started_at desc
+        limit (case when p_limit = 0 then null else p_limit end)
+        offset p_offset
+    ) rr;
+end
+$$ language plpgsql;
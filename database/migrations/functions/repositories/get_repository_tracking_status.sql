@@ -0,0 +1,58 @@
+-- get_repository_tracking_status returns the tracking status of the provided
+-- repository as a json object. The repository is considered to be running
+-- while the tracker holds its advisory lock, and queued when its tracking has
+-- been requested after the last tracking finished.
+create or replace function get_repository_tracking_status(
+    p_user_id uuid,
+    p_repository_name text,
+    p_lock_key int
+) returns setof json as $$
+declare
+    v_repository_id uuid;
+    v_owner_user_id uuid;
+    v_owner_organization_name text;
+begin
+    -- Get user or organization owning the repository
+    select r.repository_id, r.user_id, o.name
+    into v_repository_id, v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+
+    -- Check if the user doing the request is the owner or belongs to the
+    -- organization which owns it
+    if v_owner_organization_name is not null then
+        if not user_belongs_to_organization(p_user_id, v_owner_organization_name) then
+            raise insufficient_privilege;
+        end if;
+    elsif v_owner_user_id <> p_user_id then
+        raise insufficient_privilege;
+    end if;
+
+    return query
+    select json_strip_nulls(json_build_object(
+        'status', (
+            case
+                when exists (
+                    select 1 from pg_locks
+                    where locktype = 'advisory'
+                    and classid = p_lock_key::oid
+                    and objid = hashtext(r.repository_id::text)::oid
+                    and objsubid = 2
+                    and granted
+                ) then 'running'
+                when r.tracking_requested_at is not null and (
+                    r.last_tracking_ts is null or r.last_tracking_ts < r.tracking_requested_at
+                ) then 'queued'
+                else 'finished'
+            end
+        ),
+        'tracking_requested_at', floor(extract(epoch from r.tracking_requested_at)),
+        'next_tracking_at', floor(extract(epoch from r.next_tracking_at)),
+        'last_tracking_ts', floor(extract(epoch from r.last_tracking_ts)),
+        'last_tracking_errors', r.last_tracking_errors
+    ))
+    from repository r
+    where r.repository_id = v_repository_id;
+end
+$$ language plpgsql;
@@ -0,0 +1,42 @@
+-- register_repository_tracking_run registers the provided repository tracking
+-- run. Only the most recent runs of each repository are kept.
+create or replace function register_repository_tracking_run(p_run jsonb)
+returns void as $$
+declare
+    v_max_runs_per_repository constant int := 100;
+    v_repository_id uuid := (p_run->>'repository_id')::uuid;
+begin
+    insert into repository_tracking_run (
+        repository_id,
+        started_at,
+        finished_at,
+        remote_digest,
+        packages_added,
+        packages_updated,
+        packages_removed,
+        packages_ignored,
+        errors
+    ) values (
+        v_repository_id,
+        to_timestamp((p_run->>'started_at')::bigint),
+        to_timestamp((p_run->>'finished_at')::bigint),
+        nullif(p_run->>'remote_digest', ''),
+        coalesce((p_run->>'packages_added')::int, 0),
+        coalesce((p_run->>'packages_updated')::int, 0),
+        coalesce((p_run->>'packages_removed')::int, 0),
+        coalesce((p_run->>'packages_ignored')::int, 0),
+        nullif(nullif(p_run->'errors', 'null'), '[]')
+    );
+
+    -- Delete oldest runs
+    delete from repository_tracking_run
+    where repository_id = v_repository_id
+    and repository_tracking_run_id not in (
+        select repository_tracking_run_id
+        from repository_tracking_run
+        where repository_id = v_repository_id
+        order by started_at desc
+        limit v_max_runs_per_repository
+    );
+end
+$$ language plpgsql;
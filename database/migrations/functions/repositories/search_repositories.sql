@@ -26,8 +26,16 @@ begin
             r.display_name,
             r.url,
             r.branch,
+            r.git_url,
+            r.git_ref,
+            r.git_subdir,
             r.auth_user,
             r.auth_pass,
+            r.auth_ssh_key,
+            r.auth_ssh_known_hosts,
+            r.auth_github_app_id,
+            r.auth_github_app_installation_id,
+            r.auth_github_app_key,
             r.repository_kind_id,
             r.verified_publisher,
             r.official,
@@ -41,6 +49,7 @@ begin
             r.last_tracking_errors,
             r.data as repository_data,
             r.packages_deletion_protection,
+            r.tracking_interval,
             u.alias as user_alias,
             o.name as organization_name,
             o.display_name as organization_display_name
@@ -72,9 +81,24 @@ begin
             'display_name', display_name,
             'url', url,
             'branch', branch,
-            'private', (case when auth_user is not null or auth_pass is not null then true else null end),
+            'git_url', git_url,
+            'git_ref', git_ref,
+            'git_subdir', git_subdir,
+            'private', (
+                case when
+                    auth_user is not null
+                    or auth_pass is not null
+                    or auth_ssh_key is not null
+                    or auth_github_app_key is not null
+                then true else null end
+            ),
             'auth_user', (case when v_include_credentials then auth_user else null end),
             'auth_pass', (case when v_include_credentials then auth_pass else null end),
+            'auth_ssh_key', (case when v_include_credentials then auth_ssh_key else null end),
+            'auth_ssh_known_hosts', (case when v_include_credentials then auth_ssh_known_hosts else null end),
+            'auth_github_app_id', (case when v_include_credentials then auth_github_app_id else null end),
+            'auth_github_app_installation_id', (case when v_include_credentials then auth_github_app_installation_id else null end),
+            'auth_github_app_key', (case when v_include_credentials then auth_github_app_key else null end),
             'kind', repository_kind_id,
             'verified_publisher', verified_publisher,
             'official', official,
@@ -88,6 +112,7 @@ begin
             'last_tracking_errors', last_tracking_errors,
             'data', repository_data,
             'packages_deletion_protection', packages_deletion_protection,
+            'tracking_interval', tracking_interval,
             'user_alias', user_alias,
             'organization_name', organization_name,
             'organization_display_name', organization_display_name
@@ -2,7 +2,7 @@
 -- to the requesting user or an organization he belongs to. The user must own
 -- the repository transferred or belong to the organization which owns it,
 -- unless this transfer is part of an ownership claim request that has been
--- previously authorized.
+-- previously authorized. The transfer is registered in the audit log.
 create or replace function transfer_repository(
     p_repository_name text,
     p_user_id uuid,
@@ -13,15 +13,15 @@ declare
     v_owner_user_id uuid;
     v_owner_organization_name text;
 begin
+    -- Get user or organization owning the repository
+    select r.user_id, o.name into v_owner_user_id, v_owner_organization_name
+    from repository r
+    left join organization o using (organization_id)
+    where r.name = p_repository_name;
+
     -- Validate repository ownership unless this transfer is part of an
     -- ownership claim request
     if not p_ownership_claim then
-        -- Get user or organization owning the repository
-        select r.user_id, o.name into v_owner_user_id, v_owner_organization_name
-        from repository r
-        left join organization o using (organization_id)
-        where r.name = p_repository_name;
-
         -- Check if the user doing the request is the owner or belongs to the
         -- organization which owns it
         if v_owner_organization_name is not null then
@@ -92,5 +92,16 @@ begin
     from new_tsdoc
     where package.package_id = new_tsdoc.package_id;
 
+    -- Register audit event
+    perform register_audit_event(jsonb_build_object(
+        'action', 'repositoryTransferred',
+        'user_id', p_user_id,
+        'organization_name', v_owner_organization_name,
+        'target_organization_name', p_org_name,
+        'details', jsonb_build_object(
+            'repository_name', p_repository_name,
+            'ownership_claim', p_ownership_claim::text
+        )
+    ));
 end
 $$ language plpgsql;
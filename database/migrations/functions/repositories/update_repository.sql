@@ -9,6 +9,11 @@ declare
     v_scanner_disabled boolean;
     v_auth_user text;
     v_auth_pass text;
+    v_auth_ssh_key text;
+    v_auth_ssh_known_hosts text;
+    v_auth_github_app_id text;
+    v_auth_github_app_installation_id text;
+    v_auth_github_app_key text;
 begin
     -- Get some information about the repository
     select
@@ -16,13 +21,23 @@ begin
         disabled,
         scanner_disabled,
         auth_user,
-        auth_pass
+        auth_pass,
+        auth_ssh_key,
+        auth_ssh_known_hosts,
+        auth_github_app_id,
+        auth_github_app_installation_id,
+        auth_github_app_key
     into
         v_repository_id,
         v_disabled,
         v_scanner_disabled,
         v_auth_user,
-        v_auth_pass
+        v_auth_pass,
+        v_auth_ssh_key,
+        v_auth_ssh_known_hosts,
+        v_auth_github_app_id,
+        v_auth_github_app_installation_id,
+        v_auth_github_app_key
     from repository r
     where r.name = p_repository->>'name'
     for update;
@@ -48,6 +63,9 @@ begin
         display_name = nullif(p_repository->>'display_name', ''),
         url = p_repository->>'url',
         branch = nullif(p_repository->>'branch', ''),
+        git_url = nullif(p_repository->>'git_url', ''),
+        git_ref = nullif(p_repository->>'git_ref', ''),
+        git_subdir = nullif(p_repository->>'git_subdir', ''),
         auth_user = (
             case
                 when (p_repository->>'auth_user' = '=') then v_auth_user
@@ -60,9 +78,40 @@ begin
                 else nullif(p_repository->>'auth_pass', '')
             end
         ),
+        auth_ssh_key = (
+            case
+                when (p_repository->>'auth_ssh_key' = '=') then v_auth_ssh_key
+                else nullif(p_repository->>'auth_ssh_key', '')
+            end
+        ),
+        auth_ssh_known_hosts = (
+            case
+                when (p_repository->>'auth_ssh_known_hosts' = '=') then v_auth_ssh_known_hosts
+                else nullif(p_repository->>'auth_ssh_known_hosts', '')
+            end
+        ),
+        auth_github_app_id = (
+            case
+                when (p_repository->>'auth_github_app_id' = '=') then v_auth_github_app_id
+                else nullif(p_repository->>'auth_github_app_id', '')
+            end
+        ),
+        auth_github_app_installation_id = (
+            case
+                when (p_repository->>'auth_github_app_installation_id' = '=') then v_auth_github_app_installation_id
+                else nullif(p_repository->>'auth_github_app_installation_id', '')
+            end
+        ),
+        auth_github_app_key = (
+            case
+                when (p_repository->>'auth_github_app_key' = '=') then v_auth_github_app_key
+                else nullif(p_repository->>'auth_github_app_key', '')
+            end
+        ),
         disabled = (p_repository->>'disabled')::boolean,
         scanner_disabled = (p_repository->>'scanner_disabled')::boolean,
-        data = nullif(p_repository->'data', 'null')
+        data = nullif(p_repository->'data', 'null'),
+        tracking_interval = nullif((p_repository->>'tracking_interval')::int, 0)
     where repository_id = v_repository_id;
 
     -- If the repository has been disabled, remove packages belonging to it and
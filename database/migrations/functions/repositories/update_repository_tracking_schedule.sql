@@ -0,0 +1,47 @@
+-- update_repository_tracking_schedule schedules the next tracking of the
+-- provided repository. When the tracking succeeded and the repository's
+-- digest did not change, the backoff factor applied to the tracking interval
+-- is doubled (up to the maximum provided). Otherwise it is reset.
+--
+-- Any tracking requested after the tracking started (p_tracking_started_at)
+-- is kept pending.
+create or replace function update_repository_tracking_schedule(
+    p_repository_id uuid,
+    p_previous_digest text,
+    p_succeeded boolean,
+    p_default_interval int,
+    p_max_backoff_factor int,
+    p_tracking_started_at timestamptz
+) returns void as $$
+declare
+    v_digest text;
+    v_tracking_interval int;
+    v_backoff_factor int;
+begin
+    -- Get repository's current tracking details
+    select digest, coalesce(tracking_interval, p_default_interval), tracking_backoff_factor
+    into v_digest, v_tracking_interval, v_backoff_factor
+    from repository
+    where repository_id = p_repository_id
+    for update;
+
+    -- Calculate new backoff factor
+    if p_succeeded and v_digest is not null and v_digest = p_previous_digest then
+        v_backoff_factor = least(v_backoff_factor * 2, greatest(p_max_backoff_factor, 1));
+    else
+        v_backoff_factor = 1;
+    end if;
+
+    -- Schedule next tracking
+    update repository set
+        tracking_backoff_factor = v_backoff_factor,
+        next_tracking_at = current_timestamp + make_interval(secs => v_tracking_interval * v_backoff_factor),
+        tracking_requested_at = (
+            case
+                when tracking_requested_at > p_tracking_started_at then tracking_requested_at
+                else null
+            end
+        )
+    where repository_id = p_repository_id;
+end
+$$ language plpgsql;
@@ -0,0 +1,40 @@
+-- register_failed_login_attempt registers a failed login attempt for the
+-- provided user from the client given. When the number of consecutive failed
+-- attempts from the client reaches the maximum provided, the account is locked
+-- temporarily for that client and the sessions pending approval created from
+-- it are deleted. It returns true when the account has been locked.
+create or replace function register_failed_login_attempt(
+    p_user_id uuid,
+    p_client text,
+    p_max_failed_attempts int,
+    p_lockout_seconds int
+)
+returns boolean as $$
+declare
+    v_failed_attempts int;
+begin
+    -- Increment failed login attempts counter
+    insert into login_lockout (user_id, client, failed_attempts)
+    select p_user_id, p_client, 1
+    where exists (select 1 from "user" where user_id = p_user_id)
+    on conflict (user_id, client) do update
+    set failed_attempts = login_lockout.failed_attempts + 1
+    returning failed_attempts into v_failed_attempts;
+    if v_failed_attempts is null or v_failed_attempts < p_max_failed_attempts then
+        return false;
+    end if;
+
+    -- Lock account for the client and delete its sessions pending approval
+    update login_lockout set
+        failed_attempts = 0,
+        locked_until = current_timestamp + make_interval(secs => p_lockout_seconds)
+    where user_id = p_user_id
+    and client = p_client;
+    delete from session
+    where user_id = p_user_id
+    and approved = false
+    and host(ip) = p_client;
+
+    return true;
+end
+$$ language plpgsql;
@@ -14,7 +14,8 @@ begin
         email,
         email_verified,
         password,
-        profile_image_id
+        profile_image_id,
+        ldap_dn
     ) values (
         p_user->>'alias',
         nullif(p_user->>'first_name', ''),
@@ -22,7 +23,8 @@ begin
         p_user->>'email',
         (p_user->>'email_verified')::boolean,
         nullif(p_user->>'password', ''),
-        nullif(p_user->>'profile_image_id', '')::uuid
+        nullif(p_user->>'profile_image_id', '')::uuid,
+        nullif(p_user->>'ldap_dn', '')
     ) returning user_id into v_user_id;
 
     -- Register email verification code if email isn't already verified
@@ -0,0 +1,90 @@
+-- sync_user_organizations_memberships synchronizes the memberships of the
+-- provided user to the organizations listed, as well as the roles the user has
+-- in the authorization policy data of those using the rbac.v1 policy.
+create or replace function sync_user_organizations_memberships(p_user_id uuid, p_memberships jsonb)
+returns void as $$
+declare
+    v_user_alias text;
+    v_membership jsonb;
+    v_organization_id uuid;
+    v_predefined_policy text;
+    v_policy_data jsonb;
+    v_new_policy_data jsonb;
+    v_role record;
+    v_role_users jsonb;
+    v_users_in_organization int;
+begin
+    select alias into v_user_alias from "user" where user_id = p_user_id;
+
+    for v_membership in select * from jsonb_array_elements(p_memberships)
+    loop
+        -- Skip organizations that do not exist
+        select organization_id, predefined_policy, policy_data
+        into v_organization_id, v_predefined_policy, v_policy_data
+        from organization
+        where name = v_membership->>'organization_name';
+        if not found then
+            continue;
+        end if;
+
+        -- Sync membership
+        if (v_membership->>'member')::boolean then
+            insert into user__organization (user_id, organization_id, confirmed)
+            values (p_user_id, v_organization_id, true)
+            on conflict (user_id, organization_id) do update set confirmed = true;
+        else
+            -- Last member of an organization cannot leave it
+            select count(*) into v_users_in_organization
+            from user__organization
+            where organization_id = v_organization_id
+            and user_id <> p_user_id;
+            if v_users_in_organization > 0 then
+                delete from user__organization
+                where user_id = p_user_id
+                and organization_id = v_organization_id;
+
+                -- Delete user opt-out entries for repositories belonging to the org
+                delete from opt_out
+                where user_id = p_user_id
+                and repository_id in (
+                    select repository_id
+                    from repository
+                    where organization_id = v_organization_id
+                );
+            end if;
+        end if;
+
+        -- Sync roles (only supported for the rbac.v1 predefined policy)
+        if v_predefined_policy is distinct from 'rbac.v1' then
+            continue;
+        end if;
+        v_new_policy_data := coalesce(v_policy_data, '{}');
+        for v_role in
+            select key as name, value::boolean as granted
+            from jsonb_each_text(coalesce(v_membership->'roles', '{}'))
+        loop
+            if not v_role.granted and v_new_policy_data->'roles'->v_role.name is null then
+                continue;
+            end if;
+            if jsonb_typeof(v_new_policy_data->'roles') is distinct from 'object' then
+                v_new_policy_data := v_new_policy_data || '{"roles": {}}';
+            end if;
+            select coalesce(jsonb_agg(u), '[]') into v_role_users
+            from jsonb_array_elements(coalesce(v_new_policy_data->'roles'->v_role.name->'users', '[]')) u
+            where u <> to_jsonb(v_user_alias);
+            if v_role.granted then
+                v_role_users := v_role_users || jsonb_build_array(v_user_alias);
+            end if;
+            v_new_policy_data := jsonb_set(
+                v_new_policy_data,
+                array['roles', v_role.name],
+                coalesce(v_new_policy_data->'roles'->v_role.name, '{}') || jsonb_build_object('users', v_role_users)
+            );
+        end loop;
+        if v_new_policy_data is distinct from coalesce(v_policy_data, '{}') then
+            update organization set policy_data = v_new_policy_data
+            where organization_id = v_organization_id;
+        end if;
+    end loop;
+end
+$$ language plpgsql;
@@ -1,5 +1,5 @@
 -- get_webhook returns the webhook identified by the id provided as a json
--- object.
+-- object. The webhook secret is never returned, just "=" when it's set.
 create or replace function get_webhook(p_user_id uuid, p_webhook_id uuid)
 returns setof json as $$
 begin
@@ -12,7 +12,7 @@ begin
         'name', wh.name,
         'description', wh.description,
         'url', wh.url,
-        'secret', wh.secret,
+        'secret', case when wh.secret is not null then '=' end,
         'content_type', wh.content_type,
         'template', wh.template,
         'active', wh.active,
@@ -1,4 +1,5 @@
--- update_webhook updates the provided webhook in the database.
+-- update_webhook updates the provided webhook in the database. The current
+-- secret is kept when "=" is provided as the secret.
 create or replace function update_webhook(p_user_id uuid, p_webhook jsonb)
 returns void as $$
 declare
@@ -17,7 +18,10 @@ begin
         name = p_webhook->>'name',
         description = nullif(p_webhook->>'description', ''),
         url = p_webhook->>'url',
-        secret = nullif(p_webhook->>'secret', ''),
+        secret = case
+            when p_webhook->>'secret' = '=' then secret
+            else nullif(p_webhook->>'secret', '')
+        end,
         content_type = nullif(p_webhook->>'content_type', ''),
         template = nullif(p_webhook->>'template', ''),
         active = (p_webhook->>'active')::boolean
@@ -0,0 +1,39 @@
+create table if not exists audit_log (
+    audit_log_id uuid primary key default gen_random_uuid(),
+    action text not null check (action <> ''),
+    user_id uuid not null references "user" on delete cascade,
+    organization_id uuid references organization on delete cascade,
+    target_organization_id uuid references organization on delete set null,
+    details jsonb,
+    created_at timestamptz default current_timestamp not null
+);
+
+create index audit_log_user_id_idx on audit_log (user_id);
+create index audit_log_organization_id_idx on audit_log (organization_id);
+create index audit_log_target_organization_id_idx on audit_log (target_organization_id);
+create index audit_log_created_at_idx on audit_log (created_at);
+
+create or replace function prevent_audit_log_changes()
+returns trigger as $$
+begin
+    -- Changes made by foreign keys referential actions (i.e. when a user or an
+    -- organization is deleted) are allowed
+    if pg_trigger_depth() > 1 then
+        if tg_op = 'DELETE' then
+            return old;
+        end if;
+        return new;
+    end if;
+    raise exception 'audit log entries cannot be modified';
+end
+$$ language plpgsql;
+
+create trigger trigger_prevent_audit_log_changes
+before update or delete on audit_log
+for each row
+execute function prevent_audit_log_changes();
+
+---- create above / drop below ----
+
+drop table if exists audit_log;
+drop function if exists prevent_audit_log_changes;
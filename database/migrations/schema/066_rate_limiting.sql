@@ -0,0 +1,22 @@
+create table if not exists rate_limit_counter (
+    key text not null check (key <> ''),
+    window_start timestamptz not null,
+    hits integer not null default 0,
+    expires_at timestamptz not null,
+    primary key (key, window_start)
+);
+
+create index rate_limit_counter_expires_at_idx on rate_limit_counter (expires_at);
+
+create table if not exists login_lockout (
+    user_id uuid not null references "user" on delete cascade,
+    client text not null,
+    failed_attempts integer not null default 0,
+    locked_until timestamptz,
+    primary key (user_id, client)
+);
+
+---- create above / drop below ----
+
+drop table if exists login_lockout;
+drop table if exists rate_limit_counter;
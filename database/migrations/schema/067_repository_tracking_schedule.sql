@@ -0,0 +1,13 @@
+alter table repository add column tracking_interval integer check (tracking_interval > 0);
+alter table repository add column tracking_backoff_factor integer not null default 1 check (tracking_backoff_factor >= 1);
+alter table repository add column next_tracking_at timestamptz;
+
+create index repository_next_tracking_at_idx on repository (next_tracking_at);
+
+---- create above / drop below ----
+
+drop index if exists repository_next_tracking_at_idx;
+
+alter table repository drop column next_tracking_at;
+alter table repository drop column tracking_backoff_factor;
+alter table repository drop column tracking_interval;
@@ -0,0 +1,12 @@
+alter table repository add column push_secret text check (push_secret <> '');
+alter table repository add column tracking_requested_at timestamptz;
+
+create index repository_tracking_requested_at_idx on repository (tracking_requested_at)
+where tracking_requested_at is not null;
+
+---- create above / drop below ----
+
+drop index if exists repository_tracking_requested_at_idx;
+
+alter table repository drop column tracking_requested_at;
+alter table repository drop column push_secret;
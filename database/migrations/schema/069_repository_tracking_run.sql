@@ -0,0 +1,19 @@
+create table if not exists repository_tracking_run (
+    repository_tracking_run_id uuid primary key default gen_random_uuid(),
+    repository_id uuid not null references repository on delete cascade,
+    started_at timestamptz not null,
+    finished_at timestamptz not null,
+    remote_digest text check (remote_digest <> ''),
+    packages_added integer not null default 0,
+    packages_updated integer not null default 0,
+    packages_removed integer not null default 0,
+    packages_ignored integer not null default 0,
+    errors jsonb
+);
+
+create index repository_tracking_run_repository_id_started_at_idx
+on repository_tracking_run (repository_id, started_at desc);
+
+---- create above / drop below ----
+
+drop table if exists repository_tracking_run;
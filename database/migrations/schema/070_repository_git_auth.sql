@@ -0,0 +1,13 @@
+alter table repository add column auth_ssh_key text check (auth_ssh_key <> '');
+alter table repository add column auth_ssh_known_hosts text check (auth_ssh_known_hosts <> '');
+alter table repository add column auth_github_app_id text check (auth_github_app_id <> '');
+alter table repository add column auth_github_app_installation_id text check (auth_github_app_installation_id <> '');
+alter table repository add column auth_github_app_key text check (auth_github_app_key <> '');
+
+---- create above / drop below ----
+
+alter table repository drop column auth_github_app_key;
+alter table repository drop column auth_github_app_installation_id;
+alter table repository drop column auth_github_app_id;
+alter table repository drop column auth_ssh_known_hosts;
+alter table repository drop column auth_ssh_key;
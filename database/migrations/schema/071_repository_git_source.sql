@@ -0,0 +1,9 @@
+alter table repository add column git_url text check (git_url <> '');
+alter table repository add column git_ref text check (git_ref <> '');
+alter table repository add column git_subdir text check (git_subdir <> '');
+
+---- create above / drop below ----
+
+alter table repository drop column git_subdir;
+alter table repository drop column git_ref;
+alter table repository drop column git_url;
@@ -0,0 +1,54 @@
+create table if not exists snapshot_dependency (
+    package_id uuid not null,
+    version text not null,
+    name text not null check (name <> ''),
+    version_constraint text check (version_constraint <> ''),
+    repository_url text check (repository_url <> ''),
+    dependency_package_id uuid references package on delete set null,
+    foreign key (package_id, version) references snapshot (package_id, version) on delete cascade
+);
+
+create index snapshot_dependency_package_id_version_idx on snapshot_dependency (package_id, version);
+create index snapshot_dependency_dependency_package_id_idx on snapshot_dependency (dependency_package_id);
+create index snapshot_dependency_unresolved_name_idx on snapshot_dependency (name) where dependency_package_id is null;
+
+-- Register the dependencies of the Helm charts snapshots already available
+insert into snapshot_dependency (
+    package_id,
+    version,
+    name,
+    version_constraint,
+    repository_url,
+    dependency_package_id
+)
+select
+    s.package_id,
+    s.version,
+    dep->>'name',
+    nullif(dep->>'version', ''),
+    nullif(dep->>'repository', ''),
+    (
+        select dp.package_id
+        from package dp
+        join repository dr using (repository_id)
+        where dr.repository_kind_id = 0
+        and dp.name = dep->>'name'
+        and case
+            when starts_with(dep->>'repository', 'file://') then dr.url = r.url
+            else rtrim(dr.url, '/') = rtrim(dep->>'repository', '/')
+                or dr.url = rtrim(dep->>'repository', '/') || '/' || (dep->>'name')
+        end
+        order by dr.verified_publisher desc
+        limit 1
+    )
+from snapshot s
+join package p using (package_id)
+join repository r using (repository_id)
+cross join jsonb_array_elements(s.data->'dependencies') as dep
+where r.repository_kind_id = 0
+and jsonb_typeof(s.data->'dependencies') = 'array'
+and dep->>'name' <> '';
+
+---- create above / drop below ----
+
+drop table if exists snapshot_dependency;
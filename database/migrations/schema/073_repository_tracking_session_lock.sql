@@ -0,0 +1,3 @@
+drop function if exists update_repository_tracking_schedule(uuid, text, boolean, int, int);
+
+---- create above / drop below ----
@@ -0,0 +1,5 @@
+alter table "user" add column ldap_dn text unique;
+
+---- create above / drop below ----
+
+alter table "user" drop column ldap_dn;
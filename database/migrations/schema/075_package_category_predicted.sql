@@ -0,0 +1,5 @@
+alter table package add column package_category_predicted boolean;
+
+---- create above / drop below ----
+
+alter table package drop column package_category_predicted;
@@ -0,0 +1,121 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name) values (:'org1ID', 'org1');
+insert into organization (organization_id, name) values (:'org2ID', 'org2');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org1ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org2ID', true);
+insert into audit_log (audit_log_id, action, user_id, created_at)
+values ('00000000-0000-0000-0000-000000000001', 'login', :'user1ID', '2026-01-01 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, organization_id, details, created_at)
+values ('00000000-0000-0000-0000-000000000002', 'organizationMemberAdded', :'user1ID', :'org1ID', '{"user_alias": "user2"}', '2026-01-02 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, organization_id, target_organization_id, details, created_at)
+values ('00000000-0000-0000-0000-000000000003', 'repositoryTransferred', :'user2ID', :'org2ID', :'org1ID', '{"repository_name": "repo1"}', '2026-01-03 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, organization_id, created_at)
+values ('00000000-0000-0000-0000-000000000004', 'authorizationPolicyUpdated', :'user2ID', :'org2ID', '2026-01-04 00:00:00+00');
+
+-- Run some tests
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_organization_audit_log('00000000-0000-0000-0000-000000000001', 'org1', '{}')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000003",
+                    "action": "repositoryTransferred",
+                    "user_alias": "user2",
+                    "organization_name": "org2",
+                    "target_organization_name": "org1",
+                    "details": {
+                        "repository_name": "repo1"
+                    },
+                    "created_at": 1767398400
+                },
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "organizationMemberAdded",
+                    "user_alias": "user1",
+                    "organization_name": "org1",
+                    "details": {
+                        "user_alias": "user2"
+                    },
+                    "created_at": 1767312000
+                }
+            ]'::jsonb,
+            2
+        )
+    $$,
+    'All org1 audit log entries (including repositories transferred to it) should be returned'
+);
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_organization_audit_log('00000000-0000-0000-0000-000000000002', 'org1', '{"limit": 1, "offset": 1}')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "organizationMemberAdded",
+                    "user_alias": "user1",
+                    "organization_name": "org1",
+                    "details": {
+                        "user_alias": "user2"
+                    },
+                    "created_at": 1767312000
+                }
+            ]'::jsonb,
+            2
+        )
+    $$,
+    'Limit and offset of 1 used, organizationMemberAdded entry should be returned'
+);
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_organization_audit_log('00000000-0000-0000-0000-000000000002', 'org2', '{
+            "actions": ["authorizationPolicyUpdated"],
+            "from": "2026-01-01T00:00:00Z"
+        }')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000004",
+                    "action": "authorizationPolicyUpdated",
+                    "user_alias": "user2",
+                    "organization_name": "org2",
+                    "created_at": 1767484800
+                }
+            ]'::jsonb,
+            1
+        )
+    $$,
+    'Only authorizationPolicyUpdated entries should be returned'
+);
+select throws_ok(
+    $$ select * from get_organization_audit_log('00000000-0000-0000-0000-000000000001', 'org2', '{}') $$,
+    42501,
+    'insufficient_privilege',
+    'User1 should not be able to get org2 audit log'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
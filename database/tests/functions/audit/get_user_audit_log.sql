@@ -0,0 +1,131 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name) values (:'org1ID', 'org1');
+insert into audit_log (audit_log_id, action, user_id, details, created_at)
+values ('00000000-0000-0000-0000-000000000001', 'login', :'user1ID', '{"ip": "192.168.1.100"}', '2026-01-01 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, created_at)
+values ('00000000-0000-0000-0000-000000000002', 'tfaEnabled', :'user1ID', '2026-01-02 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, organization_id, details, created_at)
+values ('00000000-0000-0000-0000-000000000003', 'organizationMemberAdded', :'user1ID', :'org1ID', '{"user_alias": "user2"}', '2026-01-03 00:00:00+00');
+insert into audit_log (audit_log_id, action, user_id, created_at)
+values ('00000000-0000-0000-0000-000000000004', 'login', :'user2ID', '2026-01-04 00:00:00+00');
+
+-- Run some tests
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_user_audit_log('00000000-0000-0000-0000-000000000001', '{}')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000003",
+                    "action": "organizationMemberAdded",
+                    "organization_name": "org1",
+                    "details": {
+                        "user_alias": "user2"
+                    },
+                    "created_at": 1767398400
+                },
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "tfaEnabled",
+                    "created_at": 1767312000
+                },
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000001",
+                    "action": "login",
+                    "details": {
+                        "ip": "192.168.1.100"
+                    },
+                    "created_at": 1767225600
+                }
+            ]'::jsonb,
+            3
+        )
+    $$,
+    'All user1 audit log entries should be returned'
+);
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_user_audit_log('00000000-0000-0000-0000-000000000001', '{"limit": 1, "offset": 1}')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "tfaEnabled",
+                    "created_at": 1767312000
+                }
+            ]'::jsonb,
+            3
+        )
+    $$,
+    'Limit and offset of 1 used, tfaEnabled entry should be returned'
+);
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_user_audit_log('00000000-0000-0000-0000-000000000001', '{"actions": ["login", "tfaEnabled"]}')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "tfaEnabled",
+                    "created_at": 1767312000
+                },
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000001",
+                    "action": "login",
+                    "details": {
+                        "ip": "192.168.1.100"
+                    },
+                    "created_at": 1767225600
+                }
+            ]'::jsonb,
+            2
+        )
+    $$,
+    'Only login and tfaEnabled entries should be returned'
+);
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_user_audit_log('00000000-0000-0000-0000-000000000001', '{
+            "from": "2026-01-02T00:00:00Z",
+            "to": "2026-01-02T23:59:59Z"
+        }')
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "audit_log_id": "00000000-0000-0000-0000-000000000002",
+                    "action": "tfaEnabled",
+                    "created_at": 1767312000
+                }
+            ]'::jsonb,
+            1
+        )
+    $$,
+    'Only entries registered between from and to should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,89 @@
+-- Start transaction and plan tests
+begin;
+select plan(4);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name) values (:'org1ID', 'org1');
+insert into organization (organization_id, name) values (:'org2ID', 'org2');
+
+-- Register some audit events
+select register_audit_event('
+{
+    "action": "login",
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "details": {
+        "ip": "192.168.1.100",
+        "user_agent": "Safari 13.0.5"
+    }
+}
+');
+select register_audit_event('
+{
+    "action": "repositoryTransferred",
+    "user_id": "00000000-0000-0000-0000-000000000001",
+    "organization_name": "org1",
+    "target_organization_name": "org2",
+    "details": {
+        "repository_name": "repo1"
+    }
+}
+');
+
+-- Run some tests
+select results_eq(
+    $$
+        select action, user_id, organization_id, target_organization_id, details
+        from audit_log
+        order by action
+    $$,
+    $$
+        values
+            (
+                'login',
+                '00000000-0000-0000-0000-000000000001'::uuid,
+                null::uuid,
+                null::uuid,
+                '{"ip": "192.168.1.100", "user_agent": "Safari 13.0.5"}'::jsonb
+            ),
+            (
+                'repositoryTransferred',
+                '00000000-0000-0000-0000-000000000001'::uuid,
+                '00000000-0000-0000-0000-000000000001'::uuid,
+                '00000000-0000-0000-0000-000000000002'::uuid,
+                '{"repository_name": "repo1"}'::jsonb
+            )
+    $$,
+    'Audit events should have been registered'
+);
+select throws_ok(
+    $$ update audit_log set action = 'tfaDisabled' $$,
+    'audit log entries cannot be modified',
+    'Audit log entries cannot be updated'
+);
+select throws_ok(
+    $$ delete from audit_log $$,
+    'audit log entries cannot be modified',
+    'Audit log entries cannot be deleted'
+);
+delete from organization where organization_id = :'org2ID';
+select results_eq(
+    $$
+        select target_organization_id
+        from audit_log
+        where action = 'repositoryTransferred'
+    $$,
+    $$
+        values (null::uuid)
+    $$,
+    'Target organization should be unset when the organization is deleted'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
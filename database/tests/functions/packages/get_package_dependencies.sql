@@ -0,0 +1,148 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set package1ID '00000000-0000-0000-0000-000000000001'
+\set package2ID '00000000-0000-0000-0000-000000000002'
+\set package3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'oci://registry.io/charts/pkg3', 0, :'user1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package1ID', 'pkg1', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package2ID', 'pkg2', '1.0.0', :'repo1ID');
+insert into package (package_id, name, latest_version, repository_id)
+values (:'package3ID', 'pkg3', '1.0.0', :'repo2ID');
+insert into snapshot (package_id, version) values (:'package1ID', '0.9.0');
+insert into snapshot (package_id, version) values (:'package1ID', '1.0.0');
+insert into snapshot (package_id, version) values (:'package2ID', '1.0.0');
+insert into snapshot (package_id, version) values (:'package3ID', '1.0.0');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, dependency_package_id)
+values (:'package1ID', '0.9.0', 'pkg2', '0.x', 'https://repo1.com', :'package2ID');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, dependency_package_id)
+values (:'package1ID', '1.0.0', 'pkg2', '^1.0.0', 'https://repo1.com', :'package2ID');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url)
+values (:'package1ID', '1.0.0', 'pkg4', '1.x', 'https://repo4.com');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, dependency_package_id)
+values (:'package2ID', '1.0.0', 'pkg3', '~1.0.0', 'oci://registry.io/charts', :'package3ID');
+insert into snapshot_dependency (package_id, version, name, version_constraint, repository_url, dependency_package_id)
+values (:'package3ID', '1.0.0', 'pkg1', '>=1.0.0', 'https://repo1.com', :'package1ID');
+
+-- Run some tests
+select is(
+    (
+        select jsonb_agg(jsonb_build_object(
+            'depth', d->'depth',
+            'parent_package_id', d->'parent_package_id',
+            'package_id', d->'package_id',
+            'name', d->'name',
+            'version_constraint', d->'version_constraint',
+            'package_name', d->'package'->'name'
+        ))
+        from jsonb_array_elements(get_package_dependencies('{
+            "repository_name": "repo1",
+            "package_name": "pkg1"
+        }')::jsonb) as d
+    ),
+    '[
+        {
+            "depth": 1,
+            "parent_package_id": "00000000-0000-0000-0000-000000000001",
+            "package_id": "00000000-0000-0000-0000-000000000002",
+            "name": "pkg2",
+            "version_constraint": "^1.0.0",
+            "package_name": "pkg2"
+        },
+        {
+            "depth": 1,
+            "parent_package_id": "00000000-0000-0000-0000-000000000001",
+            "package_id": null,
+            "name": "pkg4",
+            "version_constraint": "1.x",
+            "package_name": null
+        }
+    ]'::jsonb,
+    'Direct dependencies of pkg1 latest version should be returned'
+);
+select is(
+    (
+        select jsonb_agg(jsonb_build_object(
+            'depth', d->'depth',
+            'parent_package_id', d->'parent_package_id',
+            'name', d->'name',
+            'version_constraint', d->'version_constraint'
+        ))
+        from jsonb_array_elements(get_package_dependencies('{
+            "repository_name": "repo1",
+            "package_name": "pkg1",
+            "version": "0.9.0"
+        }')::jsonb) as d
+    ),
+    '[
+        {
+            "depth": 1,
+            "parent_package_id": "00000000-0000-0000-0000-000000000001",
+            "name": "pkg2",
+            "version_constraint": "0.x"
+        }
+    ]'::jsonb,
+    'Direct dependencies of pkg1 version 0.9.0 should be returned'
+);
+select is(
+    (
+        select jsonb_agg(jsonb_build_object(
+            'depth', d->'depth',
+            'parent_package_id', d->'parent_package_id',
+            'package_id', d->'package_id'
+        ))
+        from jsonb_array_elements(get_package_dependencies('{
+            "repository_name": "repo1",
+            "package_name": "pkg1",
+            "depth": 5
+        }')::jsonb) as d
+    ),
+    '[
+        {
+            "depth": 1,
+            "parent_package_id": "00000000-0000-0000-0000-000000000001",
+            "package_id": "00000000-0000-0000-0000-000000000002"
+        },
+        {
+            "depth": 1,
+            "parent_package_id": "00000000-0000-0000-0000-000000000001",
+            "package_id": null
+        },
+        {
+            "depth": 2,
+            "parent_package_id": "00000000-0000-0000-0000-000000000002",
+            "package_id": "00000000-0000-0000-0000-000000000003"
+        },
+        {
+            "depth": 3,
+            "parent_package_id": "00000000-0000-0000-0000-000000000003",
+            "package_id": "00000000-0000-0000-0000-000000000001"
+        }
+    ]'::jsonb,
+    'Transitive dependencies of pkg1 should be returned, stopping at cycles'
+);
+select is_empty(
+    $$ select get_package_dependencies('{"repository_name": "repo1", "package_name": "pkg9"}') $$,
+    'No dependencies should be returned for a package that does not exist'
+);
+select is_empty(
+    $$ select get_package_dependencies('{"repository_name": "repo1", "package_name": "pkg1", "version": "2.0.0"}') $$,
+    'No dependencies should be returned for a package version that does not exist'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
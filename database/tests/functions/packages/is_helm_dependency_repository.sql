@@ -0,0 +1,39 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Test function
+select is(
+    is_helm_dependency_repository('pkg1', 'https://repo1.com/', 'https://repo2.com', 'https://repo1.com'),
+    true,
+    'Repository url should match ignoring trailing slashes'
+);
+select is(
+    is_helm_dependency_repository('pkg1', 'https://repo1.com', 'https://repo2.com', 'https://repo3.com'),
+    false,
+    'Repository url should not match'
+);
+select is(
+    is_helm_dependency_repository('pkg1', 'oci://registry.io/charts', 'https://repo2.com', 'oci://registry.io/charts/pkg1'),
+    true,
+    'OCI repository url including the chart name should match'
+);
+select is(
+    is_helm_dependency_repository('pkg1', 'oci://registry.io/charts', 'https://repo2.com', 'oci://registry.io/charts/pkg2'),
+    false,
+    'OCI repository url including a different chart name should not match'
+);
+select is(
+    is_helm_dependency_repository('pkg1', 'file://../pkg1', 'https://repo2.com', 'https://repo2.com'),
+    true,
+    'Local dependency should match the dependent repository'
+);
+select is(
+    is_helm_dependency_repository('pkg1', 'file://../pkg1', 'https://repo2.com', 'https://repo1.com'),
+    false,
+    'Local dependency should not match other repositories'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(14);
+select plan(16);
 
 -- Declare some variables
 \set org1ID '00000000-0000-0000-0000-000000000001'
@@ -132,6 +132,7 @@ select results_eq(
             channels,
             default_channel,
             package_category_id,
+            package_category_predicted,
             repository_id
         from package
         where name='package1'
@@ -154,6 +155,7 @@ select results_eq(
             ]'::jsonb,
             'stable',
             1,
+            false,
             '00000000-0000-0000-0000-000000000001'::uuid
         )
     $$,
@@ -310,6 +312,7 @@ select register_package('
         }
     ],
     "category": 2,
+    "category_predicted": true,
     "repository": {
         "repository_id": "00000000-0000-0000-0000-000000000001"
     }
@@ -319,14 +322,16 @@ select results_eq(
     $$
         select
             is_operator,
-            package_category_id
+            package_category_id,
+            package_category_predicted
         from package
         where name = 'package1'
     $$,
     $$
         values (
             false,
-            2
+            2,
+            true
         )
     $$,
     'is_operator flag and category should have been updated'
@@ -557,6 +562,74 @@ select is_empty(
     'No new release event should exist for package1 version 0.0.9'
 );
 
+-- Register a package with some dependencies and check they have been registered
+select register_package('
+{
+    "name": "package3",
+    "version": "1.0.0",
+    "data": {
+        "dependencies": [
+            {
+                "name": "package1",
+                "version": "^1.0.0",
+                "repository": "https://repo1.com/"
+            },
+            {
+                "name": "package4",
+                "version": "1.x",
+                "repository": "https://repo1.com"
+            }
+        ]
+    },
+    "repository": {
+        "repository_id": "00000000-0000-0000-0000-000000000001"
+    }
+}
+');
+select results_eq(
+    $$
+        select sd.version, sd.name, sd.version_constraint, sd.repository_url, dp.name
+        from snapshot_dependency sd
+        join package p using (package_id)
+        left join package dp on dp.package_id = sd.dependency_package_id
+        where p.name = 'package3'
+        order by sd.name asc
+    $$,
+    $$
+        values
+            ('1.0.0', 'package1', '^1.0.0', 'https://repo1.com/', 'package1'),
+            ('1.0.0', 'package4', '1.x', 'https://repo1.com', null)
+    $$,
+    'package3 dependencies should exist, and only package1 should be resolved'
+);
+
+-- Register the package not available yet and check the dependency is resolved
+select register_package('
+{
+    "name": "package4",
+    "version": "1.0.0",
+    "repository": {
+        "repository_id": "00000000-0000-0000-0000-000000000001"
+    }
+}
+');
+select results_eq(
+    $$
+        select sd.name, dp.name
+        from snapshot_dependency sd
+        join package p using (package_id)
+        left join package dp on dp.package_id = sd.dependency_package_id
+        where p.name = 'package3'
+        order by sd.name asc
+    $$,
+    $$
+        values
+            ('package1', 'package1'),
+            ('package4', 'package4')
+    $$,
+    'package3 dependencies should be resolved once package4 has been registered'
+);
+
 -- Disable repository and check that trying to register a package raises an error
 update repository set disabled = true where repository_id = :'repo1ID';
 select throws_ok(
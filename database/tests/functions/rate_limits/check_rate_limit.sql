@@ -0,0 +1,41 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Run some tests
+select is(
+    check_rate_limit('key1', 3, 60, false)::jsonb - 'reset',
+    '{"allowed": true, "limit": 3, "remaining": 3}'::jsonb,
+    'Rate limit without hits should allow more hits'
+);
+select check_rate_limit('key1', 3, 60, true);
+select check_rate_limit('key1', 3, 60, true);
+select is(
+    check_rate_limit('key1', 3, 60, true)::jsonb - 'reset',
+    '{"allowed": true, "limit": 3, "remaining": 0}'::jsonb,
+    'Hit reaching the limit should be allowed'
+);
+select is(
+    check_rate_limit('key1', 3, 60, false)::jsonb - 'reset',
+    '{"allowed": false, "limit": 3, "remaining": 0}'::jsonb,
+    'Rate limit should not allow more hits once the limit has been reached'
+);
+select is(
+    check_rate_limit('key1', 3, 60, true)::jsonb - 'reset',
+    '{"allowed": false, "limit": 3, "remaining": 0}'::jsonb,
+    'Hit exceeding the limit should not be allowed'
+);
+select is(
+    (select sum(hits)::int from rate_limit_counter where key = 'key1'),
+    4,
+    'Four hits should have been registered for key1'
+);
+select is(
+    check_rate_limit('key2', 3, 60, false)::jsonb - 'reset',
+    '{"allowed": true, "limit": 3, "remaining": 3}'::jsonb,
+    'Hits registered for key1 should not affect key2'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
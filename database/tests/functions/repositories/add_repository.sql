@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(3);
+select plan(5);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -25,6 +25,7 @@ select add_repository(:'user1ID', null, '
     "disabled": false,
     "scanner_disabled": false,
     "data": {"k1": "v1"},
+    "tracking_interval": 3600,
     "kind": 0
 }
 '::jsonb);
@@ -40,6 +41,7 @@ select results_eq(
             disabled,
             scanner_disabled,
             data,
+            tracking_interval,
             repository_kind_id,
             user_id,
             organization_id
@@ -57,6 +59,7 @@ select results_eq(
             false,
             false,
             '{"k1": "v1"}'::jsonb,
+            3600,
             0,
             '00000000-0000-0000-0000-000000000001'::uuid,
             null::uuid
@@ -117,6 +120,77 @@ select results_eq(
     'Repository should exist and be owned by organization'
 );
 
+-- Add repository using an SSH deploy key and a GitHub App
+select add_repository(:'user1ID', null, '
+{
+    "name": "repo3",
+    "display_name": "Repository 3",
+    "url": "repo3_url",
+    "auth_ssh_key": "encrypted-ssh-key",
+    "auth_ssh_known_hosts": "github.com ssh-ed25519 key",
+    "auth_github_app_id": "1",
+    "auth_github_app_installation_id": "2",
+    "auth_github_app_key": "encrypted-github-app-key",
+    "disabled": false,
+    "scanner_disabled": false,
+    "kind": 6
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            name,
+            auth_ssh_key,
+            auth_ssh_known_hosts,
+            auth_github_app_id,
+            auth_github_app_installation_id,
+            auth_github_app_key
+        from repository
+        where name = 'repo3'
+    $$,
+    $$
+        values (
+            'repo3',
+            'encrypted-ssh-key',
+            'github.com ssh-ed25519 key',
+            '1',
+            '2',
+            'encrypted-github-app-key'
+        )
+    $$,
+    'Repository git credentials should have been stored'
+);
+
+-- Add repository using a git url, ref and subdirectory
+select add_repository(:'user1ID', null, '
+{
+    "name": "repo5",
+    "url": "https://gitea.example.com/group/subgroup/repo5",
+    "git_url": "https://gitea.example.com/group/subgroup/repo5.git",
+    "git_ref": "refs/tags/v1.0.0",
+    "git_subdir": "path/to/pkgs",
+    "disabled": false,
+    "scanner_disabled": false,
+    "kind": 6
+}
+'::jsonb);
+select results_eq(
+    $$
+        select name, git_url, git_ref, git_subdir
+        from repository
+        where name = 'repo5'
+    $$,
+    $$
+        values (
+            'repo5',
+            'https://gitea.example.com/group/subgroup/repo5.git',
+            'refs/tags/v1.0.0',
+            'path/to/pkgs'
+        )
+    $$,
+    'Repository git source should have been stored'
+);
+
 -- Add repository owned by organization, but user does not belong to it
 select throws_ok(
     $$
@@ -0,0 +1,65 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
+\set repo4ID '00000000-0000-0000-0000-000000000004'
+
+-- No repositories at this point
+select is(
+    get_repositories_due_for_tracking('{}', '{}')::jsonb,
+    '[]'::jsonb,
+    'With no repositories an empty json array is returned'
+);
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id, next_tracking_at)
+values (:'repo1ID', 'repo1', 'https://repo1.com', 0, :'user1ID', current_timestamp - '1 hour'::interval);
+insert into repository (repository_id, name, url, repository_kind_id, user_id, next_tracking_at)
+values (:'repo2ID', 'repo2', 'https://repo2.com', 0, :'user1ID', null);
+insert into repository (repository_id, name, url, repository_kind_id, user_id, next_tracking_at)
+values (:'repo3ID', 'repo3', 'https://repo3.com', 1, :'user1ID', current_timestamp + '1 hour'::interval);
+insert into repository (repository_id, name, url, repository_kind_id, user_id, disabled)
+values (:'repo4ID', 'repo4', 'https://repo4.com', 1, :'user1ID', true);
+
+-- Run some tests
+select is(
+    get_repositories_due_for_tracking('{}', '{}')::jsonb,
+    format('["%s", "%s"]', :'repo2ID', :'repo1ID')::jsonb,
+    'Enabled repositories due for tracking are returned, never scheduled first'
+);
+select is(
+    get_repositories_due_for_tracking('{repo1}', '{}')::jsonb,
+    format('["%s"]', :'repo1ID')::jsonb,
+    'Only repositories due for tracking matching the names provided are returned'
+);
+select is(
+    get_repositories_due_for_tracking('{}', '{1}')::jsonb,
+    '[]'::jsonb,
+    'No repositories of the kinds provided are due for tracking'
+);
+update repository set next_tracking_at = current_timestamp - '1 minute'::interval
+where repository_id = :'repo3ID';
+select is(
+    get_repositories_due_for_tracking('{}', '{1}')::jsonb,
+    format('["%s"]', :'repo3ID')::jsonb,
+    'Repositories of the kinds provided due for tracking are returned'
+);
+update repository set tracking_requested_at = current_timestamp
+where repository_id = :'repo3ID';
+update repository set next_tracking_at = current_timestamp + '1 hour'::interval
+where repository_id = :'repo3ID';
+select is(
+    get_repositories_due_for_tracking('{}', '{}')::jsonb,
+    format('["%s", "%s", "%s"]', :'repo3ID', :'repo2ID', :'repo1ID')::jsonb,
+    'Repositories whose tracking has been requested are returned first'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,11 +1,12 @@
 -- Start transaction and plan tests
 begin;
-select plan(5);
+select plan(7);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
 \set repo1ID '00000000-0000-0000-0000-000000000001'
 \set repo2ID '00000000-0000-0000-0000-000000000002'
+\set repo3ID '00000000-0000-0000-0000-000000000003'
 
 -- Non existing repository
 select is_empty(
@@ -64,7 +65,8 @@ insert into repository (
     last_scanning_ts,
     last_scanning_errors,
     last_tracking_ts,
-    last_tracking_errors
+    last_tracking_errors,
+    tracking_requested_at
 )
 values (
     :'repo2ID',
@@ -78,7 +80,8 @@ values (
     '2020-06-16 11:20:34+02',
     'error1\nerror2\n',
     '2020-06-16 11:20:34+02',
-    'error1\nerror2\n'
+    'error1\nerror2\n',
+    '2020-06-16 11:30:34+02'
 );
 
 -- Run some tests
@@ -152,6 +155,7 @@ select is(
         "last_scanning_errors": "error1\\nerror2\\n",
         "last_tracking_ts": 1592299234,
         "last_tracking_errors": "error1\\nerror2\\n",
+        "tracking_requested": true,
         "user_alias": "user1"
     }'::jsonb,
     'Repository 2 is returned as a json object (no credentials)'
@@ -174,11 +178,91 @@ select is(
         "last_scanning_errors": "error1\\nerror2\\n",
         "last_tracking_ts": 1592299234,
         "last_tracking_errors": "error1\\nerror2\\n",
+        "tracking_requested": true,
         "user_alias": "user1"
     }'::jsonb,
     'Repository 2 is returned as a json object (no credentials)'
 );
 
+-- Repository using git credentials
+insert into repository (
+    repository_id,
+    name,
+    display_name,
+    url,
+    git_url,
+    git_ref,
+    git_subdir,
+    auth_ssh_key,
+    auth_ssh_known_hosts,
+    auth_github_app_id,
+    auth_github_app_installation_id,
+    auth_github_app_key,
+    repository_kind_id,
+    user_id
+)
+values (
+    :'repo3ID',
+    'repo3',
+    'Repo 3',
+    'https://github.com/org3/repo3',
+    'https://github.com/org3/repo3.git',
+    'refs/tags/v1.0.0',
+    'pkgs',
+    'encrypted-ssh-key',
+    'github.com ssh-ed25519 key',
+    '1',
+    '2',
+    'encrypted-github-app-key',
+    6,
+    :'user1ID'
+);
+select is(
+    get_repository_by_id('00000000-0000-0000-0000-000000000003', false)::jsonb,
+    '{
+        "repository_id": "00000000-0000-0000-0000-000000000003",
+        "name": "repo3",
+        "display_name": "Repo 3",
+        "url": "https://github.com/org3/repo3",
+        "git_url": "https://github.com/org3/repo3.git",
+        "git_ref": "refs/tags/v1.0.0",
+        "git_subdir": "pkgs",
+        "private": true,
+        "kind": 6,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }'::jsonb,
+    'Repository 3 is returned as a json object (without git credentials)'
+);
+select is(
+    get_repository_by_id('00000000-0000-0000-0000-000000000003', true)::jsonb,
+    '{
+        "repository_id": "00000000-0000-0000-0000-000000000003",
+        "name": "repo3",
+        "display_name": "Repo 3",
+        "url": "https://github.com/org3/repo3",
+        "git_url": "https://github.com/org3/repo3.git",
+        "git_ref": "refs/tags/v1.0.0",
+        "git_subdir": "pkgs",
+        "private": true,
+        "auth_ssh_key": "encrypted-ssh-key",
+        "auth_ssh_known_hosts": "github.com ssh-ed25519 key",
+        "auth_github_app_id": "1",
+        "auth_github_app_installation_id": "2",
+        "auth_github_app_key": "encrypted-github-app-key",
+        "kind": 6,
+        "verified_publisher": false,
+        "official": false,
+        "disabled": false,
+        "scanner_disabled": false,
+        "user_alias": "user1"
+    }'::jsonb,
+    'Repository 3 is returned as a json object (with git credentials)'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
@@ -0,0 +1,174 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+\set run1ID '00000000-0000-0000-0000-000000000001'
+\set run2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+insert into repository_tracking_run (
+    repository_tracking_run_id,
+    repository_id,
+    started_at,
+    finished_at,
+    remote_digest,
+    packages_added,
+    packages_updated,
+    packages_removed,
+    packages_ignored,
+    errors
+) values (
+    :'run1ID',
+    :'repo1ID',
+    '2020-06-16 11:20:34+02',
+    '2020-06-16 11:21:34+02',
+    'digest1',
+    2,
+    1,
+    1,
+    3,
+    '[{"package_name": "pkg1", "package_version": "1.0.0", "message": "error1"}]'
+);
+insert into repository_tracking_run (
+    repository_tracking_run_id,
+    repository_id,
+    started_at,
+    finished_at,
+    remote_digest
+) values (
+    :'run2ID',
+    :'repo1ID',
+    '2020-06-16 11:30:34+02',
+    '2020-06-16 11:31:34+02',
+    'digest2'
+);
+
+-- Try to get the tracking runs of a repository owned by a user by other user
+select throws_ok(
+    $$
+        select * from get_repository_tracking_runs('00000000-0000-0000-0000-000000000002', 'repo1', 0, 0)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking runs should fail because requesting user is not the owner'
+);
+
+-- Try to get the tracking runs of a repository owned by organization by user not belonging to it
+select throws_ok(
+    $$
+        select * from get_repository_tracking_runs('00000000-0000-0000-0000-000000000002', 'repo2', 0, 0)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking runs should fail because requesting user does not belong to owning organization'
+);
+
+-- Get tracking runs of repository owned by user
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_repository_tracking_runs('00000000-0000-0000-0000-000000000001', 'repo1', 0, 0)
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "repository_tracking_run_id": "00000000-0000-0000-0000-000000000002",
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "started_at": 1592299834,
+                    "finished_at": 1592299894,
+                    "remote_digest": "digest2",
+                    "packages_added": 0,
+                    "packages_updated": 0,
+                    "packages_removed": 0,
+                    "packages_ignored": 0
+                },
+                {
+                    "repository_tracking_run_id": "00000000-0000-0000-0000-000000000001",
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "started_at": 1592299234,
+                    "finished_at": 1592299294,
+                    "remote_digest": "digest1",
+                    "packages_added": 2,
+                    "packages_updated": 1,
+                    "packages_removed": 1,
+                    "packages_ignored": 3,
+                    "errors": [
+                        {
+                            "package_name": "pkg1",
+                            "package_version": "1.0.0",
+                            "message": "error1"
+                        }
+                    ]
+                }
+            ]'::jsonb,
+            2
+        )
+    $$,
+    'Tracking runs should be returned, most recent first'
+);
+
+-- Get tracking runs of repository owned by user (with pagination)
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_repository_tracking_runs('00000000-0000-0000-0000-000000000001', 'repo1', 1, 1)
+    $$,
+    $$
+        values (
+            '[
+                {
+                    "repository_tracking_run_id": "00000000-0000-0000-0000-000000000001",
+                    "repository_id": "00000000-0000-0000-0000-000000000001",
+                    "started_at": 1592299234,
+                    "finished_at": 1592299294,
+                    "remote_digest": "digest1",
+                    "packages_added": 2,
+                    "packages_updated": 1,
+                    "packages_removed": 1,
+                    "packages_ignored": 3,
+                    "errors": [
+                        {
+                            "package_name": "pkg1",
+                            "package_version": "1.0.0",
+                            "message": "error1"
+                        }
+                    ]
+                }
+            ]'::jsonb,
+            2
+        )
+    $$,
+    'Second tracking run should be returned'
+);
+
+-- Get tracking runs of repository owned by organization (requesting user belongs to organization)
+select results_eq(
+    $$
+        select data::jsonb, total_count::integer
+        from get_repository_tracking_runs('00000000-0000-0000-0000-000000000001', 'repo2', 0, 0)
+    $$,
+    $$
+        values ('[]'::jsonb, 0)
+    $$,
+    'No tracking runs should be returned'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,101 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (
+    repository_id,
+    name,
+    display_name,
+    url,
+    repository_kind_id,
+    user_id,
+    last_tracking_ts,
+    last_tracking_errors
+) values (
+    :'repo1ID',
+    'repo1',
+    'Repo 1',
+    'https://repo1.com',
+    0,
+    :'user1ID',
+    '2020-06-16 11:20:34+02',
+    'error1'
+);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+
+-- Try to get the tracking status of a repository owned by a user by other user
+select throws_ok(
+    $$
+        select get_repository_tracking_status('00000000-0000-0000-0000-000000000002', 'repo1', 2)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking status should fail because requesting user is not the owner'
+);
+
+-- Try to get the tracking status of a repository owned by organization by user not belonging to it
+select throws_ok(
+    $$
+        select get_repository_tracking_status('00000000-0000-0000-0000-000000000002', 'repo2', 2)
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Getting tracking status should fail because requesting user does not belong to owning organization'
+);
+
+-- Get tracking status of repository already tracked
+select is(
+    get_repository_tracking_status(:'user1ID', 'repo1', 2)::jsonb,
+    '{
+        "status": "finished",
+        "last_tracking_ts": 1592299234,
+        "last_tracking_errors": "error1"
+    }'::jsonb,
+    'Repository status should be finished'
+);
+
+-- Get tracking status of repository with tracking requested
+update repository set tracking_requested_at = '2020-06-16 11:30:34+02' where name = 'repo1';
+select is(
+    get_repository_tracking_status(:'user1ID', 'repo1', 2)::jsonb,
+    '{
+        "status": "queued",
+        "tracking_requested_at": 1592299834,
+        "last_tracking_ts": 1592299234,
+        "last_tracking_errors": "error1"
+    }'::jsonb,
+    'Repository status should be queued'
+);
+
+-- Get tracking status of repository being tracked
+select pg_advisory_xact_lock(2, hashtext(:'repo1ID'));
+select is(
+    get_repository_tracking_status(:'user1ID', 'repo1', 2)->>'status',
+    'running',
+    'Repository status should be running'
+);
+
+-- Get tracking status of repository owned by organization never tracked
+select is(
+    get_repository_tracking_status(:'user1ID', 'repo2', 2)::jsonb,
+    '{"status": "finished"}'::jsonb,
+    'Repository status should be finished'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
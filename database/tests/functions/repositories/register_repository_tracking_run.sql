@@ -0,0 +1,106 @@
+-- Start transaction and plan tests
+begin;
+select plan(3);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+
+-- Register some tracking runs
+select register_repository_tracking_run('
+{
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "started_at": 1592299234,
+    "finished_at": 1592299294,
+    "remote_digest": "digest",
+    "packages_added": 2,
+    "packages_updated": 1,
+    "packages_removed": 1,
+    "packages_ignored": 3,
+    "errors": [
+        {
+            "package_name": "pkg1",
+            "package_version": "1.0.0",
+            "message": "error1"
+        }
+    ]
+}
+');
+select register_repository_tracking_run('
+{
+    "repository_id": "00000000-0000-0000-0000-000000000001",
+    "started_at": 1592299834,
+    "finished_at": 1592299894,
+    "errors": []
+}
+');
+
+-- Check runs were registered
+select results_eq(
+    $$
+        select
+            started_at,
+            finished_at,
+            remote_digest,
+            packages_added,
+            packages_updated,
+            packages_removed,
+            packages_ignored,
+            errors
+        from repository_tracking_run
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+        order by started_at asc
+    $$,
+    $$
+        values
+            (
+                '2020-06-16 11:20:34+02'::timestamptz,
+                '2020-06-16 11:21:34+02'::timestamptz,
+                'digest',
+                2,
+                1,
+                1,
+                3,
+                '[{"package_name": "pkg1", "package_version": "1.0.0", "message": "error1"}]'::jsonb
+            ),
+            (
+                '2020-06-16 11:30:34+02'::timestamptz,
+                '2020-06-16 11:31:34+02'::timestamptz,
+                null,
+                0,
+                0,
+                0,
+                0,
+                null
+            )
+    $$,
+    'Tracking runs should have been registered'
+);
+
+-- Register more runs than the maximum allowed per repository
+select register_repository_tracking_run(jsonb_build_object(
+    'repository_id', :'repo1ID',
+    'started_at', 1592300000 + i,
+    'finished_at', 1592300000 + i
+))
+from generate_series(1, 100) as i;
+select is(
+    (select count(*) from repository_tracking_run where repository_id = :'repo1ID'),
+    100::bigint,
+    'Only the most recent 100 runs should be kept'
+);
+select is(
+    (select min(started_at) from repository_tracking_run where repository_id = :'repo1ID'),
+    to_timestamp(1592300001),
+    'Oldest runs should have been deleted'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -41,6 +41,8 @@ insert into repository (
     cncf,
     auth_user,
     auth_pass,
+    auth_ssh_key,
+    auth_ssh_known_hosts,
     last_tracking_ts,
     last_tracking_errors,
     packages_deletion_protection,
@@ -54,6 +56,8 @@ insert into repository (
     true,
     'user',
     'pass',
+    'encrypted-ssh-key',
+    'github.com ssh-ed25519 key',
     '1970-01-01 00:00:00 UTC',
     'error1\nerror2\nerror3',
     true,
@@ -353,6 +357,8 @@ select results_eq(
                     "private": true,
                     "auth_user": "user",
                     "auth_pass": "pass",
+                    "auth_ssh_key": "encrypted-ssh-key",
+                    "auth_ssh_known_hosts": "github.com ssh-ed25519 key",
                     "kind": 0,
                     "verified_publisher": false,
                     "official": false,
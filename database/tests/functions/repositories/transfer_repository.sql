@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(16);
+select plan(18);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -192,6 +192,23 @@ select results_eq(
 );
 select is(count(*), 0::bigint, 'No repository ownership claim events should have been registered')
 from event where repository_id=:'repo2ID' and event_kind_id = 3;
+select results_eq(
+    $$
+        select action, user_id, organization_id, target_organization_id, details
+        from audit_log
+        where target_organization_id = '00000000-0000-0000-0000-000000000003'
+    $$,
+    $$
+        values (
+            'repositoryTransferred',
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000001'::uuid,
+            '00000000-0000-0000-0000-000000000003'::uuid,
+            '{"repository_name": "repo2", "ownership_claim": "false"}'::jsonb
+        )
+    $$,
+    'Repository transfer audit event should have been registered'
+);
 
 -- Transfer user owned repository to org
 select transfer_repository(
@@ -269,6 +286,31 @@ select results_eq(
 );
 select is(count(*), 2::bigint, 'Another repository ownership claim event should have been registered')
 from event where repository_id=:'repo1ID' and event_kind_id = 3;
+select results_eq(
+    $$
+        select user_id, organization_id, target_organization_id, details
+        from audit_log
+        where action = 'repositoryTransferred'
+        and details->>'ownership_claim' = 'true'
+        order by created_at, user_id
+    $$,
+    $$
+        values
+            (
+                '00000000-0000-0000-0000-000000000001'::uuid,
+                null::uuid,
+                null::uuid,
+                '{"repository_name": "repo1", "ownership_claim": "true"}'::jsonb
+            ),
+            (
+                '00000000-0000-0000-0000-000000000002'::uuid,
+                '00000000-0000-0000-0000-000000000001'::uuid,
+                null::uuid,
+                '{"repository_name": "repo1", "ownership_claim": "true"}'::jsonb
+            )
+    $$,
+    'Ownership claim transfers audit events should have been registered'
+);
 
 -- Finish tests and rollback transaction
 select * from finish();
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(10);
+select plan(13);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -110,7 +110,8 @@ select update_repository(:'user1ID', '
     "auth_pass": "pass1",
     "disabled": true,
     "scanner_disabled": false,
-    "data": {"k1": "v1"}
+    "data": {"k1": "v1"},
+    "tracking_interval": 3600
 }
 '::jsonb);
 select results_eq(
@@ -124,7 +125,8 @@ select results_eq(
             auth_pass,
             disabled,
             digest,
-            data
+            data,
+            tracking_interval
         from repository
         where name = 'repo1'
     $$,
@@ -138,7 +140,8 @@ select results_eq(
             'pass1',
             true,
             null,
-            '{"k1": "v1"}'::jsonb
+            '{"k1": "v1"}'::jsonb,
+            3600
         )
     $$,
     'Repository should have been updated by user who owns it'
@@ -223,6 +226,114 @@ select results_eq(
     'Repository credentials should have been removed'
 );
 
+-- Update same repository again setting git credentials
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated",
+    "branch": "main",
+    "auth_ssh_key": "encrypted-ssh-key",
+    "auth_ssh_known_hosts": "github.com ssh-ed25519 key",
+    "auth_github_app_id": "1",
+    "auth_github_app_installation_id": "2",
+    "auth_github_app_key": "encrypted-github-app-key",
+    "disabled": true,
+    "scanner_disabled": false
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            auth_ssh_key,
+            auth_ssh_known_hosts,
+            auth_github_app_id,
+            auth_github_app_installation_id,
+            auth_github_app_key
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values (
+            'encrypted-ssh-key',
+            'github.com ssh-ed25519 key',
+            '1',
+            '2',
+            'encrypted-github-app-key'
+        )
+    $$,
+    'Repository git credentials should have been updated'
+);
+
+-- Update same repository again providing shadowed git credentials
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated",
+    "branch": "main",
+    "auth_ssh_key": "=",
+    "auth_ssh_known_hosts": "=",
+    "auth_github_app_id": "=",
+    "auth_github_app_installation_id": "=",
+    "auth_github_app_key": "=",
+    "disabled": true,
+    "scanner_disabled": false
+}
+'::jsonb);
+select results_eq(
+    $$
+        select
+            auth_ssh_key,
+            auth_ssh_known_hosts,
+            auth_github_app_id,
+            auth_github_app_installation_id,
+            auth_github_app_key
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values (
+            'encrypted-ssh-key',
+            'github.com ssh-ed25519 key',
+            '1',
+            '2',
+            'encrypted-github-app-key'
+        )
+    $$,
+    'Repository git credentials should not have been updated'
+);
+
+-- Update same repository again setting its git source
+select update_repository(:'user1ID', '
+{
+    "name": "repo1",
+    "display_name": "Repo 1 updated",
+    "url": "https://repo1.com/updated",
+    "git_url": "ssh://git@repo1.com/group/subgroup/repo1.git",
+    "git_ref": "refs/tags/v1.0.0",
+    "git_subdir": "path/to/pkgs",
+    "disabled": true,
+    "scanner_disabled": false
+}
+'::jsonb);
+select results_eq(
+    $$
+        select branch, git_url, git_ref, git_subdir
+        from repository
+        where name = 'repo1'
+    $$,
+    $$
+        values (
+            null,
+            'ssh://git@repo1.com/group/subgroup/repo1.git',
+            'refs/tags/v1.0.0',
+            'path/to/pkgs'
+        )
+    $$,
+    'Repository git source should have been updated'
+);
+
 -- Update repository owned by organization (requesting user belongs to
 -- organization) disabling security scanning
 select update_repository(:'user1ID', '
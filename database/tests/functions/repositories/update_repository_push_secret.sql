@@ -0,0 +1,60 @@
+-- Start transaction and plan tests
+begin;
+select plan(5);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into user__organization (user_id, organization_id, confirmed) values(:'user1ID', :'org1ID', true);
+insert into repository (repository_id, name, display_name, url, repository_kind_id, user_id)
+values (:'repo1ID', 'repo1', 'Repo 1', 'https://repo1.com', 0, :'user1ID');
+insert into repository (repository_id, name, display_name, url, repository_kind_id, organization_id)
+values (:'repo2ID', 'repo2', 'Repo 2', 'https://repo2.com', 0, :'org1ID');
+
+-- Try to update the push secret of a repository owned by a user by other user
+select throws_ok(
+    $$
+        select update_repository_push_secret('00000000-0000-0000-0000-000000000002', 'repo1', 'secret')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Push secret update should fail because requesting user is not the owner'
+);
+
+-- Try to update the push secret of a repository owned by organization by user not belonging to it
+select throws_ok(
+    $$
+        select update_repository_push_secret('00000000-0000-0000-0000-000000000002', 'repo2', 'secret')
+    $$,
+    42501,
+    'insufficient_privilege',
+    'Push secret update should fail because requesting user does not belong to owning organization'
+);
+
+-- Update push secret of repository owned by user
+select update_repository_push_secret(:'user1ID', 'repo1', 'secret1');
+select is(push_secret, 'secret1', 'Push secret should have been updated by user who owns the repository')
+from repository where name = 'repo1';
+
+-- Update push secret of repository owned by organization (requesting user belongs to organization)
+select update_repository_push_secret(:'user1ID', 'repo2', 'secret2');
+select is(push_secret, 'secret2', 'Push secret should have been updated by user who belongs to owning organization')
+from repository where name = 'repo2';
+
+-- Disable push events
+select update_repository_push_secret(:'user1ID', 'repo1', '');
+select is(push_secret, null, 'Push secret should have been removed')
+from repository where name = 'repo1';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
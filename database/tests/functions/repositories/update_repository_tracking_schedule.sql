@@ -0,0 +1,99 @@
+-- Start transaction and plan tests
+begin;
+select plan(10);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set repo1ID '00000000-0000-0000-0000-000000000001'
+\set repo2ID '00000000-0000-0000-0000-000000000002'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into repository (repository_id, name, url, repository_kind_id, user_id, digest)
+values (:'repo1ID', 'repo1', 'https://repo1.com', 0, :'user1ID', 'digest1');
+insert into repository (repository_id, name, url, repository_kind_id, user_id, tracking_interval)
+values (:'repo2ID', 'repo2', 'https://repo2.com', 0, :'user1ID', 600);
+
+-- Digest changed: backoff factor is reset and default interval is used
+select update_repository_tracking_schedule(:'repo1ID', 'digest0', true, 1800, 8, current_timestamp);
+select results_eq(
+    $$
+        select
+            tracking_backoff_factor,
+            next_tracking_at - current_timestamp
+        from repository
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (1, '30 minutes'::interval)
+    $$,
+    'Backoff factor should be 1 and next tracking scheduled in 30 minutes'
+);
+
+-- Digest unchanged: backoff factor is doubled on each run up to the maximum
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp);
+select is(tracking_backoff_factor, 2, 'Backoff factor should be 2')
+from repository where repository_id = :'repo1ID';
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp);
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp);
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp);
+select results_eq(
+    $$
+        select
+            tracking_backoff_factor,
+            next_tracking_at - current_timestamp
+        from repository
+        where repository_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values (8, '4 hours'::interval)
+    $$,
+    'Backoff factor should be capped at 8 and next tracking scheduled in 4 hours'
+);
+
+-- Tracking failed: backoff factor is reset
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', false, 1800, 8, current_timestamp);
+select is(tracking_backoff_factor, 1, 'Backoff factor should have been reset after a failure')
+from repository where repository_id = :'repo1ID';
+
+-- Repository with no digest: backoff is never applied
+select update_repository_tracking_schedule(:'repo2ID', '', true, 1800, 8, current_timestamp);
+select is(tracking_backoff_factor, 1, 'Backoff factor should be 1 for repositories without digest')
+from repository where repository_id = :'repo2ID';
+select update_repository_tracking_schedule(:'repo2ID', '', true, 1800, 8, current_timestamp);
+select is(tracking_backoff_factor, 1, 'Backoff factor should still be 1 for repositories without digest')
+from repository where repository_id = :'repo2ID';
+
+-- Repository's tracking interval takes precedence over the default one
+select is(
+    next_tracking_at - current_timestamp,
+    '10 minutes'::interval,
+    'Next tracking should be scheduled using the repository tracking interval'
+)
+from repository where repository_id = :'repo2ID';
+update repository set digest = 'digest2' where repository_id = :'repo2ID';
+select update_repository_tracking_schedule(:'repo2ID', 'digest2', true, 1800, 8, current_timestamp);
+select is(
+    next_tracking_at - current_timestamp,
+    '20 minutes'::interval,
+    'Next tracking should be scheduled using the repository tracking interval with backoff'
+)
+from repository where repository_id = :'repo2ID';
+
+-- Tracking requested before the tracking started is cleared
+update repository set tracking_requested_at = current_timestamp - '1 minute'::interval
+where repository_id = :'repo1ID';
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp);
+select is(tracking_requested_at, null, 'Tracking request should have been cleared')
+from repository where repository_id = :'repo1ID';
+
+-- Tracking requested while the repository was being tracked is kept
+update repository set tracking_requested_at = current_timestamp - '1 minute'::interval
+where repository_id = :'repo1ID';
+select update_repository_tracking_schedule(:'repo1ID', 'digest1', true, 1800, 8, current_timestamp - '2 minutes'::interval);
+select isnt(tracking_requested_at, null, 'Tracking request should have been kept')
+from repository where repository_id = :'repo1ID';
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -0,0 +1,76 @@
+-- Start transaction and plan tests
+begin;
+select plan(7);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set session1ID '00001'
+\set session2ID '00002'
+\set session3ID '00003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email)
+values (:'user1ID', 'user1', 'user1@email.com');
+insert into session (session_id, user_id, ip, approved) values (:'session1ID', :'user1ID', '192.168.1.1', true);
+insert into session (session_id, user_id, ip, approved) values (:'session2ID', :'user1ID', '192.168.1.1', false);
+insert into session (session_id, user_id, ip, approved) values (:'session3ID', :'user1ID', '192.168.1.2', false);
+
+-- Run some tests
+select is(
+    register_failed_login_attempt(:'user1ID', '192.168.1.1', 2, 60),
+    false,
+    'Account should not be locked after the first failed attempt'
+);
+select results_eq(
+    $$
+        select client, failed_attempts, locked_until
+        from login_lockout where user_id = '00000000-0000-0000-0000-000000000001'
+    $$,
+    $$
+        values ('192.168.1.1', 1, null::timestamptz)
+    $$,
+    'Failed login attempts counter should have been incremented for the client'
+);
+select is(
+    register_failed_login_attempt(:'user1ID', '192.168.1.2', 2, 60),
+    false,
+    'Account should not be locked after the first failed attempt from another client'
+);
+select is(
+    register_failed_login_attempt(:'user1ID', '192.168.1.1', 2, 60),
+    true,
+    'Account should be locked after reaching the maximum failed attempts'
+);
+select results_eq(
+    $$
+        select client, failed_attempts, locked_until
+        from login_lockout where user_id = '00000000-0000-0000-0000-000000000001'
+        order by client asc
+    $$,
+    $$
+        values
+            ('192.168.1.1', 0, current_timestamp + interval '60 seconds'),
+            ('192.168.1.2', 1, null::timestamptz)
+    $$,
+    'Account should be locked for 60 seconds only for the client and its counter reset'
+);
+select results_eq(
+    $$
+        select session_id from session
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        order by session_id asc
+    $$,
+    $$
+        values ('00001'::bytea), ('00003'::bytea)
+    $$,
+    'Only the sessions pending approval created from the client should have been deleted'
+);
+select is(
+    register_failed_login_attempt('00000000-0000-0000-0000-000000000002', '192.168.1.1', 2, 60),
+    false,
+    'Unknown user should not be locked'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(5);
+select plan(6);
 
 -- Register user
 select register_user('
@@ -120,6 +120,40 @@ select is_empty(
     'No email verification code should be registered for user alias3'
 );
 
+-- Register new user (ldap registration)
+select register_user('
+{
+    "alias": "alias4",
+    "email": "email4",
+    "email_verified": true,
+    "ldap_dn": "uid=alias4,dc=example,dc=com"
+}
+');
+
+-- Check if user registration succeeded
+select results_eq(
+    $$
+        select
+            alias,
+            email,
+            email_verified,
+            password,
+            ldap_dn
+        from "user"
+        where alias = 'alias4'
+    $$,
+    $$
+        values (
+            'alias4',
+            'email4',
+            true,
+            null,
+            'uid=alias4,dc=example,dc=com'
+        )
+    $$,
+    'User4 should exist and be linked to its ldap entry'
+);
+
 -- Finish tests and rollback transaction
 select * from finish();
 rollback;
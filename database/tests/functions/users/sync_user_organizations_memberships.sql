@@ -0,0 +1,140 @@
+-- Start transaction and plan tests
+begin;
+select plan(6);
+
+-- Declare some variables
+\set user1ID '00000000-0000-0000-0000-000000000001'
+\set user2ID '00000000-0000-0000-0000-000000000002'
+\set org1ID '00000000-0000-0000-0000-000000000001'
+\set org2ID '00000000-0000-0000-0000-000000000002'
+\set org3ID '00000000-0000-0000-0000-000000000003'
+
+-- Seed some data
+insert into "user" (user_id, alias, email) values (:'user1ID', 'user1', 'user1@email.com');
+insert into "user" (user_id, alias, email) values (:'user2ID', 'user2', 'user2@email.com');
+insert into organization (organization_id, name, display_name, description, home_url)
+values (:'org1ID', 'org1', 'Organization 1', 'Description 1', 'https://org1.com');
+insert into organization (
+    organization_id,
+    name,
+    authorization_enabled,
+    predefined_policy,
+    policy_data
+) values (
+    :'org2ID',
+    'org2',
+    true,
+    'rbac.v1',
+    '{"roles": {"owner": {"users": ["user2"]}, "admin": {"users": ["user1"], "allowed_actions": ["all"]}}}'
+);
+insert into organization (organization_id, name) values (:'org3ID', 'org3');
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org2ID', true);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user1ID', :'org3ID', false);
+insert into user__organization (user_id, organization_id, confirmed) values (:'user2ID', :'org3ID', true);
+
+-- Sync user1 memberships
+select sync_user_organizations_memberships(:'user1ID', '
+[
+    {
+        "organization_name": "org1",
+        "member": true,
+        "roles": {}
+    },
+    {
+        "organization_name": "org2",
+        "member": true,
+        "roles": {
+            "owner": true,
+            "admin": false,
+            "viewer": false
+        }
+    },
+    {
+        "organization_name": "org3",
+        "member": false,
+        "roles": {}
+    },
+    {
+        "organization_name": "org4",
+        "member": true,
+        "roles": {}
+    }
+]
+');
+
+-- Run some tests
+select results_eq(
+    $$
+        select organization_id, confirmed
+        from user__organization
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        order by organization_id
+    $$,
+    $$
+        values
+            ('00000000-0000-0000-0000-000000000001'::uuid, true),
+            ('00000000-0000-0000-0000-000000000002'::uuid, true)
+    $$,
+    'User1 should be a confirmed member of org1 and org2 and not of org3'
+);
+select is(
+    (select policy_data from organization where name = 'org2'),
+    '{"roles": {"owner": {"users": ["user2", "user1"]}, "admin": {"users": [], "allowed_actions": ["all"]}}}'::jsonb,
+    'User1 should have been granted owner role and revoked admin role in org2'
+);
+select is(
+    (select policy_data from organization where name = 'org1'),
+    null,
+    'Policy data of organizations not using rbac.v1 should not be modified'
+);
+
+-- Sync user1 memberships again (no longer belongs to org2)
+select sync_user_organizations_memberships(:'user1ID', '
+[
+    {
+        "organization_name": "org2",
+        "member": false,
+        "roles": {
+            "owner": false
+        }
+    }
+]
+');
+select is_empty(
+    $$
+        select *
+        from user__organization
+        where user_id = '00000000-0000-0000-0000-000000000001'
+        and organization_id = '00000000-0000-0000-0000-000000000002'
+    $$,
+    'User1 should not be a member of org2 anymore'
+);
+select is(
+    (select policy_data->'roles'->'owner' from organization where name = 'org2'),
+    '{"users": ["user2"]}'::jsonb,
+    'User1 should not have the owner role in org2 anymore'
+);
+
+-- Last member of an organization is not removed from it
+select sync_user_organizations_memberships(:'user2ID', '
+[
+    {
+        "organization_name": "org2",
+        "member": false,
+        "roles": {}
+    }
+]
+');
+select isnt_empty(
+    $$
+        select *
+        from user__organization
+        where user_id = '00000000-0000-0000-0000-000000000002'
+        and organization_id = '00000000-0000-0000-0000-000000000002'
+    $$,
+    'User2 should still be a member of org2 as it is its last member'
+);
+
+-- Finish tests and rollback transaction
+select * from finish();
+rollback;
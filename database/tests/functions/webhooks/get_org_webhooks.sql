@@ -102,7 +102,7 @@ select results_eq(
                     "name": "webhook1",
                     "description": "description",
                     "url": "http://webhook1.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
@@ -136,7 +136,7 @@ select results_eq(
                     "name": "webhook2",
                     "description": "description",
                     "url": "http://webhook2.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
@@ -183,7 +183,7 @@ select results_eq(
                     "name": "webhook2",
                     "description": "description",
                     "url": "http://webhook2.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
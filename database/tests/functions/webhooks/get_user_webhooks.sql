@@ -98,7 +98,7 @@ select results_eq(
                     "name": "webhook1",
                     "description": "description",
                     "url": "http://webhook1.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
@@ -132,7 +132,7 @@ select results_eq(
                     "name": "webhook2",
                     "description": "description",
                     "url": "http://webhook2.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
@@ -179,7 +179,7 @@ select results_eq(
                     "name": "webhook2",
                     "description": "description",
                     "url": "http://webhook2.url",
-                    "secret": "very",
+                    "secret": "=",
                     "content_type": "application/json",
                     "template": "custom payload",
                     "active": true,
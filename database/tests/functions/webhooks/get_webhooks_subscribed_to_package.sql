@@ -86,7 +86,7 @@ select is(
             "name": "webhook1",
             "description": "description",
             "url": "http://webhook1.url",
-            "secret": "very",
+            "secret": "=",
             "content_type": "application/json",
             "template": "custom payload",
             "active": true,
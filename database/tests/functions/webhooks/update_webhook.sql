@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(6);
+select plan(7);
 
 -- Declare some variables
 \set user1ID '00000000-0000-0000-0000-000000000001'
@@ -137,6 +137,28 @@ select results_eq(
     'Webhook1 should now be linked to package2'
 );
 
+-- Update webhook keeping the current secret
+select update_webhook('00000000-0000-0000-0000-000000000001', '
+{
+    "webhook_id": "00000000-0000-0000-0000-000000000001",
+    "name": "webhook1 updated",
+    "url": "http://webhook1.url/updated",
+    "secret": "=",
+    "active": false,
+    "event_kinds": [1],
+    "packages": [
+        {
+            "package_id": "00000000-0000-0000-0000-000000000002"
+        }
+    ]
+}
+'::jsonb);
+select is(
+    (select secret from webhook where webhook_id = '00000000-0000-0000-0000-000000000001'),
+    'very updated',
+    'Webhook1 secret should have been kept'
+);
+
 -- Update webhook owned by organization (requesting user belongs to organization)
 select update_webhook('00000000-0000-0000-0000-000000000001', '
 {
@@ -1,6 +1,6 @@
 -- Start transaction and plan tests
 begin;
-select plan(190);
+select plan(223);
 
 -- Check default_text_search_config is correct
 select results_eq(
@@ -17,12 +17,14 @@ select has_extension('pg_partman');
 
 -- Check expected tables exist
 select has_table('api_key');
+select has_table('audit_log');
 select has_table('delete_user_code');
 select has_table('email_verification_code');
 select has_table('event');
 select has_table('event_kind');
 select has_table('image');
 select has_table('image_version');
+select has_table('login_lockout');
 select has_table('maintainer');
 select has_table('notification');
 select has_table('opt_out');
@@ -33,10 +35,13 @@ select has_table('package_views');
 select has_table('package__maintainer');
 select has_table('password_reset_code');
 select has_table('production_usage');
+select has_table('rate_limit_counter');
 select has_table('repository');
 select has_table('repository_kind');
+select has_table('repository_tracking_run');
 select has_table('session');
 select has_table('snapshot');
+select has_table('snapshot_dependency');
 select has_table('subscription');
 select has_table('user');
 select has_table('user_starred_package');
@@ -55,6 +60,15 @@ select columns_are('api_key', array[
     'user_id',
     'created_at'
 ]);
+select columns_are('audit_log', array[
+    'audit_log_id',
+    'action',
+    'user_id',
+    'organization_id',
+    'target_organization_id',
+    'details',
+    'created_at'
+]);
 select columns_are('delete_user_code', array[
     'delete_user_code_id',
     'user_id',
@@ -89,6 +103,12 @@ select columns_are('image_version', array[
     'version',
     'data'
 ]);
+select columns_are('login_lockout', array[
+    'user_id',
+    'client',
+    'failed_attempts',
+    'locked_until'
+]);
 select columns_are('maintainer', array[
     'maintainer_id',
     'name',
@@ -127,6 +147,12 @@ select columns_are('production_usage', array[
     'package_id',
     'organization_id'
 ]);
+select columns_are('rate_limit_counter', array[
+    'key',
+    'window_start',
+    'hits',
+    'expires_at'
+]);
 select columns_are('package', array[
     'package_id',
     'name',
@@ -142,6 +168,7 @@ select columns_are('package', array[
     'default_channel',
     'created_at',
     'package_category_id',
+    'package_category_predicted',
     'repository_id'
 ]);
 select columns_are('package_category', array[
@@ -185,6 +212,19 @@ select columns_are('repository', array[
     'created_at',
     'data',
     'packages_deletion_protection',
+    'tracking_interval',
+    'tracking_backoff_factor',
+    'next_tracking_at',
+    'push_secret',
+    'tracking_requested_at',
+    'auth_ssh_key',
+    'auth_ssh_known_hosts',
+    'auth_github_app_id',
+    'auth_github_app_installation_id',
+    'auth_github_app_key',
+    'git_url',
+    'git_ref',
+    'git_subdir',
     'repository_kind_id',
     'user_id',
     'organization_id'
@@ -193,6 +233,18 @@ select columns_are('repository_kind', array[
     'repository_kind_id',
     'name'
 ]);
+select columns_are('repository_tracking_run', array[
+    'repository_tracking_run_id',
+    'repository_id',
+    'started_at',
+    'finished_at',
+    'remote_digest',
+    'packages_added',
+    'packages_updated',
+    'packages_removed',
+    'packages_ignored',
+    'errors'
+]);
 select columns_are('session', array[
     'session_id',
     'user_id',
@@ -241,6 +293,14 @@ select columns_are('snapshot', array[
     'signatures',
     'relative_path'
 ]);
+select columns_are('snapshot_dependency', array[
+    'package_id',
+    'version',
+    'name',
+    'version_constraint',
+    'repository_url',
+    'dependency_package_id'
+]);
 select columns_are('subscription', array[
     'user_id',
     'package_id',
@@ -259,7 +319,8 @@ select columns_are('user', array[
     'tfa_enabled',
     'tfa_recovery_codes',
     'tfa_url',
-    'repositories_notifications_disabled'
+    'repositories_notifications_disabled',
+    'ldap_dn'
 ]);
 select columns_are('user_starred_package', array[
     'user_id',
@@ -303,6 +364,13 @@ select columns_are('webhook__package', array[
 select indexes_are('api_key', array[
     'api_key_pkey'
 ]);
+select indexes_are('audit_log', array[
+    'audit_log_pkey',
+    'audit_log_user_id_idx',
+    'audit_log_organization_id_idx',
+    'audit_log_target_organization_id_idx',
+    'audit_log_created_at_idx'
+]);
 select indexes_are('delete_user_code', array[
     'delete_user_code_pkey',
     'delete_user_code_user_id_key'
@@ -324,6 +392,9 @@ select indexes_are('image', array[
 select indexes_are('image_version', array[
     'image_version_pkey'
 ]);
+select indexes_are('login_lockout', array[
+    'login_lockout_pkey'
+]);
 select indexes_are('maintainer', array[
     'maintainer_pkey',
     'maintainer_email_key'
@@ -346,6 +417,10 @@ select indexes_are('organization', array[
 select indexes_are('production_usage', array[
     'production_usage_pkey'
 ]);
+select indexes_are('rate_limit_counter', array[
+    'rate_limit_counter_pkey',
+    'rate_limit_counter_expires_at_idx'
+]);
 select indexes_are('package', array[
     'package_pkey',
     'package_tsdoc_idx',
@@ -373,11 +448,17 @@ select indexes_are('repository', array[
     'repository_url_idx',
     'repository_repository_kind_id_idx',
     'repository_user_id_idx',
-    'repository_organization_id_idx'
+    'repository_organization_id_idx',
+    'repository_next_tracking_at_idx',
+    'repository_tracking_requested_at_idx'
 ]);
 select indexes_are('repository_kind', array[
     'repository_kind_pkey'
 ]);
+select indexes_are('repository_tracking_run', array[
+    'repository_tracking_run_pkey',
+    'repository_tracking_run_repository_id_started_at_idx'
+]);
 select indexes_are('session', array[
     'session_pkey'
 ]);
@@ -385,6 +466,11 @@ select indexes_are('snapshot', array[
     'snapshot_pkey',
     'snapshot_not_deprecated_with_readme_idx'
 ]);
+select indexes_are('snapshot_dependency', array[
+    'snapshot_dependency_package_id_version_idx',
+    'snapshot_dependency_dependency_package_id_idx',
+    'snapshot_dependency_unresolved_name_idx'
+]);
 select indexes_are('subscription', array[
     'subscription_pkey',
     'subscription_package_id_idx'
@@ -393,6 +479,7 @@ select indexes_are('user', array[
     'user_pkey',
     'user_alias_key',
     'user_email_key',
+    'user_ldap_dn_key',
     'user_repositories_notifications_disabled_idx'
 ]);
 select indexes_are('user__organization', array[
@@ -421,6 +508,11 @@ select has_function('delete_api_key');
 select has_function('get_api_key');
 select has_function('get_user_api_keys');
 select has_function('update_api_key');
+-- Audit log
+select has_function('get_organization_audit_log');
+select has_function('get_user_audit_log');
+select has_function('prevent_audit_log_changes');
+select has_function('register_audit_event');
 -- Authz
 select has_function('notify_authorization_policies_updates');
 -- Events
@@ -457,6 +549,8 @@ select has_function('get_helm_exporter_dump');
 select has_function('get_nova_dump');
 select has_function('get_package');
 select has_function('get_package_changelog');
+select has_function('get_package_dependencies');
+select has_function('get_package_dependents');
 select has_function('get_package_summary');
 select has_function('get_packages_starred_by_user');
 select has_function('get_package_stars');
@@ -465,6 +559,7 @@ select has_function('get_packages_stats');
 select has_function('get_production_usage');
 select has_function('get_random_packages');
 select has_function('get_snapshots_to_scan');
+select has_function('is_helm_dependency_repository');
 select has_function('is_latest');
 select has_function('register_package');
 select has_function('search_packages');
@@ -474,19 +569,29 @@ select has_function('semver_gte');
 select has_function('toggle_star');
 select has_function('update_snapshot_security_report');
 select has_function('unregister_package');
+-- Rate limits
+select has_function('check_rate_limit');
 -- Repositories
 select has_function('add_repository');
 select has_function('delete_repository');
 select has_function('get_repository_by_id');
+select has_function('get_repositories_due_for_tracking');
 select has_function('get_repository_by_name');
 select has_function('get_repository_packages_digest');
+select has_function('get_repository_packages_paths');
 select has_function('get_repository_summary');
+select has_function('get_repository_tracking_runs');
+select has_function('get_repository_tracking_status');
+select has_function('register_repository_tracking_run');
+select has_function('request_repository_tracking');
 select has_function('search_repositories');
 select has_function('set_last_scanning_results');
 select has_function('set_last_tracking_results');
 select has_function('set_verified_publisher');
 select has_function('transfer_repository');
 select has_function('update_repository');
+select has_function('update_repository_push_secret');
+select has_function('update_repository_tracking_schedule');
 -- Stats
 select has_function('get_stats');
 -- Subscriptions
@@ -505,10 +610,12 @@ select has_function('delete_user');
 select has_function('get_user_profile');
 select has_function('get_user_tfa_config');
 select has_function('register_delete_user_code');
+select has_function('register_failed_login_attempt');
 select has_function('register_password_reset_code');
 select has_function('register_session');
 select has_function('register_user');
 select has_function('reset_user_password');
+select has_function('sync_user_organizations_memberships');
 select has_function('update_user_password');
 select has_function('update_user_profile');
 select has_function('verify_email');
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
@@ -54,11 +55,24 @@ func (m *Manager) Add(ctx context.Context, ak *hub.APIKey) (*hub.APIKey, error)
 	apiKeySecret := base64.StdEncoding.EncodeToString(randomBytes)
 	apiKeySecretHashed := hash(apiKeySecret)
 
-	// Add api key to the database
+	// Add api key to the database and register audit event
 	var apiKeyID string
 	ak.Secret = apiKeySecretHashed
 	akJSON, _ := json.Marshal(ak)
-	if err := m.db.QueryRow(ctx, addAPIKeyDBQ, akJSON).Scan(&apiKeyID); err != nil {
+	err := util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, addAPIKeyDBQ, akJSON).Scan(&apiKeyID); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionAPIKeyAdded,
+			UserID: ak.UserID,
+			Details: map[string]string{
+				"api_key_id": apiKeyID,
+				"name":       ak.Name,
+			},
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
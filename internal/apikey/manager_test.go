@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/jackc/pgx/v4"
@@ -64,13 +65,38 @@ func TestAdd(t *testing.T) {
 			UserID: "userID",
 		}
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, addAPIKeyDBQ, mock.Anything).Return(nil, tests.ErrFakeDB)
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, addAPIKeyDBQ, mock.Anything).Return(nil, tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
 		m := NewManager(db)
 
 		output, err := m.Add(ctx, ak)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, output)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		ak := &hub.APIKey{
+			Name:   "apikey1",
+			UserID: "userID",
+		}
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, addAPIKeyDBQ, mock.Anything).Return("apiKeyID", nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(db)
+
+		output, err := m.Add(ctx, ak)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, output)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("add api key succeeded", func(t *testing.T) {
@@ -80,7 +106,11 @@ func TestAdd(t *testing.T) {
 			UserID: "userID",
 		}
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, addAPIKeyDBQ, mock.Anything).Return("apiKeyID", nil)
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, addAPIKeyDBQ, mock.Anything).Return("apiKeyID", nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		m := NewManager(db)
 
 		output, err := m.Add(ctx, ak)
@@ -88,6 +118,7 @@ func TestAdd(t *testing.T) {
 		assert.Equal(t, "apiKeyID", output.APIKeyID)
 		assert.NotEmpty(t, output.Secret)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 }
 
@@ -0,0 +1,63 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/jackc/pgx/v4"
+)
+
+// RegisterEventDBQ represents the query used to register audit events in the
+// database.
+const RegisterEventDBQ = `select register_audit_event($1::jsonb)`
+
+// RegisterEvent registers the audit event provided in the database. It must
+// be called within the transaction used to make the change being audited, so
+// that both are committed or rolled back together.
+func RegisterEvent(ctx context.Context, tx pgx.Tx, e *hub.AuditEvent) error {
+	eJSON, _ := json.Marshal(e)
+	_, err := tx.Exec(ctx, RegisterEventDBQ, eJSON)
+	return err
+}
+
+// ValidateGetLogInput validates the input provided to get entries from the
+// audit log.
+func ValidateGetLogInput(input *hub.GetAuditLogInput) error {
+	if input == nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "input not provided")
+	}
+	for _, action := range input.Actions {
+		if !slices.Contains(hub.ValidAuditActions, action) {
+			return fmt.Errorf("%w: %s: %s", hub.ErrInvalidInput, "invalid action", action)
+		}
+	}
+	var from, to time.Time
+	if input.From != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339, input.From)
+		if err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid from timestamp")
+		}
+	}
+	if input.To != "" {
+		var err error
+		to, err = time.Parse(time.RFC3339, input.To)
+		if err != nil {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid to timestamp")
+		}
+	}
+	if input.From != "" && input.To != "" && from.After(to) {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "from timestamp is after to timestamp")
+	}
+	if input.Limit < 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit")
+	}
+	if input.Offset < 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid offset")
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterEvent(t *testing.T) {
+	ctx := context.Background()
+	e := &hub.AuditEvent{
+		Action:           hub.AuditActionOrganizationMemberAdded,
+		UserID:           "00000000-0000-0000-0000-000000000001",
+		OrganizationName: "org1",
+		Details: map[string]string{
+			"user_alias": "user2",
+		},
+	}
+	eJSON := []byte(`{"action":"organizationMemberAdded","user_id":"00000000-0000-0000-0000-000000000001","organization_name":"org1","details":{"user_alias":"user2"}}`)
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, RegisterEventDBQ, eJSON).Return(tests.ErrFakeDB)
+
+		err := RegisterEvent(ctx, tx, e)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("event registered successfully", func(t *testing.T) {
+		t.Parallel()
+		tx := &tests.TXMock{}
+		tx.On("Exec", ctx, RegisterEventDBQ, eJSON).Return(nil)
+
+		err := RegisterEvent(ctx, tx, e)
+		assert.NoError(t, err)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestValidateGetLogInput(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			input  *hub.GetAuditLogInput
+		}{
+			{
+				"input not provided",
+				nil,
+			},
+			{
+				"invalid action",
+				&hub.GetAuditLogInput{
+					Actions: []hub.AuditAction{"invalid"},
+				},
+			},
+			{
+				"invalid from timestamp",
+				&hub.GetAuditLogInput{
+					From: "2026-01-01",
+				},
+			},
+			{
+				"invalid to timestamp",
+				&hub.GetAuditLogInput{
+					To: "invalid",
+				},
+			},
+			{
+				"from timestamp is after to timestamp",
+				&hub.GetAuditLogInput{
+					From: "2026-02-01T00:00:00Z",
+					To:   "2026-01-01T00:00:00Z",
+				},
+			},
+			{
+				"invalid limit",
+				&hub.GetAuditLogInput{
+					Limit: -1,
+				},
+			},
+			{
+				"invalid offset",
+				&hub.GetAuditLogInput{
+					Offset: -1,
+				},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				err := ValidateGetLogInput(tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("valid input", func(t *testing.T) {
+		t.Parallel()
+		err := ValidateGetLogInput(&hub.GetAuditLogInput{
+			Actions: []hub.AuditAction{hub.AuditActionLogin, hub.AuditActionTFAEnabled},
+			From:    "2026-01-01T00:00:00Z",
+			To:      "2026-02-01T00:00:00Z",
+			Limit:   10,
+			Offset:  20,
+		})
+		assert.NoError(t, err)
+	})
+}
@@ -2,10 +2,12 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"net/http"
 	"path"
+	"strconv"
 	"strings"
 	"time"
 
@@ -21,6 +23,7 @@ import (
 	"github.com/artifacthub/hub/internal/handlers/webhook"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/img"
+	"github.com/artifacthub/hub/internal/ratelimit"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -43,9 +46,29 @@ const WebhooksHTTPClientTimeout = 60 * time.Second
 // uuidRE is the regex pattern for matching UUID path parameters.
 const uuidRE = "[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}"
 
+// Default values used when the API rate limiting configuration does not
+// provide them.
+const (
+	defaultAPIRateLimitWindow   = 1 * time.Minute
+	defaultMaxRequestsPerIP     = 300
+	defaultMaxRequestsPerAPIKey = 1200
+)
+
+// Rate limit headers included in the API responses, as defined in the IETF
+// RateLimit header fields for HTTP draft.
+const (
+	rateLimitLimitHeader     = "RateLimit-Limit"
+	rateLimitRemainingHeader = "RateLimit-Remaining"
+	rateLimitResetHeader     = "RateLimit-Reset"
+)
+
 // xForwardedFor is the key for the X-Forwarded-For header.
 var xForwardedFor = http.CanonicalHeaderKey("X-Forwarded-For")
 
+// errRateLimitExceeded indicates that the client has exceeded the number of
+// requests allowed in the rate limit window.
+var errRateLimitExceeded = errors.New("rate limit exceeded, please try again later")
+
 // Services is a wrapper around several internal services used by the handlers.
 type Services struct {
 	OrganizationManager hub.OrganizationManager
@@ -61,6 +84,7 @@ type Services struct {
 	HTTPClient          hub.HTTPClient
 	OCIPuller           hub.OCIPuller
 	ViewsTracker        hub.ViewsTracker
+	RateLimiter         hub.RateLimiter
 }
 
 // Metrics groups some metrics collected from a Handlers instance.
@@ -71,11 +95,12 @@ type Metrics struct {
 // Handlers groups all the http handlers defined for the hub, including the
 // router in charge of sending requests to the right handler.
 type Handlers struct {
-	cfg     *viper.Viper
-	svc     *Services
-	metrics *Metrics
-	logger  zerolog.Logger
-	Router  http.Handler
+	cfg           *viper.Viper
+	svc           *Services
+	metrics       *Metrics
+	logger        zerolog.Logger
+	ipRateLimiter hub.RateLimiter
+	Router        http.Handler
 
 	Organizations *org.Handlers
 	Users         *user.Handlers
@@ -90,15 +115,16 @@ type Handlers struct {
 
 // Setup creates a new Handlers instance.
 func Setup(ctx context.Context, cfg *viper.Viper, svc *Services) (*Handlers, error) {
-	userHandlers, err := user.NewHandlers(ctx, svc.UserManager, svc.APIKeyManager, cfg)
+	userHandlers, err := user.NewHandlers(ctx, svc.UserManager, svc.APIKeyManager, svc.RateLimiter, cfg)
 	if err != nil {
 		return nil, err
 	}
 	h := &Handlers{
-		cfg:     cfg,
-		svc:     svc,
-		metrics: setupMetrics(),
-		logger:  log.With().Str("handlers", "root").Logger(),
+		cfg:           cfg,
+		svc:           svc,
+		metrics:       setupMetrics(),
+		logger:        log.With().Str("handlers", "root").Logger(),
+		ipRateLimiter: ratelimit.NewMemoryLimiter(),
 
 		Organizations: org.NewHandlers(svc.OrganizationManager, svc.Authorizer, cfg),
 		Users:         userHandlers,
@@ -169,6 +195,9 @@ func (h *Handlers) setupRouter() {
 
 	// API
 	r.Route("/api/v1", func(r chi.Router) {
+		// Rate limiting
+		r.Use(h.APIRateLimiter)
+
 		// CSRF
 		r.Use(csrfSkipper)
 		r.Use(csrf.Protect(
@@ -188,6 +217,9 @@ func (h *Handlers) setupRouter() {
 			r.Post("/", h.Users.RegisterUser)
 			r.Post("/check-password-strength", h.Users.CheckPasswordStrength)
 			r.Post("/login", h.Users.Login)
+			if h.cfg.GetBool("server.ldap.enabled") {
+				r.Post("/login/ldap", h.Users.LoginLDAP)
+			}
 			r.Put("/approve-session", h.Users.ApproveSession)
 			r.Post("/password-reset-code", h.Users.RegisterPasswordResetCode)
 			r.Put("/reset-password", h.Users.ResetPassword)
@@ -196,6 +228,7 @@ func (h *Handlers) setupRouter() {
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
 				r.Delete("/", h.Users.DeleteUser)
+				r.Get("/audit-log", h.Users.GetAuditLog)
 				r.Post("/delete-user-code", h.Users.RegisterDeleteUserCode)
 				r.Route("/tfa", func(r chi.Router) {
 					r.Put("/disable", h.Users.DisableTFA)
@@ -206,6 +239,9 @@ func (h *Handlers) setupRouter() {
 				r.Get("/profile", h.Users.GetProfile)
 				r.Put("/profile", h.Users.UpdateProfile)
 				r.Put("/password", h.Users.UpdatePassword)
+				if h.cfg.GetBool("server.ldap.enabled") {
+					r.Put("/ldap", h.Users.LinkLDAPAccount)
+				}
 			})
 		})
 
@@ -227,6 +263,7 @@ func (h *Handlers) setupRouter() {
 						r.Put("/", h.Organizations.UpdateAuthorizationPolicy)
 					})
 					r.Get("/accept-invitation", h.Organizations.ConfirmMembership)
+					r.Get("/audit-log", h.Organizations.GetAuditLog)
 					r.Get("/members", h.Organizations.GetMembers)
 					r.Route("/member/{userAlias}", func(r chi.Router) {
 						r.Post("/", h.Organizations.AddMember)
@@ -240,6 +277,7 @@ func (h *Handlers) setupRouter() {
 		// Repositories
 		r.Route("/repositories", func(r chi.Router) {
 			r.With(h.Users.InjectUserID).Get("/search", h.Repositories.Search)
+			r.Post("/push/{repoName}", h.Repositories.Push)
 			r.Group(func(r chi.Router) {
 				r.Use(h.Users.RequireLogin)
 				r.Route("/user", func(r chi.Router) {
@@ -247,6 +285,11 @@ func (h *Handlers) setupRouter() {
 					r.Route("/{repoName}", func(r chi.Router) {
 						r.Put("/claim-ownership", h.Repositories.ClaimOwnership)
 						r.Put("/transfer", h.Repositories.Transfer)
+						r.Put("/push-secret", h.Repositories.GeneratePushSecret)
+						r.Delete("/push-secret", h.Repositories.DeletePushSecret)
+						r.Get("/track", h.Repositories.GetTrackingStatus)
+						r.Get("/tracking-runs", h.Repositories.GetTrackingRuns)
+						r.Put("/track", h.Repositories.RequestTracking)
 						r.Put("/", h.Repositories.Update)
 						r.Delete("/", h.Repositories.Delete)
 					})
@@ -256,6 +299,11 @@ func (h *Handlers) setupRouter() {
 					r.Route("/{repoName}", func(r chi.Router) {
 						r.Put("/claim-ownership", h.Repositories.ClaimOwnership)
 						r.Put("/transfer", h.Repositories.Transfer)
+						r.Put("/push-secret", h.Repositories.GeneratePushSecret)
+						r.Delete("/push-secret", h.Repositories.DeletePushSecret)
+						r.Get("/track", h.Repositories.GetTrackingStatus)
+						r.Get("/tracking-runs", h.Repositories.GetTrackingRuns)
+						r.Put("/track", h.Repositories.RequestTracking)
 						r.Put("/", h.Repositories.Update)
 						r.Delete("/", h.Repositories.Delete)
 					})
@@ -274,6 +322,8 @@ func (h *Handlers) setupRouter() {
 				r.With(corsMW).Get("/summary", h.Packages.GetSummary)
 				r.Get("/{version}", h.Packages.Get)
 				r.Get("/changelog.md", h.Packages.GenerateChangelogMD)
+				r.Get("/dependencies", h.Packages.GetDependencies)
+				r.Get("/dependents", h.Packages.GetDependents)
 				r.Route("/production-usage", func(r chi.Router) {
 					r.Use(h.Users.RequireLogin)
 					r.Get("/", h.Packages.GetProductionUsage)
@@ -290,8 +340,11 @@ func (h *Handlers) setupRouter() {
 			r.Get(fmt.Sprintf("/{packageID:%s}/{version}/values", uuidRE), h.Packages.GetChartValues)
 			r.Get(fmt.Sprintf("/{packageID:%s}/{version}/values-schema", uuidRE), h.Packages.GetValuesSchema)
 			r.Get(fmt.Sprintf("/{packageID:%s}/{version}/templates", uuidRE), h.Packages.GetChartTemplates)
+			r.Post(fmt.Sprintf("/{packageID:%s}/{version}/render", uuidRE), h.Packages.RenderChart)
 			r.Post(fmt.Sprintf("/{packageID:%s}/{version}/views", uuidRE), h.Packages.TrackView)
 			r.Get(fmt.Sprintf("/{packageID:%s}/views", uuidRE), h.Packages.GetViews)
+			r.Get(fmt.Sprintf("/{packageID:%s}/values-diff", uuidRE), h.Packages.GetValuesDiff)
+			r.Get(fmt.Sprintf("/{packageID:%s}/values-schema-diff", uuidRE), h.Packages.GetValuesSchemaDiff)
 			r.Get(fmt.Sprintf("/{packageID:%s}/changelog", uuidRE), h.Packages.GetChangelog)
 		})
 
@@ -392,7 +445,7 @@ func (h *Handlers) setupRouter() {
 	// from the Helm Hub to Artifact Hub, allowing the existing Helm tooling to
 	// continue working without modifications. This is a temporary solution and
 	// future Helm CLI versions should use the generic Artifact Hub search API.
-	r.With(compress).Get("/api/chartsvc/v1/charts/search", h.Packages.SearchMonocular)
+	r.With(h.APIRateLimiter, compress).Get("/api/chartsvc/v1/charts/search", h.Packages.SearchMonocular)
 
 	// Monocular charts url redirect endpoint
 	//
@@ -464,6 +517,83 @@ func (h *Handlers) setupRouter() {
 	h.Router = r
 }
 
+// APIRateLimiter is an http middleware that limits the number of API requests
+// clients can make in a sliding window of time. Clients are identified by the
+// API key provided (when it is valid) or by their IP address, and requests
+// authenticated using API keys get a higher quota. Counters of API keys are
+// stored in the database, so they are shared by all hub replicas. Counters of
+// IP addresses are kept in memory instead, to avoid hitting the database on
+// each anonymous request, so they are enforced by each replica separately.
+// Valid API keys are recorded in the request context, so that they are not
+// checked again when the request requires the user to be logged in.
+func (h *Handlers) APIRateLimiter(next http.Handler) http.Handler {
+	if !h.cfg.GetBool("server.rateLimit.api.enabled") || h.svc.RateLimiter == nil {
+		return next
+	}
+	window := h.cfg.GetDuration("server.rateLimit.api.window")
+	if window <= 0 {
+		window = defaultAPIRateLimitWindow
+	}
+	maxRequestsPerIP := h.cfg.GetInt("server.rateLimit.api.maxRequestsPerIP")
+	if maxRequestsPerIP <= 0 {
+		maxRequestsPerIP = defaultMaxRequestsPerIP
+	}
+	maxRequestsPerAPIKey := h.cfg.GetInt("server.rateLimit.api.maxRequestsPerAPIKey")
+	if maxRequestsPerAPIKey <= 0 {
+		maxRequestsPerAPIKey = defaultMaxRequestsPerAPIKey
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var rl hub.RateLimiter
+		var key string
+		var limit int
+
+		// Use the API key provided to identify the client when it's valid
+		apiKeyID := r.Header.Get(user.APIKeyIDHeader)
+		apiKeySecret := r.Header.Get(user.APIKeySecretHeader)
+		if apiKeyID != "" && apiKeySecret != "" {
+			checkAPIKeyOutput, err := h.svc.APIKeyManager.Check(r.Context(), apiKeyID, apiKeySecret)
+			if err != nil {
+				h.logger.Error().Err(err).Str("method", "APIRateLimiter").Msg("checkAPIKey failed")
+			} else if checkAPIKeyOutput.Valid {
+				r = r.WithContext(user.WithValidAPIKey(r.Context(), apiKeyID, checkAPIKeyOutput.UserID))
+				rl = h.svc.RateLimiter
+				key = "api:apikey:" + apiKeyID
+				limit = maxRequestsPerAPIKey
+			}
+		}
+
+		// Otherwise use the client's IP address
+		if key == "" {
+			ip, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				ip = r.RemoteAddr
+			}
+			rl = h.ipRateLimiter
+			key = "api:ip:" + ip
+			limit = maxRequestsPerIP
+		}
+
+		// Register request and check if it's allowed
+		s, err := rl.Hit(r.Context(), key, limit, window)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "APIRateLimiter").Msg("registerRateLimitHit failed")
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Set(rateLimitLimitHeader, strconv.Itoa(s.Limit))
+		w.Header().Set(rateLimitRemainingHeader, strconv.Itoa(s.Remaining))
+		w.Header().Set(rateLimitResetHeader, strconv.Itoa(s.Reset))
+		if !s.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(s.Reset))
+			helpers.RenderErrorWithCodeJSON(w, errRateLimitExceeded, http.StatusTooManyRequests)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
 // MetricsCollector is an http middleware that collects some metrics about
 // requests processed.
 func (h *Handlers) MetricsCollector(next http.Handler) http.Handler {
@@ -496,6 +626,12 @@ func csrfSkipper(next http.Handler) http.Handler {
 		if (r.Method == "GET" && r.URL.Path != "/api/v1/csrf") || r.Method == "HEAD" {
 			r = csrf.UnsafeSkipCheck(r)
 		}
+		// Skip checks for push events sent by Git forges and OCI registries.
+		// These requests are not sent from a browser and are verified using
+		// the repository's push secret.
+		if r.Method == "POST" && strings.HasPrefix(r.URL.Path, "/api/v1/repositories/push/") {
+			r = csrf.UnsafeSkipCheck(r)
+		}
 		next.ServeHTTP(w, r)
 	})
 }
@@ -1,14 +1,197 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/artifacthub/hub/internal/apikey"
+	"github.com/artifacthub/hub/internal/handlers/user"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/ratelimit"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/rs/zerolog"
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
+func TestAPIRateLimiter(t *testing.T) {
+	ipKey := "api:ip:1.1.1.1"
+	apiKeyKey := "api:apikey:keyID"
+	window := 10 * time.Second
+
+	newRequest := func(withAPIKey bool) *http.Request {
+		r, _ := http.NewRequest("GET", "/", nil)
+		r.RemoteAddr = "1.1.1.1:"
+		if withAPIKey {
+			r.Header.Set(user.APIKeyIDHeader, "keyID")
+			r.Header.Set(user.APIKeySecretHeader, "secret")
+		}
+		return r
+	}
+
+	t.Run("rate limiter disabled", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(false)
+
+		hw := newHandlersWrapper(false)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, h.Get(rateLimitLimitHeader))
+		hw.assertExpectations(t)
+	})
+
+	t.Run("request allowed (ip)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(false)
+
+		hw := newHandlersWrapper(true)
+		hw.ipRL.On("Hit", r.Context(), ipKey, 5, window).Return(&hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     5,
+			Remaining: 4,
+			Reset:     8,
+		}, nil)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "5", h.Get(rateLimitLimitHeader))
+		assert.Equal(t, "4", h.Get(rateLimitRemainingHeader))
+		assert.Equal(t, "8", h.Get(rateLimitResetHeader))
+		assert.Empty(t, h.Get("Retry-After"))
+		hw.assertExpectations(t)
+	})
+
+	t.Run("request not allowed (ip)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(false)
+
+		hw := newHandlersWrapper(true)
+		hw.ipRL.On("Hit", r.Context(), ipKey, 5, window).Return(&hub.RateLimitStatus{
+			Allowed:   false,
+			Limit:     5,
+			Remaining: 0,
+			Reset:     3,
+		}, nil)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "5", h.Get(rateLimitLimitHeader))
+		assert.Equal(t, "0", h.Get(rateLimitRemainingHeader))
+		assert.Equal(t, "3", h.Get(rateLimitResetHeader))
+		assert.Equal(t, "3", h.Get("Retry-After"))
+		hw.assertExpectations(t)
+	})
+
+	t.Run("request allowed (valid api key)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(true)
+
+		hw := newHandlersWrapper(true)
+		hw.am.On("Check", r.Context(), "keyID", "secret").Return(&hub.CheckAPIKeyOutput{
+			Valid:  true,
+			UserID: "userID",
+		}, nil)
+		hw.rl.On("Hit", mock.Anything, apiKeyKey, 20, window).Return(&hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     20,
+			Remaining: 19,
+			Reset:     8,
+		}, nil)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "20", h.Get(rateLimitLimitHeader))
+		assert.Equal(t, "19", h.Get(rateLimitRemainingHeader))
+		hw.assertExpectations(t)
+	})
+
+	t.Run("invalid api key, ip used instead", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(true)
+
+		hw := newHandlersWrapper(true)
+		hw.am.On("Check", r.Context(), "keyID", "secret").Return(&hub.CheckAPIKeyOutput{Valid: false}, nil)
+		hw.ipRL.On("Hit", r.Context(), ipKey, 5, window).Return(&hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     5,
+			Remaining: 4,
+			Reset:     8,
+		}, nil)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("error checking api key, ip used instead", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(true)
+
+		hw := newHandlersWrapper(true)
+		hw.am.On("Check", r.Context(), "keyID", "secret").Return(nil, tests.ErrFakeDB)
+		hw.ipRL.On("Hit", r.Context(), ipKey, 5, window).Return(&hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     5,
+			Remaining: 4,
+			Reset:     8,
+		}, nil)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("error registering hit, request allowed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(false)
+
+		hw := newHandlersWrapper(true)
+		hw.ipRL.On("Hit", r.Context(), ipKey, 5, window).Return(nil, tests.ErrFakeDB)
+		hw.h.APIRateLimiter(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Empty(t, h.Get(rateLimitLimitHeader))
+		hw.assertExpectations(t)
+	})
+}
+
 func TestRealIP(t *testing.T) {
 	checkRemoteAddr := func(expectedRemoteAddr string) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
@@ -107,3 +290,86 @@ func TestRealIP(t *testing.T) {
 		})
 	}
 }
+
+func TestRouter(t *testing.T) {
+	// Setup handlers using the real router
+	webBuildPath := t.TempDir()
+	err := os.WriteFile(filepath.Join(webBuildPath, "index.html"), []byte("index"), 0o600)
+	require.NoError(t, err)
+	cfg := viper.New()
+	cfg.Set("server.csrf.authKey", "authKey")
+	cfg.Set("server.csrf.secure", true)
+	cfg.Set("server.webBuildPath", webBuildPath)
+	rm := &repo.ManagerMock{}
+	h, err := Setup(context.Background(), cfg, &Services{
+		RepositoryManager: rm,
+	})
+	require.NoError(t, err)
+
+	t.Run("push events are not subject to csrf checks", func(t *testing.T) {
+		body := `{"ref": "refs/heads/main"}`
+		rm.On("ProcessPushEvent", mock.Anything, "repo1", mock.Anything, []byte(body)).Return(true, nil)
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "https://hub.test/api/v1/repositories/push/repo1", strings.NewReader(body))
+		r.Header.Set("X-GitHub-Event", "push")
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("other requests are still subject to csrf checks", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "https://hub.test/api/v1/repositories/user", strings.NewReader(`{}`))
+		h.Router.ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func testsOK(w http.ResponseWriter, r *http.Request) {}
+
+type handlersWrapper struct {
+	am   *apikey.ManagerMock
+	rl   *ratelimit.LimiterMock
+	ipRL *ratelimit.LimiterMock
+	h    *Handlers
+}
+
+func newHandlersWrapper(rateLimitEnabled bool) *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("server.rateLimit.api.enabled", rateLimitEnabled)
+	cfg.Set("server.rateLimit.api.window", 10*time.Second)
+	cfg.Set("server.rateLimit.api.maxRequestsPerIP", 5)
+	cfg.Set("server.rateLimit.api.maxRequestsPerAPIKey", 20)
+
+	am := &apikey.ManagerMock{}
+	rl := &ratelimit.LimiterMock{}
+	ipRL := &ratelimit.LimiterMock{}
+	h := &Handlers{
+		cfg: cfg,
+		svc: &Services{
+			APIKeyManager: am,
+			RateLimiter:   rl,
+		},
+		logger:        zerolog.Nop(),
+		ipRateLimiter: ipRL,
+	}
+
+	return &handlersWrapper{
+		am:   am,
+		rl:   rl,
+		ipRL: ipRL,
+		h:    h,
+	}
+}
+
+func (hw *handlersWrapper) assertExpectations(t *testing.T) {
+	hw.am.AssertExpectations(t)
+	hw.rl.AssertExpectations(t)
+	hw.ipRL.AssertExpectations(t)
+}
@@ -36,6 +36,25 @@ func BuildCacheControlHeader(cacheMaxAge time.Duration) string {
 	return fmt.Sprintf("max-age=%d", int64(cacheMaxAge.Seconds()))
 }
 
+// GetAuditLogInput is a helper that extracts the input used to get entries
+// from the audit log from the query string values provided.
+func GetAuditLogInput(qs url.Values) (*hub.GetAuditLogInput, error) {
+	p, err := GetPagination(qs, PaginationDefaultLimit, PaginationMaxLimit)
+	if err != nil {
+		return nil, err
+	}
+	input := &hub.GetAuditLogInput{
+		From:   qs.Get("from"),
+		To:     qs.Get("to"),
+		Limit:  p.Limit,
+		Offset: p.Offset,
+	}
+	for _, action := range qs["action"] {
+		input.Actions = append(input.Actions, hub.AuditAction(action))
+	}
+	return input, nil
+}
+
 // GetPagination is a helper that extracts the pagination information from the
 // query string values provided.
 func GetPagination(qs url.Values, defaultLimit, maxLimit int) (*hub.Pagination, error) {
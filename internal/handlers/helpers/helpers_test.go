@@ -15,6 +15,7 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBuildCacheControlHeader(t *testing.T) {
@@ -44,6 +45,35 @@ func TestBuildCacheControlHeader(t *testing.T) {
 	}
 }
 
+func TestGetAuditLogInput(t *testing.T) {
+	t.Run("invalid pagination", func(t *testing.T) {
+		t.Parallel()
+		input, err := GetAuditLogInput(map[string][]string{
+			"limit": {"aa"},
+		})
+		assert.Error(t, err)
+		assert.Nil(t, input)
+	})
+
+	t.Run("input extracted successfully", func(t *testing.T) {
+		t.Parallel()
+		input, err := GetAuditLogInput(map[string][]string{
+			"action": {"login", "tfaEnabled"},
+			"from":   {"2026-01-01T00:00:00Z"},
+			"to":     {"2026-02-01T00:00:00Z"},
+			"offset": {"20"},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, &hub.GetAuditLogInput{
+			Actions: []hub.AuditAction{hub.AuditActionLogin, hub.AuditActionTFAEnabled},
+			From:    "2026-01-01T00:00:00Z",
+			To:      "2026-02-01T00:00:00Z",
+			Limit:   PaginationDefaultLimit,
+			Offset:  20,
+		}, input)
+	})
+}
+
 func TestGetPagination(t *testing.T) {
 	testCases := []struct {
 		qs                 url.Values
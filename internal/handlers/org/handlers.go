@@ -129,6 +129,27 @@ func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
 }
 
+// GetAuditLog is an http handler that returns the audit log entries of the
+// provided organization.
+func (h *Handlers) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	input, err := helpers.GetAuditLogInput(r.URL.Query())
+	if err != nil {
+		err = fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetAuditLog").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	orgName := chi.URLParam(r, "orgName")
+	result, err := h.orgManager.GetAuditLogJSON(r.Context(), orgName, input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetAuditLog").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.Header().Set(helpers.PaginationTotalCount, strconv.Itoa(result.TotalCount))
+	helpers.RenderJSON(w, result.Data, 0, http.StatusOK)
+}
+
 // GetAuthorizationPolicy is an http handler that returns the organization's
 // authorization policy.
 func (h *Handlers) GetAuthorizationPolicy(w http.ResponseWriter, r *http.Request) {
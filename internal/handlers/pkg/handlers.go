@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"sort"
@@ -16,18 +18,53 @@ import (
 	"github.com/artifacthub/hub/internal/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tracker/source/helm"
+	"github.com/artifacthub/hub/internal/valuesdiff"
 	"github.com/go-chi/chi/v5"
 	"github.com/gorilla/feeds"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"github.com/spf13/viper"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
 )
 
 const (
 	searchDefaultLimit = 20
+
+	// diffsCacheSize represents the maximum number of values and values schema
+	// diffs between chart versions kept in the cache.
+	diffsCacheSize = 500
+
+	// maxRenderValuesSize represents the maximum size of the values document
+	// that can be provided when rendering a chart.
+	maxRenderValuesSize = 256 << 10 // 256KB
+
+	// renderTimeout represents the maximum amount of time rendering a chart
+	// can take.
+	renderTimeout = 10 * time.Second
+
+	// maxConcurrentRenders represents the maximum number of charts that can be
+	// rendered concurrently.
+	maxConcurrentRenders = 4
+
+	// maxRenderOutputSize represents the maximum size of the templates
+	// produced when rendering a chart.
+	maxRenderOutputSize = 5 << 20 // 5MB
+
+	// maxRenderMemory represents the maximum amount of memory the processes
+	// used to render charts can allocate.
+	maxRenderMemory = 1 << 30 // 1GB
+
+	// chartArchivesCacheSize represents the maximum number of charts archives
+	// kept in the cache.
+	chartArchivesCacheSize = 50
+
+	// maxCachedChartArchiveSize represents the maximum size of the charts
+	// archives that can be kept in the cache.
+	maxCachedChartArchiveSize = 5 << 20 // 5MB
 )
 
 // Handlers represents a group of http handlers in charge of handling packages
@@ -41,6 +78,9 @@ type Handlers struct {
 	op              hub.OCIPuller
 	vt              hub.ViewsTracker
 	tmplChangelogMD *template.Template
+	diffsCache      *lru.Cache[string, []byte]
+	archivesCache   *lru.Cache[string, []byte]
+	renderer        *helm.ChartRenderer
 }
 
 // NewHandlers creates a new Handlers instance.
@@ -52,6 +92,8 @@ func NewHandlers(
 	op hub.OCIPuller,
 	vt hub.ViewsTracker,
 ) *Handlers {
+	diffsCache, _ := lru.New[string, []byte](diffsCacheSize)
+	archivesCache, _ := lru.New[string, []byte](chartArchivesCacheSize)
 	return &Handlers{
 		pkgManager:      pkgManager,
 		repoManager:     repoManager,
@@ -61,6 +103,14 @@ func NewHandlers(
 		op:              op,
 		vt:              vt,
 		tmplChangelogMD: setupChangelogMDTmpl(),
+		diffsCache:      diffsCache,
+		archivesCache:   archivesCache,
+		renderer: helm.NewChartRenderer(&helm.ChartRendererOptions{
+			MaxConcurrency: maxConcurrentRenders,
+			MaxOutputSize:  maxRenderOutputSize,
+			MaxMemory:      maxRenderMemory,
+			Timeout:        renderTimeout,
+		}),
 	}
 }
 
@@ -207,6 +257,42 @@ func (h *Handlers) GetChangelog(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetDependencies is an http handler used to get the dependencies of a
+// package version.
+func (h *Handlers) GetDependencies(w http.ResponseWriter, r *http.Request) {
+	input, err := buildDependenciesInput(r)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetDependencies").Msg("invalid query")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, err := h.pkgManager.GetDependenciesJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetDependencies").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
+// GetDependents is an http handler used to get the packages that depend on a
+// given package.
+func (h *Handlers) GetDependents(w http.ResponseWriter, r *http.Request) {
+	input, err := buildDependenciesInput(r)
+	if err != nil {
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetDependents").Msg("invalid query")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, err := h.pkgManager.GetDependentsJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Interface("input", input).Str("method", "GetDependents").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
+}
+
 // GetChartTemplates is an http handler used to get the templates for a given
 // Helm chart package snapshot.
 func (h *Handlers) GetChartTemplates(w http.ResponseWriter, r *http.Request) {
@@ -415,6 +501,22 @@ func (h *Handlers) GetValuesSchema(w http.ResponseWriter, r *http.Request) {
 	helpers.RenderJSON(w, dataJSON, helpers.DefaultAPICacheMaxAge, http.StatusOK)
 }
 
+// GetValuesDiff is an http handler used to get the changes in the default
+// values between two versions of a Helm chart package.
+func (h *Handlers) GetValuesDiff(w http.ResponseWriter, r *http.Request) {
+	h.renderChartsDiff(w, r, "GetValuesDiff", func(from, to *chart.Chart) ([]*valuesdiff.Change, error) {
+		return valuesdiff.Values(from.Values, to.Values), nil
+	})
+}
+
+// GetValuesSchemaDiff is an http handler used to get the changes in the values
+// schema between two versions of a Helm chart package.
+func (h *Handlers) GetValuesSchemaDiff(w http.ResponseWriter, r *http.Request) {
+	h.renderChartsDiff(w, r, "GetValuesSchemaDiff", func(from, to *chart.Chart) ([]*valuesdiff.Change, error) {
+		return valuesdiff.Schemas(from.Schema, to.Schema)
+	})
+}
+
 // GetViews is an http handler used to get the views of the package provided.
 func (h *Handlers) GetViews(w http.ResponseWriter, r *http.Request) {
 	packageID := chi.URLParam(r, "packageID")
@@ -461,6 +563,52 @@ func (h *Handlers) InjectIndexMeta(next http.Handler) http.Handler {
 	})
 }
 
+// RenderChart is an http handler used to render the templates of a given
+// Helm chart package snapshot using the values document provided in the
+// request body.
+func (h *Handlers) RenderChart(w http.ResponseWriter, r *http.Request) {
+	// Read values provided
+	values, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRenderValuesSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			helpers.RenderErrorWithCodeJSON(w, errors.New("values document too large"), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.logger.Error().Err(err).Str("method", "RenderChart").Msg("error reading values")
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+
+	// Get chart's archive from original source
+	archive, err := h.getChartArchiveData(
+		r.Context(),
+		chi.URLParam(r, "packageID"),
+		chi.URLParam(r, "version"),
+	)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "RenderChart").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Render chart templates and return the resulting manifests
+	templates, err := h.renderer.Render(r.Context(), archive, values)
+	if err != nil {
+		if errors.Is(err, helm.ErrTooManyRenders) {
+			helpers.RenderErrorWithCodeJSON(w, err, http.StatusTooManyRequests)
+			return
+		}
+		if !errors.Is(err, hub.ErrInvalidInput) {
+			h.logger.Error().Err(err).Str("method", "RenderChart").Send()
+		}
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(templates)
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
 // RssFeed is an http handler used to get the RSS feed of a given package.
 func (h *Handlers) RssFeed(w http.ResponseWriter, r *http.Request) {
 	// Get package details
@@ -588,6 +736,17 @@ func (h *Handlers) TrackView(w http.ResponseWriter, r *http.Request) {
 // getChartArchive is a helper function used to download a chart's archive from
 // the original source.
 func (h *Handlers) getChartArchive(ctx context.Context, packageID, version string) (*chart.Chart, error) {
+	data, err := h.getChartArchiveData(ctx, packageID, version)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
+
+// getChartArchiveData is a helper function used to get the content of a
+// chart's archive from the original source. Archives are cached per package
+// and version.
+func (h *Handlers) getChartArchiveData(ctx context.Context, packageID, version string) ([]byte, error) {
 	// Get package from database as we need the content url
 	input := &hub.GetPackageInput{
 		PackageID: packageID,
@@ -604,6 +763,12 @@ func (h *Handlers) getChartArchive(ctx context.Context, packageID, version strin
 		return nil, fmt.Errorf("%w: operation not supported for this repository kind", hub.ErrInvalidInput)
 	}
 
+	// Use cached archive if available
+	cacheKey := packageID + "@" + version
+	if data, ok := h.archivesCache.Get(cacheKey); ok {
+		return data, nil
+	}
+
 	// Download chart package from remote source
 	var username, password string
 	if p.Repository.Private {
@@ -616,7 +781,7 @@ func (h *Handlers) getChartArchive(ctx context.Context, packageID, version strin
 		password = repo.AuthPass
 	}
 	u, _ := url.Parse(p.ContentURL)
-	chrt, err := helm.LoadChartArchive(
+	data, err := helm.GetChartArchive(
 		ctx,
 		u,
 		&helm.LoadChartArchiveOptions{
@@ -629,8 +794,89 @@ func (h *Handlers) getChartArchive(ctx context.Context, packageID, version strin
 	if err != nil {
 		return nil, err
 	}
+	if len(data) <= maxCachedChartArchiveSize {
+		h.archivesCache.Add(cacheKey, data)
+	}
+
+	return data, nil
+}
+
+// renderChartsDiff renders the changes between the chart versions provided in
+// the request (from and to query parameters), as computed by the diff function
+// provided. Diffs are cached per package and versions pair.
+func (h *Handlers) renderChartsDiff(
+	w http.ResponseWriter,
+	r *http.Request,
+	method string,
+	diff func(from, to *chart.Chart) ([]*valuesdiff.Change, error),
+) {
+	packageID := chi.URLParam(r, "packageID")
+	fromVersion := r.URL.Query().Get("from")
+	toVersion := r.URL.Query().Get("to")
+	logger := h.logger.With().Str("method", method).Logger()
+
+	// Validate input
+	var err error
+	switch {
+	case fromVersion == "":
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, "from version not provided")
+	case toVersion == "":
+		err = fmt.Errorf("%w: %s", hub.ErrInvalidInput, "to version not provided")
+	}
+	if err != nil {
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Check if the diff is already cached
+	cacheKey := fmt.Sprintf("%s:%s:%s:%s", method, packageID, fromVersion, toVersion)
+	if dataJSON, ok := h.diffsCache.Get(cacheKey); ok {
+		helpers.RenderJSON(w, dataJSON, 24*time.Hour, http.StatusOK)
+		return
+	}
+
+	// Get charts archives from original source and compute the diff
+	fromChrt, err := h.getChartArchive(r.Context(), packageID, fromVersion)
+	if err != nil {
+		logger.Error().Err(err).Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	toChrt, err := h.getChartArchive(r.Context(), packageID, toVersion)
+	if err != nil {
+		logger.Error().Err(err).Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	changes, err := diff(fromChrt, toChrt)
+	if err != nil {
+		logger.Error().Err(err).Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(changes)
+	h.diffsCache.Add(cacheKey, dataJSON)
+	helpers.RenderJSON(w, dataJSON, 24*time.Hour, http.StatusOK)
+}
 
-	return chrt, nil
+// buildDependenciesInput builds the input used to get the dependencies or the
+// dependents of a package from the request provided.
+func buildDependenciesInput(r *http.Request) (*hub.GetPackageDependenciesInput, error) {
+	qs := r.URL.Query()
+	var depth int
+	if qs.Get("depth") != "" {
+		var err error
+		depth, err = strconv.Atoi(qs.Get("depth"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid depth: %s", hub.ErrInvalidInput, qs.Get("depth"))
+		}
+	}
+	return &hub.GetPackageDependenciesInput{
+		RepositoryName: chi.URLParam(r, "repoName"),
+		PackageName:    chi.URLParam(r, "packageName"),
+		Version:        qs.Get("version"),
+		Depth:          depth,
+	}, nil
 }
 
 // buildSearchInput builds a packages search query from a map of query string
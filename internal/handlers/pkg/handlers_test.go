@@ -9,6 +9,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"path"
 	"strconv"
 	"strings"
 	"testing"
@@ -32,6 +33,13 @@ import (
 )
 
 func TestMain(m *testing.M) {
+	// Run as a chart renderer process when launched by the chart renderer
+	if len(os.Args) > 1 && os.Args[1] == helm.RenderChartCmd {
+		if err := helm.ServeRenderChart(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
 	zerolog.SetGlobalLevel(zerolog.Disabled)
 	os.Exit(m.Run())
 }
@@ -641,6 +649,190 @@ func TestGetChartTemplates(t *testing.T) {
 	})
 }
 
+func TestGetDependencies(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName"},
+			Values: []string{"repo1", "pkg1"},
+		},
+	}
+
+	t.Run("invalid depth", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?depth=invalid", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.GetDependencies(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("get package dependencies failed", func(t *testing.T) {
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+		}
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := httpw.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetDependenciesJSON", r.Context(), input).Return(nil, tc.pmErr)
+				hw.h.GetDependencies(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.assertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get package dependencies succeeded", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+			Version:        "1.0.0",
+			Depth:          3,
+		}
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?version=1.0.0&depth=3", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetDependenciesJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetDependencies(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.assertExpectations(t)
+	})
+}
+
+func TestGetDependents(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"repoName", "packageName"},
+			Values: []string{"repo1", "pkg1"},
+		},
+	}
+
+	t.Run("invalid depth", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?depth=invalid", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.GetDependents(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("get package dependents failed", func(t *testing.T) {
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+		}
+		testCases := []struct {
+			pmErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				hub.ErrNotFound,
+				http.StatusNotFound,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.pmErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := httpw.NewRequest("GET", "/", nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.pm.On("GetDependentsJSON", r.Context(), input).Return(nil, tc.pmErr)
+				hw.h.GetDependents(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.assertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get package dependents succeeded", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+			Version:        "1.0.0",
+			Depth:          3,
+		}
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?version=1.0.0&depth=3", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("GetDependentsJSON", r.Context(), input).Return([]byte("dataJSON"), nil)
+		hw.h.GetDependents(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(helpers.DefaultAPICacheMaxAge), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.assertExpectations(t)
+	})
+}
+
 func TestGetHarborReplicationDump(t *testing.T) {
 	t.Run("get harbor replication dump succeeded", func(t *testing.T) {
 		t.Parallel()
@@ -1103,6 +1295,163 @@ func TestGetSummary(t *testing.T) {
 	})
 }
 
+func TestGetValuesDiff(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"packageID"},
+			Values: []string{"pkg"},
+		},
+	}
+	p1ContentURL := "https://content.url/pkg1-1.0.0.tgz"
+	p2ContentURL := "https://content.url/pkg2-1.0.0.tgz"
+	newPkg := func(contentURL string) *hub.Package {
+		return &hub.Package{
+			ContentURL: contentURL,
+			Repository: &hub.Repository{
+				Kind: hub.Helm,
+				URL:  "https://repo.url",
+			},
+		}
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			query  string
+			errMsg string
+		}{
+			{"?to=2.0.0", "from version not provided"},
+			{"?from=1.0.0", "to version not provided"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := httpw.NewRequest("GET", "/"+tc.query, nil)
+				r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+				hw := newHandlersWrapper()
+				hw.h.GetValuesDiff(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+				data, _ := io.ReadAll(resp.Body)
+
+				assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+				assert.Contains(t, string(data), tc.errMsg)
+				hw.assertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get chart archive failed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?from=1.0.0&to=2.0.0", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), &hub.GetPackageInput{PackageID: "pkg", Version: "1.0.0"}).
+			Return(nil, tests.ErrFakeDB)
+		hw.h.GetValuesDiff(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("diff computed and cached", func(t *testing.T) {
+		t.Parallel()
+		hw := newHandlersWrapper()
+		for i := 0; i < 2; i++ {
+			w := httptest.NewRecorder()
+			r, _ := httpw.NewRequest("GET", "/?from=1.0.0&to=2.0.0", nil)
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			if i == 0 {
+				for version, contentURL := range map[string]string{"1.0.0": p1ContentURL, "2.0.0": p2ContentURL} {
+					hw.pm.On("Get", r.Context(), &hub.GetPackageInput{PackageID: "pkg", Version: version}).
+						Return(newPkg(contentURL), nil).Once()
+					tgzReq, _ := httpw.NewRequest("GET", contentURL, nil)
+					tgzReq = tgzReq.WithContext(r.Context())
+					tgzReq.Header.Set("Accept-Encoding", "identity")
+					f, _ := os.Open("testdata/" + path.Base(contentURL))
+					hw.hc.On("Do", tgzReq).Return(&http.Response{
+						Body:       f,
+						StatusCode: http.StatusOK,
+					}, nil).Once()
+				}
+			}
+			hw.h.GetValuesDiff(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+			h := resp.Header
+			data, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "application/json", h.Get("Content-Type"))
+			assert.Equal(t, helpers.BuildCacheControlHeader(24*time.Hour), h.Get("Cache-Control"))
+			assert.JSONEq(t, `[{"path": "key", "kind": "removed", "from": "value"}]`, string(data))
+		}
+		hw.assertExpectations(t)
+	})
+}
+
+func TestGetValuesSchemaDiff(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"packageID"},
+			Values: []string{"pkg"},
+		},
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?to=2.0.0", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.GetValuesSchemaDiff(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("diff computed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("GET", "/?from=1.0.0&to=2.0.0", nil)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		for version, contentURL := range map[string]string{
+			"1.0.0": "https://content.url/pkg1-1.0.0.tgz",
+			"2.0.0": "https://content.url/pkg2-1.0.0.tgz",
+		} {
+			hw.pm.On("Get", r.Context(), &hub.GetPackageInput{PackageID: "pkg", Version: version}).
+				Return(&hub.Package{ContentURL: contentURL, Repository: &hub.Repository{Kind: hub.Helm}}, nil)
+			tgzReq, _ := httpw.NewRequest("GET", contentURL, nil)
+			tgzReq = tgzReq.WithContext(r.Context())
+			tgzReq.Header.Set("Accept-Encoding", "identity")
+			f, _ := os.Open("testdata/" + path.Base(contentURL))
+			hw.hc.On("Do", tgzReq).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+		}
+		hw.h.GetValuesSchemaDiff(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `[]`, string(data))
+		hw.assertExpectations(t)
+	})
+}
+
 func TestGetValuesSchema(t *testing.T) {
 	rctx := &chi.Context{
 		URLParams: chi.RouteParams{
@@ -1287,6 +1636,149 @@ func TestInjectIndexMeta(t *testing.T) {
 	}
 }
 
+func TestRenderChart(t *testing.T) {
+	rctx := &chi.Context{
+		URLParams: chi.RouteParams{
+			Keys:   []string{"packageID", "version"},
+			Values: []string{"pkg", "1.0.0"},
+		},
+	}
+	getPkgInput := &hub.GetPackageInput{
+		PackageID: "pkg",
+		Version:   "1.0.0",
+	}
+	p1ContentURL := "https://content.url/p1.tgz"
+	p1 := &hub.Package{
+		ContentURL: p1ContentURL,
+		Repository: &hub.Repository{
+			Kind: hub.Helm,
+			URL:  "https://repo.url",
+		},
+	}
+
+	t.Run("values document too large", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		values := strings.NewReader("key: " + strings.Repeat("a", maxRenderValuesSize))
+		r, _ := httpw.NewRequest("POST", "/", values)
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.RenderChart(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("get chart archive failed", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("POST", "/", strings.NewReader("key: value2"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.pm.On("Get", r.Context(), getPkgInput).Return(nil, tests.ErrFakeDB)
+		hw.h.RenderChart(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("too many charts being rendered", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("POST", "/", strings.NewReader("key: value2"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+		hw := newHandlersWrapper()
+		hw.h.renderer = helm.NewChartRenderer(&helm.ChartRendererOptions{
+			MaxConcurrency: 0,
+			MaxOutputSize:  maxRenderOutputSize,
+			Timeout:        renderTimeout,
+		})
+		hw.pm.On("Get", r.Context(), getPkgInput).Return(p1, nil)
+		hw.h.archivesCache.Add("pkg@1.0.0", []byte("archive"))
+		hw.h.RenderChart(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		hw.assertExpectations(t)
+	})
+
+	t.Run("chart rendered successfully using cached archive", func(t *testing.T) {
+		t.Parallel()
+		archive, _ := os.ReadFile("testdata/pkg1-1.0.0.tgz")
+		hw := newHandlersWrapper()
+		hw.h.archivesCache.Add("pkg@1.0.0", archive)
+
+		w := httptest.NewRecorder()
+		r, _ := httpw.NewRequest("POST", "/", strings.NewReader("key: value3"))
+		r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		hw.pm.On("Get", r.Context(), getPkgInput).Return(p1, nil)
+		hw.h.RenderChart(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.JSONEq(t, `[{"name": "pkg1/templates/template.yaml", "manifest": "key: value3\n"}]`, string(data))
+		hw.assertExpectations(t)
+	})
+
+	testCases := []struct {
+		desc               string
+		values             string
+		expectedStatusCode int
+		expectedData       string
+	}{
+		{
+			"invalid values",
+			"{[",
+			http.StatusBadRequest,
+			`{"message": "invalid input: invalid values: error converting YAML to JSON: yaml: line 1: did not find expected node content"}`,
+		},
+		{
+			"chart rendered successfully",
+			"key: value2",
+			http.StatusOK,
+			`[{"name": "pkg1/templates/template.yaml", "manifest": "key: value2\n"}]`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := httpw.NewRequest("POST", "/", strings.NewReader(tc.values))
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+
+			hw := newHandlersWrapper()
+			hw.pm.On("Get", r.Context(), getPkgInput).Return(p1, nil)
+			tgzReq, _ := httpw.NewRequest("GET", p1ContentURL, nil)
+			tgzReq = tgzReq.WithContext(r.Context())
+			tgzReq.Header.Set("Accept-Encoding", "identity")
+			f, _ := os.Open("testdata/pkg1-1.0.0.tgz")
+			hw.hc.On("Do", tgzReq).Return(&http.Response{
+				Body:       f,
+				StatusCode: http.StatusOK,
+			}, nil)
+			hw.h.RenderChart(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+			data, _ := io.ReadAll(resp.Body)
+
+			assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+			assert.JSONEq(t, tc.expectedData, string(data))
+			assert.True(t, hw.h.archivesCache.Contains("pkg@1.0.0"))
+			hw.assertExpectations(t)
+		})
+	}
+}
+
 func TestRssFeed(t *testing.T) {
 	t.Run("error getting rss feed package", func(t *testing.T) {
 		testCases := []struct {
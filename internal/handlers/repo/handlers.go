@@ -3,6 +3,7 @@ package repo
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -21,6 +22,7 @@ const (
 	logoSVG            = `<svg xmlns="http://www.w3.org/2000/svg" width="14" height="14" viewBox="0 0 24 24" fill="none" stroke="#ffffff" stroke-width="2" stroke-linecap="round" stroke-linejoin="round" class="feather feather-hexagon"><path d="M21 16V8a2 2 0 0 0-1-1.73l-7-4a2 2 0 0 0-2 0l-7 4A2 2 0 0 0 3 8v8a2 2 0 0 0 1 1.73l7 4a2 2 0 0 0 2 0l7-4A2 2 0 0 0 21 16z"></path></svg>`
 	searchDefaultLimit = 20
 	searchMaxLimit     = 60
+	maxPushEventSize   = 1 << 20 // 1MB
 )
 
 // Handlers represents a group of http handlers in charge of handling
@@ -124,6 +126,101 @@ func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// DeletePushSecret is an http handler that deletes the push secret of the
+// provided repository, disabling push events for it.
+func (h *Handlers) DeletePushSecret(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	if err := h.repoManager.DeletePushSecret(r.Context(), repoName); err != nil {
+		h.logger.Error().Err(err).Str("method", "DeletePushSecret").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GeneratePushSecret is an http handler that generates a new push secret for
+// the provided repository. The secret is only returned once.
+func (h *Handlers) GeneratePushSecret(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	secret, err := h.repoManager.GeneratePushSecret(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GeneratePushSecret").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	dataJSON, _ := json.Marshal(map[string]string{"push_secret": secret})
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// GetTrackingRuns is an http handler that returns the tracking runs of the
+// provided repository, most recent first.
+func (h *Handlers) GetTrackingRuns(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	p, err := helpers.GetPagination(r.URL.Query(), helpers.PaginationDefaultLimit, helpers.PaginationMaxLimit)
+	if err != nil {
+		err = fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetTrackingRuns").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	result, err := h.repoManager.GetTrackingRunsJSON(r.Context(), repoName, p)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetTrackingRuns").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.Header().Set(helpers.PaginationTotalCount, strconv.Itoa(result.TotalCount))
+	helpers.RenderJSON(w, result.Data, 0, http.StatusOK)
+}
+
+// GetTrackingStatus is an http handler that returns the tracking status of
+// the provided repository.
+func (h *Handlers) GetTrackingStatus(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	dataJSON, err := h.repoManager.GetTrackingStatusJSON(r.Context(), repoName)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetTrackingStatus").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	helpers.RenderJSON(w, dataJSON, 0, http.StatusOK)
+}
+
+// Push is an http handler that processes the push events sent by Git forges
+// and OCI registries, requesting the tracking of the repository when needed.
+func (h *Handlers) Push(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPushEventSize))
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "Push").Msg("error reading push event")
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+	trackingRequested, err := h.repoManager.ProcessPushEvent(r.Context(), repoName, r.Header, body)
+	if err != nil {
+		h.logger.Error().Err(err).Str("repo", repoName).Str("method", "Push").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	if trackingRequested {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RequestTracking is an http handler that requests the tracking of the
+// provided repository as soon as possible.
+func (h *Handlers) RequestTracking(w http.ResponseWriter, r *http.Request) {
+	repoName := chi.URLParam(r, "repoName")
+	if err := h.repoManager.RequestTracking(r.Context(), repoName); err != nil {
+		h.logger.Error().Err(err).Str("method", "RequestTracking").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
 // Search is an http handler used to search for repositories in the hub
 // database.
 func (h *Handlers) Search(w http.ResponseWriter, r *http.Request) {
@@ -157,6 +157,7 @@ func (h *Handlers) Index(w http.ResponseWriter, r *http.Request) {
 		"gaTrackingID":             h.cfg.GetString("analytics.gaTrackingID"),
 		"githubAuth":               h.cfg.IsSet("server.oauth.github"),
 		"googleAuth":               h.cfg.IsSet("server.oauth.google"),
+		"ldapAuth":                 h.cfg.GetBool("server.ldap.enabled"),
 		"motd":                     h.cfg.GetString("server.motd"),
 		"motdSeverity":             h.cfg.GetString("server.motdSeverity"),
 		"oidcAuth":                 h.cfg.IsSet("server.oauth.oidc"),
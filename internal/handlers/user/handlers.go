@@ -49,10 +49,11 @@ const (
 	// endpoint. If TFA is not enabled, sessions will be approved on creation.
 	SessionApprovedHeader = "X-SESSION-APPROVED"
 
-	sessionCookieName    = "sid"
-	oauthStateCookieName = "oas"
-	sessionDuration      = 30 * 24 * time.Hour
-	oauthFailedURL       = "/oauth-failed"
+	sessionCookieName      = "sid"
+	oauthStateCookieName   = "oas"
+	sessionDuration        = 30 * 24 * time.Hour
+	oauthFailedURL         = "/oauth-failed"
+	defaultOIDCGroupsClaim = "groups"
 )
 
 var (
@@ -63,16 +64,29 @@ var (
 	errInvalidSession = errors.New("invalid session")
 )
 
+// oidcGroupMapping represents a mapping between a group from the OpenID
+// connect provider and an organization. Users belonging to the group will be
+// added to the organization and, when a role is provided, they'll be assigned
+// that role in the organization's rbac.v1 authorization policy data.
+type oidcGroupMapping struct {
+	Group        string `mapstructure:"group"`
+	Organization string `mapstructure:"organization"`
+	Role         string `mapstructure:"role"`
+}
+
 // Handlers represents a group of http handlers in charge of handling
 // users operations.
 type Handlers struct {
-	userManager   hub.UserManager
-	apiKeyManager hub.APIKeyManager
-	cfg           *viper.Viper
-	sc            *securecookie.SecureCookie
-	oauthConfig   map[string]*oauth2.Config
-	oidcProvider  *oidc.Provider
-	logger        zerolog.Logger
+	userManager        hub.UserManager
+	apiKeyManager      hub.APIKeyManager
+	cfg                *viper.Viper
+	sc                 *securecookie.SecureCookie
+	oauthConfig        map[string]*oauth2.Config
+	oidcProvider       *oidc.Provider
+	oidcGroupsMappings []*oidcGroupMapping
+	ldap               *ldapAuthenticator
+	authRL             *authRateLimiter
+	logger             zerolog.Logger
 }
 
 // NewHandlers creates a new Handlers instance.
@@ -80,6 +94,7 @@ func NewHandlers(
 	ctx context.Context,
 	userManager hub.UserManager,
 	apiKeyManager hub.APIKeyManager,
+	rateLimiter hub.RateLimiter,
 	cfg *viper.Viper,
 ) (*Handlers, error) {
 	// Setup secure cookie instance
@@ -89,6 +104,7 @@ func NewHandlers(
 	// Setup oauth providers configuration
 	oauthConfig := make(map[string]*oauth2.Config)
 	var oidcProvider *oidc.Provider
+	var oidcGroupsMappings []*oidcGroupMapping
 	for provider := range cfg.GetStringMap("server.oauth") {
 		baseCfgKey := fmt.Sprintf("server.oauth.%s.", provider)
 		var endpoint oauth2.Endpoint
@@ -105,6 +121,14 @@ func NewHandlers(
 				return nil, fmt.Errorf("error setting up oidc provider: %w", err)
 			}
 			endpoint = oidcProvider.Endpoint()
+			if err := cfg.UnmarshalKey(baseCfgKey+"groupsMapping", &oidcGroupsMappings); err != nil {
+				return nil, fmt.Errorf("error reading oidc groups mapping: %w", err)
+			}
+			for _, gm := range oidcGroupsMappings {
+				if gm.Group == "" || gm.Organization == "" {
+					return nil, errors.New("invalid oidc groups mapping: group and organization must be provided")
+				}
+			}
 		default:
 			continue
 		}
@@ -117,14 +141,33 @@ func NewHandlers(
 		}
 	}
 
+	// Setup ldap authenticator if enabled
+	var ldapAuth *ldapAuthenticator
+	if cfg.GetBool("server.ldap.enabled") {
+		var err error
+		ldapAuth, err = newLDAPAuthenticator(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("error setting up ldap authenticator: %w", err)
+		}
+	}
+
+	// Setup authentication attempts rate limiter if enabled
+	var authRL *authRateLimiter
+	if cfg.GetBool("server.rateLimit.auth.enabled") && rateLimiter != nil {
+		authRL = newAuthRateLimiter(rateLimiter, cfg)
+	}
+
 	return &Handlers{
-		userManager:   userManager,
-		apiKeyManager: apiKeyManager,
-		cfg:           cfg,
-		sc:            sc,
-		oauthConfig:   oauthConfig,
-		oidcProvider:  oidcProvider,
-		logger:        log.With().Str("handlers", "user").Logger(),
+		userManager:        userManager,
+		apiKeyManager:      apiKeyManager,
+		cfg:                cfg,
+		sc:                 sc,
+		oauthConfig:        oauthConfig,
+		oidcProvider:       oidcProvider,
+		oidcGroupsMappings: oidcGroupsMappings,
+		ldap:               ldapAuth,
+		authRL:             authRL,
+		logger:             log.With().Str("handlers", "user").Logger(),
 	}, nil
 }
 
@@ -143,6 +186,11 @@ func (h *Handlers) ApproveSession(w http.ResponseWriter, r *http.Request) {
 	}
 	passcode := input["passcode"]
 
+	// Check if more authentication attempts are allowed
+	if !h.checkAuthAttempts(w, r, "ApproveSession", "") {
+		return
+	}
+
 	// Extract sessionID from cookie
 	var sessionID string
 	cookie, err := r.Cookie(sessionCookieName)
@@ -160,6 +208,9 @@ func (h *Handlers) ApproveSession(w http.ResponseWriter, r *http.Request) {
 	// Approve session using the passcode provided
 	if err := h.userManager.ApproveSession(r.Context(), sessionID, passcode); err != nil {
 		h.logger.Error().Err(err).Str("method", "ApproveSession").Send()
+		if errors.Is(err, hub.ErrInvalidInput) {
+			h.registerFailedAuthAttempt(r, "ApproveSession", "")
+		}
 		helpers.RenderErrorJSON(w, err)
 		return
 	}
@@ -296,6 +347,26 @@ func (h *Handlers) EnableTFA(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// GetAuditLog is an http handler used to get the audit log entries of a
+// logged in user.
+func (h *Handlers) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	input, err := helpers.GetAuditLogInput(r.URL.Query())
+	if err != nil {
+		err = fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+		h.logger.Error().Err(err).Str("query", r.URL.RawQuery).Str("method", "GetAuditLog").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	result, err := h.userManager.GetAuditLogJSON(r.Context(), input)
+	if err != nil {
+		h.logger.Error().Err(err).Str("method", "GetAuditLog").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	w.Header().Set(helpers.PaginationTotalCount, strconv.Itoa(result.TotalCount))
+	helpers.RenderJSON(w, result.Data, 0, http.StatusOK)
+}
+
 // GetProfile is an http handler used to get a logged in user profile.
 func (h *Handlers) GetProfile(w http.ResponseWriter, r *http.Request) {
 	dataJSON, err := h.userManager.GetProfileJSON(r.Context())
@@ -356,27 +427,158 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check if more authentication attempts are allowed
+	account := "email:" + input["email"]
+	if !h.checkAuthAttempts(w, r, "Login", account) {
+		return
+	}
+
 	// Check if the credentials provided are valid
-	checkCredentialsOutput, err := h.userManager.CheckCredentials(r.Context(), input["email"], input["password"])
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	checkCredentialsOutput, err := h.userManager.CheckCredentials(r.Context(), input["email"], input["password"], ip)
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "Login").Msg("checkCredentials failed")
 		helpers.RenderErrorJSON(w, err)
 		return
 	}
 	if !checkCredentialsOutput.Valid {
+		h.registerFailedAuthAttempt(r, "Login", account)
 		helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
 		return
 	}
 
+	// Register user session and set session cookie
+	h.startSession(w, r, "Login", checkCredentialsOutput.UserID)
+}
+
+// LoginLDAP is an http handler used to log a user in using the credentials
+// from the configured LDAP directory. Users will be registered automatically
+// the first time they log in, unless an account using the same email already
+// exists. In that case, the LDAP account must be linked explicitly to it first.
+func (h *Handlers) LoginLDAP(w http.ResponseWriter, r *http.Request) {
+	// Extract credentials from request
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "LoginLDAP").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+
+	// Check if more authentication attempts are allowed
+	account := "ldap:" + input["username"]
+	if !h.checkAuthAttempts(w, r, "LoginLDAP", account) {
+		return
+	}
+
+	// Check if the credentials provided are valid
+	u, ok := h.authenticateLDAP(w, r, "LoginLDAP", account, input)
+	if !ok {
+		return
+	}
+
+	// Get the user linked to the ldap account, registering him if needed
+	userID, err := h.userManager.GetUserIDFromLDAPDN(r.Context(), u.LDAPDN)
+	if errors.Is(err, user.ErrNotFound) {
+		userID, err = h.registerLDAPUser(r.Context(), u)
+	}
+	if err != nil {
+		if errors.Is(err, errLDAPAccountNotLinked) {
+			helpers.RenderErrorWithCodeJSON(w, err, http.StatusForbidden)
+			return
+		}
+		h.logger.Error().Err(err).Str("method", "LoginLDAP").Msg("registerUser failed")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	// Register user session and set session cookie
+	h.startSession(w, r, "LoginLDAP", userID)
+}
+
+// LinkLDAPAccount is an http handler used to link the account of the user
+// doing the request to his account in the configured LDAP directory, allowing
+// him to log in using his LDAP credentials.
+func (h *Handlers) LinkLDAPAccount(w http.ResponseWriter, r *http.Request) {
+	// Extract credentials from request
+	var input map[string]string
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		h.logger.Error().Err(err).Str("method", "LinkLDAPAccount").Msg(hub.ErrInvalidInput.Error())
+		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
+		return
+	}
+
+	// Check if more authentication attempts are allowed
+	account := "ldap:" + input["username"]
+	if !h.checkAuthAttempts(w, r, "LinkLDAPAccount", account) {
+		return
+	}
+
+	// Check if the credentials provided are valid
+	u, ok := h.authenticateLDAP(w, r, "LinkLDAPAccount", account, input)
+	if !ok {
+		return
+	}
+
+	// Check the ldap account is not linked to a different user already
+	userID, err := h.userManager.GetUserIDFromLDAPDN(r.Context(), u.LDAPDN)
+	if err != nil && !errors.Is(err, user.ErrNotFound) {
+		h.logger.Error().Err(err).Str("method", "LinkLDAPAccount").Msg("getUserIDFromLDAPDN failed")
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+	if userID != "" && userID != r.Context().Value(hub.UserIDKey).(string) {
+		errMsg := "ldap account already linked to a different user"
+		helpers.RenderErrorJSON(w, fmt.Errorf("%w: %s", hub.ErrInvalidInput, errMsg))
+		return
+	}
+
+	// Link ldap account
+	if err := h.userManager.LinkLDAPAccount(r.Context(), u.LDAPDN); err != nil {
+		h.logger.Error().Err(err).Str("method", "LinkLDAPAccount").Send()
+		helpers.RenderErrorJSON(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticateLDAP is a helper function that checks the credentials provided
+// in the input against the configured LDAP directory, returning the user built
+// from the LDAP entry when they are valid. When they aren't, the response is
+// written and a failed authentication attempt is registered for the account.
+func (h *Handlers) authenticateLDAP(
+	w http.ResponseWriter,
+	r *http.Request,
+	method string,
+	account string,
+	input map[string]string,
+) (*hub.User, bool) {
+	u, err := h.ldap.authenticate(input["username"], input["password"])
+	if err != nil {
+		if errors.Is(err, errInvalidLDAPCredentials) {
+			h.registerFailedAuthAttempt(r, method, account)
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusUnauthorized)
+			return nil, false
+		}
+		h.logger.Error().Err(err).Str("method", method).Msg("ldap authentication failed")
+		helpers.RenderErrorJSON(w, err)
+		return nil, false
+	}
+	return u, true
+}
+
+// startSession is a helper function that registers a new session for the
+// user provided and sets the corresponding session cookie in the response.
+func (h *Handlers) startSession(w http.ResponseWriter, r *http.Request, method, userID string) {
 	// Register user session
 	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
 	session, err := h.userManager.RegisterSession(r.Context(), &hub.Session{
-		UserID:    checkCredentialsOutput.UserID,
+		UserID:    userID,
 		IP:        ip,
 		UserAgent: r.UserAgent(),
 	})
 	if err != nil {
-		h.logger.Error().Err(err).Str("method", "Login").Msg("registerSession failed")
+		h.logger.Error().Err(err).Str("method", method).Msg("registerSession failed")
 		helpers.RenderErrorJSON(w, err)
 		return
 	}
@@ -384,7 +586,7 @@ func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
 	// Generate and set session cookie
 	encodedSessionID, err := h.sc.Encode(sessionCookieName, session.SessionID)
 	if err != nil {
-		h.logger.Error().Err(err).Str("method", "Login").Msg("sessionID encoding failed")
+		h.logger.Error().Err(err).Str("method", method).Msg("sessionID encoding failed")
 		helpers.RenderErrorJSON(w, err)
 		return
 	}
@@ -589,6 +791,7 @@ func (h *Handlers) RegisterUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	u.EmailVerified = false
+	u.LDAPDN = ""
 	if u.Password == "" {
 		errMsg := "password not provided"
 		h.logger.Error().Err(err).Str("method", "RegisterUser").Msg(errMsg)
@@ -615,6 +818,7 @@ func (h *Handlers) registerUserWithOauth(
 ) (string, error) {
 	// Build user from profile from oauth provider
 	var u *hub.User
+	var groups []string
 	var err error
 	switch provider {
 	case "github":
@@ -622,7 +826,7 @@ func (h *Handlers) registerUserWithOauth(
 	case "google":
 		u, err = h.newUserFromGoogleProfile(ctx, providerConfig, oauthToken)
 	case "oidc":
-		u, err = h.newUserFromOIDProfile(ctx, oauthToken)
+		u, groups, err = h.newUserFromOIDProfile(ctx, oauthToken)
 	default:
 		err = fmt.Errorf("invalid provider: %s", provider)
 	}
@@ -630,6 +834,27 @@ func (h *Handlers) registerUserWithOauth(
 		return "", err
 	}
 
+	// Register user if needed
+	userID, err := h.registerUserIfNeeded(ctx, u)
+	if err != nil {
+		return "", err
+	}
+
+	// Sync user's organizations memberships from OIDC groups if needed
+	if provider == "oidc" && len(h.oidcGroupsMappings) > 0 {
+		memberships := buildOrganizationsMemberships(h.oidcGroupsMappings, groups)
+		if err := h.userManager.SyncOrganizationsMemberships(ctx, userID, memberships); err != nil {
+			return "", err
+		}
+	}
+
+	return userID, nil
+}
+
+// registerUserIfNeeded is a helper function that registers the user provided,
+// whose identity has already been verified by an external provider, if he's
+// not already registered, returning the user id.
+func (h *Handlers) registerUserIfNeeded(ctx context.Context, u *hub.User) (string, error) {
 	// Check user alias availability and append suffix to it if needed
 	available, err := h.userManager.CheckAvailability(ctx, "userAlias", u.Alias)
 	if err != nil {
@@ -665,6 +890,21 @@ func (h *Handlers) registerUserWithOauth(
 	return userID, nil
 }
 
+// registerLDAPUser is a helper function that registers the user provided,
+// built from his entry in the LDAP directory, returning the user id. Users
+// are not registered when an account using the same email already exists, as
+// LDAP accounts are only linked to existing accounts explicitly.
+func (h *Handlers) registerLDAPUser(ctx context.Context, u *hub.User) (string, error) {
+	_, err := h.userManager.GetUserID(ctx, u.Email)
+	if err == nil {
+		return "", errLDAPAccountNotLinked
+	}
+	if !errors.Is(err, user.ErrNotFound) {
+		return "", err
+	}
+	return h.registerUserIfNeeded(ctx, u)
+}
+
 // newUserFromGitHubProfile builds a new hub.User instance from the user's
 // GitHub profile.
 func (h *Handlers) newUserFromGitHubProfile(
@@ -742,15 +982,16 @@ func (h *Handlers) newUserFromGoogleProfile(
 }
 
 // newUserFromOIDProfile builds a new hub.User instance from the user's OpenID
-// profile.
+// profile. The groups the user belongs to, extracted from the configured
+// groups claim, are returned as well.
 func (h *Handlers) newUserFromOIDProfile(
 	ctx context.Context,
 	oauthToken *oauth2.Token,
-) (*hub.User, error) {
+) (*hub.User, []string, error) {
 	// Extract the id token from oauth token
 	rawIDToken, ok := oauthToken.Extra("id_token").(string)
 	if !ok {
-		return nil, errors.New("id token not available")
+		return nil, nil, errors.New("id token not available")
 	}
 
 	// Parse and verify id token payload
@@ -759,7 +1000,7 @@ func (h *Handlers) newUserFromOIDProfile(
 	})
 	idToken, err := verifier.Verify(ctx, rawIDToken)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id token: %w", err)
+		return nil, nil, fmt.Errorf("invalid id token: %w", err)
 	}
 
 	// Extract claims
@@ -771,23 +1012,37 @@ func (h *Handlers) newUserFromOIDProfile(
 		PreferredUsername string `json:"preferred_username"`
 	}
 	if err := idToken.Claims(&claims); err != nil {
-		return nil, fmt.Errorf("error extracting claims from id token: %w", err)
+		return nil, nil, fmt.Errorf("error extracting claims from id token: %w", err)
 	}
 	skipEmailVerifiedCheck := h.cfg.GetBool("server.oauth.oidc.skipEmailVerifiedCheck")
 	if claims.Email == "" || (!skipEmailVerifiedCheck && !claims.EmailVerified) {
-		return nil, errors.New("no valid email available for use")
+		return nil, nil, errors.New("no valid email available for use")
 	}
 	alias := claims.PreferredUsername
 	if alias == "" {
 		alias = strings.Split(claims.Email, "@")[0]
 	}
 
+	// Extract groups
+	var groups []string
+	if len(h.oidcGroupsMappings) > 0 {
+		var allClaims map[string]interface{}
+		if err := idToken.Claims(&allClaims); err != nil {
+			return nil, nil, fmt.Errorf("error extracting claims from id token: %w", err)
+		}
+		groupsClaim := h.cfg.GetString("server.oauth.oidc.groupsClaim")
+		if groupsClaim == "" {
+			groupsClaim = defaultOIDCGroupsClaim
+		}
+		groups = getOIDCGroups(allClaims, groupsClaim)
+	}
+
 	return &hub.User{
 		Alias:     alias,
 		Email:     claims.Email,
 		FirstName: claims.GivenName,
 		LastName:  claims.FamilyName,
-	}, nil
+	}, groups, nil
 }
 
 // RequireLogin is a middleware that verifies if a user is logged in.
@@ -801,19 +1056,31 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 
 		// Use API key based authentication if API key is provided
 		if apiKeyID != "" && apiKeySecret != "" {
-			// Check the API key provided is valid
-			checkAPIKeyOutput, err := h.apiKeyManager.Check(r.Context(), apiKeyID, apiKeySecret)
-			if err != nil {
-				h.logger.Error().Err(err).Str("method", "RequireLogin").Msg("checkAPIKey failed")
-				helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
-				return
-			}
-			if !checkAPIKeyOutput.Valid {
-				helpers.RenderErrorWithCodeJSON(w, errInvalidAPIKey, http.StatusUnauthorized)
-				return
-			}
+			// Use the result of a previous check of the API key provided when
+			// available (i.e. done by the API rate limiter)
+			userID = getValidAPIKeyUserID(r.Context(), apiKeyID)
+			if userID == "" {
+				// Check if more authentication attempts are allowed
+				account := "apikey:" + apiKeyID
+				if !h.checkAuthAttempts(w, r, "RequireLogin", account) {
+					return
+				}
+
+				// Check the API key provided is valid
+				checkAPIKeyOutput, err := h.apiKeyManager.Check(r.Context(), apiKeyID, apiKeySecret)
+				if err != nil {
+					h.logger.Error().Err(err).Str("method", "RequireLogin").Msg("checkAPIKey failed")
+					helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+					return
+				}
+				if !checkAPIKeyOutput.Valid {
+					h.registerFailedAuthAttempt(r, "RequireLogin", account)
+					helpers.RenderErrorWithCodeJSON(w, errInvalidAPIKey, http.StatusUnauthorized)
+					return
+				}
 
-			userID = checkAPIKeyOutput.UserID
+				userID = checkAPIKeyOutput.UserID
+			}
 		} else {
 			// Use cookie based authentication
 			cookie, err := r.Cookie(sessionCookieName)
@@ -854,6 +1121,38 @@ func (h *Handlers) RequireLogin(next http.Handler) http.Handler {
 	})
 }
 
+// validAPIKeyKey represents the key used to store the details of the valid API
+// key provided in a request inside its context.
+type validAPIKeyKey struct{}
+
+// validAPIKey represents an API key that has already been checked and is
+// valid.
+type validAPIKey struct {
+	apiKeyID string
+	userID   string
+}
+
+// WithValidAPIKey returns a copy of the context provided indicating that the
+// API key provided in the request has already been checked and is valid, so
+// that it doesn't need to be checked again while the request is processed.
+func WithValidAPIKey(ctx context.Context, apiKeyID, userID string) context.Context {
+	return context.WithValue(ctx, validAPIKeyKey{}, &validAPIKey{
+		apiKeyID: apiKeyID,
+		userID:   userID,
+	})
+}
+
+// getValidAPIKeyUserID returns the id of the user owning the API key provided
+// when it has already been checked and is valid. Otherwise it returns an empty
+// string.
+func getValidAPIKeyUserID(ctx context.Context, apiKeyID string) string {
+	v, ok := ctx.Value(validAPIKeyKey{}).(*validAPIKey)
+	if !ok || v.apiKeyID != apiKeyID {
+		return ""
+	}
+	return v.userID
+}
+
 // ResetPassword is an http handler used to reset the user's password.
 func (h *Handlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
 	var input map[string]string
@@ -862,10 +1161,14 @@ func (h *Handlers) ResetPassword(w http.ResponseWriter, r *http.Request) {
 		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
 		return
 	}
+	if !h.checkAuthAttempts(w, r, "ResetPassword", "") {
+		return
+	}
 	err := h.userManager.ResetPassword(r.Context(), input["code"], input["password"])
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "ResetPassword").Send()
 		if errors.Is(err, user.ErrInvalidPasswordResetCode) {
+			h.registerFailedAuthAttempt(r, "ResetPassword", "")
 			helpers.RenderErrorWithCodeJSON(w, err, http.StatusBadRequest)
 		} else {
 			helpers.RenderErrorJSON(w, err)
@@ -956,10 +1259,14 @@ func (h *Handlers) VerifyPasswordResetCode(w http.ResponseWriter, r *http.Reques
 		helpers.RenderErrorJSON(w, hub.ErrInvalidInput)
 		return
 	}
+	if !h.checkAuthAttempts(w, r, "VerifyPasswordResetCode", "") {
+		return
+	}
 	err := h.userManager.VerifyPasswordResetCode(r.Context(), input["code"])
 	if err != nil {
 		h.logger.Error().Err(err).Str("method", "VerifyPasswordResetCode").Send()
 		if errors.Is(err, user.ErrInvalidPasswordResetCode) {
+			h.registerFailedAuthAttempt(r, "VerifyPasswordResetCode", "")
 			helpers.RenderErrorWithCodeJSON(w, err, http.StatusGone)
 		} else {
 			helpers.RenderErrorJSON(w, err)
@@ -997,6 +1304,63 @@ func NewOauthState(s string) (*OauthState, error) {
 	return state, nil
 }
 
+// buildOrganizationsMemberships builds the list of organizations memberships
+// that must be enforced for a user belonging to the groups provided. Only the
+// organizations and roles present in the groups mappings are managed, so the
+// memberships and roles of the user in other organizations are not affected.
+func buildOrganizationsMemberships(
+	mappings []*oidcGroupMapping,
+	groups []string,
+) []*hub.OrganizationMembershipSync {
+	userGroups := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		userGroups[group] = struct{}{}
+	}
+
+	var memberships []*hub.OrganizationMembershipSync
+	membershipsByOrg := make(map[string]*hub.OrganizationMembershipSync)
+	for _, gm := range mappings {
+		ms, ok := membershipsByOrg[gm.Organization]
+		if !ok {
+			ms = &hub.OrganizationMembershipSync{
+				OrganizationName: gm.Organization,
+				Roles:            make(map[string]bool),
+			}
+			membershipsByOrg[gm.Organization] = ms
+			memberships = append(memberships, ms)
+		}
+		_, inGroup := userGroups[gm.Group]
+		if inGroup {
+			ms.Member = true
+		}
+		if gm.Role != "" {
+			ms.Roles[gm.Role] = ms.Roles[gm.Role] || inGroup
+		}
+	}
+
+	return memberships
+}
+
+// getOIDCGroups extracts the groups from the claim provided. The claim value
+// is expected to be a list of strings, although a single string value is also
+// supported.
+func getOIDCGroups(claims map[string]interface{}, claim string) []string {
+	var groups []string
+	switch v := claims[claim].(type) {
+	case string:
+		if v != "" {
+			groups = append(groups, v)
+		}
+	case []interface{}:
+		for _, entry := range v {
+			if group, ok := entry.(string); ok && group != "" {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
 // getRandomSuffix is a helper function that returns a random numerical suffix
 // to be used in user aliases when the selected alias is already taken.
 func getRandomSuffix() (string, error) {
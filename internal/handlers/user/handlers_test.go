@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,9 +17,11 @@ import (
 	"github.com/artifacthub/hub/internal/apikey"
 	"github.com/artifacthub/hub/internal/handlers/helpers"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/ratelimit"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/user"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-ldap/ldap/v3"
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -167,6 +170,77 @@ func TestBasicAuth(t *testing.T) {
 	})
 }
 
+func TestBuildOrganizationsMemberships(t *testing.T) {
+	mappings := []*oidcGroupMapping{
+		{Group: "devs", Organization: "org1"},
+		{Group: "admins", Organization: "org1", Role: "owner"},
+		{Group: "admins", Organization: "org2", Role: "admin"},
+		{Group: "auditors", Organization: "org2", Role: "viewer"},
+	}
+
+	testCases := []struct {
+		desc                string
+		groups              []string
+		expectedMemberships []*hub.OrganizationMembershipSync
+	}{
+		{
+			"user does not belong to any of the mapped groups",
+			[]string{"other"},
+			[]*hub.OrganizationMembershipSync{
+				{
+					OrganizationName: "org1",
+					Member:           false,
+					Roles:            map[string]bool{"owner": false},
+				},
+				{
+					OrganizationName: "org2",
+					Member:           false,
+					Roles:            map[string]bool{"admin": false, "viewer": false},
+				},
+			},
+		},
+		{
+			"user belongs to a group granting membership only",
+			[]string{"devs"},
+			[]*hub.OrganizationMembershipSync{
+				{
+					OrganizationName: "org1",
+					Member:           true,
+					Roles:            map[string]bool{"owner": false},
+				},
+				{
+					OrganizationName: "org2",
+					Member:           false,
+					Roles:            map[string]bool{"admin": false, "viewer": false},
+				},
+			},
+		},
+		{
+			"user belongs to several groups granting roles",
+			[]string{"admins", "auditors"},
+			[]*hub.OrganizationMembershipSync{
+				{
+					OrganizationName: "org1",
+					Member:           true,
+					Roles:            map[string]bool{"owner": true},
+				},
+				{
+					OrganizationName: "org2",
+					Member:           true,
+					Roles:            map[string]bool{"admin": true, "viewer": true},
+				},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			memberships := buildOrganizationsMemberships(mappings, tc.groups)
+			assert.Equal(t, tc.expectedMemberships, memberships)
+		})
+	}
+}
+
 func TestCheckPasswordStrength(t *testing.T) {
 	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
@@ -522,6 +596,86 @@ func TestEnableTFA(t *testing.T) {
 	})
 }
 
+func TestGetAuditLog(t *testing.T) {
+	input := &hub.GetAuditLogInput{
+		Actions: []hub.AuditAction{hub.AuditActionLogin},
+		Limit:   10,
+		Offset:  1,
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?limit=aa", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.h.GetAuditLog(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("error getting user audit log", func(t *testing.T) {
+		testCases := []struct {
+			umErr              error
+			expectedStatusCode int
+		}{
+			{
+				hub.ErrInvalidInput,
+				http.StatusBadRequest,
+			},
+			{
+				tests.ErrFakeDB,
+				http.StatusInternalServerError,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.umErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				w := httptest.NewRecorder()
+				r, _ := http.NewRequest("GET", "/?action=login&limit=10&offset=1", nil)
+				r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+				hw := newHandlersWrapper()
+				hw.um.On("GetAuditLogJSON", r.Context(), input).Return(nil, tc.umErr)
+				hw.h.GetAuditLog(w, r)
+				resp := w.Result()
+				defer resp.Body.Close()
+
+				assert.Equal(t, tc.expectedStatusCode, resp.StatusCode)
+				hw.um.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("get user audit log succeeded", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("GET", "/?action=login&limit=10&offset=1", nil)
+		r = r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+
+		hw := newHandlersWrapper()
+		hw.um.On("GetAuditLogJSON", r.Context(), input).Return(&hub.JSONQueryResult{
+			Data:       []byte("dataJSON"),
+			TotalCount: 1,
+		}, nil)
+		hw.h.GetAuditLog(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, h.Get(helpers.PaginationTotalCount), "1")
+		assert.Equal(t, "application/json", h.Get("Content-Type"))
+		assert.Equal(t, helpers.BuildCacheControlHeader(0), h.Get("Cache-Control"))
+		assert.Equal(t, []byte("dataJSON"), data)
+		hw.um.AssertExpectations(t)
+	})
+}
+
 func TestGetProfile(t *testing.T) {
 	t.Run("error getting profile", func(t *testing.T) {
 		t.Parallel()
@@ -561,6 +715,41 @@ func TestGetProfile(t *testing.T) {
 	})
 }
 
+func TestGetOIDCGroups(t *testing.T) {
+	testCases := []struct {
+		desc           string
+		claims         map[string]interface{}
+		expectedGroups []string
+	}{
+		{
+			"claim not present",
+			map[string]interface{}{},
+			nil,
+		},
+		{
+			"claim is a list",
+			map[string]interface{}{"groups": []interface{}{"group1", "", 1, "group2"}},
+			[]string{"group1", "group2"},
+		},
+		{
+			"claim is a string",
+			map[string]interface{}{"groups": "group1"},
+			[]string{"group1"},
+		},
+		{
+			"claim has an unexpected type",
+			map[string]interface{}{"groups": map[string]interface{}{}},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedGroups, getOIDCGroups(tc.claims, "groups"))
+		})
+	}
+}
+
 func TestInjectUserID(t *testing.T) {
 	sessionID := "sessionID"
 
@@ -693,7 +882,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "", "").Return(nil, hub.ErrInvalidInput)
+		hw.um.On("CheckCredentials", r.Context(), "", "", "").Return(nil, hub.ErrInvalidInput)
 		hw.h.Login(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -708,7 +897,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "email", "pass").Return(nil, tests.ErrFakeDB)
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass", "").Return(nil, tests.ErrFakeDB)
 		hw.h.Login(w, r)
 		resp := w.Result()
 		defer resp.Body.Close()
@@ -724,7 +913,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "email", "pass2").
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass2", "").
 			Return(&hub.CheckCredentialsOutput{Valid: false, UserID: ""}, nil)
 		hw.h.Login(w, r)
 		resp := w.Result()
@@ -741,7 +930,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "email", "pass").
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass", "").
 			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
 		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
 			Return(nil, tests.ErrFakeDB)
@@ -760,7 +949,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "email", "pass").
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass", "").
 			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
 		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
 			Return(&hub.Session{
@@ -794,7 +983,7 @@ func TestLogin(t *testing.T) {
 		r, _ := http.NewRequest("POST", "/", body)
 
 		hw := newHandlersWrapper()
-		hw.um.On("CheckCredentials", r.Context(), "email", "pass").
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass", "").
 			Return(&hub.CheckCredentialsOutput{Valid: true, UserID: "userID"}, nil)
 		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
 			Return(&hub.Session{
@@ -822,6 +1011,301 @@ func TestLogin(t *testing.T) {
 	})
 }
 
+func TestLoginLDAP(t *testing.T) {
+	sessionID := "sessionID"
+	dn := "uid=user1,dc=example,dc=com"
+	setupLDAP := func(hw *handlersWrapper) {
+		hw.h.ldap = &ldapAuthenticator{
+			url:              "ldap://ldap.example.com",
+			userSearchBase:   "dc=example,dc=com",
+			userSearchFilter: ldapDefaultUserSearchFilter,
+			aliasAttr:        ldapDefaultAliasAttribute,
+			emailAttr:        ldapDefaultEmailAttribute,
+			firstNameAttr:    ldapDefaultFirstNameAttr,
+			lastNameAttr:     ldapDefaultLastNameAttr,
+			dial: func(url string, tlsConfig *tls.Config) (ldapConn, error) {
+				return &ldapStandIn{
+					entries: []*ldap.Entry{
+						{
+							DN: dn,
+							Attributes: []*ldap.EntryAttribute{
+								{Name: "uid", Values: []string{"user1"}},
+								{Name: "mail", Values: []string{"user1@example.com"}},
+							},
+						},
+					},
+					passwords: map[string]string{
+						dn: "pass",
+					},
+				}, nil
+			},
+		}
+	}
+	u := &hub.User{
+		Alias:         "user1",
+		Email:         "user1@example.com",
+		EmailVerified: true,
+		LDAPDN:        dn,
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1" ...`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid credentials provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "invalid"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("error getting user linked to ldap account", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", tests.ErrFakeDB)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("account with same email exists but ldap account not linked", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", user.ErrNotFound)
+		hw.um.On("GetUserID", r.Context()).Return("userID", nil)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, string(data), errLDAPAccountNotLinked.Error())
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("error registering user", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", user.ErrNotFound)
+		hw.um.On("GetUserID", r.Context()).Return("", user.ErrNotFound).Twice()
+		hw.um.On("CheckAvailability", r.Context(), "userAlias", "user1").Return(true, nil)
+		hw.um.On("RegisterUser", r.Context(), u).Return(tests.ErrFakeDB)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("login succeeded (user registered on first login)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", user.ErrNotFound)
+		hw.um.On("GetUserID", r.Context()).Return("", user.ErrNotFound).Twice()
+		hw.um.On("CheckAvailability", r.Context(), "userAlias", "user1").Return(true, nil)
+		hw.um.On("RegisterUser", r.Context(), u).Return(nil)
+		hw.um.On("GetUserID", r.Context()).Return("userID", nil).Once()
+		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
+			Return(&hub.Session{
+				SessionID: sessionID,
+				Approved:  true,
+			}, nil)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		require.Len(t, resp.Cookies(), 1)
+		cookie := resp.Cookies()[0]
+		assert.Equal(t, sessionCookieName, cookie.Name)
+		var cookieSessionID string
+		err := hw.h.sc.Decode(sessionCookieName, cookie.Value, &cookieSessionID)
+		require.NoError(t, err)
+		assert.Equal(t, sessionID, cookieSessionID)
+		assert.Equal(t, "true", resp.Header.Get(SessionApprovedHeader))
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("login succeeded (ldap account already linked)", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		body := strings.NewReader(`{"username": "user1", "password": "pass"}`)
+		r, _ := http.NewRequest("POST", "/", body)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("userID", nil)
+		hw.um.On("RegisterSession", r.Context(), &hub.Session{UserID: "userID"}).
+			Return(&hub.Session{
+				SessionID: sessionID,
+				Approved:  false,
+			}, nil)
+		hw.h.LoginLDAP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "false", resp.Header.Get(SessionApprovedHeader))
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestLinkLDAPAccount(t *testing.T) {
+	dn := "uid=user1,dc=example,dc=com"
+	setupLDAP := func(hw *handlersWrapper) {
+		hw.h.ldap = &ldapAuthenticator{
+			url:              "ldap://ldap.example.com",
+			userSearchBase:   "dc=example,dc=com",
+			userSearchFilter: ldapDefaultUserSearchFilter,
+			aliasAttr:        ldapDefaultAliasAttribute,
+			emailAttr:        ldapDefaultEmailAttribute,
+			firstNameAttr:    ldapDefaultFirstNameAttr,
+			lastNameAttr:     ldapDefaultLastNameAttr,
+			dial: func(url string, tlsConfig *tls.Config) (ldapConn, error) {
+				return &ldapStandIn{
+					entries: []*ldap.Entry{
+						{
+							DN: dn,
+							Attributes: []*ldap.EntryAttribute{
+								{Name: "uid", Values: []string{"user1"}},
+								{Name: "mail", Values: []string{"user1@example.com"}},
+							},
+						},
+					},
+					passwords: map[string]string{
+						dn: "pass",
+					},
+				}, nil
+			},
+		}
+	}
+	newRequest := func(body string) *http.Request {
+		r, _ := http.NewRequest("PUT", "/", strings.NewReader(body))
+		return r.WithContext(context.WithValue(r.Context(), hub.UserIDKey, "userID"))
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(`{"username": "user1" ...`)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.h.LinkLDAPAccount(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid credentials provided", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(`{"username": "user1", "password": "invalid"}`)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.h.LinkLDAPAccount(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("ldap account linked to a different user", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(`{"username": "user1", "password": "pass"}`)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("userID2", nil)
+		hw.h.LinkLDAPAccount(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("error linking ldap account", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(`{"username": "user1", "password": "pass"}`)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", user.ErrNotFound)
+		hw.um.On("LinkLDAPAccount", r.Context(), dn).Return(tests.ErrFakeDB)
+		hw.h.LinkLDAPAccount(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("ldap account linked successfully", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := newRequest(`{"username": "user1", "password": "pass"}`)
+
+		hw := newHandlersWrapper()
+		setupLDAP(hw)
+		hw.um.On("GetUserIDFromLDAPDN", r.Context(), dn).Return("", user.ErrNotFound)
+		hw.um.On("LinkLDAPAccount", r.Context(), dn).Return(nil)
+		hw.h.LinkLDAPAccount(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.um.AssertExpectations(t)
+	})
+}
+
 func TestLogout(t *testing.T) {
 	t.Run("invalid or no session cookie provided", func(t *testing.T) {
 		testCases := []struct {
@@ -1337,6 +1821,45 @@ func TestRequireLogin(t *testing.T) {
 			assert.Equal(t, http.StatusOK, resp.StatusCode)
 			hw.um.AssertExpectations(t)
 		})
+
+		t.Run("api key already checked and valid", func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Add(APIKeyIDHeader, apiKeyID)
+			r.Header.Add(APIKeySecretHeader, apiKeySecret)
+			r = r.WithContext(WithValidAPIKey(r.Context(), apiKeyID, "userID"))
+
+			hw := newHandlersWrapper()
+			next := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "userID", r.Context().Value(hub.UserIDKey))
+			}
+			hw.h.RequireLogin(http.HandlerFunc(next)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			hw.am.AssertExpectations(t)
+		})
+
+		t.Run("api key checked does not match the one provided", func(t *testing.T) {
+			t.Parallel()
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest("GET", "/", nil)
+			r.Header.Add(APIKeyIDHeader, apiKeyID)
+			r.Header.Add(APIKeySecretHeader, apiKeySecret)
+			r = r.WithContext(WithValidAPIKey(r.Context(), "otherKeyID", "userID"))
+
+			hw := newHandlersWrapper()
+			hw.am.On("Check", r.Context(), apiKeyID, apiKeySecret).
+				Return(&hub.CheckAPIKeyOutput{UserID: "", Valid: false}, nil)
+			hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+			resp := w.Result()
+			defer resp.Body.Close()
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			hw.am.AssertExpectations(t)
+		})
 	})
 
 	t.Run("session cookie based authentication", func(t *testing.T) {
@@ -1829,6 +2352,7 @@ type handlersWrapper struct {
 	cfg *viper.Viper
 	um  *user.ManagerMock
 	am  *apikey.ManagerMock
+	rl  *ratelimit.LimiterMock
 	h   *Handlers
 }
 
@@ -1841,12 +2365,14 @@ func newHandlersWrapper() *handlersWrapper {
 
 	um := &user.ManagerMock{}
 	am := &apikey.ManagerMock{}
-	h, _ := NewHandlers(context.Background(), um, am, cfg)
+	rl := &ratelimit.LimiterMock{}
+	h, _ := NewHandlers(context.Background(), um, am, rl, cfg)
 
 	return &handlersWrapper{
 		cfg: cfg,
 		um:  um,
 		am:  am,
+		rl:  rl,
 		h:   h,
 	}
 }
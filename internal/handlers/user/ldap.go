@@ -0,0 +1,203 @@
+package user
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/spf13/viper"
+)
+
+const (
+	ldapDefaultUserSearchFilter = "(uid={username})"
+	ldapDefaultAliasAttribute   = "uid"
+	ldapDefaultEmailAttribute   = "mail"
+	ldapDefaultFirstNameAttr    = "givenName"
+	ldapDefaultLastNameAttr     = "sn"
+	ldapTimeout                 = 10 * time.Second
+	ldapUsernamePlaceholder     = "{username}"
+)
+
+var (
+	// errInvalidLDAPCredentials indicates that the credentials provided to
+	// authenticate against the LDAP server are not valid.
+	errInvalidLDAPCredentials = errors.New("invalid ldap credentials")
+
+	// errLDAPAccountNotLinked indicates that an account using the same email
+	// as the LDAP account already exists, but the LDAP account hasn't been
+	// linked to it.
+	errLDAPAccountNotLinked = errors.New("an account with the same email already exists: please log in and link your ldap account to it")
+)
+
+// ldapConn describes the methods of an LDAP connection used by the LDAP
+// authenticator. It is satisfied by *ldap.Conn, but it allows using a local
+// LDAP stand-in in tests.
+type ldapConn interface {
+	Bind(username, password string) error
+	Close() error
+	Search(searchRequest *ldap.SearchRequest) (*ldap.SearchResult, error)
+	StartTLS(config *tls.Config) error
+}
+
+// ldapAuthenticator authenticates users against an LDAP directory using the
+// search and bind approach: the entry of the user is searched first (using
+// the service account credentials when provided) and then a bind operation is
+// performed using the entry's DN and the password provided by the user.
+type ldapAuthenticator struct {
+	url                string
+	startTLS           bool
+	insecureSkipVerify bool
+	bindDN             string
+	bindPassword       string
+	userSearchBase     string
+	userSearchFilter   string
+	aliasAttr          string
+	emailAttr          string
+	firstNameAttr      string
+	lastNameAttr       string
+	dial               func(url string, tlsConfig *tls.Config) (ldapConn, error)
+}
+
+// newLDAPAuthenticator creates a new ldapAuthenticator instance from the
+// configuration provided.
+func newLDAPAuthenticator(cfg *viper.Viper) (*ldapAuthenticator, error) {
+	a := &ldapAuthenticator{
+		url:                cfg.GetString("server.ldap.url"),
+		startTLS:           cfg.GetBool("server.ldap.startTLS"),
+		insecureSkipVerify: cfg.GetBool("server.ldap.insecureSkipVerify"),
+		bindDN:             cfg.GetString("server.ldap.bindDN"),
+		bindPassword:       cfg.GetString("server.ldap.bindPassword"),
+		userSearchBase:     cfg.GetString("server.ldap.userSearchBase"),
+		userSearchFilter:   cfg.GetString("server.ldap.userSearchFilter"),
+		aliasAttr:          cfg.GetString("server.ldap.attributes.alias"),
+		emailAttr:          cfg.GetString("server.ldap.attributes.email"),
+		firstNameAttr:      cfg.GetString("server.ldap.attributes.firstName"),
+		lastNameAttr:       cfg.GetString("server.ldap.attributes.lastName"),
+		dial:               dialLDAP,
+	}
+
+	// Validate configuration and set defaults
+	u, err := url.Parse(a.url)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		return nil, errors.New("invalid ldap url: a valid ldap:// or ldaps:// url must be provided")
+	}
+	if a.startTLS && u.Scheme == "ldaps" {
+		return nil, errors.New("invalid ldap configuration: startTLS cannot be used with ldaps")
+	}
+	if a.userSearchBase == "" {
+		return nil, errors.New("invalid ldap configuration: user search base not provided")
+	}
+	if a.userSearchFilter == "" {
+		a.userSearchFilter = ldapDefaultUserSearchFilter
+	}
+	if !strings.Contains(a.userSearchFilter, ldapUsernamePlaceholder) {
+		return nil, fmt.Errorf("invalid ldap configuration: user search filter must contain %s", ldapUsernamePlaceholder)
+	}
+	if a.aliasAttr == "" {
+		a.aliasAttr = ldapDefaultAliasAttribute
+	}
+	if a.emailAttr == "" {
+		a.emailAttr = ldapDefaultEmailAttribute
+	}
+	if a.firstNameAttr == "" {
+		a.firstNameAttr = ldapDefaultFirstNameAttr
+	}
+	if a.lastNameAttr == "" {
+		a.lastNameAttr = ldapDefaultLastNameAttr
+	}
+
+	return a, nil
+}
+
+// authenticate checks the credentials provided against the LDAP directory,
+// returning a hub.User instance built from the user's entry attributes when
+// they are valid.
+func (a *ldapAuthenticator) authenticate(username, password string) (*hub.User, error) {
+	// Validate input. Empty passwords must be rejected explicitly, as most
+	// LDAP servers treat them as unauthenticated binds that always succeed.
+	if username == "" || password == "" {
+		return nil, errInvalidLDAPCredentials
+	}
+
+	// Setup connection
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: a.insecureSkipVerify, // #nosec
+		MinVersion:         tls.VersionTLS12,
+	}
+	conn, err := a.dial(a.url, tlsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to ldap server: %w", err)
+	}
+	defer conn.Close()
+	if a.startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			return nil, fmt.Errorf("error starting tls: %w", err)
+		}
+	}
+
+	// Search user entry
+	if a.bindDN != "" {
+		if err := conn.Bind(a.bindDN, a.bindPassword); err != nil {
+			return nil, fmt.Errorf("error binding with service account: %w", err)
+		}
+	}
+	filter := strings.ReplaceAll(a.userSearchFilter, ldapUsernamePlaceholder, ldap.EscapeFilter(username))
+	sr, err := conn.Search(ldap.NewSearchRequest(
+		a.userSearchBase,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		2,
+		int(ldapTimeout.Seconds()),
+		false,
+		filter,
+		[]string{"dn", a.aliasAttr, a.emailAttr, a.firstNameAttr, a.lastNameAttr},
+		nil,
+	))
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("error searching user entry: %w", err)
+	}
+	if sr == nil || len(sr.Entries) != 1 {
+		return nil, errInvalidLDAPCredentials
+	}
+	entry := sr.Entries[0]
+
+	// Bind as the user to verify the password provided
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errInvalidLDAPCredentials
+		}
+		return nil, fmt.Errorf("error binding as user: %w", err)
+	}
+
+	// Build user from entry attributes
+	u := &hub.User{
+		Alias:     entry.GetAttributeValue(a.aliasAttr),
+		Email:     entry.GetAttributeValue(a.emailAttr),
+		FirstName: entry.GetAttributeValue(a.firstNameAttr),
+		LastName:  entry.GetAttributeValue(a.lastNameAttr),
+		LDAPDN:    entry.DN,
+	}
+	if u.Email == "" {
+		return nil, errors.New("no valid email available for use")
+	}
+	if u.Alias == "" {
+		u.Alias = strings.Split(u.Email, "@")[0]
+	}
+
+	return u, nil
+}
+
+// dialLDAP connects to the LDAP server at the url provided.
+func dialLDAP(url string, tlsConfig *tls.Config) (ldapConn, error) {
+	conn, err := ldap.DialURL(url, ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(ldapTimeout)
+	return conn, nil
+}
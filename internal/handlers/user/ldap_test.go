@@ -0,0 +1,266 @@
+package user
+
+import (
+	"crypto/tls"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLDAPAuthenticator(t *testing.T) {
+	t.Run("invalid configuration", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			cfg    map[string]interface{}
+		}{
+			{
+				"invalid ldap url",
+				map[string]interface{}{
+					"url":            "http://ldap.example.com",
+					"userSearchBase": "ou=people,dc=example,dc=com",
+				},
+			},
+			{
+				"startTLS cannot be used with ldaps",
+				map[string]interface{}{
+					"url":            "ldaps://ldap.example.com",
+					"startTLS":       true,
+					"userSearchBase": "ou=people,dc=example,dc=com",
+				},
+			},
+			{
+				"user search base not provided",
+				map[string]interface{}{
+					"url": "ldap://ldap.example.com",
+				},
+			},
+			{
+				"user search filter must contain {username}",
+				map[string]interface{}{
+					"url":              "ldap://ldap.example.com",
+					"userSearchBase":   "ou=people,dc=example,dc=com",
+					"userSearchFilter": "(uid=user)",
+				},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				cfg := viper.New()
+				cfg.Set("server.ldap", tc.cfg)
+				_, err := newLDAPAuthenticator(cfg)
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("defaults are set when not provided", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("server.ldap.url", "ldap://ldap.example.com")
+		cfg.Set("server.ldap.userSearchBase", "ou=people,dc=example,dc=com")
+		a, err := newLDAPAuthenticator(cfg)
+		require.NoError(t, err)
+		assert.Equal(t, ldapDefaultUserSearchFilter, a.userSearchFilter)
+		assert.Equal(t, ldapDefaultAliasAttribute, a.aliasAttr)
+		assert.Equal(t, ldapDefaultEmailAttribute, a.emailAttr)
+		assert.Equal(t, ldapDefaultFirstNameAttr, a.firstNameAttr)
+		assert.Equal(t, ldapDefaultLastNameAttr, a.lastNameAttr)
+	})
+}
+
+func TestLDAPAuthenticate(t *testing.T) {
+	user1 := &ldap.Entry{
+		DN: "uid=user1,ou=people,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{"user1"}},
+			{Name: "mail", Values: []string{"user1@example.com"}},
+			{Name: "givenName", Values: []string{"first"}},
+			{Name: "sn", Values: []string{"last"}},
+		},
+	}
+	user2 := &ldap.Entry{
+		DN: "uid=user2,ou=people,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{"user2"}},
+		},
+	}
+	user3a := &ldap.Entry{
+		DN: "uid=user3,ou=people,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{"user3"}},
+		},
+	}
+	user3b := &ldap.Entry{
+		DN: "uid=user3,ou=others,dc=example,dc=com",
+		Attributes: []*ldap.EntryAttribute{
+			{Name: "uid", Values: []string{"user3"}},
+		},
+	}
+	newDirectory := func() *ldapStandIn {
+		return &ldapStandIn{
+			entries: []*ldap.Entry{user1, user2, user3a, user3b},
+			passwords: map[string]string{
+				"cn=admin,dc=example,dc=com": "adminPass",
+				user1.DN:                     "user1Pass",
+				user2.DN:                     "user2Pass",
+			},
+		}
+	}
+	newAuthenticator := func(d *ldapStandIn) *ldapAuthenticator {
+		cfg := viper.New()
+		cfg.Set("server.ldap.url", "ldap://ldap.example.com")
+		cfg.Set("server.ldap.startTLS", true)
+		cfg.Set("server.ldap.bindDN", "cn=admin,dc=example,dc=com")
+		cfg.Set("server.ldap.bindPassword", "adminPass")
+		cfg.Set("server.ldap.userSearchBase", "dc=example,dc=com")
+		a, _ := newLDAPAuthenticator(cfg)
+		a.dial = func(url string, tlsConfig *tls.Config) (ldapConn, error) {
+			if d == nil {
+				return nil, tests.ErrFake
+			}
+			return d, nil
+		}
+		return a
+	}
+
+	t.Run("username or password not provided", func(t *testing.T) {
+		t.Parallel()
+		a := newAuthenticator(newDirectory())
+		_, err := a.authenticate("user1", "")
+		assert.True(t, errors.Is(err, errInvalidLDAPCredentials))
+		_, err = a.authenticate("", "user1Pass")
+		assert.True(t, errors.Is(err, errInvalidLDAPCredentials))
+	})
+
+	t.Run("error connecting to ldap server", func(t *testing.T) {
+		t.Parallel()
+		a := newAuthenticator(nil)
+		_, err := a.authenticate("user1", "user1Pass")
+		assert.True(t, errors.Is(err, tests.ErrFake))
+	})
+
+	t.Run("error starting tls", func(t *testing.T) {
+		t.Parallel()
+		d := newDirectory()
+		d.startTLSErr = tests.ErrFake
+		a := newAuthenticator(d)
+		_, err := a.authenticate("user1", "user1Pass")
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.True(t, d.closed)
+	})
+
+	t.Run("error binding with service account", func(t *testing.T) {
+		t.Parallel()
+		d := newDirectory()
+		a := newAuthenticator(d)
+		a.bindPassword = "invalid"
+		_, err := a.authenticate("user1", "user1Pass")
+		assert.Error(t, err)
+		assert.False(t, errors.Is(err, errInvalidLDAPCredentials))
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		testCases := []struct {
+			desc     string
+			username string
+			password string
+		}{
+			{"user not found", "user9", "pass"},
+			{"filter injection attempt", "*", "pass"},
+			{"several entries found", "user3", "pass"},
+			{"invalid password", "user1", "invalid"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				t.Parallel()
+				a := newAuthenticator(newDirectory())
+				_, err := a.authenticate(tc.username, tc.password)
+				assert.True(t, errors.Is(err, errInvalidLDAPCredentials))
+			})
+		}
+	})
+
+	t.Run("entry has no email", func(t *testing.T) {
+		t.Parallel()
+		a := newAuthenticator(newDirectory())
+		_, err := a.authenticate("user2", "user2Pass")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no valid email available for use")
+	})
+
+	t.Run("valid credentials", func(t *testing.T) {
+		t.Parallel()
+		d := newDirectory()
+		a := newAuthenticator(d)
+		u, err := a.authenticate("user1", "user1Pass")
+		require.NoError(t, err)
+		assert.Equal(t, &hub.User{
+			Alias:     "user1",
+			Email:     "user1@example.com",
+			FirstName: "first",
+			LastName:  "last",
+			LDAPDN:    "uid=user1,ou=people,dc=example,dc=com",
+		}, u)
+		assert.True(t, d.tlsStarted)
+		assert.True(t, d.closed)
+	})
+}
+
+// ldapStandIn is a minimal in-memory LDAP directory used in tests. Only
+// equality filters in the form (attribute=value) are supported.
+type ldapStandIn struct {
+	entries     []*ldap.Entry
+	passwords   map[string]string
+	startTLSErr error
+	tlsStarted  bool
+	closed      bool
+}
+
+func (d *ldapStandIn) Bind(username, password string) error {
+	if p, ok := d.passwords[username]; ok && p == password {
+		return nil
+	}
+	return ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (d *ldapStandIn) Close() error {
+	d.closed = true
+	return nil
+}
+
+func (d *ldapStandIn) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	filter := strings.TrimSuffix(strings.TrimPrefix(req.Filter, "("), ")")
+	attr, value, ok := strings.Cut(filter, "=")
+	if !ok {
+		return nil, ldap.NewError(ldap.LDAPResultFilterError, errors.New("unsupported filter"))
+	}
+	sr := &ldap.SearchResult{}
+	for _, e := range d.entries {
+		if !strings.HasSuffix(e.DN, req.BaseDN) {
+			continue
+		}
+		for _, v := range e.GetAttributeValues(attr) {
+			if v == value {
+				sr.Entries = append(sr.Entries, e)
+			}
+		}
+	}
+	return sr, nil
+}
+
+func (d *ldapStandIn) StartTLS(config *tls.Config) error {
+	if d.startTLSErr != nil {
+		return d.startTLSErr
+	}
+	d.tlsStarted = true
+	return nil
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/artifacthub/hub/internal/handlers/helpers"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+)
+
+const (
+	// Default values used when the auth rate limiting configuration does not
+	// provide them.
+	defaultAuthRateLimitWindow         = 15 * time.Minute
+	defaultMaxFailedAttemptsPerIP      = 50
+	defaultMaxFailedAttemptsPerAccount = 10
+)
+
+// errTooManyAuthAttempts indicates that too many failed authentication
+// attempts have been made recently.
+var errTooManyAuthAttempts = errors.New("too many failed authentication attempts, please try again later")
+
+// authRateLimiter limits the number of failed authentication attempts that can
+// be made from a given IP address, as well as against a given account from a
+// given IP address, in a sliding window of time. Attempts against an account
+// are counted per IP address so that they cannot be used to lock legitimate
+// users out of their accounts from other locations.
+type authRateLimiter struct {
+	rl                          hub.RateLimiter
+	window                      time.Duration
+	maxFailedAttemptsPerIP      int
+	maxFailedAttemptsPerAccount int
+}
+
+// newAuthRateLimiter creates a new authRateLimiter instance from the
+// configuration provided.
+func newAuthRateLimiter(rl hub.RateLimiter, cfg *viper.Viper) *authRateLimiter {
+	l := &authRateLimiter{
+		rl:                          rl,
+		window:                      cfg.GetDuration("server.rateLimit.auth.window"),
+		maxFailedAttemptsPerIP:      cfg.GetInt("server.rateLimit.auth.maxFailedAttemptsPerIP"),
+		maxFailedAttemptsPerAccount: cfg.GetInt("server.rateLimit.auth.maxFailedAttemptsPerAccount"),
+	}
+	if l.window <= 0 {
+		l.window = defaultAuthRateLimitWindow
+	}
+	if l.maxFailedAttemptsPerIP <= 0 {
+		l.maxFailedAttemptsPerIP = defaultMaxFailedAttemptsPerIP
+	}
+	if l.maxFailedAttemptsPerAccount <= 0 {
+		l.maxFailedAttemptsPerAccount = defaultMaxFailedAttemptsPerAccount
+	}
+	return l
+}
+
+// rateLimitKey represents a key used to count failed authentication attempts
+// and the maximum number of attempts allowed for it.
+type rateLimitKey struct {
+	key   string
+	limit int
+}
+
+// keys returns the rate limit keys that apply to the request and account
+// provided. The account is optional, and when provided it must be prefixed
+// with the kind of identifier used (i.e. email:user@example.com).
+func (l *authRateLimiter) keys(r *http.Request, account string) []rateLimitKey {
+	ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+	if ip == "" {
+		return nil
+	}
+	keys := []rateLimitKey{
+		{
+			key:   "auth:ip:" + ip,
+			limit: l.maxFailedAttemptsPerIP,
+		},
+	}
+	if account != "" {
+		keys = append(keys, rateLimitKey{
+			key:   "auth:account:" + ip + ":" + strings.ToLower(account),
+			limit: l.maxFailedAttemptsPerAccount,
+		})
+	}
+	return keys
+}
+
+// checkAuthAttempts checks if more authentication attempts are allowed for
+// the request and account provided. When they aren't, an error response is
+// rendered and false is returned.
+func (h *Handlers) checkAuthAttempts(w http.ResponseWriter, r *http.Request, method, account string) bool {
+	if h.authRL == nil {
+		return true
+	}
+	for _, k := range h.authRL.keys(r, account) {
+		s, err := h.authRL.rl.Check(r.Context(), k.key, k.limit, h.authRL.window)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", method).Msg("checkRateLimit failed")
+			helpers.RenderErrorWithCodeJSON(w, nil, http.StatusInternalServerError)
+			return false
+		}
+		if !s.Allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(s.Reset))
+			helpers.RenderErrorWithCodeJSON(w, errTooManyAuthAttempts, http.StatusTooManyRequests)
+			return false
+		}
+	}
+	return true
+}
+
+// registerFailedAuthAttempt registers a failed authentication attempt for the
+// request and account provided.
+func (h *Handlers) registerFailedAuthAttempt(r *http.Request, method, account string) {
+	if h.authRL == nil {
+		return
+	}
+	for _, k := range h.authRL.keys(r, account) {
+		if _, err := h.authRL.rl.Hit(r.Context(), k.key, k.limit, h.authRL.window); err != nil {
+			h.logger.Error().Err(err).Str("method", method).Msg("registerRateLimitHit failed")
+		}
+	}
+}
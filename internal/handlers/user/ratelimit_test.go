@@ -0,0 +1,318 @@
+package user
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/apikey"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/ratelimit"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/user"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testsIPKey        = "auth:ip:192.0.2.1"
+	testsIPLimit      = 5
+	testsAccountLimit = 3
+	testsRateWindow   = time.Minute
+)
+
+func TestNewAuthRateLimiter(t *testing.T) {
+	t.Run("default values", func(t *testing.T) {
+		t.Parallel()
+		l := newAuthRateLimiter(&ratelimit.LimiterMock{}, viper.New())
+		assert.Equal(t, defaultAuthRateLimitWindow, l.window)
+		assert.Equal(t, defaultMaxFailedAttemptsPerIP, l.maxFailedAttemptsPerIP)
+		assert.Equal(t, defaultMaxFailedAttemptsPerAccount, l.maxFailedAttemptsPerAccount)
+	})
+
+	t.Run("rate limiter disabled", func(t *testing.T) {
+		t.Parallel()
+		hw := newHandlersWrapper()
+		assert.Nil(t, hw.h.authRL)
+	})
+
+	t.Run("rate limiter enabled", func(t *testing.T) {
+		t.Parallel()
+		hw := newRateLimitedHandlersWrapper()
+		require.NotNil(t, hw.h.authRL)
+		assert.Equal(t, testsRateWindow, hw.h.authRL.window)
+		assert.Equal(t, testsIPLimit, hw.h.authRL.maxFailedAttemptsPerIP)
+		assert.Equal(t, testsAccountLimit, hw.h.authRL.maxFailedAttemptsPerAccount)
+	})
+}
+
+func TestAuthRateLimiterKeys(t *testing.T) {
+	l := newAuthRateLimiter(&ratelimit.LimiterMock{}, viper.New())
+
+	t.Run("ip and account", func(t *testing.T) {
+		t.Parallel()
+		r := httptest.NewRequest("POST", "/", nil)
+		assert.Equal(t, []rateLimitKey{
+			{key: testsIPKey, limit: defaultMaxFailedAttemptsPerIP},
+			{key: "auth:account:192.0.2.1:email:user1@email.com", limit: defaultMaxFailedAttemptsPerAccount},
+		}, l.keys(r, "email:User1@Email.com"))
+	})
+
+	t.Run("ip only", func(t *testing.T) {
+		t.Parallel()
+		r := httptest.NewRequest("POST", "/", nil)
+		assert.Equal(t, []rateLimitKey{
+			{key: testsIPKey, limit: defaultMaxFailedAttemptsPerIP},
+		}, l.keys(r, ""))
+	})
+
+	t.Run("no remote address available", func(t *testing.T) {
+		t.Parallel()
+		r, _ := http.NewRequest("POST", "/", nil)
+		assert.Empty(t, l.keys(r, ""))
+	})
+}
+
+func TestLoginRateLimited(t *testing.T) {
+	accountKey := "auth:account:192.0.2.1:email:email"
+	body := `{"email": "email", "password": "pass"}`
+
+	t.Run("error checking rate limit", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).Return(nil, tests.ErrFakeDB)
+		hw.h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("too many failed attempts from ip", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: false, Reset: 30}, nil)
+		hw.h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "30", h.Get("Retry-After"))
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("too many failed attempts against account", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Check", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: false, Reset: 45}, nil)
+		hw.h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+		h := resp.Header
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "45", h.Get("Retry-After"))
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials register failed attempt", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(body))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Check", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.um.On("CheckCredentials", r.Context(), "email", "pass", "192.0.2.1").
+			Return(&hub.CheckCredentialsOutput{Valid: false}, nil)
+		hw.rl.On("Hit", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Hit", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(nil, tests.ErrFakeDB)
+		hw.h.Login(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+}
+
+func TestApproveSessionRateLimited(t *testing.T) {
+	t.Run("too many failed attempts from ip", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"passcode": "123456"}`))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: false, Reset: 10}, nil)
+		hw.h.ApproveSession(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("invalid passcode registers failed attempt", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"passcode": "123456"}`))
+		encodedSessionID, _ := newHandlersWrapper().h.sc.Encode(sessionCookieName, "sessionID")
+		r.AddCookie(&http.Cookie{Name: sessionCookieName, Value: encodedSessionID})
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.um.On("ApproveSession", r.Context(), "sessionID", "123456").Return(hub.ErrInvalidInput)
+		hw.rl.On("Hit", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.h.ApproveSession(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func TestRequireLoginRateLimited(t *testing.T) {
+	accountKey := "auth:account:192.0.2.1:apikey:keyid"
+
+	t.Run("too many failed attempts against api key", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(APIKeyIDHeader, "keyID")
+		r.Header.Set(APIKeySecretHeader, "secret")
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Check", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: false, Reset: 20}, nil)
+		hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.am.AssertExpectations(t)
+	})
+
+	t.Run("invalid api key registers failed attempt", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(APIKeyIDHeader, "keyID")
+		r.Header.Set(APIKeySecretHeader, "secret")
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Check", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.am.On("Check", r.Context(), "keyID", "secret").Return(&hub.CheckAPIKeyOutput{Valid: false}, nil)
+		hw.rl.On("Hit", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.rl.On("Hit", r.Context(), accountKey, testsAccountLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.h.RequireLogin(http.HandlerFunc(testsOK)).ServeHTTP(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.am.AssertExpectations(t)
+	})
+}
+
+func TestVerifyPasswordResetCodeRateLimited(t *testing.T) {
+	t.Run("too many failed attempts from ip", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"code": "code"}`))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: false, Reset: 5}, nil)
+		hw.h.VerifyPasswordResetCode(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+
+	t.Run("invalid code registers failed attempt", func(t *testing.T) {
+		t.Parallel()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("POST", "/", strings.NewReader(`{"code": "code"}`))
+
+		hw := newRateLimitedHandlersWrapper()
+		hw.rl.On("Check", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.um.On("VerifyPasswordResetCode", r.Context(), "code").Return(user.ErrInvalidPasswordResetCode)
+		hw.rl.On("Hit", r.Context(), testsIPKey, testsIPLimit, testsRateWindow).
+			Return(&hub.RateLimitStatus{Allowed: true}, nil)
+		hw.h.VerifyPasswordResetCode(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusGone, resp.StatusCode)
+		hw.rl.AssertExpectations(t)
+		hw.um.AssertExpectations(t)
+	})
+}
+
+func newRateLimitedHandlersWrapper() *handlersWrapper {
+	cfg := viper.New()
+	cfg.Set("server.cookie.hashKey", "tests")
+	cfg.Set("server.rateLimit.auth.enabled", true)
+	cfg.Set("server.rateLimit.auth.window", testsRateWindow)
+	cfg.Set("server.rateLimit.auth.maxFailedAttemptsPerIP", testsIPLimit)
+	cfg.Set("server.rateLimit.auth.maxFailedAttemptsPerAccount", testsAccountLimit)
+
+	um := &user.ManagerMock{}
+	am := &apikey.ManagerMock{}
+	rl := &ratelimit.LimiterMock{}
+	h, _ := NewHandlers(context.Background(), um, am, rl, cfg)
+
+	return &handlersWrapper{
+		cfg: cfg,
+		um:  um,
+		am:  am,
+		rl:  rl,
+		h:   h,
+	}
+}
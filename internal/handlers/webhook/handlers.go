@@ -117,7 +117,8 @@ func (h *Handlers) GetOwnedByUser(w http.ResponseWriter, r *http.Request) {
 }
 
 // TriggerTest is an http handler used to test a webhook before adding or
-// updating it.
+// updating it. When the secret provided is "=", the secret currently stored
+// for the webhook will be used.
 func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
 	// Read webhook from request body
 	wh := &hub.Webhook{}
@@ -126,6 +127,17 @@ func (h *Handlers) TriggerTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Use the webhook's current secret if requested
+	if wh.Secret == "=" {
+		secret, err := h.webhookManager.GetSecret(r.Context(), wh.WebhookID)
+		if err != nil {
+			h.logger.Error().Err(err).Str("method", "TriggerTest").Msg("getSecret failed")
+			helpers.RenderErrorJSON(w, err)
+			return
+		}
+		wh.Secret = secret
+	}
+
 	// Prepare payload
 	var tmpl *template.Template
 	if wh.Template != "" {
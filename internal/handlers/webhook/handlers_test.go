@@ -593,6 +593,46 @@ func TestTriggerTest(t *testing.T) {
 			})
 		}
 	})
+	t.Run("error getting stored secret", func(t *testing.T) {
+		t.Parallel()
+		wh := &hub.Webhook{WebhookID: "webhookID", URL: "http://webhook1.url", Secret: "="}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetSecret", r.Context(), "webhookID").Return("", hub.ErrInsufficientPrivilege)
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
+
+	t.Run("stored secret used when secret was not changed", func(t *testing.T) {
+		t.Parallel()
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "very", r.Header.Get("X-ArtifactHub-Secret"))
+		}))
+		defer ts.Close()
+
+		wh := &hub.Webhook{WebhookID: "webhookID", URL: ts.URL, Secret: "="}
+		webhookJSON, _ := json.Marshal(wh)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest("POST", "/", bytes.NewReader(webhookJSON))
+
+		hw := newHandlersWrapper()
+		hw.wm.On("GetSecret", r.Context(), "webhookID").Return("very", nil)
+		hw.h.TriggerTest(w, r)
+		resp := w.Result()
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+		hw.wm.AssertExpectations(t)
+	})
 }
 
 func TestUpdate(t *testing.T) {
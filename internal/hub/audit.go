@@ -0,0 +1,84 @@
+package hub
+
+// AuditAction represents the kind of security relevant action recorded in the
+// audit log.
+type AuditAction string
+
+const (
+	// AuditActionAccountLocked represents the action of temporarily locking
+	// an account after too many failed login attempts.
+	AuditActionAccountLocked AuditAction = "accountLocked"
+
+	// AuditActionAPIKeyAdded represents the action of adding an API key.
+	AuditActionAPIKeyAdded AuditAction = "apiKeyAdded"
+
+	// AuditActionAuthorizationPolicyUpdated represents the action of updating
+	// an organization authorization policy.
+	AuditActionAuthorizationPolicyUpdated AuditAction = "authorizationPolicyUpdated"
+
+	// AuditActionLogin represents the action of logging in.
+	AuditActionLogin AuditAction = "login"
+
+	// AuditActionOrganizationMemberAdded represents the action of adding a
+	// member to an organization.
+	AuditActionOrganizationMemberAdded AuditAction = "organizationMemberAdded"
+
+	// AuditActionOrganizationMemberDeleted represents the action of deleting a
+	// member from an organization.
+	AuditActionOrganizationMemberDeleted AuditAction = "organizationMemberDeleted"
+
+	// AuditActionPasswordReset represents the action of resetting the
+	// password of a user using a password reset code.
+	AuditActionPasswordReset AuditAction = "passwordReset"
+
+	// AuditActionPasswordUpdated represents the action of updating the
+	// password of a user.
+	AuditActionPasswordUpdated AuditAction = "passwordUpdated"
+
+	// AuditActionRepositoryTransferred represents the action of transferring
+	// a repository to a different owner.
+	AuditActionRepositoryTransferred AuditAction = "repositoryTransferred"
+
+	// AuditActionTFADisabled represents the action of disabling two-factor
+	// authentication.
+	AuditActionTFADisabled AuditAction = "tfaDisabled"
+
+	// AuditActionTFAEnabled represents the action of enabling two-factor
+	// authentication.
+	AuditActionTFAEnabled AuditAction = "tfaEnabled"
+)
+
+// ValidAuditActions contains all the valid audit actions.
+var ValidAuditActions = []AuditAction{
+	AuditActionAccountLocked,
+	AuditActionAPIKeyAdded,
+	AuditActionAuthorizationPolicyUpdated,
+	AuditActionLogin,
+	AuditActionOrganizationMemberAdded,
+	AuditActionOrganizationMemberDeleted,
+	AuditActionPasswordReset,
+	AuditActionPasswordUpdated,
+	AuditActionRepositoryTransferred,
+	AuditActionTFADisabled,
+	AuditActionTFAEnabled,
+}
+
+// AuditEvent represents a security relevant action performed by a user that
+// must be recorded in the audit log.
+type AuditEvent struct {
+	Action                 AuditAction       `json:"action"`
+	UserID                 string            `json:"user_id"`
+	OrganizationName       string            `json:"organization_name,omitempty"`
+	TargetOrganizationName string            `json:"target_organization_name,omitempty"`
+	Details                map[string]string `json:"details,omitempty"`
+}
+
+// GetAuditLogInput represents the input used to get entries from the audit
+// log.
+type GetAuditLogInput struct {
+	Actions []AuditAction `json:"actions,omitempty"`
+	From    string        `json:"from,omitempty"`
+	To      string        `json:"to,omitempty"`
+	Limit   int           `json:"limit,omitempty"`
+	Offset  int           `json:"offset,omitempty"`
+}
@@ -32,6 +32,10 @@ const (
 	// authorization policy.
 	GetAuthorizationPolicy Action = "getAuthorizationPolicy"
 
+	// GetOrganizationAuditLog represents the action of getting an organization
+	// audit log.
+	GetOrganizationAuditLog Action = "getOrganizationAuditLog"
+
 	// TransferOrganizationRepository represents the action of transferring a
 	// repository that belongs to an organization.
 	TransferOrganizationRepository Action = "transferOrganizationRepository"
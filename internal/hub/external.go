@@ -53,6 +53,12 @@ type OCIPuller interface {
 	) (ocispec.Descriptor, []byte, error)
 }
 
+// OCIRepositoriesLister is the interface that wraps the Repositories method,
+// used to list the repositories available under a namespace in a OCI registry.
+type OCIRepositoriesLister interface {
+	Repositories(ctx context.Context, r *Repository, listing string) ([]string, error)
+}
+
 // SignatureChecker is the interface that wraps the HasCosignSignature method,
 // used to check if the OCI artifact identified by the reference provided has a
 // cosign (sigstore) signature.
@@ -26,6 +26,7 @@ type OrganizationManager interface {
 	DeleteMember(ctx context.Context, orgName, userAlias string) error
 	GetJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetByUserJSON(ctx context.Context, p *Pagination) (*JSONQueryResult, error)
+	GetAuditLogJSON(ctx context.Context, orgName string, input *GetAuditLogInput) (*JSONQueryResult, error)
 	GetAuthorizationPolicyJSON(ctx context.Context, orgName string) ([]byte, error)
 	GetMembersJSON(ctx context.Context, orgName string, p *Pagination) (*JSONQueryResult, error)
 	Update(ctx context.Context, orgName string, org *Organization) error
@@ -40,6 +40,15 @@ type GetPackageInput struct {
 	Version        string `json:"version"`
 }
 
+// GetPackageDependenciesInput represents the input used to get the
+// dependencies or the dependents of a specific package.
+type GetPackageDependenciesInput struct {
+	RepositoryName string `json:"repository_name"`
+	PackageName    string `json:"package_name"`
+	Version        string `json:"version"`
+	Depth          int    `json:"depth"`
+}
+
 // Link represents a url associated with a package.
 type Link struct {
 	Name string `json:"name" yaml:"name"`
@@ -68,6 +77,7 @@ type Package struct {
 	NormalizedName                 string                 `json:"normalized_name" hash:"ignore"`
 	AlternativeName                string                 `json:"alternative_name"`
 	Category                       PackageCategory        `json:"category"`
+	CategoryPredicted              bool                   `json:"category_predicted" hash:"ignore"`
 	LogoURL                        string                 `json:"logo_url"`
 	LogoImageID                    string                 `json:"logo_image_id" hash:"ignore"`
 	IsOperator                     bool                   `json:"is_operator"`
@@ -175,6 +185,30 @@ func PackageCategoryFromName(category string) (PackageCategory, error) {
 	}
 }
 
+// GetPackageCategoryName returns the name of the provided package category.
+func GetPackageCategoryName(category PackageCategory) string {
+	switch category {
+	case AIMachineLearning:
+		return "ai-machine-learning"
+	case Database:
+		return "database"
+	case IntegrationDelivery:
+		return "integration-delivery"
+	case MonitoringLogging:
+		return "monitoring-logging"
+	case Networking:
+		return "networking"
+	case Security:
+		return "security"
+	case Storage:
+		return "storage"
+	case StreamingMessaging:
+		return "streaming-messaging"
+	default:
+		return "unknown"
+	}
+}
+
 // PackageCategoryClassifier describes the methods a PackageCategoryClassifier
 // implementation must provide.
 type PackageCategoryClassifier interface {
@@ -188,6 +222,8 @@ type PackageManager interface {
 	DeleteProductionUsage(ctx context.Context, repoName, pkgName, orgName string) error
 	Get(ctx context.Context, input *GetPackageInput) (*Package, error)
 	GetChangelog(ctx context.Context, pkgID string) (*Changelog, error)
+	GetDependenciesJSON(ctx context.Context, input *GetPackageDependenciesInput) ([]byte, error)
+	GetDependentsJSON(ctx context.Context, input *GetPackageDependenciesInput) ([]byte, error)
 	GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error)
 	GetHelmExporterDumpJSON(ctx context.Context) ([]byte, error)
 	GetJSON(ctx context.Context, input *GetPackageInput) ([]byte, error)
@@ -210,6 +246,17 @@ type PackageManager interface {
 	Unregister(ctx context.Context, pkg *Package) error
 }
 
+// PackageDependency represents an edge of the packages dependency graph.
+type PackageDependency struct {
+	Depth             int             `json:"depth"`
+	ParentPackageID   string          `json:"parent_package_id"`
+	PackageID         string          `json:"package_id,omitempty"`
+	Name              string          `json:"name"`
+	VersionConstraint string          `json:"version_constraint,omitempty"`
+	RepositoryURL     string          `json:"repository_url,omitempty"`
+	Package           json.RawMessage `json:"package,omitempty"`
+}
+
 // PackageMetadata represents some metadata about a given package. It's usually
 // provided by repositories publishers, to provide the required information
 // about the content they'd like to be indexed.
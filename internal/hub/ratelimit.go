@@ -0,0 +1,20 @@
+package hub
+
+import (
+	"context"
+	"time"
+)
+
+// RateLimitStatus represents the status of a rate limit for a given key.
+type RateLimitStatus struct {
+	Allowed   bool `json:"allowed"`
+	Limit     int  `json:"limit"`
+	Remaining int  `json:"remaining"`
+	Reset     int  `json:"reset"`
+}
+
+// RateLimiter describes the methods a RateLimiter implementation must provide.
+type RateLimiter interface {
+	Check(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitStatus, error)
+	Hit(ctx context.Context, key string, limit int, window time.Duration) (*RateLimitStatus, error)
+}
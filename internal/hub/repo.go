@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
 
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
@@ -27,7 +28,8 @@ const (
 // ContainerImageData represents some data specific to repositories of the
 // container image kind.
 type ContainerImageData struct {
-	Tags []ContainerImageTag `json:"tags"`
+	Tags      []ContainerImageTag          `json:"tags"`
+	Discovery *ContainerImageTagsDiscovery `json:"discovery,omitempty"`
 }
 
 // ContainerImageTag represents some information about a container image tag.
@@ -36,6 +38,37 @@ type ContainerImageTag struct {
 	Mutable bool   `json:"mutable"`
 }
 
+// ContainerImageTagsDiscovery represents the configuration used to discover
+// from the registry the container image tags to process, in addition to the
+// ones explicitly listed.
+type ContainerImageTagsDiscovery struct {
+	Include []ContainerImageTagPattern `json:"include"`
+	Exclude []string                   `json:"exclude"`
+	Semver  bool                       `json:"semver"`
+	Latest  int                        `json:"latest"`
+}
+
+// ContainerImageTagPattern represents a regular expression used to select the
+// container image tags to process. Tags selected by a pattern inherit its
+// mutability.
+type ContainerImageTagPattern struct {
+	Pattern string `json:"pattern"`
+	Mutable bool   `json:"mutable"`
+}
+
+// HelmData represents some data specific to repositories of the Helm kind.
+type HelmData struct {
+	// Namespace indicates that the url of the OCI repository points to a
+	// namespace in the registry, and that all the charts found under it must
+	// be tracked as packages of the repository.
+	Namespace bool `json:"namespace,omitempty"`
+
+	// Listing represents the reference of an OCI artifact listing the charts
+	// available in the namespace. When not provided, the charts available are
+	// obtained using the registry's catalog API.
+	Listing string `json:"listing,omitempty"`
+}
+
 // TektonData represents some data specific to repositories of the Tekton tasks
 // or pipelines kinds.
 type TektonData struct {
@@ -274,6 +307,29 @@ type HelmIndexLoader interface {
 	LoadIndex(r *Repository) (*helmrepo.IndexFile, string, error)
 }
 
+// TektonBundle represents a Tekton bundle stored in an OCI registry. Each of
+// the bundle's layers contains a single Tekton resource.
+type TektonBundle struct {
+	Digest      string
+	Annotations map[string]string
+	Resources   []*TektonBundleResource
+}
+
+// TektonBundleResource represents a Tekton resource available in a bundle.
+type TektonBundleResource struct {
+	APIVersion  string
+	Kind        string
+	Name        string
+	Annotations map[string]string
+	Data        []byte
+}
+
+// TektonBundleLoader interface defines the methods a Tekton bundle loader
+// implementation should provide.
+type TektonBundleLoader interface {
+	LoadBundle(ctx context.Context, r *Repository, tag string) (*TektonBundle, error)
+}
+
 // OLMOCIExporter describes the methods an OLMOCIExporter implementation must
 // provide.
 type OLMOCIExporter interface {
@@ -288,30 +344,41 @@ type Owner struct {
 
 // Repository represents a packages repository.
 type Repository struct {
-	RepositoryID               string          `json:"repository_id"`
-	Name                       string          `json:"name"`
-	DisplayName                string          `json:"display_name"`
-	URL                        string          `json:"url"`
-	Branch                     string          `json:"branch"`
-	Private                    bool            `json:"private"`
-	AuthUser                   string          `json:"auth_user"`
-	AuthPass                   string          `json:"auth_pass"`
-	Digest                     string          `json:"digest"`
-	Kind                       RepositoryKind  `json:"kind"`
-	UserID                     string          `json:"user_id"`
-	UserAlias                  string          `json:"user_alias"`
-	OrganizationID             string          `json:"organization_id"`
-	OrganizationName           string          `json:"organization_name"`
-	OrganizationDisplayName    string          `json:"organization_display_name"`
-	LastScanningErrors         string          `json:"last_scanning_errors"`
-	LastTrackingErrors         string          `json:"last_tracking_errors"`
-	VerifiedPublisher          bool            `json:"verified_publisher"`
-	Official                   bool            `json:"official"`
-	CNCF                       bool            `json:"cncf"`
-	Disabled                   bool            `json:"disabled"`
-	ScannerDisabled            bool            `json:"scanner_disabled"`
-	Data                       json.RawMessage `json:"data,omitempty"`
-	PackagesDeletionProtection bool            `json:"packages_deletion_protection"`
+	RepositoryID                string          `json:"repository_id"`
+	Name                        string          `json:"name"`
+	DisplayName                 string          `json:"display_name"`
+	URL                         string          `json:"url"`
+	Branch                      string          `json:"branch"`
+	GitURL                      string          `json:"git_url"`
+	GitRef                      string          `json:"git_ref"`
+	GitSubdir                   string          `json:"git_subdir"`
+	Private                     bool            `json:"private"`
+	AuthUser                    string          `json:"auth_user"`
+	AuthPass                    string          `json:"auth_pass"`
+	AuthSSHKey                  string          `json:"auth_ssh_key"`
+	AuthSSHKnownHosts           string          `json:"auth_ssh_known_hosts"`
+	AuthGitHubAppID             string          `json:"auth_github_app_id"`
+	AuthGitHubAppInstallationID string          `json:"auth_github_app_installation_id"`
+	AuthGitHubAppKey            string          `json:"auth_github_app_key"`
+	PushSecret                  string          `json:"push_secret"`
+	Digest                      string          `json:"digest"`
+	Kind                        RepositoryKind  `json:"kind"`
+	UserID                      string          `json:"user_id"`
+	UserAlias                   string          `json:"user_alias"`
+	OrganizationID              string          `json:"organization_id"`
+	OrganizationName            string          `json:"organization_name"`
+	OrganizationDisplayName     string          `json:"organization_display_name"`
+	LastScanningErrors          string          `json:"last_scanning_errors"`
+	LastTrackingErrors          string          `json:"last_tracking_errors"`
+	VerifiedPublisher           bool            `json:"verified_publisher"`
+	Official                    bool            `json:"official"`
+	CNCF                        bool            `json:"cncf"`
+	Disabled                    bool            `json:"disabled"`
+	ScannerDisabled             bool            `json:"scanner_disabled"`
+	Data                        json.RawMessage `json:"data,omitempty"`
+	PackagesDeletionProtection  bool            `json:"packages_deletion_protection"`
+	TrackingInterval            int             `json:"tracking_interval"`
+	TrackingRequested           bool            `json:"tracking_requested"`
 }
 
 // RepositoryCloner describes the methods a RepositoryCloner implementation
@@ -322,6 +389,14 @@ type RepositoryCloner interface {
 	// packages are located. It's the caller's responsibility to delete the
 	// temporary dir when done.
 	CloneRepository(ctx context.Context, r *Repository) (tmpDir string, packagesPath string, err error)
+
+	// SyncRepository updates the local copy of the packages repository
+	// provided kept in the cache dir, cloning it when it's not available yet.
+	// In addition to the local copy path and the path where the packages are
+	// located, it returns the paths of the files that have changed since the
+	// commit provided. When they cannot be determined (i.e. the commit is not
+	// available in the local copy history), nil changed paths are returned.
+	SyncRepository(ctx context.Context, r *Repository, sinceCommit string) (dir, packagesPath string, changedPaths []string, err error)
 }
 
 // RepositoryManager describes the methods an RepositoryManager
@@ -330,12 +405,21 @@ type RepositoryManager interface {
 	Add(ctx context.Context, orgName string, r *Repository) error
 	CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error)
 	ClaimOwnership(ctx context.Context, name, orgName string) error
+	ClearTrackingRequest(ctx context.Context, repositoryID string, trackingStartedAt int64) error
 	Delete(ctx context.Context, name string) error
+	DeletePushSecret(ctx context.Context, name string) error
+	GeneratePushSecret(ctx context.Context, name string) (string, error)
 	GetByID(ctx context.Context, repositoryID string, includeCredentials bool) (*Repository, error)
 	GetByName(ctx context.Context, name string, includeCredentials bool) (*Repository, error)
 	GetMetadata(r *Repository, basePath string) (*RepositoryMetadata, error)
 	GetPackagesDigest(ctx context.Context, repositoryID string) (map[string]string, error)
+	GetPackagesPaths(ctx context.Context, repositoryID string) (map[string]string, error)
 	GetRemoteDigest(ctx context.Context, r *Repository) (string, error)
+	GetTrackingRunsJSON(ctx context.Context, name string, p *Pagination) (*JSONQueryResult, error)
+	GetTrackingStatusJSON(ctx context.Context, name string) ([]byte, error)
+	ProcessPushEvent(ctx context.Context, name string, h http.Header, body []byte) (bool, error)
+	RegisterTrackingRun(ctx context.Context, run *TrackingRun) error
+	RequestTracking(ctx context.Context, name string) error
 	Search(ctx context.Context, input *SearchRepositoryInput) (*SearchRepositoryResult, error)
 	SearchJSON(ctx context.Context, input *SearchRepositoryInput) (*JSONQueryResult, error)
 	SetLastScanningResults(ctx context.Context, repositoryID, errs string) error
@@ -381,3 +465,25 @@ type SearchRepositoryResult struct {
 	Repositories []*Repository
 	TotalCount   int
 }
+
+// TrackingRun represents a record of a repository tracking run.
+type TrackingRun struct {
+	RepositoryID    string              `json:"repository_id"`
+	StartedAt       int64               `json:"started_at"`
+	FinishedAt      int64               `json:"finished_at"`
+	RemoteDigest    string              `json:"remote_digest,omitempty"`
+	PackagesAdded   int                 `json:"packages_added"`
+	PackagesUpdated int                 `json:"packages_updated"`
+	PackagesRemoved int                 `json:"packages_removed"`
+	PackagesIgnored int                 `json:"packages_ignored"`
+	Errors          []*TrackingRunError `json:"errors,omitempty"`
+}
+
+// TrackingRunError represents an error found during a repository tracking
+// run. The package name and version are only set when the error is related to
+// a specific package version.
+type TrackingRunError struct {
+	PackageName    string `json:"package_name,omitempty"`
+	PackageVersion string `json:"package_version,omitempty"`
+	Message        string `json:"message"`
+}
@@ -52,6 +52,13 @@ type TrackerSourceInput struct {
 	PackagesRegistered map[string]string
 	BasePath           string
 	Svc                *TrackerSourceServices
+
+	// ChangedPaths contains the paths (relative to the base path) of the files
+	// that have changed since the last time the repository was processed. It
+	// is only set when the repository can be processed incrementally, along
+	// with the relative paths of the packages registered (PackagesPaths).
+	ChangedPaths  []string
+	PackagesPaths map[string]string
 }
 
 // TrackerSourceLoader represents a function that sets up the appropriate
@@ -18,6 +18,17 @@ type CheckSessionOutput struct {
 	UserID string `json:"user_id"`
 }
 
+// OrganizationMembershipSync represents the membership state of a user in an
+// organization that must be enforced when synchronizing the user's memberships
+// from an external identity provider. Roles indicate if the user must (true)
+// or must not (false) be assigned each of them in the organization's rbac.v1
+// authorization policy data.
+type OrganizationMembershipSync struct {
+	OrganizationName string          `json:"organization_name"`
+	Member           bool            `json:"member"`
+	Roles            map[string]bool `json:"roles"`
+}
+
 // Session represents some information about a user session.
 type Session struct {
 	SessionID string `json:"session_id"`
@@ -53,6 +64,7 @@ type User struct {
 	ProfileImageID string `json:"profile_image_id"`
 	PasswordSet    bool   `json:"password_set"`
 	TFAEnabled     bool   `json:"tfa_enabled"`
+	LDAPDN         string `json:"ldap_dn"`
 }
 
 type userIDKey struct{}
@@ -64,21 +76,25 @@ var UserIDKey = userIDKey{}
 type UserManager interface {
 	ApproveSession(ctx context.Context, sessionID, passcode string) error
 	CheckAvailability(ctx context.Context, resourceKind, value string) (bool, error)
-	CheckCredentials(ctx context.Context, email, password string) (*CheckCredentialsOutput, error)
+	CheckCredentials(ctx context.Context, email, password, client string) (*CheckCredentialsOutput, error)
 	CheckSession(ctx context.Context, sessionID string, duration time.Duration) (*CheckSessionOutput, error)
 	DeleteSession(ctx context.Context, sessionID string) error
 	DeleteUser(ctx context.Context, code string) error
 	DisableTFA(ctx context.Context, passcode string) error
 	EnableTFA(ctx context.Context, passcode string) error
+	GetAuditLogJSON(ctx context.Context, input *GetAuditLogInput) (*JSONQueryResult, error)
 	GetProfile(ctx context.Context) (*User, error)
 	GetProfileJSON(ctx context.Context) ([]byte, error)
 	GetUserID(ctx context.Context, email string) (string, error)
+	GetUserIDFromLDAPDN(ctx context.Context, dn string) (string, error)
+	LinkLDAPAccount(ctx context.Context, dn string) error
 	RegisterDeleteUserCode(ctx context.Context) error
 	RegisterPasswordResetCode(ctx context.Context, userEmail string) error
 	RegisterSession(ctx context.Context, session *Session) (*Session, error)
 	RegisterUser(ctx context.Context, user *User) error
 	ResetPassword(ctx context.Context, code, newPassword string) error
 	SetupTFA(ctx context.Context) ([]byte, error)
+	SyncOrganizationsMemberships(ctx context.Context, userID string, memberships []*OrganizationMembershipSync) error
 	UpdatePassword(ctx context.Context, old, new string) error
 	UpdateProfile(ctx context.Context, user *User) error
 	VerifyEmail(ctx context.Context, code string) (bool, error)
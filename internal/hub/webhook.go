@@ -25,6 +25,7 @@ type WebhookManager interface {
 	GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 	GetOwnedByOrgJSON(ctx context.Context, orgName string, p *Pagination) (*JSONQueryResult, error)
 	GetOwnedByUserJSON(ctx context.Context, p *Pagination) (*JSONQueryResult, error)
+	GetSecret(ctx context.Context, webhookID string) (string, error)
 	GetSubscribedTo(ctx context.Context, e *Event) ([]*Webhook, error)
 	Update(ctx context.Context, wh *Webhook) error
 }
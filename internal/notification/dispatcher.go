@@ -10,6 +10,7 @@ import (
 
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/patrickmn/go-cache"
 	"github.com/spf13/viper"
 )
@@ -58,6 +59,7 @@ type Services struct {
 	RepositoryManager   hub.RepositoryManager
 	PackageManager      hub.PackageManager
 	HTTPClient          hub.HTTPClient
+	Keyring             *util.Keyring
 }
 
 // Dispatcher handles a group of workers in charge of delivering notifications.
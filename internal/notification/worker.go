@@ -173,13 +173,19 @@ func (w *Worker) deliverWebhookNotification(ctx context.Context, n *hub.Notifica
 		contentType = DefaultPayloadContentType
 	}
 
+	// Decrypt webhook secret
+	secret, err := w.svc.Keyring.Decrypt(n.Webhook.Secret)
+	if err != nil {
+		return fmt.Errorf("error decrypting webhook secret: %w", err)
+	}
+
 	// Call webhook endpoint
 	req, err := httpw.NewRequest("POST", n.Webhook.URL, &payload)
 	if err != nil {
 		return err
 	}
 	req.Header.Set("Content-Type", contentType)
-	req.Header.Set("X-ArtifactHub-Secret", n.Webhook.Secret)
+	req.Header.Set("X-ArtifactHub-Secret", secret)
 	resp, err := w.svc.HTTPClient.Do(req)
 	if err != nil {
 		return err
@@ -17,10 +17,12 @@ import (
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/subscription"
 	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
 	"github.com/patrickmn/go-cache"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWorker(t *testing.T) {
@@ -259,10 +261,13 @@ func TestWorker(t *testing.T) {
 	})
 
 	t.Run("webhook notification delivered successfully (real http server)", func(t *testing.T) {
+		encryptedSecret, err := newServicesWrapper().svc.Keyring.Encrypt("very")
+		require.NoError(t, err)
 		testCases := []struct {
 			id              string
 			contentType     string
 			template        string
+			storedSecret    string
 			secret          string
 			expectedPayload []byte
 		}{
@@ -271,6 +276,7 @@ func TestWorker(t *testing.T) {
 				"",
 				"",
 				"",
+				"",
 				[]byte(`
 {
 	"specversion" : "1.0",
@@ -301,6 +307,15 @@ func TestWorker(t *testing.T) {
 				"custom/type",
 				"Package {{ .Package.Name }} {{ .Package.Version}} updated!",
 				"very",
+				"very",
+				[]byte("Package package1 1.0.0 updated!"),
+			},
+			{
+				"3",
+				"custom/type",
+				"Package {{ .Package.Name }} {{ .Package.Version}} updated!",
+				encryptedSecret,
+				"very",
 				[]byte("Package package1 1.0.0 updated!"),
 			},
 		}
@@ -330,7 +345,7 @@ func TestWorker(t *testing.T) {
 						URL:         ts.URL,
 						ContentType: tc.contentType,
 						Template:    tc.template,
-						Secret:      tc.secret,
+						Secret:      tc.storedSecret,
 					},
 				}, nil)
 				sw.pm.On("Get", sw.ctx, gpi).Return(p, nil)
@@ -379,6 +394,7 @@ func newServicesWrapper() *servicesWrapper {
 	pm := &pkg.ManagerMock{}
 	cache := cache.New(1*time.Minute, 5*time.Minute)
 	hc := &tests.HTTPClientMock{}
+	kr, _ := util.NewKeyring([]*util.EncryptionKey{{ID: "key1", Key: "secret1"}})
 
 	return &servicesWrapper{
 		ctx:        ctx,
@@ -403,6 +419,7 @@ func newServicesWrapper() *servicesWrapper {
 			RepositoryManager:   rm,
 			PackageManager:      pm,
 			HTTPClient:          hc,
+			Keyring:             kr,
 		},
 	}
 }
@@ -27,6 +27,23 @@ func (m *PullerMock) PullLayer(
 	return desc, data, args.Error(2)
 }
 
+// RepositoriesListerMock is a mock implementation of the
+// hub.OCIRepositoriesLister interface.
+type RepositoriesListerMock struct {
+	mock.Mock
+}
+
+// Repositories implements the OCIRepositoriesLister interface.
+func (m *RepositoriesListerMock) Repositories(
+	ctx context.Context,
+	r *hub.Repository,
+	listing string,
+) ([]string, error) {
+	args := m.Called(ctx, r, listing)
+	repositories, _ := args.Get(0).([]string)
+	return repositories, args.Error(1)
+}
+
 // SignatureCheckerMock is a mock implementation of the hub.OCISignatureChecker
 // interface.
 type SignatureCheckerMock struct {
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -21,6 +22,7 @@ import (
 	csremote "github.com/sigstore/cosign/v3/pkg/oci/remote"
 	cstypes "github.com/sigstore/cosign/v3/pkg/types"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
 	oraserdef "oras.land/oras-go/v2/errdef"
 	orasremote "oras.land/oras-go/v2/registry/remote"
 	orasauth "oras.land/oras-go/v2/registry/remote/auth"
@@ -33,6 +35,11 @@ const (
 	// Cosign represents the cosign signature kind.
 	Cosign = "cosign"
 
+	// RepositoriesListingMediaType represents the media type of the layer
+	// containing the list of repositories available in a namespace, used when
+	// the registry does not support the catalog API.
+	RepositoriesListingMediaType = "application/vnd.cncf.artifacthub.repositories-listing.layer.v1.yaml"
+
 	// cosignSigArtifactType is the OCI 1.1 artifact type that cosign uses
 	// when attaching signatures via the referrers API.
 	cosignSigArtifactType = "application/vnd.dev.cosign.artifact.sig.v1+json"
@@ -278,6 +285,85 @@ func (tg *TagsGetter) Tags(
 	return tags, nil
 }
 
+// RepositoriesLister provides a mechanism to list the repositories available
+// under a given namespace in a OCI registry.
+type RepositoriesLister struct {
+	cfg     *viper.Viper
+	op      hub.OCIPuller
+	catalog func(ctx context.Context, target name.Registry, options ...ggcrremote.Option) ([]string, error)
+}
+
+// NewRepositoriesLister creates a new RepositoriesLister instance.
+func NewRepositoriesLister(cfg *viper.Viper, op hub.OCIPuller) *RepositoriesLister {
+	if op == nil {
+		op = NewPuller(cfg)
+	}
+	return &RepositoriesLister{
+		cfg:     cfg,
+		op:      op,
+		catalog: ggcrremote.Catalog,
+	}
+}
+
+// Repositories returns the names (relative to the namespace) of the
+// repositories available under the namespace the repository url provided
+// points to. When a listing artifact reference is provided, the repositories
+// will be read from it. Otherwise they will be obtained using the registry's
+// catalog API, which only includes the repositories located directly under
+// the namespace.
+func (l *RepositoriesLister) Repositories(
+	ctx context.Context,
+	r *hub.Repository,
+	listing string,
+) ([]string, error) {
+	namespace, err := name.NewRepository(strings.TrimSuffix(strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid namespace: %w", err)
+	}
+
+	var repositories []string
+	if listing != "" {
+		// Read repositories from the listing artifact
+		ref := strings.TrimPrefix(listing, hub.RepositoryOCIPrefix)
+		_, data, err := l.op.PullLayer(ctx, ref, RepositoriesListingMediaType, r.AuthUser, r.AuthPass)
+		if err != nil {
+			return nil, fmt.Errorf("error pulling repositories listing: %w", err)
+		}
+		var md struct {
+			Repositories []string `yaml:"repositories"`
+		}
+		if err := yaml.Unmarshal(data, &md); err != nil {
+			return nil, fmt.Errorf("error unmarshaling repositories listing: %w", err)
+		}
+		for _, repository := range md.Repositories {
+			repository = strings.Trim(repository, "/")
+			if _, err := name.NewRepository(namespace.Name() + "/" + repository); err != nil || repository == "" {
+				return nil, fmt.Errorf("invalid repository in listing: %s", repository)
+			}
+			repositories = append(repositories, repository)
+		}
+	} else {
+		// Get repositories from the registry catalog
+		options := PrepareRemoteOptions(ctx, l.cfg, namespace.Tag("latest"), r.AuthUser, r.AuthPass)
+		catalog, err := l.catalog(ctx, namespace.Registry, options...)
+		if err != nil {
+			return nil, fmt.Errorf("error getting registry catalog: %w", err)
+		}
+		prefix := namespace.RepositoryStr() + "/"
+		for _, repository := range catalog {
+			if !strings.HasPrefix(repository, prefix) {
+				continue
+			}
+			repository = strings.TrimPrefix(repository, prefix)
+			if repository != "" && !strings.Contains(repository, "/") {
+				repositories = append(repositories, repository)
+			}
+		}
+	}
+	slices.Sort(repositories)
+	return slices.Compact(repositories), nil
+}
+
 // PrepareRemoteOptions prepares some options used to interact with a remote
 // registry.
 func PrepareRemoteOptions(
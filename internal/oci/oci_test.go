@@ -6,6 +6,8 @@ import (
 	"io"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	v1empty "github.com/google/go-containerregistry/pkg/v1/empty"
@@ -739,3 +741,85 @@ func TestPullLayer(t *testing.T) {
 		assert.ErrorIs(t, err, ErrLayerNotFound)
 	})
 }
+
+func TestRepositoriesLister(t *testing.T) {
+	ctx := context.Background()
+	r := &hub.Repository{
+		URL:      "oci://registry.io/org/charts",
+		AuthUser: "user",
+		AuthPass: "pass",
+	}
+
+	t.Run("returns error for invalid namespaces", func(t *testing.T) {
+		t.Parallel()
+		l := NewRepositoriesLister(viper.New(), &PullerMock{})
+		repositories, err := l.Repositories(ctx, &hub.Repository{URL: "oci://INVALID"}, "")
+		assert.Nil(t, repositories)
+		assert.ErrorContains(t, err, "invalid namespace")
+	})
+
+	t.Run("returns catalog errors", func(t *testing.T) {
+		t.Parallel()
+		l := NewRepositoriesLister(viper.New(), &PullerMock{})
+		l.catalog = func(context.Context, name.Registry, ...ggcrremote.Option) ([]string, error) {
+			return nil, tests.ErrFake
+		}
+		repositories, err := l.Repositories(ctx, r, "")
+		assert.Nil(t, repositories)
+		assert.ErrorIs(t, err, tests.ErrFake)
+	})
+
+	t.Run("returns repositories located directly under the namespace from catalog", func(t *testing.T) {
+		t.Parallel()
+		l := NewRepositoriesLister(viper.New(), &PullerMock{})
+		l.catalog = func(_ context.Context, target name.Registry, _ ...ggcrremote.Option) ([]string, error) {
+			assert.Equal(t, "registry.io", target.RegistryStr())
+			return []string{
+				"org/charts/chart2",
+				"org/charts/chart1",
+				"org/charts/sub/chart3",
+				"org/charts",
+				"org/other/chart4",
+			}, nil
+		}
+		repositories, err := l.Repositories(ctx, r, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"chart1", "chart2"}, repositories)
+	})
+
+	t.Run("returns listing pull errors", func(t *testing.T) {
+		t.Parallel()
+		op := &PullerMock{}
+		op.On("PullLayer", ctx, "registry.io/org/charts/listing:latest", RepositoriesListingMediaType, "user", "pass").
+			Return(nil, nil, tests.ErrFake)
+		l := NewRepositoriesLister(viper.New(), op)
+		repositories, err := l.Repositories(ctx, r, "oci://registry.io/org/charts/listing:latest")
+		assert.Nil(t, repositories)
+		assert.ErrorIs(t, err, tests.ErrFake)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("returns error for invalid repositories in listing", func(t *testing.T) {
+		t.Parallel()
+		op := &PullerMock{}
+		op.On("PullLayer", ctx, "registry.io/org/charts/listing:latest", RepositoriesListingMediaType, "user", "pass").
+			Return(nil, []byte("repositories:\n  - chart1\n  - INVALID\n"), nil)
+		l := NewRepositoriesLister(viper.New(), op)
+		repositories, err := l.Repositories(ctx, r, "oci://registry.io/org/charts/listing:latest")
+		assert.Nil(t, repositories)
+		assert.EqualError(t, err, "invalid repository in listing: INVALID")
+		op.AssertExpectations(t)
+	})
+
+	t.Run("returns repositories from listing", func(t *testing.T) {
+		t.Parallel()
+		op := &PullerMock{}
+		op.On("PullLayer", ctx, "registry.io/org/charts/listing:latest", RepositoriesListingMediaType, "user", "pass").
+			Return(nil, []byte("repositories:\n  - chart2\n  - chart1\n  - sub/chart3\n  - chart1\n"), nil)
+		l := NewRepositoriesLister(viper.New(), op)
+		repositories, err := l.Repositories(ctx, r, "oci://registry.io/org/charts/listing:latest")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"chart1", "chart2", "sub/chart3"}, repositories)
+		op.AssertExpectations(t)
+	})
+}
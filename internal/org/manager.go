@@ -11,10 +11,12 @@ import (
 
 	_ "embed" // Used by templates
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/open-policy-agent/opa/ast" // nolint:staticcheck // SA1019 (deprecated)
 	"github.com/satori/uuid"
 	"github.com/spf13/viper"
@@ -29,6 +31,7 @@ const (
 	deleteOrgDBQ         = `select delete_organization($1::uuid, $2::text)`
 	deleteOrgMemberDBQ   = `select delete_organization_member($1::uuid, $2::text, $3::text)`
 	getAuthzPolicyDBQ    = `select get_authorization_policy($1::uuid, $2::text)`
+	getOrgAuditLogDBQ    = `select * from get_organization_audit_log($1::uuid, $2::text, $3::jsonb)`
 	getOrgDBQ            = `select get_organization($1::text)`
 	getOrgMembersDBQ     = `select * from get_organization_members($1::uuid, $2::text, $3::int, $4::int)`
 	getUserAliasDBQ      = `select alias from "user" where user_id = $1`
@@ -111,8 +114,21 @@ func (m *Manager) AddMember(ctx context.Context, orgName, userAlias string) erro
 		return err
 	}
 
-	// Add organization member to database
-	if _, err := m.db.Exec(ctx, addOrgMemberDBQ, userID, orgName, userAlias); err != nil {
+	// Add organization member to database and register audit event
+	err := util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, addOrgMemberDBQ, userID, orgName, userAlias); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action:           hub.AuditActionOrganizationMemberAdded,
+			UserID:           userID,
+			OrganizationName: orgName,
+			Details: map[string]string{
+				"user_alias": userAlias,
+			},
+		})
+	})
+	if err != nil {
 		if err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 			return hub.ErrInsufficientPrivilege
 		}
@@ -257,14 +273,57 @@ func (m *Manager) DeleteMember(ctx context.Context, orgName, userAlias string) e
 		}
 	}
 
-	// Delete organization member from database
-	_, err := m.db.Exec(ctx, deleteOrgMemberDBQ, userID, orgName, userAlias)
+	// Delete organization member from database and register audit event
+	err := util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, deleteOrgMemberDBQ, userID, orgName, userAlias); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action:           hub.AuditActionOrganizationMemberDeleted,
+			UserID:           userID,
+			OrganizationName: orgName,
+			Details: map[string]string{
+				"user_alias": userAlias,
+			},
+		})
+	})
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
 	return err
 }
 
+// GetAuditLogJSON returns the audit log entries of the provided organization
+// that match the input provided as a json object.
+func (m *Manager) GetAuditLogJSON(
+	ctx context.Context,
+	orgName string,
+	input *hub.GetAuditLogInput,
+) (*hub.JSONQueryResult, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if orgName == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+	}
+	if err := audit.ValidateGetLogInput(input); err != nil {
+		return nil, err
+	}
+
+	// Authorize action
+	if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+		OrganizationName: orgName,
+		UserID:           userID,
+		Action:           hub.GetOrganizationAuditLog,
+	}); err != nil {
+		return nil, err
+	}
+
+	// Get audit log entries from database
+	inputJSON, _ := json.Marshal(input)
+	return util.DBQueryJSONWithPagination(ctx, m.db, getOrgAuditLogDBQ, userID, orgName, inputJSON)
+}
+
 // GetAuthorizationPolicyJSON returns the organization's authorization policy
 // as a json object.
 func (m *Manager) GetAuthorizationPolicyJSON(ctx context.Context, orgName string) ([]byte, error) {
@@ -409,9 +468,18 @@ func (m *Manager) UpdateAuthorizationPolicy(
 		return err
 	}
 
-	// Update authorization policy in database
+	// Update authorization policy in database and register audit event
 	policyJSON, _ := json.Marshal(p)
-	_, err = m.db.Exec(ctx, updateAuthzPolicyDBQ, userID, orgName, policyJSON)
+	err = util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, updateAuthzPolicyDBQ, userID, orgName, policyJSON); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action:           hub.AuditActionAuthorizationPolicyUpdated,
+			UserID:           userID,
+			OrganizationName: orgName,
+		})
+	})
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
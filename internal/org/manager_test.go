@@ -2,10 +2,12 @@ package org
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
@@ -148,6 +150,29 @@ func TestAddMember(t *testing.T) {
 		az.AssertExpectations(t)
 	})
 
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("Exec", ctx, addOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.AddOrganizationMember,
+		}).Return(nil)
+		m := NewManager(cfg, db, nil, az)
+
+		err := m.AddMember(ctx, "orgName", "userAlias")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
 	t.Run("database query succeeded", func(t *testing.T) {
 		testCases := []struct {
 			description         string
@@ -166,7 +191,11 @@ func TestAddMember(t *testing.T) {
 			t.Run(tc.description, func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
-				db.On("Exec", ctx, addOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
+				tx.On("Exec", ctx, addOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+				tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+				tx.On("Commit", ctx).Return(nil)
 				db.On("QueryRow", ctx, getUserEmailDBQ, mock.Anything).Return("email", nil)
 				es := &email.SenderMock{}
 				es.On("SendEmail", mock.Anything).Return(tc.emailSenderResponse)
@@ -181,6 +210,7 @@ func TestAddMember(t *testing.T) {
 				err := m.AddMember(ctx, "orgName", "userAlias")
 				assert.Equal(t, tc.emailSenderResponse, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
 				es.AssertExpectations(t)
 				az.AssertExpectations(t)
 			})
@@ -205,7 +235,10 @@ func TestAddMember(t *testing.T) {
 			t.Run(tc.dbErr.Error(), func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
-				db.On("Exec", ctx, addOrgMemberDBQ, "userID", "orgName", "userAlias").Return(tc.dbErr)
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
+				tx.On("Exec", ctx, addOrgMemberDBQ, "userID", "orgName", "userAlias").Return(tc.dbErr)
+				tx.On("Rollback", ctx).Return(nil)
 				az := &authz.AuthorizerMock{}
 				az.On("Authorize", ctx, &hub.AuthorizeInput{
 					OrganizationName: "orgName",
@@ -217,6 +250,7 @@ func TestAddMember(t *testing.T) {
 				err := m.AddMember(ctx, "orgName", "userAlias")
 				assert.Equal(t, tc.expectedError, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
 				az.AssertExpectations(t)
 			})
 		}
@@ -474,11 +508,32 @@ func TestDeleteMember(t *testing.T) {
 		az.AssertExpectations(t)
 	})
 
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getUserAliasDBQ, "userID").Return("userAlias", nil)
+		tx.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		err := m.DeleteMember(ctx, "orgName", "userAlias")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
 	t.Run("member deleted successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getUserAliasDBQ, "userID").Return("requestingUserAlias", nil)
-		db.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		az := &authz.AuthorizerMock{}
 		az.On("Authorize", ctx, &hub.AuthorizeInput{
 			OrganizationName: "orgName",
@@ -490,19 +545,25 @@ func TestDeleteMember(t *testing.T) {
 		err := m.DeleteMember(ctx, "orgName", "userAlias")
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 		az.AssertExpectations(t)
 	})
 
 	t.Run("user left organization successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getUserAliasDBQ, "userID").Return("userAlias", nil)
-		db.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		m := NewManager(cfg, db, nil, nil)
 
 		err := m.DeleteMember(ctx, "orgName", "userAlias")
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("error deleting member", func(t *testing.T) {
@@ -523,8 +584,11 @@ func TestDeleteMember(t *testing.T) {
 			t.Run(tc.dbErr.Error(), func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
 				db.On("QueryRow", ctx, getUserAliasDBQ, "userID").Return("requestingUserAlias", nil)
-				db.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(tc.dbErr)
+				tx.On("Exec", ctx, deleteOrgMemberDBQ, "userID", "orgName", "userAlias").Return(tc.dbErr)
+				tx.On("Rollback", ctx).Return(nil)
 				az := &authz.AuthorizerMock{}
 				az.On("Authorize", ctx, &hub.AuthorizeInput{
 					OrganizationName: "orgName",
@@ -536,6 +600,128 @@ func TestDeleteMember(t *testing.T) {
 				err := m.DeleteMember(ctx, "orgName", "userAlias")
 				assert.Equal(t, tc.expectedError, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
+				az.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestGetAuditLogJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	input := &hub.GetAuditLogInput{
+		Actions: []hub.AuditAction{hub.AuditActionOrganizationMemberAdded},
+		Limit:   10,
+		Offset:  1,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetAuditLogJSON(context.Background(), "org1", input)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg  string
+			orgName string
+			input   *hub.GetAuditLogInput
+		}{
+			{
+				"organization name not provided",
+				"",
+				input,
+			},
+			{
+				"invalid action",
+				"org1",
+				&hub.GetAuditLogInput{
+					Actions: []hub.AuditAction{"invalid"},
+				},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil, nil)
+				_, err := m.GetAuditLogJSON(ctx, tc.orgName, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "org1",
+			UserID:           "userID",
+			Action:           hub.GetOrganizationAuditLog,
+		}).Return(tests.ErrFake)
+		m := NewManager(cfg, nil, nil, az)
+
+		result, err := m.GetAuditLogJSON(ctx, "org1", input)
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, result)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getOrgAuditLogDBQ, "userID", "org1", inputJSON).
+			Return([]interface{}{[]byte("dataJSON"), 1}, nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "org1",
+			UserID:           "userID",
+			Action:           hub.GetOrganizationAuditLog,
+		}).Return(nil)
+		m := NewManager(cfg, db, nil, az)
+
+		result, err := m.GetAuditLogJSON(ctx, "org1", input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), result.Data)
+		assert.Equal(t, 1, result.TotalCount)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getOrgAuditLogDBQ, "userID", "org1", inputJSON).Return(nil, tc.dbErr)
+				az := &authz.AuthorizerMock{}
+				az.On("Authorize", ctx, &hub.AuthorizeInput{
+					OrganizationName: "org1",
+					UserID:           "userID",
+					Action:           hub.GetOrganizationAuditLog,
+				}).Return(nil)
+				m := NewManager(cfg, db, nil, az)
+
+				result, err := m.GetAuditLogJSON(ctx, "org1", input)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, result)
+				db.AssertExpectations(t)
 				az.AssertExpectations(t)
 			})
 		}
@@ -1036,10 +1222,38 @@ func TestUpdateAuthorizationPolicy(t *testing.T) {
 		az.AssertExpectations(t)
 	})
 
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("Exec", ctx, updateAuthzPolicyDBQ, "userID", "org1", mock.Anything).Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		az := &authz.AuthorizerMock{}
+		az.On("WillUserBeLockedOut", ctx, validPolicy, "userID").Return(false, nil).Maybe()
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "org1",
+			UserID:           "userID",
+			Action:           hub.UpdateAuthorizationPolicy,
+		}).Return(nil)
+		m := NewManager(cfg, db, nil, az)
+
+		err := m.UpdateAuthorizationPolicy(ctx, "org1", validPolicy)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
 	t.Run("database query succeeded", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("Exec", ctx, updateAuthzPolicyDBQ, "userID", "org1", mock.Anything).Return(nil)
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("Exec", ctx, updateAuthzPolicyDBQ, "userID", "org1", mock.Anything).Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		az := &authz.AuthorizerMock{}
 		az.On("WillUserBeLockedOut", ctx, validPolicy, "userID").Return(false, nil).Maybe()
 		az.On("Authorize", ctx, &hub.AuthorizeInput{
@@ -1052,6 +1266,7 @@ func TestUpdateAuthorizationPolicy(t *testing.T) {
 		err := m.UpdateAuthorizationPolicy(ctx, "org1", validPolicy)
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 		az.AssertExpectations(t)
 	})
 
@@ -1073,7 +1288,10 @@ func TestUpdateAuthorizationPolicy(t *testing.T) {
 			t.Run(tc.dbErr.Error(), func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
-				db.On("Exec", ctx, updateAuthzPolicyDBQ, "userID", "org1", mock.Anything).Return(tc.dbErr)
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
+				tx.On("Exec", ctx, updateAuthzPolicyDBQ, "userID", "org1", mock.Anything).Return(tc.dbErr)
+				tx.On("Rollback", ctx).Return(nil)
 				az := &authz.AuthorizerMock{}
 				az.On("WillUserBeLockedOut", ctx, validPolicy, "userID").Return(false, nil).Maybe()
 				az.On("Authorize", ctx, &hub.AuthorizeInput{
@@ -1086,6 +1304,7 @@ func TestUpdateAuthorizationPolicy(t *testing.T) {
 				err := m.UpdateAuthorizationPolicy(ctx, "org1", validPolicy)
 				assert.Equal(t, tc.expectedError, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
 				az.AssertExpectations(t)
 			})
 		}
@@ -62,6 +62,17 @@ func (m *ManagerMock) GetAuthorizationPolicyJSON(ctx context.Context, orgName st
 	return data, args.Error(1)
 }
 
+// GetAuditLogJSON implements the OrganizationManager interface.
+func (m *ManagerMock) GetAuditLogJSON(
+	ctx context.Context,
+	orgName string,
+	input *hub.GetAuditLogInput,
+) (*hub.JSONQueryResult, error) {
+	args := m.Called(ctx, orgName, input)
+	data, _ := args.Get(0).(*hub.JSONQueryResult)
+	return data, args.Error(1)
+}
+
 // GetByUserJSON implements the OrganizationManager interface.
 func (m *ManagerMock) GetByUserJSON(ctx context.Context, p *hub.Pagination) (*hub.JSONQueryResult, error) {
 	args := m.Called(ctx, p)
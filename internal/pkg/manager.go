@@ -29,6 +29,8 @@ const (
 	getNovaDumpDBQ                  = `select get_nova_dump()`
 	getPkgDBQ                       = `select get_package($1::jsonb)`
 	getPkgChangelogDBQ              = `select get_package_changelog($1::uuid)`
+	getPkgDependenciesDBQ           = `select get_package_dependencies($1::jsonb)`
+	getPkgDependentsDBQ             = `select get_package_dependents($1::jsonb)`
 	getPkgStarsDBQ                  = `select get_package_stars($1::uuid, $2::uuid)`
 	getPkgSummaryDBQ                = `select get_package_summary($1::jsonb)`
 	getPkgViewsDBQ                  = `select get_package_views($1::uuid, $2::date, $3::date)`
@@ -48,6 +50,12 @@ const (
 	unregisterPkgDBQ                = `select unregister_package($1::jsonb)`
 )
 
+const (
+	// MaxDependenciesDepth represents the maximum depth allowed when getting
+	// the dependencies or the dependents of a package.
+	MaxDependenciesDepth = 5
+)
+
 var (
 	validCapabilities = []string{
 		"basic install",
@@ -115,6 +123,54 @@ func (m *Manager) GetChangelog(ctx context.Context, pkgID string) (*hub.Changelo
 	return changelog, err
 }
 
+// GetDependenciesJSON returns the dependencies of the package version
+// identified by the input provided as a json array. The dependencies resolved
+// to packages available in Artifact Hub are expanded transitively up to the
+// depth provided. The json array is built by the database.
+func (m *Manager) GetDependenciesJSON(ctx context.Context, input *hub.GetPackageDependenciesInput) ([]byte, error) {
+	// Validate input
+	if err := validateDependenciesInput(input); err != nil {
+		return nil, err
+	}
+
+	// Get dependencies from database
+	inputJSON, _ := json.Marshal(input)
+	return util.DBQueryJSON(ctx, m.db, getPkgDependenciesDBQ, inputJSON)
+}
+
+// GetDependentsJSON returns the packages that depend on the package identified
+// by the input provided as a json array, expanding them transitively up to the
+// depth provided. When a version is provided, only the packages whose
+// dependency constraint is satisfied by that version are returned.
+func (m *Manager) GetDependentsJSON(ctx context.Context, input *hub.GetPackageDependenciesInput) ([]byte, error) {
+	// Validate input
+	if err := validateDependenciesInput(input); err != nil {
+		return nil, err
+	}
+	var version *semver.Version
+	if input.Version != "" {
+		var err error
+		version, err = semver.NewVersion(input.Version)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid version")
+		}
+	}
+
+	// Get dependents from database
+	inputJSON, _ := json.Marshal(input)
+	dataJSON, err := util.DBQueryJSON(ctx, m.db, getPkgDependentsDBQ, inputJSON)
+	if err != nil || version == nil {
+		return dataJSON, err
+	}
+
+	// Filter out the dependents not compatible with the version provided
+	var dependents []*hub.PackageDependency
+	if err := json.Unmarshal(dataJSON, &dependents); err != nil {
+		return nil, err
+	}
+	return json.Marshal(filterDependentsByVersion(dependents, version))
+}
+
 // GetHarborReplicationDumpJSON returns a json list with all packages versions
 // of kind Helm available so that they can be synchronized in Harbor.
 func (m *Manager) GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error) {
@@ -477,6 +533,44 @@ func ParseKey(key string) (string, string) {
 	return p[0], p[1]
 }
 
+// validateDependenciesInput checks if the input provided to get the
+// dependencies or the dependents of a package is valid.
+func validateDependenciesInput(input *hub.GetPackageDependenciesInput) error {
+	if input.PackageName == "" || input.RepositoryName == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "package name not provided")
+	}
+	if input.Depth < 0 || input.Depth > MaxDependenciesDepth {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid depth")
+	}
+	return nil
+}
+
+// filterDependentsByVersion returns the dependents whose dependency constraint
+// is satisfied by the version provided. Transitive dependents are kept only
+// when the dependent they hang from is kept as well.
+func filterDependentsByVersion(
+	dependents []*hub.PackageDependency,
+	version *semver.Version,
+) []*hub.PackageDependency {
+	filtered := make([]*hub.PackageDependency, 0, len(dependents))
+	kept := make(map[string]struct{})
+	for _, d := range dependents {
+		if d.Depth == 1 {
+			if d.VersionConstraint != "" {
+				c, err := semver.NewConstraint(d.VersionConstraint)
+				if err != nil || !c.Check(version) {
+					continue
+				}
+			}
+		} else if _, ok := kept[d.ParentPackageID]; !ok {
+			continue
+		}
+		kept[d.PackageID] = struct{}{}
+		filtered = append(filtered, d)
+	}
+	return filtered
+}
+
 // getUserID returns the user id from the context provided when available.
 func getUserID(ctx context.Context) *string {
 	var userID *string
@@ -477,6 +477,171 @@ func TestGetChangelog(t *testing.T) {
 	})
 }
 
+func TestGetDependenciesJSON(t *testing.T) {
+	ctx := context.Background()
+	input := &hub.GetPackageDependenciesInput{
+		RepositoryName: "repo1",
+		PackageName:    "pkg1",
+		Version:        "1.0.0",
+		Depth:          2,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			input  *hub.GetPackageDependenciesInput
+			errMsg string
+		}{
+			{
+				&hub.GetPackageDependenciesInput{},
+				"package name not provided",
+			},
+			{
+				&hub.GetPackageDependenciesInput{RepositoryName: "repo1", PackageName: "pkg1", Depth: -1},
+				"invalid depth",
+			},
+			{
+				&hub.GetPackageDependenciesInput{RepositoryName: "repo1", PackageName: "pkg1", Depth: MaxDependenciesDepth + 1},
+				"invalid depth",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				_, err := m.GetDependenciesJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependenciesDBQ, inputJSON).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependenciesDBQ, inputJSON).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependenciesJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetDependentsJSON(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			input  *hub.GetPackageDependenciesInput
+			errMsg string
+		}{
+			{
+				&hub.GetPackageDependenciesInput{},
+				"package name not provided",
+			},
+			{
+				&hub.GetPackageDependenciesInput{RepositoryName: "repo1", PackageName: "pkg1", Depth: MaxDependenciesDepth + 1},
+				"invalid depth",
+			},
+			{
+				&hub.GetPackageDependenciesInput{RepositoryName: "repo1", PackageName: "pkg1", Version: "invalid"},
+				"invalid version",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(nil)
+				_, err := m.GetDependentsJSON(ctx, tc.input)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+		}
+		inputJSON, _ := json.Marshal(input)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependentsDBQ, inputJSON).Return([]byte("dataJSON"), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependentsJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded, dependents filtered by version", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+			Version:        "12.1.0",
+			Depth:          2,
+		}
+		inputJSON, _ := json.Marshal(input)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependentsDBQ, inputJSON).Return([]byte(`
+		[
+			{"depth": 1, "parent_package_id": "p1", "package_id": "p2", "name": "pkg1", "version_constraint": "12.x.x"},
+			{"depth": 1, "parent_package_id": "p1", "package_id": "p3", "name": "pkg1", "version_constraint": "^11.0.0"},
+			{"depth": 1, "parent_package_id": "p1", "package_id": "p4", "name": "pkg1"},
+			{"depth": 2, "parent_package_id": "p2", "package_id": "p5", "name": "pkg2", "version_constraint": "1.0.0"},
+			{"depth": 2, "parent_package_id": "p3", "package_id": "p6", "name": "pkg3", "version_constraint": "1.0.0"}
+		]
+		`), nil)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependentsJSON(ctx, input)
+		require.NoError(t, err)
+		var dependents []*hub.PackageDependency
+		require.NoError(t, json.Unmarshal(dataJSON, &dependents))
+		packagesIDs := make([]string, 0, len(dependents))
+		for _, d := range dependents {
+			packagesIDs = append(packagesIDs, d.PackageID)
+		}
+		assert.Equal(t, []string{"p2", "p4", "p5"}, packagesIDs)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		input := &hub.GetPackageDependenciesInput{
+			RepositoryName: "repo1",
+			PackageName:    "pkg1",
+			Version:        "1.0.0",
+		}
+		inputJSON, _ := json.Marshal(input)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPkgDependentsDBQ, inputJSON).Return(nil, tests.ErrFakeDB)
+		m := NewManager(db)
+
+		dataJSON, err := m.GetDependentsJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetHarborReplicationDumpJSON(t *testing.T) {
 	ctx := context.Background()
 
@@ -38,6 +38,26 @@ func (m *ManagerMock) GetChangelog(ctx context.Context, pkgID string) (*hub.Chan
 	return data, args.Error(1)
 }
 
+// GetDependenciesJSON implements the PackageManager interface.
+func (m *ManagerMock) GetDependenciesJSON(
+	ctx context.Context,
+	input *hub.GetPackageDependenciesInput,
+) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// GetDependentsJSON implements the PackageManager interface.
+func (m *ManagerMock) GetDependentsJSON(
+	ctx context.Context,
+	input *hub.GetPackageDependenciesInput,
+) ([]byte, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
 // GetHarborReplicationDumpJSON implements the PackageManager interface.
 func (m *ManagerMock) GetHarborReplicationDumpJSON(ctx context.Context) ([]byte, error) {
 	args := m.Called(ctx)
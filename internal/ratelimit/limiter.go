@@ -0,0 +1,136 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// Database queries
+	checkRateLimitDBQ        = `select check_rate_limit($1::text, $2::integer, $3::integer, $4::boolean)`
+	deleteExpiredCountersDBQ = `delete from rate_limit_counter where expires_at < current_timestamp`
+
+	// defaultCleanupFrequency represents how often expired counters will be
+	// deleted from the database.
+	defaultCleanupFrequency = 15 * time.Minute
+
+	// minWindow represents the minimum duration of a rate limit window.
+	minWindow = 1 * time.Second
+)
+
+// Limiter is a rate limiter that uses a sliding window algorithm. Counters are
+// stored in the database, so they are shared by all hub instances.
+type Limiter struct {
+	db               hub.DB
+	cleanupFrequency time.Duration
+}
+
+// NewLimiter creates a new Limiter instance.
+func NewLimiter(db hub.DB, opts ...func(l *Limiter)) *Limiter {
+	l := &Limiter{
+		db:               db,
+		cleanupFrequency: defaultCleanupFrequency,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// WithCleanupFrequency allows configuring how often expired counters are
+// deleted from the database.
+func WithCleanupFrequency(d time.Duration) func(l *Limiter) {
+	return func(l *Limiter) {
+		l.cleanupFrequency = d
+	}
+}
+
+// Check returns the status of the rate limit for the key provided without
+// registering a hit. The status will be allowed when there is still room for
+// one more hit in the current window.
+func (l *Limiter) Check(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	return l.checkRateLimit(ctx, key, limit, window, false)
+}
+
+// Hit registers a hit for the key provided and returns the resulting status
+// of the rate limit. The status will be allowed when the hit registered does
+// not exceed the limit.
+func (l *Limiter) Hit(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	return l.checkRateLimit(ctx, key, limit, window, true)
+}
+
+// checkRateLimit returns the status of the rate limit for the key provided,
+// registering a hit first if requested.
+func (l *Limiter) checkRateLimit(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+	registerHit bool,
+) (*hub.RateLimitStatus, error) {
+	// Validate input
+	if err := validateInput(key, limit, window); err != nil {
+		return nil, err
+	}
+
+	// Check rate limit in database
+	var s *hub.RateLimitStatus
+	err := util.DBQueryUnmarshal(
+		ctx,
+		l.db,
+		&s,
+		checkRateLimitDBQ,
+		key,
+		limit,
+		int(window.Seconds()),
+		registerHit,
+	)
+	return s, err
+}
+
+// validateInput checks the rate limit input provided is valid.
+func validateInput(key string, limit int, window time.Duration) error {
+	if key == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "key not provided")
+	}
+	if limit <= 0 {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid limit")
+	}
+	if window < minWindow {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid window")
+	}
+	return nil
+}
+
+// Cleaner deletes periodically the counters that have expired. It'll keep
+// running until the context provided is done.
+func (l *Limiter) Cleaner(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for {
+		select {
+		case <-time.After(l.cleanupFrequency):
+			if _, err := l.db.Exec(ctx, deleteExpiredCountersDBQ); err != nil {
+				log.Error().Err(err).Msg("error deleting expired rate limit counters")
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
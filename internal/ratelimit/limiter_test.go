@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMain(m *testing.M) {
+	zerolog.SetGlobalLevel(zerolog.Disabled)
+	os.Exit(m.Run())
+}
+
+func TestCheck(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			key    string
+			limit  int
+			window time.Duration
+		}{
+			{
+				"key not provided",
+				"",
+				10,
+				time.Minute,
+			},
+			{
+				"invalid limit",
+				"key",
+				0,
+				time.Minute,
+			},
+			{
+				"invalid window",
+				"key",
+				10,
+				time.Millisecond,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				l := NewLimiter(nil)
+				_, err := l.Check(ctx, tc.key, tc.limit, tc.window)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkRateLimitDBQ, "key", 10, 60, false).Return(nil, tests.ErrFakeDB)
+		l := NewLimiter(db)
+
+		s, err := l.Check(ctx, "key", 10, time.Minute)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, s)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("rate limit status returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkRateLimitDBQ, "key", 10, 60, false).Return([]byte(`
+		{
+			"allowed": false,
+			"limit": 10,
+			"remaining": 0,
+			"reset": 30
+		}
+		`), nil)
+		l := NewLimiter(db)
+
+		s, err := l.Check(ctx, "key", 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, &hub.RateLimitStatus{
+			Allowed:   false,
+			Limit:     10,
+			Remaining: 0,
+			Reset:     30,
+		}, s)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestHit(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		l := NewLimiter(nil)
+		_, err := l.Hit(ctx, "", 10, time.Minute)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkRateLimitDBQ, "key", 10, 60, true).Return(nil, tests.ErrFakeDB)
+		l := NewLimiter(db)
+
+		s, err := l.Hit(ctx, "key", 10, time.Minute)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, s)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("hit registered successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkRateLimitDBQ, "key", 10, 60, true).Return([]byte(`
+		{
+			"allowed": true,
+			"limit": 10,
+			"remaining": 9,
+			"reset": 45
+		}
+		`), nil)
+		l := NewLimiter(db)
+
+		s, err := l.Hit(ctx, "key", 10, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, &hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     10,
+			Remaining: 9,
+			Reset:     45,
+		}, s)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestCleaner(t *testing.T) {
+	t.Run("custom cleanup frequency", func(t *testing.T) {
+		t.Parallel()
+		l := NewLimiter(nil, WithCleanupFrequency(2*time.Second))
+		assert.Equal(t, 2*time.Second, l.cleanupFrequency)
+	})
+
+	t.Run("expired counters deleted until ctx is done", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		deleted := make(chan struct{})
+		db.On("Exec", mock.Anything, deleteExpiredCountersDBQ).Return(tests.ErrFakeDB).Once()
+		db.On("Exec", mock.Anything, deleteExpiredCountersDBQ).Run(func(args mock.Arguments) {
+			close(deleted)
+		}).Return(nil).Once()
+		db.On("Exec", mock.Anything, deleteExpiredCountersDBQ).Return(nil).Maybe()
+
+		l := NewLimiter(db, WithCleanupFrequency(10*time.Millisecond))
+		wg.Add(1)
+		go l.Cleaner(ctx, &wg)
+		<-deleted
+		cancel()
+		wg.Wait()
+		db.AssertExpectations(t)
+	})
+}
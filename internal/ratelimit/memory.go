@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// memorySweepFrequency represents how often the counters that have expired
+// will be deleted from the memory limiter.
+const memorySweepFrequency = 1 * time.Minute
+
+// MemoryLimiter is a rate limiter that uses the same sliding window algorithm
+// as Limiter, but keeps the counters in memory. Counters are not shared by
+// the hub instances, so limits are enforced per instance, but no database
+// access is required to check them.
+type MemoryLimiter struct {
+	mu        sync.Mutex
+	counters  map[string]*memoryCounter
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// memoryCounter represents the hits registered for a given key in the current
+// and previous fixed windows.
+type memoryCounter struct {
+	window       time.Duration
+	windowStart  time.Time
+	currentHits  int
+	previousHits int
+	expiresAt    time.Time
+}
+
+// NewMemoryLimiter creates a new MemoryLimiter instance.
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		counters:  make(map[string]*memoryCounter),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+// Check returns the status of the rate limit for the key provided without
+// registering a hit. The status will be allowed when there is still room for
+// one more hit in the current window.
+func (l *MemoryLimiter) Check(
+	_ context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	return l.checkRateLimit(key, limit, window, false)
+}
+
+// Hit registers a hit for the key provided and returns the resulting status
+// of the rate limit. The status will be allowed when the hit registered does
+// not exceed the limit.
+func (l *MemoryLimiter) Hit(
+	_ context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	return l.checkRateLimit(key, limit, window, true)
+}
+
+// checkRateLimit returns the status of the rate limit for the key provided,
+// registering a hit first if requested.
+func (l *MemoryLimiter) checkRateLimit(
+	key string,
+	limit int,
+	window time.Duration,
+	registerHit bool,
+) (*hub.RateLimitStatus, error) {
+	// Validate input
+	if err := validateInput(key, limit, window); err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Delete expired counters periodically
+	now := l.now()
+	if now.Sub(l.lastSweep) >= memorySweepFrequency {
+		for k, c := range l.counters {
+			if !now.Before(c.expiresAt) {
+				delete(l.counters, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	// Get counter for the key provided, moving it to the current window
+	windowStart := now.Truncate(window)
+	c, ok := l.counters[key]
+	if !ok || c.window != window {
+		c = &memoryCounter{window: window, windowStart: windowStart}
+	}
+	switch {
+	case c.windowStart.Equal(windowStart):
+	case c.windowStart.Equal(windowStart.Add(-window)):
+		c.previousHits, c.currentHits = c.currentHits, 0
+	default:
+		c.previousHits, c.currentHits = 0, 0
+	}
+	c.windowStart = windowStart
+	c.expiresAt = windowStart.Add(2 * window)
+
+	// Register hit in the current window if requested
+	if registerHit {
+		c.currentHits++
+		l.counters[key] = c
+	}
+
+	// Estimate the number of hits in the sliding window
+	windowSeconds := window.Seconds()
+	elapsed := now.Sub(windowStart).Seconds()
+	currentHits, previousHits := float64(c.currentHits), float64(c.previousHits)
+	estimatedHits := previousHits*(1-elapsed/windowSeconds) + currentHits
+
+	// A registered hit is allowed when it doesn't exceed the limit. When no
+	// hit is registered, we check if there is still room for one more.
+	var allowed bool
+	if registerHit {
+		allowed = estimatedHits <= float64(limit)
+	} else {
+		allowed = estimatedHits < float64(limit)
+	}
+
+	// Calculate the seconds until the current window ends or, when the limit
+	// has been reached, until the estimated hits drop below the limit
+	var reset float64
+	switch {
+	case allowed:
+		reset = windowSeconds - elapsed
+	case currentHits < float64(limit):
+		reset = windowSeconds*(1-(float64(limit)-currentHits)/previousHits) - elapsed
+	default:
+		reset = (windowSeconds - elapsed) + windowSeconds*(1-float64(limit)/currentHits)
+	}
+
+	return &hub.RateLimitStatus{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(limit-int(math.Ceil(estimatedHits)), 0),
+		Reset:     max(int(math.Ceil(reset)), 1),
+	}, nil
+}
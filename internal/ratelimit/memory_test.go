@@ -0,0 +1,101 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLimiter(t *testing.T) {
+	ctx := context.Background()
+	windowStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLimiter()
+		_, err := l.Hit(ctx, "", 10, time.Minute)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		_, err = l.Check(ctx, "key", 10, time.Millisecond)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("hits allowed until the limit is exceeded", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLimiter()
+		l.now = func() time.Time { return windowStart.Add(15 * time.Second) }
+
+		for i := 1; i <= 3; i++ {
+			s, err := l.Hit(ctx, "key", 3, time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, &hub.RateLimitStatus{
+				Allowed:   true,
+				Limit:     3,
+				Remaining: 3 - i,
+				Reset:     45,
+			}, s)
+		}
+		s, err := l.Check(ctx, "key", 3, time.Minute)
+		require.NoError(t, err)
+		assert.False(t, s.Allowed)
+		s, err = l.Hit(ctx, "key", 3, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.RateLimitStatus{
+			Allowed:   false,
+			Limit:     3,
+			Remaining: 0,
+			Reset:     60,
+		}, s)
+
+		// Other keys are not affected
+		s, err = l.Check(ctx, "other", 3, time.Minute)
+		require.NoError(t, err)
+		assert.True(t, s.Allowed)
+	})
+
+	t.Run("previous window hits are weighted", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLimiter()
+		l.now = func() time.Time { return windowStart.Add(30 * time.Second) }
+		for range 4 {
+			_, _ = l.Hit(ctx, "key", 4, time.Minute)
+		}
+
+		// Half of the next window has elapsed, so only half of the hits in
+		// the previous one are taken into account
+		l.now = func() time.Time { return windowStart.Add(90 * time.Second) }
+		s, err := l.Check(ctx, "key", 4, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, &hub.RateLimitStatus{
+			Allowed:   true,
+			Limit:     4,
+			Remaining: 2,
+			Reset:     30,
+		}, s)
+
+		// Hits older than the previous window are ignored
+		l.now = func() time.Time { return windowStart.Add(150 * time.Second) }
+		s, err = l.Check(ctx, "key", 4, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 4, s.Remaining)
+	})
+
+	t.Run("expired counters are deleted", func(t *testing.T) {
+		t.Parallel()
+		l := NewMemoryLimiter()
+		l.now = func() time.Time { return windowStart }
+		l.lastSweep = windowStart
+		_, _ = l.Hit(ctx, "key1", 10, time.Minute)
+		_, _ = l.Hit(ctx, "key2", 10, time.Hour)
+
+		l.now = func() time.Time { return windowStart.Add(2 * time.Minute) }
+		_, _ = l.Check(ctx, "key3", 10, time.Minute)
+		assert.NotContains(t, l.counters, "key1")
+		assert.Contains(t, l.counters, "key2")
+		assert.NotContains(t, l.counters, "key3")
+	})
+}
@@ -0,0 +1,38 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// LimiterMock is a mock implementation of the RateLimiter interface.
+type LimiterMock struct {
+	mock.Mock
+}
+
+// Check implements the RateLimiter interface.
+func (m *LimiterMock) Check(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	args := m.Called(ctx, key, limit, window)
+	data, _ := args.Get(0).(*hub.RateLimitStatus)
+	return data, args.Error(1)
+}
+
+// Hit implements the RateLimiter interface.
+func (m *LimiterMock) Hit(
+	ctx context.Context,
+	key string,
+	limit int,
+	window time.Duration,
+) (*hub.RateLimitStatus, error) {
+	args := m.Called(ctx, key, limit, window)
+	data, _ := args.Get(0).(*hub.RateLimitStatus)
+	return data, args.Error(1)
+}
@@ -2,61 +2,187 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 )
 
 const (
 	// DefaultBranch represents the branch used by default when cloning a
 	// repository if no branch has been configured on it.
 	DefaultBranch = "master"
+
+	// remoteRefsPrefix represents the prefix of the references that track
+	// the remote branches in the local copy of a repository.
+	remoteRefsPrefix = "refs/remotes/" + git.DefaultRemoteName
 )
 
+// ErrCacheDirNotAvailable indicates that the local copies of the repositories
+// cannot be kept because no cache dir is available.
+var ErrCacheDirNotAvailable = errors.New("cache dir not available")
+
 // Cloner is a hub.RepositoryCloner implementation.
-type Cloner struct{}
+type Cloner struct {
+	hc       hub.HTTPClient
+	cacheDir string
+}
+
+// NewCloner creates a new Cloner instance. When no cache dir is provided, the
+// local copies of the repositories are kept in the user's cache dir.
+func NewCloner(hc hub.HTTPClient, opts ...func(c *Cloner)) *Cloner {
+	c := &Cloner{
+		hc: hc,
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	if c.cacheDir == "" {
+		if userCacheDir, err := os.UserCacheDir(); err == nil {
+			c.cacheDir = filepath.Join(userCacheDir, "artifacthub", "git")
+		}
+	}
+	return c
+}
+
+// WithCacheDir allows providing the dir where the local copies of the
+// repositories will be kept.
+func WithCacheDir(dir string) func(c *Cloner) {
+	return func(c *Cloner) {
+		c.cacheDir = dir
+	}
+}
+
+// CacheDir returns the dir where the local copies of the repositories are
+// kept. An empty string is returned when no cache dir is available.
+func (c *Cloner) CacheDir() string {
+	return c.cacheDir
+}
 
 // CloneRepository implements the hub.RepositoryCloner interface.
 func (c *Cloner) CloneRepository(ctx context.Context, r *hub.Repository) (string, string, error) {
-	// Parse repository url
-	var repoBaseURL, packagesPath string
-	matches := GitRepoURLRE.FindStringSubmatch(r.URL)
-	if len(matches) < 3 {
-		return "", "", fmt.Errorf("invalid repository url")
-	}
-	repoBaseURL = matches[1]
-	if len(matches) == 4 {
-		packagesPath = strings.TrimSuffix(matches[3], "/")
+	// Get repository git source
+	src, err := getGitSource(r)
+	if err != nil {
+		return "", "", err
 	}
 
 	// Clone git repository
+	cloneURL, auth, err := getGitAuth(ctx, c.hc, r, src.url)
+	if err != nil {
+		return "", "", err
+	}
 	tmpDir, err := os.MkdirTemp("", "artifact-hub")
 	if err != nil {
 		return "", "", fmt.Errorf("error creating temp dir: %w", err)
 	}
-	cloneOptions := &git.CloneOptions{
-		URL:           repoBaseURL,
-		ReferenceName: plumbing.NewBranchReferenceName(GetBranch(r)),
-		SingleBranch:  true,
-		Depth:         1,
+	gr, err := git.PlainCloneContext(ctx, tmpDir, false, getCloneOptions(src, cloneURL, auth))
+	if err != nil {
+		return "", "", err
 	}
-	if r.AuthPass != "" {
-		cloneOptions.Auth = &http.BasicAuth{
-			Username: "artifact-hub",
-			Password: r.AuthPass,
+	if src.ref.isCommit() {
+		if err := checkoutGitRef(gr, src.ref); err != nil {
+			return "", "", err
 		}
 	}
-	_, err = git.PlainCloneContext(ctx, tmpDir, false, cloneOptions)
+
+	return tmpDir, src.subdir, nil
+}
+
+// SyncRepository implements the hub.RepositoryCloner interface.
+func (c *Cloner) SyncRepository(
+	ctx context.Context,
+	r *hub.Repository,
+	sinceCommit string,
+) (string, string, []string, error) {
+	if c.cacheDir == "" {
+		return "", "", nil, ErrCacheDirNotAvailable
+	}
+
+	// Get repository git source
+	src, err := getGitSource(r)
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
+	}
+
+	// Fetch new commits into the local copy, cloning the repository again if
+	// the local copy is not available or cannot be updated
+	cloneURL, auth, err := getGitAuth(ctx, c.hc, r, src.url)
+	if err != nil {
+		return "", "", nil, err
+	}
+	dir := filepath.Join(c.cacheDir, r.RepositoryID)
+	gr, err := fetchRepository(ctx, dir, src, cloneURL, auth)
+	if err != nil {
+		if err := os.RemoveAll(dir); err != nil {
+			return "", "", nil, fmt.Errorf("error removing local copy: %w", err)
+		}
+		gr, err = git.PlainCloneContext(ctx, dir, false, getCloneOptions(src, cloneURL, auth))
+		if err != nil {
+			return "", "", nil, err
+		}
 	}
 
-	return tmpDir, packagesPath, nil
+	// Update the local copy working tree to the commit the ref points to
+	hash, err := resolveGitRef(gr, src.ref)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error resolving git ref: %w", err)
+	}
+	wt, err := gr.Worktree()
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error getting worktree: %w", err)
+	}
+	if err := wt.Reset(&git.ResetOptions{Commit: hash, Mode: git.HardReset}); err != nil {
+		return "", "", nil, fmt.Errorf("error updating worktree: %w", err)
+	}
+
+	// Get paths of the files that changed since the commit provided
+	changedPaths, err := getChangedPaths(ctx, gr, sinceCommit, hash)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("error getting changed paths: %w", err)
+	}
+
+	return dir, src.subdir, changedPaths, nil
+}
+
+// PruneCache deletes the local copies kept in the cache dir of the
+// repositories that are no longer registered (i.e. have been deleted).
+func (c *Cloner) PruneCache(ctx context.Context, rm hub.RepositoryManager) error {
+	if c.cacheDir == "" {
+		return nil
+	}
+	entries, err := os.ReadDir(c.cacheDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("error reading cache dir: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		_, err := rm.GetByID(ctx, entry.Name(), false)
+		if err == nil {
+			continue
+		}
+		if !errors.Is(err, hub.ErrNotFound) && !errors.Is(err, hub.ErrInvalidInput) {
+			return fmt.Errorf("error getting repository: %w", err)
+		}
+		if err := os.RemoveAll(filepath.Join(c.cacheDir, entry.Name())); err != nil {
+			return fmt.Errorf("error removing local copy: %w", err)
+		}
+	}
+	return nil
 }
 
 // GetBranch returns the branch configured in the repository or the default one
@@ -68,3 +194,196 @@ func GetBranch(r *hub.Repository) string {
 	}
 	return branch
 }
+
+// IsGitRepository checks if the repository provided is a git repository,
+// based on its kind and url.
+func IsGitRepository(r *hub.Repository) bool {
+	if strings.HasPrefix(r.URL, hub.RepositoryOCIPrefix) {
+		return false
+	}
+	switch r.Kind {
+	case
+		hub.ArgoTemplate,
+		hub.Backstage,
+		hub.Bootc,
+		hub.CoreDNS,
+		hub.Falco,
+		hub.Gatekeeper,
+		hub.Headlamp,
+		hub.HelmPlugin,
+		hub.InspektorGadget,
+		hub.KCL,
+		hub.KedaScaler,
+		hub.Keptn,
+		hub.KnativeClientPlugin,
+		hub.Krew,
+		hub.KubeArmor,
+		hub.Kubewarden,
+		hub.Kyverno,
+		hub.Meshery,
+		hub.OPA,
+		hub.OpenCost,
+		hub.Radius,
+		hub.TBAction,
+		hub.TektonPipeline,
+		hub.TektonTask,
+		hub.TektonStepAction,
+		hub.OLM:
+		return true
+	default:
+		return false
+	}
+}
+
+// fetchRepository opens the local copy of the repository located in the dir
+// provided and fetches the new commits available in the remote ref.
+func fetchRepository(
+	ctx context.Context,
+	dir string,
+	src *gitSource,
+	cloneURL string,
+	auth transport.AuthMethod,
+) (*git.Repository, error) {
+	gr, err := git.PlainOpen(dir)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := gr.Remote(git.DefaultRemoteName)
+	if err != nil {
+		return nil, err
+	}
+	if urls := remote.Config().URLs; len(urls) == 0 || urls[0] != cloneURL {
+		return nil, errors.New("repository url has changed")
+	}
+	var refSpec config.RefSpec
+	if src.ref.isCommit() {
+		// Commits never change, so there is nothing to fetch if the commit is
+		// already available in the local copy
+		if _, err := gr.CommitObject(src.ref.hash); err == nil {
+			return gr, nil
+		}
+		refSpec = config.RefSpec(fmt.Sprintf("+refs/heads/*:%s/*", remoteRefsPrefix))
+	} else {
+		refSpec = config.RefSpec(fmt.Sprintf("+%s:%s", src.ref.name, src.ref.localName()))
+	}
+	err = gr.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: []config.RefSpec{refSpec},
+		Auth:     auth,
+	})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return nil, err
+	}
+	return gr, nil
+}
+
+// resolveGitRef returns the hash of the commit the git ref provided points to
+// in the local copy of the repository.
+func resolveGitRef(gr *git.Repository, ref *gitRef) (plumbing.Hash, error) {
+	if ref.isCommit() {
+		if _, err := gr.CommitObject(ref.hash); err != nil {
+			return plumbing.ZeroHash, err
+		}
+		return ref.hash, nil
+	}
+	hash, err := gr.ResolveRevision(plumbing.Revision(ref.localName()))
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return *hash, nil
+}
+
+// checkoutGitRef updates the working tree of the repository provided to the
+// commit the git ref points to.
+func checkoutGitRef(gr *git.Repository, ref *gitRef) error {
+	hash, err := resolveGitRef(gr, ref)
+	if err != nil {
+		return fmt.Errorf("error resolving git ref: %w", err)
+	}
+	wt, err := gr.Worktree()
+	if err != nil {
+		return fmt.Errorf("error getting worktree: %w", err)
+	}
+	if err := wt.Checkout(&git.CheckoutOptions{Hash: hash, Force: true}); err != nil {
+		return fmt.Errorf("error checking out git ref: %w", err)
+	}
+	return nil
+}
+
+// getChangedPaths returns the paths of the files that changed between the
+// commits provided. When the commit to compare from is not available, nil is
+// returned.
+func getChangedPaths(ctx context.Context, gr *git.Repository, from string, to plumbing.Hash) ([]string, error) {
+	if !plumbing.IsHash(from) {
+		return nil, nil
+	}
+	fromCommit, err := gr.CommitObject(plumbing.NewHash(from))
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	toCommit, err := gr.CommitObject(to)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, fromTree, toTree, nil)
+	if err != nil {
+		return nil, err
+	}
+	changedPaths := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if change.From.Name != "" {
+			changedPaths = append(changedPaths, change.From.Name)
+		}
+		if change.To.Name != "" && change.To.Name != change.From.Name {
+			changedPaths = append(changedPaths, change.To.Name)
+		}
+	}
+	return changedPaths, nil
+}
+
+// getCloneOptions returns the options used to clone the repository provided.
+// When the ref points to a specific commit, the whole repository history is
+// cloned, as it's not possible to clone a single commit.
+func getCloneOptions(src *gitSource, cloneURL string, auth transport.AuthMethod) *git.CloneOptions {
+	if src.ref.isCommit() {
+		return &git.CloneOptions{
+			URL:  cloneURL,
+			Auth: auth,
+		}
+	}
+	return &git.CloneOptions{
+		URL:           cloneURL,
+		ReferenceName: src.ref.name,
+		SingleBranch:  true,
+		Depth:         1,
+		Auth:          auth,
+	}
+}
+
+// parseGitRepoURL extracts the repository base url and the path where the
+// packages are located from the git repository url provided.
+func parseGitRepoURL(repoURL string) (string, string, error) {
+	var repoBaseURL, packagesPath string
+	matches := GitRepoURLRE.FindStringSubmatch(repoURL)
+	if len(matches) < 3 {
+		return "", "", fmt.Errorf("invalid repository url")
+	}
+	repoBaseURL = matches[1]
+	if len(matches) == 4 {
+		packagesPath = strings.TrimSuffix(matches[3], "/")
+	}
+	return repoBaseURL, packagesPath, nil
+}
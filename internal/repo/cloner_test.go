@@ -0,0 +1,230 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloner(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup git repository with a couple of commits and a tag
+	gr, commit := newTestGitRepository(t)
+	dir := getWorktreeDir(t, gr)
+	commit1 := commit(map[string]string{"charts/pkgs/pkg1/artifacthub-pkg.yml": "version: 1.0.0"})
+	_, err := gr.CreateTag("v1.0.0", commit1, &git.CreateTagOptions{
+		Tagger:  &object.Signature{Name: "test", Email: "test@email.com", When: time.Now()},
+		Message: "v1.0.0",
+	})
+	require.NoError(t, err)
+	commit2 := commit(map[string]string{"charts/pkgs/pkg1/artifacthub-pkg.yml": "version: 2.0.0"})
+	cacheDir := t.TempDir()
+	c := NewCloner(nil, WithCacheDir(cacheDir))
+
+	testCases := []struct {
+		ref             string
+		expectedVersion string
+	}{
+		{"", "version: 2.0.0"},
+		{"master", "version: 2.0.0"},
+		{"refs/tags/v1.0.0", "version: 1.0.0"},
+		{commit1.String(), "version: 1.0.0"},
+	}
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("clone repository (ref: %s)", tc.ref), func(t *testing.T) {
+			r := &hub.Repository{GitURL: dir, GitRef: tc.ref, GitSubdir: "/charts/pkgs/"}
+
+			tmpDir, packagesPath, err := c.CloneRepository(ctx, r)
+			require.NoError(t, err)
+			defer os.RemoveAll(tmpDir)
+			assert.Equal(t, "charts/pkgs", packagesPath)
+			data, err := os.ReadFile(filepath.Join(tmpDir, packagesPath, "pkg1", "artifacthub-pkg.yml"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, string(data))
+		})
+
+		t.Run(fmt.Sprintf("sync repository (ref: %s)", tc.ref), func(t *testing.T) {
+			r := &hub.Repository{RepositoryID: "repo-" + tc.ref, GitURL: dir, GitRef: tc.ref}
+
+			// Initial sync clones the repository
+			localDir, packagesPath, changedPaths, err := c.SyncRepository(ctx, r, "")
+			require.NoError(t, err)
+			assert.Empty(t, packagesPath)
+			assert.Nil(t, changedPaths)
+			data, err := os.ReadFile(filepath.Join(localDir, "charts", "pkgs", "pkg1", "artifacthub-pkg.yml"))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, string(data))
+
+			// Subsequent syncs reuse the local copy
+			localDir2, _, _, err := c.SyncRepository(ctx, r, commit2.String())
+			require.NoError(t, err)
+			assert.Equal(t, localDir, localDir2)
+			assert.Equal(t, filepath.Join(cacheDir, r.RepositoryID), localDir)
+		})
+	}
+
+	t.Run("sync repository: cache dir not available", func(t *testing.T) {
+		t.Setenv("XDG_CACHE_HOME", "")
+		t.Setenv("HOME", "")
+		c := NewCloner(nil)
+		r := &hub.Repository{RepositoryID: "repo1", GitURL: dir}
+
+		assert.Empty(t, c.CacheDir())
+		_, _, _, err := c.SyncRepository(ctx, r, "")
+		assert.ErrorIs(t, err, ErrCacheDirNotAvailable)
+	})
+}
+
+func TestClonerPruneCache(t *testing.T) {
+	ctx := context.Background()
+	repo1ID := "00000000-0000-0000-0000-000000000001"
+	repo2ID := "00000000-0000-0000-0000-000000000002"
+
+	setupCacheDir := func(t *testing.T) string {
+		t.Helper()
+		cacheDir := t.TempDir()
+		for _, name := range []string{repo1ID, repo2ID} {
+			require.NoError(t, os.MkdirAll(filepath.Join(cacheDir, name), 0o755))
+		}
+		return cacheDir
+	}
+
+	t.Run("cache dir does not exist", func(t *testing.T) {
+		t.Parallel()
+		rm := &ManagerMock{}
+		c := NewCloner(nil, WithCacheDir(filepath.Join(t.TempDir(), "git")))
+
+		err := c.PruneCache(ctx, rm)
+		assert.NoError(t, err)
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("error getting repository", func(t *testing.T) {
+		t.Parallel()
+		cacheDir := setupCacheDir(t)
+		rm := &ManagerMock{}
+		rm.On("GetByID", ctx, repo1ID, false).Return(nil, tests.ErrFakeDB)
+		c := NewCloner(nil, WithCacheDir(cacheDir))
+
+		err := c.PruneCache(ctx, rm)
+		assert.ErrorIs(t, err, tests.ErrFakeDB)
+		assert.DirExists(t, filepath.Join(cacheDir, repo1ID))
+		assert.DirExists(t, filepath.Join(cacheDir, repo2ID))
+		rm.AssertExpectations(t)
+	})
+
+	t.Run("local copies of deleted repositories removed", func(t *testing.T) {
+		t.Parallel()
+		cacheDir := setupCacheDir(t)
+		rm := &ManagerMock{}
+		rm.On("GetByID", ctx, repo1ID, false).Return(&hub.Repository{RepositoryID: repo1ID}, nil)
+		rm.On("GetByID", ctx, repo2ID, false).Return(nil, hub.ErrNotFound)
+		c := NewCloner(nil, WithCacheDir(cacheDir))
+
+		err := c.PruneCache(ctx, rm)
+		assert.NoError(t, err)
+		assert.DirExists(t, filepath.Join(cacheDir, repo1ID))
+		assert.NoDirExists(t, filepath.Join(cacheDir, repo2ID))
+		rm.AssertExpectations(t)
+	})
+}
+
+func TestGetChangedPaths(t *testing.T) {
+	ctx := context.Background()
+
+	// Setup git repository with a couple of commits
+	gr, commit := newTestGitRepository(t)
+	commit1 := commit(map[string]string{
+		"pkgs/pkg1/1.0.0/artifacthub-pkg.yml": "version: 1.0.0",
+		"pkgs/pkg2/1.0.0/artifacthub-pkg.yml": "version: 1.0.0",
+		"pkgs/pkg2/1.0.0/README.md":           "readme",
+	})
+	commit2 := commit(map[string]string{
+		"pkgs/pkg1/1.0.0/artifacthub-pkg.yml": "version: 1.0.0\nname: pkg1",
+		"pkgs/pkg3/1.0.0/artifacthub-pkg.yml": "version: 1.0.0",
+	}, "pkgs/pkg2/1.0.0/README.md")
+
+	t.Run("commit not provided", func(t *testing.T) {
+		t.Parallel()
+		changedPaths, err := getChangedPaths(ctx, gr, "", commit2)
+		require.NoError(t, err)
+		assert.Nil(t, changedPaths)
+	})
+
+	t.Run("commit not available", func(t *testing.T) {
+		t.Parallel()
+		changedPaths, err := getChangedPaths(ctx, gr, "0123456789012345678901234567890123456789", commit2)
+		require.NoError(t, err)
+		assert.Nil(t, changedPaths)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		t.Parallel()
+		changedPaths, err := getChangedPaths(ctx, gr, commit2.String(), commit2)
+		require.NoError(t, err)
+		assert.NotNil(t, changedPaths)
+		assert.Empty(t, changedPaths)
+	})
+
+	t.Run("some files changed", func(t *testing.T) {
+		t.Parallel()
+		changedPaths, err := getChangedPaths(ctx, gr, commit1.String(), commit2)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"pkgs/pkg1/1.0.0/artifacthub-pkg.yml",
+			"pkgs/pkg2/1.0.0/README.md",
+			"pkgs/pkg3/1.0.0/artifacthub-pkg.yml",
+		}, changedPaths)
+	})
+}
+
+// newTestGitRepository is a helper function that initializes a new git
+// repository in a temporary directory. It returns the repository and a
+// function that can be used to commit changes to it.
+func newTestGitRepository(t *testing.T) (*git.Repository, func(map[string]string, ...string) plumbing.Hash) {
+	t.Helper()
+	dir := t.TempDir()
+	gr, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	wt, err := gr.Worktree()
+	require.NoError(t, err)
+	commit := func(files map[string]string, removed ...string) plumbing.Hash {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+			_, err := wt.Add(name)
+			require.NoError(t, err)
+		}
+		for _, name := range removed {
+			_, err := wt.Remove(name)
+			require.NoError(t, err)
+		}
+		hash, err := wt.Commit("commit", &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@email.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash
+	}
+	return gr, commit
+}
+
+// getWorktreeDir is a helper function that returns the directory of the
+// worktree of the git repository provided.
+func getWorktreeDir(t *testing.T, gr *git.Repository) string {
+	t.Helper()
+	wt, err := gr.Worktree()
+	require.NoError(t, err)
+	return wt.Filesystem.Root()
+}
@@ -0,0 +1,327 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// githubAPIURL represents the url of the GitHub API.
+	githubAPIURL = "https://api.github.com"
+
+	// githubAppTokenExpirationMargin represents the margin used when checking
+	// if a cached GitHub App installation token has expired, so that tokens
+	// are not used when they are about to expire.
+	githubAppTokenExpirationMargin = 5 * time.Minute
+)
+
+var (
+	// githubAppTokensMu protects the GitHub App installation tokens cache.
+	githubAppTokensMu sync.Mutex
+
+	// githubAppTokens caches the GitHub App installation tokens minted, so
+	// that they can be reused while they are still valid.
+	githubAppTokens = make(map[string]*githubAppToken)
+
+	// githubAppTokensGroup ensures that only one installation token is minted
+	// at a time for each installation.
+	githubAppTokensGroup singleflight.Group
+)
+
+// githubAppToken represents a GitHub App installation access token.
+type githubAppToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// getGitAuth returns the url and the authentication method that should be
+// used to access the git repository provided. Repositories can authenticate
+// using an SSH deploy key, GitHub App credentials or an access token (sent
+// using http basic auth).
+func getGitAuth(
+	ctx context.Context,
+	hc hub.HTTPClient,
+	r *hub.Repository,
+	repoBaseURL string,
+) (string, transport.AuthMethod, error) {
+	switch {
+	case r.AuthSSHKey != "":
+		auth, err := getSSHAuth(r)
+		if err != nil {
+			return "", nil, err
+		}
+		return getSSHURL(repoBaseURL), auth, nil
+	case r.AuthGitHubAppKey != "":
+		token, err := getGitHubAppInstallationToken(ctx, hc, r, repoBaseURL)
+		if err != nil {
+			return "", nil, fmt.Errorf("error getting github app installation token: %w", err)
+		}
+		return repoBaseURL, &githttp.BasicAuth{
+			Username: "x-access-token",
+			Password: token,
+		}, nil
+	case r.AuthPass != "":
+		return repoBaseURL, &githttp.BasicAuth{
+			Username: "artifact-hub",
+			Password: r.AuthPass,
+		}, nil
+	default:
+		return repoBaseURL, nil, nil
+	}
+}
+
+// getSSHAuth returns the SSH authentication method for the repository
+// provided. Only the hosts keys in the repository's known hosts are accepted.
+func getSSHAuth(r *hub.Repository) (*gitssh.PublicKeys, error) {
+	auth, err := gitssh.NewPublicKeys("git", []byte(r.AuthSSHKey), "")
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ssh key: %w", err)
+	}
+	hostKeyCallback, err := newKnownHostsCallback(r.AuthSSHKnownHosts)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing ssh known hosts: %w", err)
+	}
+	auth.HostKeyCallback = hostKeyCallback
+	return auth, nil
+}
+
+// getSSHURL returns the SSH url of the http based git repository url provided.
+func getSSHURL(repoBaseURL string) string {
+	return strings.Replace(repoBaseURL, "https://", "ssh://git@", 1)
+}
+
+// newKnownHostsCallback returns a host key callback that only accepts the
+// hosts keys included in the known hosts provided (in OpenSSH format).
+func newKnownHostsCallback(knownHosts string) (ssh.HostKeyCallback, error) {
+	if strings.TrimSpace(knownHosts) == "" {
+		return nil, errors.New("known hosts not provided")
+	}
+	f, err := os.CreateTemp("", "artifact-hub-known-hosts")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(knownHosts); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+	return knownhosts.New(f.Name())
+}
+
+// getGitHubAppInstallationToken returns an installation access token for the
+// GitHub App configured in the repository provided. Tokens are minted using
+// the app's private key and cached until they are about to expire.
+func getGitHubAppInstallationToken(
+	ctx context.Context,
+	hc hub.HTTPClient,
+	r *hub.Repository,
+	repoBaseURL string,
+) (string, error) {
+	// Use cached token if it's still valid
+	keyHash := sha256.Sum256([]byte(r.AuthGitHubAppKey))
+	cacheKey := fmt.Sprintf("%s:%s:%s",
+		r.AuthGitHubAppID,
+		r.AuthGitHubAppInstallationID,
+		hex.EncodeToString(keyHash[:]),
+	)
+	if token, ok := getCachedGitHubAppToken(cacheKey); ok {
+		return token, nil
+	}
+
+	// Mint a new installation token, sharing the result with any concurrent
+	// requests for the same installation
+	v, err, _ := githubAppTokensGroup.Do(cacheKey, func() (interface{}, error) {
+		if token, ok := getCachedGitHubAppToken(cacheKey); ok {
+			return token, nil
+		}
+		t, err := mintGitHubAppInstallationToken(ctx, hc, r, repoBaseURL)
+		if err != nil {
+			return "", err
+		}
+		cacheGitHubAppToken(cacheKey, t)
+		return t.Token, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return v.(string), nil
+}
+
+// getCachedGitHubAppToken returns the GitHub App installation token cached
+// for the key provided when it's still valid.
+func getCachedGitHubAppToken(cacheKey string) (string, bool) {
+	githubAppTokensMu.Lock()
+	defer githubAppTokensMu.Unlock()
+
+	t, ok := githubAppTokens[cacheKey]
+	if !ok || time.Until(t.ExpiresAt) <= githubAppTokenExpirationMargin {
+		return "", false
+	}
+	return t.Token, true
+}
+
+// cacheGitHubAppToken stores the GitHub App installation token provided in
+// the cache, deleting the tokens that have already expired.
+func cacheGitHubAppToken(cacheKey string, t *githubAppToken) {
+	githubAppTokensMu.Lock()
+	defer githubAppTokensMu.Unlock()
+
+	now := time.Now()
+	for k, ct := range githubAppTokens {
+		if !now.Before(ct.ExpiresAt) {
+			delete(githubAppTokens, k)
+		}
+	}
+	githubAppTokens[cacheKey] = t
+}
+
+// mintGitHubAppInstallationToken requests a new installation access token for
+// the GitHub App configured in the repository provided.
+func mintGitHubAppInstallationToken(
+	ctx context.Context,
+	hc hub.HTTPClient,
+	r *hub.Repository,
+	repoBaseURL string,
+) (*githubAppToken, error) {
+	appJWT, err := newGitHubAppJWT(r.AuthGitHubAppID, r.AuthGitHubAppKey)
+	if err != nil {
+		return nil, err
+	}
+	apiURL, err := getGitHubAPIURL(repoBaseURL)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("%s/app/installations/%s/access_tokens", apiURL, r.AuthGitHubAppInstallationID)
+	req, _ := http.NewRequestWithContext(ctx, "POST", u, nil)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+appJWT)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	var t *githubAppToken
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	if t == nil || t.Token == "" {
+		return nil, errors.New("token not found in response")
+	}
+	return t, nil
+}
+
+// newGitHubAppJWT creates a JWT signed with the GitHub App private key
+// provided, that can be used to authenticate as the app.
+func newGitHubAppJWT(appID, key string) (string, error) {
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(key))
+	if err != nil {
+		return "", fmt.Errorf("error parsing github app private key: %w", err)
+	}
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		Issuer:    appID,
+		IssuedAt:  jwt.NewNumericDate(now.Add(-60 * time.Second)), // Allow some clock drift
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(privateKey)
+}
+
+// getGitHubAPIURL returns the url of the GitHub API that should be used for
+// the repository url provided. Repositories hosted in GitHub Enterprise
+// Server instances use the API available in the instance.
+func getGitHubAPIURL(repoBaseURL string) (string, error) {
+	u, err := url.Parse(repoBaseURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host == "github.com" {
+		return githubAPIURL, nil
+	}
+	return fmt.Sprintf("https://%s/api/v3", u.Host), nil
+}
+
+// validateGitAuth validates the git authentication settings of the
+// repository provided. Values set to "=" are kept unchanged on updates, so
+// they are not validated.
+func validateGitAuth(r *hub.Repository) error {
+	sshAuth := isAuthValueSet(r.AuthSSHKey) || isAuthValueSet(r.AuthSSHKnownHosts)
+	githubAppAuth := isAuthValueSet(r.AuthGitHubAppID) ||
+		isAuthValueSet(r.AuthGitHubAppInstallationID) ||
+		isAuthValueSet(r.AuthGitHubAppKey)
+	tokenAuth := isAuthValueSet(r.AuthUser) || isAuthValueSet(r.AuthPass)
+	if !sshAuth && !githubAppAuth {
+		return nil
+	}
+	if !IsGitRepository(r) || (r.GitURL == "" && !GitRepoURLRE.MatchString(r.URL)) {
+		return errors.New("ssh and github app authentication are only supported by git repositories")
+	}
+	if (sshAuth && githubAppAuth) || tokenAuth {
+		return errors.New("only one authentication method can be used at a time")
+	}
+
+	if sshAuth {
+		if r.AuthSSHKey == "" || r.AuthSSHKnownHosts == "" {
+			return errors.New("ssh key and known hosts must be provided")
+		}
+		if isAuthValueSet(r.AuthSSHKey) {
+			if _, err := ssh.ParsePrivateKey([]byte(r.AuthSSHKey)); err != nil {
+				return fmt.Errorf("invalid ssh key: %w", err)
+			}
+		}
+		if isAuthValueSet(r.AuthSSHKnownHosts) {
+			if _, err := newKnownHostsCallback(r.AuthSSHKnownHosts); err != nil {
+				return fmt.Errorf("invalid ssh known hosts: %w", err)
+			}
+		}
+	}
+
+	if githubAppAuth {
+		if r.AuthGitHubAppID == "" || r.AuthGitHubAppInstallationID == "" || r.AuthGitHubAppKey == "" {
+			return errors.New("github app id, installation id and private key must be provided")
+		}
+		if isAuthValueSet(r.AuthGitHubAppInstallationID) {
+			if _, err := strconv.ParseInt(r.AuthGitHubAppInstallationID, 10, 64); err != nil {
+				return errors.New("invalid github app installation id")
+			}
+		}
+		if isAuthValueSet(r.AuthGitHubAppKey) {
+			if _, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(r.AuthGitHubAppKey)); err != nil {
+				return fmt.Errorf("invalid github app private key: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isAuthValueSet checks if the authentication value provided has been set,
+// ignoring the values that must be kept unchanged ("=").
+func isAuthValueSet(v string) bool {
+	return v != "" && v != "="
+}
@@ -0,0 +1,340 @@
+package repo
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestGetGitAuth(t *testing.T) {
+	ctx := context.Background()
+	sshKey, knownHosts := generateSSHCredentials(t)
+	appKey := generateGitHubAppKey(t)
+
+	t.Run("no credentials", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{}
+
+		u, auth, err := getGitAuth(ctx, nil, r, "https://github.com/org1/repo1")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/org1/repo1", u)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{AuthPass: "token"}
+
+		u, auth, err := getGitAuth(ctx, nil, r, "https://github.com/org1/repo1")
+		require.NoError(t, err)
+		assert.Equal(t, "https://github.com/org1/repo1", u)
+		assert.Equal(t, &githttp.BasicAuth{Username: "artifact-hub", Password: "token"}, auth)
+	})
+
+	t.Run("ssh key", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			AuthSSHKey:        sshKey,
+			AuthSSHKnownHosts: knownHosts,
+		}
+
+		u, auth, err := getGitAuth(ctx, nil, r, "https://github.com/org1/repo1")
+		require.NoError(t, err)
+		assert.Equal(t, "ssh://git@github.com/org1/repo1", u)
+		require.IsType(t, &gitssh.PublicKeys{}, auth)
+		assert.Equal(t, "git", auth.(*gitssh.PublicKeys).User)
+		assert.NotNil(t, auth.(*gitssh.PublicKeys).HostKeyCallback)
+	})
+
+	t.Run("ssh key: invalid known hosts", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			AuthSSHKey:        sshKey,
+			AuthSSHKnownHosts: "invalid",
+		}
+
+		_, _, err := getGitAuth(ctx, nil, r, "https://github.com/org1/repo1")
+		assert.ErrorContains(t, err, "error parsing ssh known hosts")
+	})
+
+	t.Run("github app: error minting installation token", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			AuthGitHubAppID:             "app1",
+			AuthGitHubAppInstallationID: "1",
+			AuthGitHubAppKey:            appKey,
+		}
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader("")),
+			StatusCode: http.StatusUnauthorized,
+		}, nil)
+
+		_, _, err := getGitAuth(ctx, hc, r, "https://github.com/org1/repo1")
+		assert.ErrorContains(t, err, "unexpected status code received: 401")
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("github app: installation token minted and cached", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			AuthGitHubAppID:             "app1",
+			AuthGitHubAppInstallationID: "2",
+			AuthGitHubAppKey:            appKey,
+		}
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", mock.MatchedBy(func(req *http.Request) bool {
+			// Check the JWT used to authenticate as the app
+			tokenString := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+			privateKey, _ := jwt.ParseRSAPrivateKeyFromPEM([]byte(appKey))
+			token, err := jwt.Parse(tokenString, func(*jwt.Token) (any, error) {
+				return &privateKey.PublicKey, nil
+			}, jwt.WithValidMethods([]string{"RS256"}), jwt.WithIssuer("app1"))
+
+			return req.Method == "POST" &&
+				req.URL.String() == "https://ghe.example.com/api/v3/app/installations/2/access_tokens" &&
+				req.Header.Get("Accept") == "application/vnd.github+json" &&
+				err == nil && token.Valid
+		})).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader(`{"token": "token1", "expires_at": "2999-01-01T00:00:00Z"}`)),
+			StatusCode: http.StatusCreated,
+		}, nil).Once()
+
+		for i := 0; i < 2; i++ {
+			u, auth, err := getGitAuth(ctx, hc, r, "https://ghe.example.com/org1/repo1")
+			require.NoError(t, err)
+			assert.Equal(t, "https://ghe.example.com/org1/repo1", u)
+			assert.Equal(t, &githttp.BasicAuth{Username: "x-access-token", Password: "token1"}, auth)
+		}
+		hc.AssertExpectations(t)
+	})
+
+	t.Run("github app: installation token minted once for concurrent requests", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			AuthGitHubAppID:             "app1",
+			AuthGitHubAppInstallationID: "3",
+			AuthGitHubAppKey:            appKey,
+		}
+		hc := &tests.HTTPClientMock{}
+		hc.On("Do", mock.Anything).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader(`{"token": "token3", "expires_at": "2999-01-01T00:00:00Z"}`)),
+			StatusCode: http.StatusCreated,
+		}, nil).Once()
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, auth, err := getGitAuth(ctx, hc, r, "https://github.com/org1/repo1")
+				assert.NoError(t, err)
+				assert.Equal(t, &githttp.BasicAuth{Username: "x-access-token", Password: "token3"}, auth)
+			}()
+		}
+		wg.Wait()
+		hc.AssertExpectations(t)
+	})
+}
+
+func TestCacheGitHubAppToken(t *testing.T) {
+	now := time.Now()
+	cacheGitHubAppToken("expired", &githubAppToken{Token: "token1", ExpiresAt: now.Add(-1 * time.Minute)})
+	cacheGitHubAppToken("aboutToExpire", &githubAppToken{Token: "token2", ExpiresAt: now.Add(1 * time.Minute)})
+	cacheGitHubAppToken("valid", &githubAppToken{Token: "token3", ExpiresAt: now.Add(1 * time.Hour)})
+
+	// Expired tokens are deleted from the cache
+	githubAppTokensMu.Lock()
+	_, expiredFound := githubAppTokens["expired"]
+	_, aboutToExpireFound := githubAppTokens["aboutToExpire"]
+	githubAppTokensMu.Unlock()
+	assert.False(t, expiredFound)
+	assert.True(t, aboutToExpireFound)
+
+	// Tokens about to expire are not used
+	_, ok := getCachedGitHubAppToken("aboutToExpire")
+	assert.False(t, ok)
+	token, ok := getCachedGitHubAppToken("valid")
+	assert.True(t, ok)
+	assert.Equal(t, "token3", token)
+}
+
+func TestValidateGitAuth(t *testing.T) {
+	sshKey, knownHosts := generateSSHCredentials(t)
+	appKey := generateGitHubAppKey(t)
+
+	testCases := []struct {
+		desc   string
+		r      *hub.Repository
+		errMsg string
+	}{
+		{
+			"no git credentials",
+			&hub.Repository{Kind: hub.Helm, URL: "https://repo1.com", AuthPass: "pass1"},
+			"",
+		},
+		{
+			"not a git repository",
+			&hub.Repository{Kind: hub.Helm, URL: "https://repo1.com", AuthSSHKey: sshKey, AuthSSHKnownHosts: knownHosts},
+			"only supported by git repositories",
+		},
+		{
+			"multiple authentication methods",
+			&hub.Repository{
+				Kind:              hub.OPA,
+				URL:               "https://github.com/org1/repo1",
+				AuthPass:          "pass1",
+				AuthSSHKey:        sshKey,
+				AuthSSHKnownHosts: knownHosts,
+			},
+			"only one authentication method can be used at a time",
+		},
+		{
+			"ssh known hosts not provided",
+			&hub.Repository{Kind: hub.OPA, URL: "https://github.com/org1/repo1", AuthSSHKey: sshKey},
+			"ssh key and known hosts must be provided",
+		},
+		{
+			"invalid ssh key",
+			&hub.Repository{
+				Kind:              hub.OPA,
+				URL:               "https://github.com/org1/repo1",
+				AuthSSHKey:        "invalid",
+				AuthSSHKnownHosts: knownHosts,
+			},
+			"invalid ssh key",
+		},
+		{
+			"invalid ssh known hosts",
+			&hub.Repository{
+				Kind:              hub.OPA,
+				URL:               "https://github.com/org1/repo1",
+				AuthSSHKey:        sshKey,
+				AuthSSHKnownHosts: "invalid",
+			},
+			"invalid ssh known hosts",
+		},
+		{
+			"valid ssh credentials",
+			&hub.Repository{
+				Kind:              hub.OPA,
+				URL:               "https://github.com/org1/repo1",
+				AuthPass:          "=",
+				AuthSSHKey:        sshKey,
+				AuthSSHKnownHosts: knownHosts,
+			},
+			"",
+		},
+		{
+			"ssh credentials unchanged",
+			&hub.Repository{
+				Kind:              hub.OPA,
+				URL:               "https://github.com/org1/repo1",
+				AuthSSHKey:        "=",
+				AuthSSHKnownHosts: "=",
+			},
+			"",
+		},
+		{
+			"github app private key not provided",
+			&hub.Repository{
+				Kind:                        hub.OPA,
+				URL:                         "https://github.com/org1/repo1",
+				AuthGitHubAppID:             "1",
+				AuthGitHubAppInstallationID: "1",
+			},
+			"github app id, installation id and private key must be provided",
+		},
+		{
+			"invalid github app installation id",
+			&hub.Repository{
+				Kind:                        hub.OPA,
+				URL:                         "https://github.com/org1/repo1",
+				AuthGitHubAppID:             "1",
+				AuthGitHubAppInstallationID: "invalid",
+				AuthGitHubAppKey:            appKey,
+			},
+			"invalid github app installation id",
+		},
+		{
+			"invalid github app private key",
+			&hub.Repository{
+				Kind:                        hub.OPA,
+				URL:                         "https://github.com/org1/repo1",
+				AuthGitHubAppID:             "1",
+				AuthGitHubAppInstallationID: "1",
+				AuthGitHubAppKey:            "invalid",
+			},
+			"invalid github app private key",
+		},
+		{
+			"valid github app credentials",
+			&hub.Repository{
+				Kind:                        hub.OPA,
+				URL:                         "https://github.com/org1/repo1",
+				AuthGitHubAppID:             "1",
+				AuthGitHubAppInstallationID: "1",
+				AuthGitHubAppKey:            appKey,
+			},
+			"",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			err := validateGitAuth(tc.r)
+			if tc.errMsg != "" {
+				assert.ErrorContains(t, err, tc.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// generateSSHCredentials is a helper function that generates a new SSH
+// private key and a known hosts entry for github.com.
+func generateSSHCredentials(t *testing.T) (string, string) {
+	t.Helper()
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	block, err := ssh.MarshalPrivateKey(privateKey, "")
+	require.NoError(t, err)
+	hostPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	sshHostPublicKey, err := ssh.NewPublicKey(hostPublicKey)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(block)), knownhosts.Line([]string{"github.com"}, sshHostPublicKey)
+}
+
+// generateGitHubAppKey is a helper function that generates a new GitHub App
+// private key.
+func generateGitHubAppKey(t *testing.T) string {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+	}))
+}
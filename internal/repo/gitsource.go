@@ -0,0 +1,239 @@
+package repo
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// gitSCPURLRE is a regexp used to validate scp-like git urls (for example:
+// git@gitea.example.com:org/group/repo.git).
+var gitSCPURLRE = regexp.MustCompile(`^[A-Za-z0-9_.-]+@[A-Za-z0-9_.-]+:[^/].*$`)
+
+// gitSource represents the location of the packages in a git repository.
+type gitSource struct {
+	// url represents the url of the git remote.
+	url string
+
+	// ref represents the reference (branch, tag or commit) to use.
+	ref *gitRef
+
+	// subdir represents the path in the repository where the packages are
+	// located.
+	subdir string
+}
+
+// GitWebSource represents the location of the packages of a git repository in
+// the web interface of the git host, which can be used to build links to the
+// repository files.
+type GitWebSource struct {
+	// BaseURL represents the web url of the git repository (for example:
+	// https://github.com/org/repo).
+	BaseURL string
+
+	// Host represents the git host (for example: github.com).
+	Host string
+
+	// Ref represents the branch, tag or commit used.
+	Ref string
+
+	// Subdir represents the path in the repository where the packages are
+	// located.
+	Subdir string
+}
+
+// gitRef represents a git reference. It can point to a branch, a tag or a
+// specific commit.
+type gitRef struct {
+	name plumbing.ReferenceName
+	hash plumbing.Hash
+}
+
+// isCommit checks if the reference points to a specific commit.
+func (ref *gitRef) isCommit() bool {
+	return !ref.hash.IsZero()
+}
+
+// localName returns the name of the local reference that tracks the remote
+// reference once it has been fetched.
+func (ref *gitRef) localName() plumbing.ReferenceName {
+	switch {
+	case ref.name == plumbing.HEAD:
+		return plumbing.NewRemoteHEADReferenceName(git.DefaultRemoteName)
+	case ref.name.IsBranch():
+		return plumbing.NewRemoteReferenceName(git.DefaultRemoteName, ref.name.Short())
+	default:
+		return ref.name
+	}
+}
+
+// getGitSource returns the git source of the repository provided. When the
+// repository has a git url set, the git url, ref and subdirectory configured
+// are used. Otherwise the git remote and packages path are extracted from the
+// repository url, which must follow the format used by GitHub-like hosts
+// (https://host/org/repo/path/to/packages).
+func getGitSource(r *hub.Repository) (*gitSource, error) {
+	if r.GitURL != "" {
+		ref := r.GitRef
+		if ref == "" {
+			ref = r.Branch
+		}
+		return &gitSource{
+			url:    r.GitURL,
+			ref:    parseGitRef(ref),
+			subdir: cleanGitSubdir(r.GitSubdir),
+		}, nil
+	}
+
+	repoBaseURL, packagesPath, err := parseGitRepoURL(r.URL)
+	if err != nil {
+		return nil, err
+	}
+	ref := r.GitRef
+	if ref == "" {
+		ref = GetBranch(r)
+	}
+	return &gitSource{
+		url:    repoBaseURL,
+		ref:    parseGitRef(ref),
+		subdir: packagesPath,
+	}, nil
+}
+
+// GetGitWebSource returns the location in the git host web interface of the
+// packages of the repository provided, based on its git url, ref and
+// subdirectory (or its url when they are not set). It returns nil when the
+// location cannot be determined.
+func GetGitWebSource(r *hub.Repository) *GitWebSource {
+	src, err := getGitSource(r)
+	if err != nil {
+		return nil
+	}
+	baseURL, host := getGitWebURL(src.url)
+	if baseURL == "" {
+		return nil
+	}
+	var ref string
+	switch {
+	case src.ref.isCommit():
+		ref = src.ref.hash.String()
+	case src.ref.name == plumbing.HEAD:
+		ref = plumbing.HEAD.String()
+	default:
+		ref = src.ref.name.Short()
+	}
+	return &GitWebSource{
+		BaseURL: baseURL,
+		Host:    host,
+		Ref:     ref,
+		Subdir:  src.subdir,
+	}
+}
+
+// getGitWebURL returns the web url and the host of the git remote url
+// provided (https, ssh and scp-like urls are supported).
+func getGitWebURL(gitURL string) (string, string) {
+	var host, repoPath string
+	if gitSCPURLRE.MatchString(gitURL) {
+		_, hostAndPath, _ := strings.Cut(gitURL, "@")
+		host, repoPath, _ = strings.Cut(hostAndPath, ":")
+	} else {
+		u, err := url.Parse(gitURL)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "ssh") {
+			return "", ""
+		}
+		host, repoPath = u.Hostname(), u.Path
+	}
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return "", ""
+	}
+	return "https://" + host + "/" + repoPath, host
+}
+
+// parseGitRef parses the git reference provided. Commits are identified by
+// their full hash, and tags and other references by their full name (i.e.
+// refs/tags/v1.0.0). Any other value is considered a branch name. When no
+// reference is provided, the remote's HEAD (default branch) is used.
+func parseGitRef(ref string) *gitRef {
+	switch {
+	case ref == "":
+		return &gitRef{name: plumbing.HEAD}
+	case plumbing.IsHash(ref):
+		return &gitRef{hash: plumbing.NewHash(ref)}
+	case strings.HasPrefix(ref, "refs/"):
+		return &gitRef{name: plumbing.ReferenceName(ref)}
+	default:
+		return &gitRef{name: plumbing.NewBranchReferenceName(ref)}
+	}
+}
+
+// cleanGitSubdir returns a clean version of the git subdirectory provided,
+// relative to the repository root.
+func cleanGitSubdir(subdir string) string {
+	return strings.Trim(path.Clean("/"+subdir), "/")
+}
+
+// isHTTPGitURL checks if the git url provided uses the https scheme.
+func isHTTPGitURL(gitURL string) bool {
+	return strings.HasPrefix(gitURL, "https://")
+}
+
+// validateGitSource validates the git source settings of the repository
+// provided.
+func validateGitSource(r *hub.Repository) error {
+	if r.GitURL == "" && r.GitRef == "" && r.GitSubdir == "" {
+		return nil
+	}
+	if !IsGitRepository(r) {
+		return errors.New("git url, ref and subdirectory are only supported by git repositories")
+	}
+
+	// Git url
+	if r.GitURL == "" {
+		if r.GitSubdir != "" {
+			return errors.New("git subdirectory can only be used when a git url is provided")
+		}
+	} else {
+		if !gitSCPURLRE.MatchString(r.GitURL) {
+			u, err := url.Parse(r.GitURL)
+			if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "ssh") {
+				return errors.New("invalid git url: only https, ssh and scp-like urls are supported")
+			}
+			if _, ok := u.User.Password(); ok {
+				return errors.New("git urls with credentials not allowed")
+			}
+		}
+		if !isHTTPGitURL(r.GitURL) && (isAuthValueSet(r.AuthPass) || isAuthValueSet(r.AuthGitHubAppKey)) {
+			return errors.New("access token and github app authentication require an https git url")
+		}
+	}
+
+	// Git ref
+	if r.GitRef != "" {
+		if strings.ContainsAny(r.GitRef, " ~^:?*[\\") || strings.Contains(r.GitRef, "..") {
+			return errors.New("invalid git ref")
+		}
+		if strings.HasPrefix(r.GitRef, "refs/") && !plumbing.ReferenceName(r.GitRef).IsTag() &&
+			!plumbing.ReferenceName(r.GitRef).IsBranch() {
+			return errors.New("invalid git ref: only branches, tags and commits are supported")
+		}
+	}
+
+	// Git subdirectory
+	if r.GitSubdir != "" {
+		for _, part := range strings.Split(r.GitSubdir, "/") {
+			if part == ".." {
+				return errors.New("invalid git subdirectory")
+			}
+		}
+	}
+
+	return nil
+}
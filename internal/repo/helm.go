@@ -1,11 +1,15 @@
 package repo
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/url"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/artifacthub/hub/internal/httpw"
@@ -19,6 +23,70 @@ const (
 	helmRepoIndexFile = "index.yaml"
 )
 
+// HelmOCIChart represents a chart available in a Helm OCI repository.
+type HelmOCIChart struct {
+	Name     string
+	URL      string
+	Versions []string
+}
+
+// GetHelmOCICharts returns the charts available in the Helm OCI repository
+// provided, along with their versions. When the repository has been set up in
+// namespace mode, all the charts found under the namespace are returned.
+func GetHelmOCICharts(
+	ctx context.Context,
+	tg hub.OCITagsGetter,
+	rl hub.OCIRepositoriesLister,
+	r *hub.Repository,
+) ([]*HelmOCIChart, error) {
+	data, err := getHelmData(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repository url points to a single chart
+	if data == nil || !data.Namespace {
+		versions, err := tg.Tags(ctx, r, true, true)
+		if err != nil {
+			return nil, err
+		}
+		return []*HelmOCIChart{{Name: path.Base(r.URL), URL: r.URL, Versions: versions}}, nil
+	}
+
+	// Repository url points to a namespace containing multiple charts
+	names, err := rl.Repositories(ctx, r, data.Listing)
+	if err != nil {
+		return nil, err
+	}
+	charts := make([]*HelmOCIChart, 0, len(names))
+	for _, name := range names {
+		chartRepository := *r
+		chartRepository.URL = strings.TrimSuffix(r.URL, "/") + "/" + name
+		versions, err := tg.Tags(ctx, &chartRepository, true, true)
+		if err != nil {
+			return nil, fmt.Errorf("error getting chart %s versions: %w", name, err)
+		}
+		charts = append(charts, &HelmOCIChart{
+			Name:     path.Base(name),
+			URL:      chartRepository.URL,
+			Versions: versions,
+		})
+	}
+	return charts, nil
+}
+
+// getHelmData returns the Helm specific data of the repository provided.
+func getHelmData(r *hub.Repository) (*hub.HelmData, error) {
+	if r.Data == nil {
+		return nil, nil
+	}
+	var data *hub.HelmData
+	if err := json.Unmarshal(r.Data, &data); err != nil {
+		return nil, fmt.Errorf("invalid helm repository data: %w", err)
+	}
+	return data, nil
+}
+
 // HelmIndexLoader provides a mechanism to load a Helm repository index file,
 // verifying it is valid.
 type HelmIndexLoader struct{}
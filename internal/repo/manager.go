@@ -2,7 +2,9 @@ package repo
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,6 +15,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,7 +25,7 @@ import (
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
-	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -34,21 +37,30 @@ import (
 
 const (
 	// Database queries
-	addRepoDBQ                = `select add_repository($1::uuid, $2::text, $3::jsonb)`
-	checkRepoNameAvailDBQ     = `select repository_id from repository where name = $1`
-	checkRepoURLAvailDBQ      = `select repository_id from repository where trim(trailing '/' from url) = $1`
-	deleteRepoDBQ             = `select delete_repository($1::uuid, $2::text)`
-	getRepoByIDDBQ            = `select get_repository_by_id($1::uuid, $2::boolean)`
-	getRepoByNameDBQ          = `select get_repository_by_name($1::text, $2::boolean)`
-	getRepoPkgsDigestDBQ      = `select get_repository_packages_digest($1::uuid)`
-	getUserEmailDBQ           = `select email from "user" where user_id = $1`
-	searchRepositoriesDBQ     = `select * from search_repositories($1::jsonb)`
-	setLastScanningResultsDBQ = `select set_last_scanning_results($1::uuid, $2::text, $3::boolean)`
-	setLastTrackingResultsDBQ = `select set_last_tracking_results($1::uuid, $2::text, $3::boolean)`
-	setVerifiedPublisherDBQ   = `select set_verified_publisher($1::uuid, $2::boolean)`
-	transferRepoDBQ           = `select transfer_repository($1::text, $2::uuid, $3::text, $4::boolean)`
-	updateRepoDBQ             = `select update_repository($1::uuid, $2::jsonb)`
-	updateRepoDigestDBQ       = `update repository set digest = $2 where repository_id = $1`
+	addRepoDBQ                    = `select add_repository($1::uuid, $2::text, $3::jsonb)`
+	checkRepoNameAvailDBQ         = `select repository_id from repository where name = $1`
+	checkRepoURLAvailDBQ          = `select repository_id from repository where trim(trailing '/' from url) = $1`
+	clearRepoTrackingRequestDBQ   = `update repository set tracking_requested_at = null where repository_id = $1 and tracking_requested_at <= to_timestamp($2)`
+	deleteRepoDBQ                 = `select delete_repository($1::uuid, $2::text)`
+	getRepoByIDDBQ                = `select get_repository_by_id($1::uuid, $2::boolean)`
+	getRepoByNameDBQ              = `select get_repository_by_name($1::text, $2::boolean)`
+	getRepoPkgsDigestDBQ          = `select get_repository_packages_digest($1::uuid)`
+	getRepoPushSecretDBQ          = `select coalesce(push_secret, '') from repository where repository_id = $1`
+	getRepoPkgsPathsDBQ           = `select get_repository_packages_paths($1::uuid)`
+	getRepoTrackingRunsDBQ        = `select * from get_repository_tracking_runs($1::uuid, $2::text, $3::int, $4::int)`
+	getRepoTrackingStatusDBQ      = `select get_repository_tracking_status($1::uuid, $2::text, $3::integer)`
+	getUserEmailDBQ               = `select email from "user" where user_id = $1`
+	registerRepoTrackingRunDBQ    = `select register_repository_tracking_run($1::jsonb)`
+	requestRepoTrackingDBQ        = `update repository set tracking_requested_at = current_timestamp where repository_id = $1`
+	requestRepoTrackingByOwnerDBQ = `select request_repository_tracking($1::uuid, $2::text)`
+	searchRepositoriesDBQ         = `select * from search_repositories($1::jsonb)`
+	setLastScanningResultsDBQ     = `select set_last_scanning_results($1::uuid, $2::text, $3::boolean)`
+	setLastTrackingResultsDBQ     = `select set_last_tracking_results($1::uuid, $2::text, $3::boolean)`
+	setVerifiedPublisherDBQ       = `select set_verified_publisher($1::uuid, $2::boolean)`
+	transferRepoDBQ               = `select transfer_repository($1::text, $2::uuid, $3::text, $4::boolean)`
+	updateRepoDBQ                 = `select update_repository($1::uuid, $2::jsonb)`
+	updateRepoDigestDBQ           = `update repository set digest = $2 where repository_id = $1`
+	updateRepoPushSecretDBQ       = `select update_repository_push_secret($1::uuid, $2::text, $3::text)`
 )
 
 const (
@@ -56,8 +68,16 @@ const (
 	// contains the repository metadata in an OCI image.
 	MetadataLayerMediaType = "application/vnd.cncf.artifacthub.repository-metadata.layer.v1.yaml"
 
-	artifacthubTag        = "artifacthub.io"
-	maxContainerImageTags = 10
+	// MaxDiscoveredContainerImageTags represents the maximum number of tags
+	// that can be discovered from the registry in a container image
+	// repository.
+	MaxDiscoveredContainerImageTags = 50
+
+	artifacthubTag            = "artifacthub.io"
+	maxContainerImageTags     = 10
+	maxContainerImagePatterns = 10
+	minTrackingInterval       = 5 * 60           // 5 minutes (in seconds)
+	maxTrackingInterval       = 7 * 24 * 60 * 60 // 1 week (in seconds)
 )
 
 var (
@@ -74,6 +94,10 @@ var (
 	// repository url is not supported.
 	ErrSchemeNotSupported = errors.New("scheme not supported")
 
+	// errTrackerDaemonNotEnabled indicates that the repositories cannot be
+	// tracked on demand because the tracker is not running in daemon mode.
+	errTrackerDaemonNotEnabled = errors.New("repositories can only be tracked on demand when the tracker runs in daemon mode")
+
 	// GitRepoURLRE is a regexp used to validate and parse an http based git
 	// repository URL.
 	GitRepoURLRE = regexp.MustCompile(`^(https:\/\/([A-Za-z0-9_.-]+)\/[A-Za-z0-9_.-]+\/[A-Za-z0-9_.-]+)\/?(.*)$`)
@@ -121,7 +145,9 @@ type Manager struct {
 	il  hub.HelmIndexLoader
 	tg  hub.OCITagsGetter
 	op  hub.OCIPuller
+	rl  hub.OCIRepositoriesLister
 	az  hub.Authorizer
+	kr  *util.Keyring
 }
 
 // NewManager creates a new Manager instance.
@@ -148,7 +174,12 @@ func NewManager(
 
 	// Setup repository cloner
 	if m.rc == nil {
-		m.rc = &Cloner{}
+		m.rc = NewCloner(hc)
+	}
+
+	// Setup OCI repositories lister
+	if m.rl == nil {
+		m.rl = oci.NewRepositoriesLister(cfg, m.op)
 	}
 
 	return m
@@ -162,6 +193,14 @@ func WithHelmIndexLoader(l hub.HelmIndexLoader) func(m *Manager) {
 	}
 }
 
+// WithKeyring allows providing the Keyring used to encrypt and decrypt the
+// repositories credentials for a Manager instance.
+func WithKeyring(kr *util.Keyring) func(m *Manager) {
+	return func(m *Manager) {
+		m.kr = kr
+	}
+}
+
 // WithOCITagsGetter allows providing a specific OCITagsGetter implementation
 // for a Manager instance.
 func WithOCITagsGetter(tg hub.OCITagsGetter) func(m *Manager) {
@@ -178,6 +217,14 @@ func WithOCIPuller(p hub.OCIPuller) func(m *Manager) {
 	}
 }
 
+// WithOCIRepositoriesLister allows providing a specific
+// OCIRepositoriesLister implementation for a Manager instance.
+func WithOCIRepositoriesLister(rl hub.OCIRepositoriesLister) func(m *Manager) {
+	return func(m *Manager) {
+		m.rl = rl
+	}
+}
+
 // Add adds the provided repository to the database.
 func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -201,6 +248,9 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 	if err := validateData(r); err != nil {
 		return fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
 	}
+	if err := validateTrackingInterval(r); err != nil {
+		return fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+	}
 
 	// Authorize action if the repository will be added to an organization
 	if orgName != "" {
@@ -214,8 +264,12 @@ func (m *Manager) Add(ctx context.Context, orgName string, r *hub.Repository) er
 	}
 
 	// Add repository to the database
-	rJSON, _ := json.Marshal(r)
-	_, err := m.db.Exec(ctx, addRepoDBQ, userID, orgName, rJSON)
+	rToStore, err := m.encryptCredentials(r)
+	if err != nil {
+		return err
+	}
+	rJSON, _ := json.Marshal(rToStore)
+	_, err = m.db.Exec(ctx, addRepoDBQ, userID, orgName, rJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
@@ -286,34 +340,7 @@ func (m *Manager) ClaimOwnership(ctx context.Context, repoName, orgName string)
 
 	// Get repository metadata
 	var basePath string
-	switch r.Kind {
-	case
-		hub.ArgoTemplate,
-		hub.Backstage,
-		hub.Bootc,
-		hub.CoreDNS,
-		hub.Falco,
-		hub.Gatekeeper,
-		hub.Headlamp,
-		hub.HelmPlugin,
-		hub.InspektorGadget,
-		hub.KCL,
-		hub.KedaScaler,
-		hub.Keptn,
-		hub.KnativeClientPlugin,
-		hub.Krew,
-		hub.KubeArmor,
-		hub.Kubewarden,
-		hub.Kyverno,
-		hub.Meshery,
-		hub.OLM,
-		hub.OPA,
-		hub.OpenCost,
-		hub.Radius,
-		hub.TBAction,
-		hub.TektonPipeline,
-		hub.TektonTask,
-		hub.TektonStepAction:
+	if IsGitRepository(r) {
 		tmpDir, packagesPath, err := m.rc.CloneRepository(ctx, r)
 		if err != nil {
 			return err
@@ -342,6 +369,14 @@ func (m *Manager) ClaimOwnership(ctx context.Context, repoName, orgName string)
 	return hub.ErrInsufficientPrivilege
 }
 
+// ClearTrackingRequest clears the tracking request of the provided repository,
+// provided it was requested before the tracking started. Requests received
+// while the repository was being tracked are kept pending.
+func (m *Manager) ClearTrackingRequest(ctx context.Context, repositoryID string, trackingStartedAt int64) error {
+	_, err := m.db.Exec(ctx, clearRepoTrackingRequestDBQ, repositoryID, trackingStartedAt)
+	return err
+}
+
 // Delete deletes the provided repository from the database.
 func (m *Manager) Delete(ctx context.Context, name string) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -374,6 +409,66 @@ func (m *Manager) Delete(ctx context.Context, name string) error {
 	return err
 }
 
+// DeletePushSecret deletes the push secret of the provided repository,
+// disabling push events for it.
+func (m *Manager) DeletePushSecret(ctx context.Context, name string) error {
+	return m.updatePushSecret(ctx, name, "")
+}
+
+// GeneratePushSecret generates a new secret used to verify the push events
+// received for the provided repository, replacing the existing one if any.
+func (m *Manager) GeneratePushSecret(ctx context.Context, name string) (string, error) {
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	secret := hex.EncodeToString(randomBytes)
+	if err := m.updatePushSecret(ctx, name, secret); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// updatePushSecret updates the push secret of the provided repository.
+func (m *Manager) updatePushSecret(ctx context.Context, name, secret string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Authorize action if the repository is owned by an organization
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return err
+	}
+	if r.OrganizationName != "" {
+		if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+			OrganizationName: r.OrganizationName,
+			UserID:           userID,
+			Action:           hub.UpdateOrganizationRepository,
+		}); err != nil {
+			return err
+		}
+	}
+
+	// Encrypt push secret when encryption is enabled
+	if secret != "" && m.kr.Enabled() {
+		secret, err = m.kr.Encrypt(secret)
+		if err != nil {
+			return fmt.Errorf("error encrypting push secret: %w", err)
+		}
+	}
+
+	// Update push secret in database
+	_, err = m.db.Exec(ctx, updateRepoPushSecretDBQ, userID, name, secret)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // GetByID returns the repository identified by the id provided.
 func (m *Manager) GetByID(
 	ctx context.Context,
@@ -390,8 +485,15 @@ func (m *Manager) GetByID(
 
 	// Get repository from database
 	var r *hub.Repository
-	err := util.DBQueryUnmarshal(ctx, m.db, &r, getRepoByIDDBQ, repositoryID, includeCredentials)
-	return r, err
+	if err := util.DBQueryUnmarshal(ctx, m.db, &r, getRepoByIDDBQ, repositoryID, includeCredentials); err != nil {
+		return nil, err
+	}
+	if includeCredentials && r != nil {
+		if err := m.decryptCredentials(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
 }
 
 // GetByName returns the repository identified by the name provided.
@@ -407,8 +509,15 @@ func (m *Manager) GetByName(
 
 	// Get repository from database
 	var r *hub.Repository
-	err := util.DBQueryUnmarshal(ctx, m.db, &r, getRepoByNameDBQ, name, includeCredentials)
-	return r, err
+	if err := util.DBQueryUnmarshal(ctx, m.db, &r, getRepoByNameDBQ, name, includeCredentials); err != nil {
+		return nil, err
+	}
+	if includeCredentials && r != nil {
+		if err := m.decryptCredentials(r); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
 }
 
 // GetMetadata reads and parses the metadata file of the repository provided.
@@ -499,7 +608,11 @@ func (m *Manager) locateMetadataFile(r *hub.Repository, basePath string) string
 		hub.TektonPipeline,
 		hub.TektonTask,
 		hub.TektonStepAction:
-		mdFile = filepath.Join(basePath, hub.RepositoryMetadataFile)
+		if (SupportsOCIArtifacts(r.Kind) || isTektonKind(r.Kind)) && SchemeIsOCI(u) {
+			mdFile = r.URL
+		} else {
+			mdFile = filepath.Join(basePath, hub.RepositoryMetadataFile)
+		}
 	}
 	return mdFile
 }
@@ -561,6 +674,23 @@ func (m *Manager) GetPackagesDigest(
 	return pd, err
 }
 
+// GetPackagesPaths returns the relative paths for all packages in the
+// repository identified by the id provided.
+func (m *Manager) GetPackagesPaths(
+	ctx context.Context,
+	repositoryID string,
+) (map[string]string, error) {
+	// Validate input
+	if _, err := uuid.FromString(repositoryID); err != nil {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+
+	// Get repository packages paths from database
+	pp := make(map[string]string)
+	err := util.DBQueryUnmarshal(ctx, m.db, &pp, getRepoPkgsPathsDBQ, repositoryID)
+	return pp, err
+}
+
 // GetRemoteDigest gets the repository's digest available in the remote.
 func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (string, error) {
 	var digest string
@@ -577,12 +707,22 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 				return "", err
 			}
 		case SchemeIsOCI(u):
-			// Digest is obtained by hashing the list of versions available
-			versions, err := m.tg.Tags(ctx, r, true, true)
+			// Digest is obtained by hashing the list of versions available (of
+			// each of the charts found when the url points to a namespace)
+			charts, err := GetHelmOCICharts(ctx, m.tg, m.rl, r)
 			if err != nil {
 				return digest, err
 			}
-			digest = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(versions, ","))))
+			data, _ := getHelmData(r)
+			if data == nil || !data.Namespace {
+				digest = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(charts[0].Versions, ","))))
+			} else {
+				entries := make([]string, 0, len(charts))
+				for _, c := range charts {
+					entries = append(entries, c.Name+":"+strings.Join(c.Versions, ","))
+				}
+				digest = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(entries, ";"))))
+			}
 		}
 
 	case r.Kind == hub.OLM && SchemeIsOCI(u):
@@ -598,9 +738,17 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 		}
 		digest = desc.Digest.String()
 
-	case GitRepoURLRE.MatchString(r.URL):
+	case (SupportsOCIArtifacts(r.Kind) || isTektonKind(r.Kind)) && SchemeIsOCI(u):
+		// Digest is obtained by hashing the list of tags available
+		tags, err := m.tg.Tags(ctx, r, true, false)
+		if err != nil {
+			return digest, err
+		}
+		digest = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(tags, ","))))
+
+	case r.GitURL != "" || GitRepoURLRE.MatchString(r.URL):
 		// Do not track repo's digest for Tekton repos using git based versioning
-		if (r.Kind == hub.TektonTask || r.Kind == hub.TektonPipeline || r.Kind == hub.TektonStepAction) && r.Data != nil {
+		if isTektonKind(r.Kind) && r.Data != nil {
 			var data *hub.TektonData
 			if err := json.Unmarshal(r.Data, &data); err != nil {
 				return "", fmt.Errorf("invalid tekton repository data: %w", err)
@@ -610,26 +758,44 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 			}
 		}
 
-		// Digest is obtained from the last commit in the repository
-		matches := GitRepoURLRE.FindStringSubmatch(r.URL)
-		repoBaseURL := matches[1]
+		// Digest is obtained from the commit the repository's git ref points to
+		src, err := getGitSource(r)
+		if err != nil {
+			return digest, err
+		}
+		if src.ref.isCommit() {
+			return src.ref.hash.String(), nil
+		}
+		remoteURL, auth, err := getGitAuth(ctx, m.hc, r, src.url)
+		if err != nil {
+			return digest, err
+		}
 		remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{
-			URLs: []string{repoBaseURL},
+			URLs: []string{remoteURL},
+		})
+		refs, err := remote.ListContext(ctx, &git.ListOptions{
+			Auth:          auth,
+			PeelingOption: git.AppendPeeled,
 		})
-		listOptions := &git.ListOptions{}
-		if r.AuthPass != "" {
-			listOptions.Auth = &githttp.BasicAuth{
-				Username: "artifact-hub",
-				Password: r.AuthPass,
-			}
-		}
-		refs, err := remote.List(listOptions)
 		if err != nil {
 			return digest, err
 		}
-		branch := GetBranch(r)
+		refName := src.ref.name
 		for _, ref := range refs {
-			if ref.Name().IsBranch() && ref.Name().Short() == branch {
+			if ref.Name() == plumbing.HEAD && refName == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference {
+				refName = ref.Target()
+			}
+		}
+		peeledRefName := plumbing.ReferenceName(refName.String() + "^{}")
+		for _, ref := range refs {
+			if ref.Type() != plumbing.HashReference {
+				continue
+			}
+			switch ref.Name() {
+			case peeledRefName:
+				// Annotated tags are peeled to the commit they point to
+				return ref.Hash().String(), nil
+			case refName:
 				digest = ref.Hash().String()
 			}
 		}
@@ -638,6 +804,139 @@ func (m *Manager) GetRemoteDigest(ctx context.Context, r *hub.Repository) (strin
 	return digest, nil
 }
 
+// GetTrackingRunsJSON returns the tracking runs of the provided repository,
+// most recent first, as a json array.
+func (m *Manager) GetTrackingRunsJSON(
+	ctx context.Context,
+	name string,
+	p *hub.Pagination,
+) (*hub.JSONQueryResult, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Get tracking runs from database
+	return util.DBQueryJSONWithPagination(ctx, m.db, getRepoTrackingRunsDBQ, userID, name, p.Limit, p.Offset)
+}
+
+// GetTrackingStatusJSON returns the tracking status of the provided
+// repository as a json object.
+func (m *Manager) GetTrackingStatusJSON(ctx context.Context, name string) ([]byte, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+
+	// Get tracking status from database
+	return util.DBQueryJSON(ctx, m.db, getRepoTrackingStatusDBQ, userID, name, util.DBLockKeyTrackRepository)
+}
+
+// ProcessPushEvent verifies the push event provided and, if it affects the
+// repository, requests the tracker to process it as soon as possible. The
+// returned boolean indicates whether the repository tracking was requested.
+func (m *Manager) ProcessPushEvent(ctx context.Context, name string, h http.Header, body []byte) (bool, error) {
+	// Validate input
+	if name == "" {
+		return false, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+	if !m.cfg.GetBool("tracker.daemon.enabled") {
+		return false, fmt.Errorf("%w: %w", hub.ErrInvalidInput, errTrackerDaemonNotEnabled)
+	}
+
+	// Verify push event (only the push secret is decrypted, the repository
+	// credentials are left to the tracker)
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return false, err
+	}
+	if err := m.db.QueryRow(ctx, getRepoPushSecretDBQ, r.RepositoryID).Scan(&r.PushSecret); err != nil {
+		return false, err
+	}
+	if r.PushSecret == "" {
+		return false, fmt.Errorf("%w: %s", hub.ErrInvalidInput, "push events not enabled for this repository")
+	}
+	r.PushSecret, err = m.kr.Decrypt(r.PushSecret)
+	if err != nil {
+		return false, fmt.Errorf("error decrypting push secret: %w", err)
+	}
+	affected, err := parsePushEvent(r, h, body)
+	if err != nil {
+		if errors.Is(err, errInvalidPushEventSignature) {
+			return false, hub.ErrInsufficientPrivilege
+		}
+		return false, fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+	}
+	if !affected || r.Disabled {
+		return false, nil
+	}
+
+	// Request repository tracking
+	if _, err := m.db.Exec(ctx, requestRepoTrackingDBQ, r.RepositoryID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RegisterTrackingRun registers the provided repository tracking run.
+func (m *Manager) RegisterTrackingRun(ctx context.Context, run *hub.TrackingRun) error {
+	// Validate input
+	if run == nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "tracking run not provided")
+	}
+	if _, err := uuid.FromString(run.RepositoryID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid repository id")
+	}
+
+	// Register tracking run in database
+	runJSON, _ := json.Marshal(run)
+	_, err := m.db.Exec(ctx, registerRepoTrackingRunDBQ, runJSON)
+	return err
+}
+
+// RequestTracking requests the tracker to process the provided repository as
+// soon as possible.
+func (m *Manager) RequestTracking(ctx context.Context, name string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if name == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "name not provided")
+	}
+	if !m.cfg.GetBool("tracker.daemon.enabled") {
+		return fmt.Errorf("%w: %w", hub.ErrInvalidInput, errTrackerDaemonNotEnabled)
+	}
+
+	// Authorize action if the repository is owned by an organization
+	r, err := m.GetByName(ctx, name, false)
+	if err != nil {
+		return err
+	}
+	if r.OrganizationName != "" {
+		if err := m.az.Authorize(ctx, &hub.AuthorizeInput{
+			OrganizationName: r.OrganizationName,
+			UserID:           userID,
+			Action:           hub.UpdateOrganizationRepository,
+		}); err != nil {
+			return err
+		}
+	}
+	if r.Disabled {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "repository is disabled")
+	}
+
+	// Request repository tracking
+	_, err = m.db.Exec(ctx, requestRepoTrackingByOwnerDBQ, userID, name)
+	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
+		return hub.ErrInsufficientPrivilege
+	}
+	return err
+}
+
 // Search searches for repositories in the database that the criteria defined
 // in the input provided.
 func (m *Manager) Search(
@@ -659,6 +958,13 @@ func (m *Manager) Search(
 	if err := json.Unmarshal(result.Data, &repositories); err != nil {
 		return nil, err
 	}
+	if input.IncludeCredentials {
+		for _, r := range repositories {
+			if err := m.decryptCredentials(r); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	return &hub.SearchRepositoryResult{
 		Repositories: repositories,
@@ -762,7 +1068,8 @@ func (m *Manager) Transfer(ctx context.Context, repoName, orgName string, owners
 		}
 	}
 
-	// Update repository owner in database
+	// Update repository owner in database (the audit event is registered by
+	// the database function in the same transaction)
 	_, err := m.db.Exec(ctx, transferRepoDBQ, repoName, userIDP, orgNameP, ownershipClaim)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
@@ -787,6 +1094,9 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 	if err := validateData(r); err != nil {
 		return fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
 	}
+	if err := validateTrackingInterval(r); err != nil {
+		return fmt.Errorf("%w: %w", hub.ErrInvalidInput, err)
+	}
 
 	// Authorize action if the repository is owned by an organization
 	rBefore, err := m.GetByName(ctx, r.Name, false)
@@ -804,7 +1114,11 @@ func (m *Manager) Update(ctx context.Context, r *hub.Repository) error {
 	}
 
 	// Update repository in database
-	rJSON, _ := json.Marshal(r)
+	rToStore, err := m.encryptCredentials(r)
+	if err != nil {
+		return err
+	}
+	rJSON, _ := json.Marshal(rToStore)
 	_, err = m.db.Exec(ctx, updateRepoDBQ, userID, rJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
@@ -872,22 +1186,81 @@ func (m *Manager) validateURL(r *hub.Repository) error {
 		hub.TektonPipeline,
 		hub.TektonTask,
 		hub.TektonStepAction:
-		if SchemeIsHTTP(u) && !GitRepoURLRE.MatchString(r.URL) {
+		if SchemeIsHTTP(u) && r.GitURL == "" && !GitRepoURLRE.MatchString(r.URL) {
 			return errors.New("invalid url format")
 		}
+		if SchemeIsOCI(u) && r.Kind != hub.OLM && !SupportsOCIArtifacts(r.Kind) && !isTektonKind(r.Kind) {
+			return errors.New("oci urls are not supported by this repository kind")
+		}
 	}
-	return nil
+	return validateGitSource(r)
 }
 
 // validateCredentials validates the credentials of the repository provided.
 func (m *Manager) validateCredentials(r *hub.Repository) error {
 	allowPrivateRepos := m.cfg.GetBool("server.allowPrivateRepositories")
-	if !allowPrivateRepos && (r.AuthUser != "" || r.AuthPass != "") {
+	hasCredentials := r.AuthUser != "" ||
+		r.AuthPass != "" ||
+		r.AuthSSHKey != "" ||
+		r.AuthSSHKnownHosts != "" ||
+		r.AuthGitHubAppID != "" ||
+		r.AuthGitHubAppInstallationID != "" ||
+		r.AuthGitHubAppKey != ""
+	if !allowPrivateRepos && hasCredentials {
 		return errors.New("private repositories not allowed")
 	}
+	if err := validateGitAuth(r); err != nil {
+		return err
+	}
+	if (isAuthValueSet(r.AuthSSHKey) || isAuthValueSet(r.AuthGitHubAppKey)) && !m.kr.Enabled() {
+		return errors.New("ssh and github app credentials cannot be stored: encryption key not configured")
+	}
+	return nil
+}
+
+// encryptCredentials returns a copy of the repository provided with its
+// credentials encrypted, ready to be stored in the database. Credentials are
+// stored as provided when encryption is not enabled.
+func (m *Manager) encryptCredentials(r *hub.Repository) (*hub.Repository, error) {
+	rCopy := *r
+	if !m.kr.Enabled() {
+		return &rCopy, nil
+	}
+	for _, v := range getCredentials(&rCopy) {
+		if !isAuthValueSet(*v) {
+			continue
+		}
+		encrypted, err := m.kr.Encrypt(*v)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting credentials: %w", err)
+		}
+		*v = encrypted
+	}
+	return &rCopy, nil
+}
+
+// decryptCredentials decrypts the credentials of the repository provided,
+// previously encrypted when they were stored in the database.
+func (m *Manager) decryptCredentials(r *hub.Repository) error {
+	for _, v := range getCredentials(r) {
+		if *v == "" {
+			continue
+		}
+		decrypted, err := m.kr.Decrypt(*v)
+		if err != nil {
+			return fmt.Errorf("error decrypting credentials: %w", err)
+		}
+		*v = decrypted
+	}
 	return nil
 }
 
+// getCredentials returns references to the repository's credentials that are
+// encrypted at rest.
+func getCredentials(r *hub.Repository) []*string {
+	return []*string{&r.AuthUser, &r.AuthPass, &r.AuthSSHKey, &r.AuthGitHubAppKey, &r.PushSecret}
+}
+
 // validateData checks the kind specific data provided.
 func validateData(r *hub.Repository) error {
 	switch r.Kind {
@@ -900,6 +1273,32 @@ func validateData(r *hub.Repository) error {
 			if len(data.Tags) > maxContainerImageTags {
 				return fmt.Errorf("too many tags (max allowed: %d)", maxContainerImageTags)
 			}
+			if data.Discovery != nil {
+				if err := validateContainerImageTagsDiscovery(data.Discovery); err != nil {
+					return fmt.Errorf("invalid tags discovery: %w", err)
+				}
+			}
+		}
+		return nil
+	case hub.Helm:
+		data, err := getHelmData(r)
+		if err != nil || data == nil {
+			return err
+		}
+		u, _ := url.Parse(r.URL)
+		if data.Namespace && !SchemeIsOCI(u) {
+			return errors.New("namespace mode is only supported by oci repositories")
+		}
+		if data.Listing != "" {
+			if !data.Namespace {
+				return errors.New("listing can only be used in namespace mode")
+			}
+			if !strings.HasPrefix(data.Listing, hub.RepositoryOCIPrefix) {
+				return errors.New("invalid listing reference")
+			}
+			if _, err := name.ParseReference(strings.TrimPrefix(data.Listing, hub.RepositoryOCIPrefix)); err != nil {
+				return fmt.Errorf("invalid listing reference: %w", err)
+			}
 		}
 		return nil
 	default:
@@ -907,6 +1306,49 @@ func validateData(r *hub.Repository) error {
 	}
 }
 
+// validateContainerImageTagsDiscovery checks the container image tags
+// discovery configuration provided.
+func validateContainerImageTagsDiscovery(d *hub.ContainerImageTagsDiscovery) error {
+	if len(d.Include)+len(d.Exclude) > maxContainerImagePatterns {
+		return fmt.Errorf("too many patterns (max allowed: %d)", maxContainerImagePatterns)
+	}
+	patterns := slices.Clone(d.Exclude)
+	for _, p := range d.Include {
+		patterns = append(patterns, p.Pattern)
+	}
+	for _, p := range patterns {
+		if p == "" {
+			return errors.New("empty pattern")
+		}
+		if _, err := regexp.Compile(p); err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", p, err)
+		}
+	}
+	if d.Latest < 0 || d.Latest > MaxDiscoveredContainerImageTags {
+		return fmt.Errorf("invalid latest value (must be between 0 and %d)", MaxDiscoveredContainerImageTags)
+	}
+	if d.Latest > 0 && !d.Semver {
+		return errors.New("latest can only be used when discovering semver tags")
+	}
+	return nil
+}
+
+// validateTrackingInterval checks the tracking interval of the repository
+// provided, when set, is within the allowed range.
+func validateTrackingInterval(r *hub.Repository) error {
+	if r.TrackingInterval == 0 {
+		return nil
+	}
+	if r.TrackingInterval < minTrackingInterval || r.TrackingInterval > maxTrackingInterval {
+		return fmt.Errorf(
+			"invalid tracking interval (must be between %d and %d seconds)",
+			minTrackingInterval,
+			maxTrackingInterval,
+		)
+	}
+	return nil
+}
+
 // validateSearchInput validates the search input provided, returning an error
 // in case it's invalid.
 func validateSearchInput(input *hub.SearchRepositoryInput) error {
@@ -934,6 +1376,51 @@ func SchemeIsOCI(u *url.URL) bool {
 	return u.Scheme == "oci"
 }
 
+// isTektonKind checks if the repository kind provided is one of the Tekton
+// kinds. Tekton repositories can be stored in OCI registries as well, in which
+// case the packages are distributed as Tekton bundles.
+func isTektonKind(kind hub.RepositoryKind) bool {
+	switch kind {
+	case hub.TektonPipeline, hub.TektonTask, hub.TektonStepAction:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsOCIArtifacts checks if the packages of the repository kind provided
+// can be distributed as OCI artifacts. These repositories are processed by the
+// generic tracker source, which pulls the package content layer of the
+// artifacts instead of cloning a git repository.
+func SupportsOCIArtifacts(kind hub.RepositoryKind) bool {
+	switch kind {
+	case
+		hub.ArgoTemplate,
+		hub.Backstage,
+		hub.Bootc,
+		hub.CoreDNS,
+		hub.Falco,
+		hub.Gatekeeper,
+		hub.Headlamp,
+		hub.InspektorGadget,
+		hub.KCL,
+		hub.KedaScaler,
+		hub.Keptn,
+		hub.KnativeClientPlugin,
+		hub.KubeArmor,
+		hub.Kubewarden,
+		hub.Kyverno,
+		hub.Meshery,
+		hub.OPA,
+		hub.OpenCost,
+		hub.Radius,
+		hub.TBAction:
+		return true
+	default:
+		return false
+	}
+}
+
 // isSchemeSupported is a helper that checks if the scheme of the url provided
 // is supported.
 func isSchemeSupported(u *url.URL) bool {
@@ -3,6 +3,9 @@ package repo
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/artifacthub/hub/internal/authz"
 	"github.com/artifacthub/hub/internal/httpw"
@@ -19,6 +23,8 @@ import (
 	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -166,6 +172,16 @@ func TestAdd(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"oci urls are not supported by this repository kind",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Krew,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace/repo",
+				},
+				nil,
+			},
 			{
 				"the url provided does not point to a valid Helm repository",
 				"org1",
@@ -224,6 +240,83 @@ func TestAdd(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tags discovery: invalid pattern",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Container,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace/repo",
+					Data: json.RawMessage(`{"tags": [], "discovery": {"exclude": ["("]}}`),
+				},
+				nil,
+			},
+			{
+				"invalid tags discovery: latest can only be used when discovering semver tags",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Container,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace/repo",
+					Data: json.RawMessage(`{"tags": [], "discovery": {"latest": 5}}`),
+				},
+				nil,
+			},
+			{
+				"invalid helm repository data",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Helm,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace",
+					Data: json.RawMessage("{["),
+				},
+				nil,
+			},
+			{
+				"namespace mode is only supported by oci repositories",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Helm,
+					Name: "repo1",
+					URL:  "https://repo1.com",
+					Data: json.RawMessage(`{"namespace": true}`),
+				},
+				nil,
+			},
+			{
+				"listing can only be used in namespace mode",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Helm,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace/chart",
+					Data: json.RawMessage(`{"listing": "oci://registry.io/namespace/listing:latest"}`),
+				},
+				nil,
+			},
+			{
+				"invalid listing reference",
+				"org1",
+				&hub.Repository{
+					Kind: hub.Helm,
+					Name: "repo1",
+					URL:  "oci://registry.io/namespace",
+					Data: json.RawMessage(`{"namespace": true, "listing": "registry.io/namespace/listing:latest"}`),
+				},
+				nil,
+			},
+			{
+				"invalid tracking interval",
+				"org1",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: 60,
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			t.Run(tc.errMsg, func(t *testing.T) {
@@ -365,6 +458,56 @@ func TestAdd(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("git credentials require an encryption key", func(t *testing.T) {
+		t.Parallel()
+		sshKey, knownHosts := generateSSHCredentials(t)
+		r := &hub.Repository{
+			Name:              "repo1",
+			URL:               "https://github.com/org1/repo1",
+			Kind:              hub.OPA,
+			AuthSSHKey:        sshKey,
+			AuthSSHKnownHosts: knownHosts,
+		}
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		m := NewManager(cfg, nil, nil, nil)
+
+		err := m.Add(ctx, "orgName", r)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Contains(t, err.Error(), "encryption key not configured")
+	})
+
+	t.Run("add repository with git credentials succeeded", func(t *testing.T) {
+		t.Parallel()
+		kr := newTestKeyring(t)
+		sshKey, knownHosts := generateSSHCredentials(t)
+		r := &hub.Repository{
+			Name:              "repo1",
+			URL:               "https://github.com/org1/repo1",
+			Kind:              hub.OPA,
+			AuthSSHKey:        sshKey,
+			AuthSSHKnownHosts: knownHosts,
+		}
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addRepoDBQ, "userID", "", mock.MatchedBy(func(rJSON []byte) bool {
+			var rStored *hub.Repository
+			_ = json.Unmarshal(rJSON, &rStored)
+			sshKeyDecrypted, err := kr.Decrypt(rStored.AuthSSHKey)
+			return err == nil &&
+				util.IsEncrypted(rStored.AuthSSHKey) &&
+				sshKeyDecrypted == sshKey &&
+				rStored.AuthSSHKnownHosts == knownHosts
+		})).Return(nil)
+		cfg := viper.New()
+		cfg.Set("server.allowPrivateRepositories", true)
+		m := NewManager(cfg, db, nil, nil, WithKeyring(kr))
+
+		err := m.Add(ctx, "", r)
+		assert.NoError(t, err)
+		assert.Equal(t, sshKey, r.AuthSSHKey)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestCheckAvailability(t *testing.T) {
@@ -621,6 +764,34 @@ func TestClaimOwnership(t *testing.T) {
 	})
 }
 
+func TestClearTrackingRequest(t *testing.T) {
+	ctx := context.Background()
+	repositoryID := "00000000-0000-0000-0000-000000000001"
+	var trackingStartedAt int64 = 1700000000
+
+	t.Run("database update succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, clearRepoTrackingRequestDBQ, repositoryID, trackingStartedAt).Return(nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		err := m.ClearTrackingRequest(ctx, repositoryID, trackingStartedAt)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, clearRepoTrackingRequestDBQ, repositoryID, trackingStartedAt).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil, nil)
+
+		err := m.ClearTrackingRequest(ctx, repositoryID, trackingStartedAt)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestDelete(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
@@ -724,6 +895,188 @@ func TestDelete(t *testing.T) {
 	})
 }
 
+func TestDeletePushSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.DeletePushSecret(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		err := m.DeletePushSecret(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("authorization failed", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.UpdateOrganizationRepository,
+		}).Return(tests.ErrFake)
+		m := NewManager(cfg, db, az, nil)
+
+		err := m.DeletePushSecret(ctx, "repo1")
+		assert.Equal(t, tests.ErrFake, err)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+				{
+					"repository_id": "00000000-0000-0000-0000-000000000001",
+					"name": "repo1",
+					"user_alias": "user1"
+				}
+				`), nil)
+				db.On("Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", "").Return(tc.dbErr)
+				m := NewManager(cfg, db, nil, nil)
+
+				err := m.DeletePushSecret(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("delete push secret succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1"
+		}
+		`), nil)
+		db.On("Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", "").Return(nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		err := m.DeletePushSecret(ctx, "repo1")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGeneratePushSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		secret, err := m.GeneratePushSecret(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Empty(t, secret)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1"
+		}
+		`), nil)
+		db.On("Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", mock.Anything).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil, nil)
+
+		secret, err := m.GeneratePushSecret(ctx, "repo1")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Empty(t, secret)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("generate push secret succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		db.On("Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", mock.Anything).Return(nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.UpdateOrganizationRepository,
+		}).Return(nil)
+		m := NewManager(cfg, db, az, nil)
+
+		secret, err := m.GeneratePushSecret(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Len(t, secret, 64)
+		db.AssertCalled(t, "Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", secret)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
+	})
+
+	t.Run("push secret encrypted before being stored", func(t *testing.T) {
+		t.Parallel()
+		kr := newTestKeyring(t)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"user_alias": "user1"
+		}
+		`), nil)
+		var storedSecret string
+		db.On("Exec", ctx, updateRepoPushSecretDBQ, "userID", "repo1", mock.Anything).
+			Run(func(args mock.Arguments) {
+				storedSecret = args.String(4)
+			}).
+			Return(nil)
+		m := NewManager(cfg, db, nil, nil, WithKeyring(kr))
+
+		secret, err := m.GeneratePushSecret(ctx, "repo1")
+		require.NoError(t, err)
+		assert.True(t, util.IsEncrypted(storedSecret))
+		decryptedSecret, err := kr.Decrypt(storedSecret)
+		require.NoError(t, err)
+		assert.Equal(t, secret, decryptedSecret)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetByID(t *testing.T) {
 	ctx := context.Background()
 
@@ -794,12 +1147,63 @@ func TestGetByID(t *testing.T) {
 		assert.True(t, r.CNCF)
 		db.AssertExpectations(t)
 	})
-}
 
-func TestGetByName(t *testing.T) {
-	ctx := context.Background()
-
-	t.Run("invalid input", func(t *testing.T) {
+	t.Run("credentials decrypted", func(t *testing.T) {
+		t.Parallel()
+		kr := newTestKeyring(t)
+		authPassEncrypted, err := kr.Encrypt("pass1")
+		require.NoError(t, err)
+		sshKeyEncrypted, err := kr.Encrypt("sshKey")
+		require.NoError(t, err)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByIDDBQ, repoID, true).Return([]byte(fmt.Sprintf(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"url": "https://github.com/org1/repo1",
+			"kind": 4,
+			"auth_user": "user1",
+			"auth_pass": "%s",
+			"auth_ssh_key": "%s",
+			"auth_ssh_known_hosts": "knownHosts"
+		}
+		`, authPassEncrypted, sshKeyEncrypted)), nil)
+		m := NewManager(cfg, db, nil, nil, WithKeyring(kr))
+
+		r, err := m.GetByID(context.Background(), repoID, true)
+		require.NoError(t, err)
+		assert.Equal(t, "user1", r.AuthUser)
+		assert.Equal(t, "pass1", r.AuthPass)
+		assert.Equal(t, "sshKey", r.AuthSSHKey)
+		assert.Equal(t, "knownHosts", r.AuthSSHKnownHosts)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error decrypting credentials", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByIDDBQ, repoID, true).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"url": "https://github.com/org1/repo1",
+			"kind": 4,
+			"auth_github_app_key": "enc:v1:key1:invalid"
+		}
+		`), nil)
+		m := NewManager(cfg, db, nil, nil, WithKeyring(newTestKeyring(t)))
+
+		r, err := m.GetByID(context.Background(), repoID, true)
+		assert.True(t, errors.Is(err, util.ErrInvalidEncryptedData))
+		assert.Nil(t, r)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetByName(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
 		m := NewManager(cfg, nil, nil, nil)
 		_, err := m.GetByName(ctx, "", false)
@@ -1098,163 +1502,796 @@ func TestGetMetadata(t *testing.T) {
 			Return(ocispec.Descriptor{}, nil, tests.ErrFake)
 		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
 
-		r := &hub.Repository{
-			Kind: hub.Helm,
-			URL:  ociRepoURL,
-		}
-		md, err := m.GetMetadata(r, "")
-		assert.Equal(t, tests.ErrFake, err)
-		assert.Nil(t, md)
-		op.AssertExpectations(t)
+		r := &hub.Repository{
+			Kind: hub.Helm,
+			URL:  ociRepoURL,
+		}
+		md, err := m.GetMetadata(r, "")
+		assert.Equal(t, tests.ErrFake, err)
+		assert.Nil(t, md)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("remote file (oci): metadata layer not found", func(t *testing.T) {
+		t.Parallel()
+		op := &oci.PullerMock{}
+		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "", "").
+			Return(ocispec.Descriptor{}, nil, oci.ErrLayerNotFound)
+		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
+
+		r := &hub.Repository{
+			Kind: hub.Helm,
+			URL:  ociRepoURL,
+		}
+		md, err := m.GetMetadata(r, "")
+		assert.Equal(t, ErrMetadataNotFound, err)
+		assert.Nil(t, md)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("remote file (oci): success", func(t *testing.T) {
+		t.Parallel()
+		op := &oci.PullerMock{}
+		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "", "").
+			Return(ocispec.Descriptor{}, expectedMetadataYaml, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
+
+		r := &hub.Repository{
+			Kind: hub.Helm,
+			URL:  ociRepoURL,
+		}
+		md, err := m.GetMetadata(r, "")
+		assert.Equal(t, expectedMetadata, md)
+		assert.NoError(t, err)
+		op.AssertExpectations(t)
+	})
+
+	t.Run("remote file (oci - with creds): success", func(t *testing.T) {
+		t.Parallel()
+		op := &oci.PullerMock{}
+		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "user", "pass").
+			Return(ocispec.Descriptor{}, expectedMetadataYaml, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
+
+		r := &hub.Repository{
+			Kind:     hub.Helm,
+			URL:      ociRepoURL,
+			AuthUser: "user",
+			AuthPass: "pass",
+		}
+		md, err := m.GetMetadata(r, "")
+		assert.Equal(t, expectedMetadata, md)
+		assert.NoError(t, err)
+		op.AssertExpectations(t)
+	})
+}
+
+func TestGetPackagesDigest(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		_, err := m.GetPackagesDigest(context.Background(), "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoPkgsDigestDBQ, "00000000-0000-0000-0000-000000000001").Return([]byte(`
+		{
+			"package1@1.0.0": "digest-package1-1.0.0",
+			"package1@0.0.9": "digest-package1-0.0.9",
+			"package2@1.0.0": "digest-package2-1.0.0",
+			"package2@0.0.9": "digest-package2-0.0.9"
+		}
+		`), nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		pd, err := m.GetPackagesDigest(ctx, "00000000-0000-0000-0000-000000000001")
+		require.NoError(t, err)
+		assert.Len(t, pd, 4)
+		assert.Equal(t, "digest-package1-1.0.0", pd["package1@1.0.0"])
+		assert.Equal(t, "digest-package1-0.0.9", pd["package1@0.0.9"])
+		assert.Equal(t, "digest-package2-1.0.0", pd["package2@1.0.0"])
+		assert.Equal(t, "digest-package2-0.0.9", pd["package2@0.0.9"])
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetPackagesPaths(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		_, err := m.GetPackagesPaths(context.Background(), "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoPkgsPathsDBQ, "00000000-0000-0000-0000-000000000001").Return([]byte(`
+		{
+			"package1@1.0.0": "/package1/1.0.0",
+			"package1@0.0.9": "/package1/0.0.9",
+			"package2@1.0.0": null
+		}
+		`), nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		pp, err := m.GetPackagesPaths(ctx, "00000000-0000-0000-0000-000000000001")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"package1@1.0.0": "/package1/1.0.0",
+			"package1@0.0.9": "/package1/0.0.9",
+			"package2@1.0.0": "",
+		}, pp)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetRemoteDigest(t *testing.T) {
+	ctx := context.Background()
+	helmHTTP := &hub.Repository{
+		Kind: hub.Helm,
+		Name: "repo1",
+		URL:  "https://myrepo.url",
+	}
+	helmOCI := &hub.Repository{
+		Kind: hub.Helm,
+		Name: "repo1",
+		URL:  "oci://myrepo.url/chart",
+	}
+	helmOCINamespace := &hub.Repository{
+		Kind: hub.Helm,
+		Name: "repo1",
+		URL:  "oci://myrepo.url/charts",
+		Data: json.RawMessage(`{"namespace": true}`),
+	}
+	helmOCINamespaceChart := func(name string) *hub.Repository {
+		r := *helmOCINamespace
+		r.URL = "oci://myrepo.url/charts/" + name
+		return &r
+	}
+
+	t.Run("helm-http: error loading index", func(t *testing.T) {
+		t.Parallel()
+		l := &HelmIndexLoaderMock{}
+		l.On("LoadIndex", helmHTTP).Return(nil, "", tests.ErrFake)
+		m := NewManager(cfg, nil, nil, nil, WithHelmIndexLoader(l))
+
+		digest, err := m.GetRemoteDigest(ctx, helmHTTP)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		l.AssertExpectations(t)
+	})
+
+	t.Run("helm-http: success", func(t *testing.T) {
+		t.Parallel()
+		l := &HelmIndexLoaderMock{}
+		l.On("LoadIndex", helmHTTP).Return(nil, "digest", nil)
+		m := NewManager(cfg, nil, nil, nil, WithHelmIndexLoader(l))
+
+		digest, err := m.GetRemoteDigest(ctx, helmHTTP)
+		assert.Equal(t, "digest", digest)
+		assert.Nil(t, err)
+		l.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci: error getting tags", func(t *testing.T) {
+		t.Parallel()
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCI, true).Return(nil, tests.ErrFake)
+		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCI)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci: success", func(t *testing.T) {
+		t.Parallel()
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCI, true).Return([]string{"2.0.0", "1.0.0"}, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCI)
+		assert.Equal(t, "32b4478532e3fbd46940cfaa0b288bc328817cec9ef38e81c9d19a803bcff285", digest)
+		assert.Nil(t, err)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci namespace: error listing charts", func(t *testing.T) {
+		t.Parallel()
+		rl := &oci.RepositoriesListerMock{}
+		rl.On("Repositories", ctx, helmOCINamespace, "").Return(nil, tests.ErrFake)
+		m := NewManager(cfg, nil, nil, nil, WithOCIRepositoriesLister(rl))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCINamespace)
+		assert.Empty(t, digest)
+		assert.Equal(t, tests.ErrFake, err)
+		rl.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci namespace: error getting chart tags", func(t *testing.T) {
+		t.Parallel()
+		rl := &oci.RepositoriesListerMock{}
+		rl.On("Repositories", ctx, helmOCINamespace, "").Return([]string{"chart1"}, nil)
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCINamespaceChart("chart1"), true).Return(nil, tests.ErrFake)
+		m := NewManager(cfg, nil, nil, nil, WithOCIRepositoriesLister(rl), WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCINamespace)
+		assert.Empty(t, digest)
+		assert.ErrorIs(t, err, tests.ErrFake)
+		rl.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("helm-oci namespace: success", func(t *testing.T) {
+		t.Parallel()
+		rl := &oci.RepositoriesListerMock{}
+		rl.On("Repositories", ctx, helmOCINamespace, "").Return([]string{"chart1", "chart2"}, nil)
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, helmOCINamespaceChart("chart1"), true).Return([]string{"1.0.0"}, nil)
+		tg.On("Tags", ctx, helmOCINamespaceChart("chart2"), true).Return([]string{"2.1.0", "2.0.0"}, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCIRepositoriesLister(rl), WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, helmOCINamespace)
+		assert.Equal(t, "2bae091476de6566224363aee1524bf09f4fb2432be1d38417ae7a127475995f", digest)
+		assert.Nil(t, err)
+		rl.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("generic-oci: success", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			Kind: hub.OPA,
+			Name: "repo1",
+			URL:  "oci://myrepo.url/policies",
+		}
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, r, true).Return([]string{"2.0.0", "1.0.0"}, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, r)
+		assert.Equal(t, "32b4478532e3fbd46940cfaa0b288bc328817cec9ef38e81c9d19a803bcff285", digest)
+		assert.Nil(t, err)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("tekton-oci: success", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			Kind: hub.TektonTask,
+			Name: "repo1",
+			URL:  "oci://myrepo.url/tasks",
+		}
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", ctx, r, true).Return([]string{"2.0.0", "1.0.0"}, nil)
+		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+
+		digest, err := m.GetRemoteDigest(ctx, r)
+		assert.Equal(t, "32b4478532e3fbd46940cfaa0b288bc328817cec9ef38e81c9d19a803bcff285", digest)
+		assert.Nil(t, err)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("git: success", func(t *testing.T) {
+		t.Parallel()
+		gr, commit := newTestGitRepository(t)
+		commit1 := commit(map[string]string{"README.md": "readme"})
+		tag, err := gr.CreateTag("v1.0.0", commit1, nil)
+		require.NoError(t, err)
+		commit2 := commit(map[string]string{"README.md": "readme updated"})
+		annotatedTag, err := gr.CreateTag("v2.0.0", commit2, &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: "test", Email: "test@email.com", When: time.Now()},
+			Message: "v2.0.0",
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, commit2, annotatedTag.Hash())
+		m := NewManager(cfg, nil, nil, nil)
+
+		for ref, expectedDigest := range map[string]string{
+			"":                 commit2.String(),
+			"master":           commit2.String(),
+			"refs/tags/v1.0.0": tag.Hash().String(),
+			"refs/tags/v2.0.0": commit2.String(),
+			commit1.String():   commit1.String(),
+			"missing":          "",
+		} {
+			r := &hub.Repository{Kind: hub.OPA, GitURL: getWorktreeDir(t, gr), GitRef: ref}
+			digest, err := m.GetRemoteDigest(ctx, r)
+			require.NoError(t, err)
+			assert.Equal(t, expectedDigest, digest)
+		}
+	})
+}
+
+func TestGetTrackingRunsJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	p := &hub.Pagination{Limit: 10, Offset: 1}
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetTrackingRunsJSON(context.Background(), "repo1", p)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		result, err := m.GetTrackingRunsJSON(ctx, "", p)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.Nil(t, result)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoTrackingRunsDBQ, "userID", "repo1", 10, 1).Return(nil, tc.dbErr)
+				m := NewManager(cfg, db, nil, nil)
+
+				result, err := m.GetTrackingRunsJSON(ctx, "repo1", p)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, result)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoTrackingRunsDBQ, "userID", "repo1", 10, 1).
+			Return([]interface{}{[]byte("dataJSON"), 1}, nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		result, err := m.GetTrackingRunsJSON(ctx, "repo1", p)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), result.Data)
+		assert.Equal(t, 1, result.TotalCount)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestGetTrackingStatusJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetTrackingStatusJSON(context.Background(), "repo1")
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		_, err := m.GetTrackingStatusJSON(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoTrackingStatusDBQ, "userID", "repo1", util.DBLockKeyTrackRepository).
+					Return(nil, tc.dbErr)
+				m := NewManager(cfg, db, nil, nil)
+
+				dataJSON, err := m.GetTrackingStatusJSON(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				assert.Nil(t, dataJSON)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("tracking status returned successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoTrackingStatusDBQ, "userID", "repo1", util.DBLockKeyTrackRepository).
+			Return([]byte("dataJSON"), nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		dataJSON, err := m.GetTrackingStatusJSON(ctx, "repo1")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), dataJSON)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestProcessPushEvent(t *testing.T) {
+	ctx := context.Background()
+	daemonCfg := viper.New()
+	daemonCfg.Set("tracker.daemon.enabled", true)
+	body := []byte(`{"ref": "refs/heads/main", "repository": {"default_branch": "main"}}`)
+	githubHeaders := func(secret string) http.Header {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		h := http.Header{}
+		h.Set("X-GitHub-Event", "push")
+		h.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		return h
+	}
+	repoJSON := []byte(`
+	{
+		"repository_id": "00000000-0000-0000-0000-000000000001",
+		"name": "repo1",
+		"url": "https://github.com/org1/repo1"
+	}
+	`)
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(daemonCfg, nil, nil, nil)
+		trackingRequested, err := m.ProcessPushEvent(ctx, "", githubHeaders("secret"), body)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.False(t, trackingRequested)
+	})
+
+	t.Run("tracker not running in daemon mode", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.True(t, errors.Is(err, errTrackerDaemonNotEnabled))
+		assert.False(t, trackingRequested)
+	})
+
+	t.Run("error getting repository", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(nil, tests.ErrFakeDB)
+		m := NewManager(daemonCfg, db, nil, nil)
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("error getting push secret", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(repoJSON, nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return(nil, tests.ErrFakeDB)
+		m := NewManager(daemonCfg, db, nil, nil)
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("push events not enabled", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1"
+		}
+		`), nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return("", nil)
+		m := NewManager(daemonCfg, db, nil, nil)
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(repoJSON, nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return("secret", nil)
+		m := NewManager(daemonCfg, db, nil, nil)
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("invalid"), body)
+		assert.Equal(t, hub.ErrInsufficientPrivilege, err)
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("unsupported push event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(repoJSON, nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return("secret", nil)
+		m := NewManager(daemonCfg, db, nil, nil)
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", http.Header{}, body)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
 	})
 
-	t.Run("remote file (oci): metadata layer not found", func(t *testing.T) {
+	t.Run("database error requesting tracking", func(t *testing.T) {
 		t.Parallel()
-		op := &oci.PullerMock{}
-		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "", "").
-			Return(ocispec.Descriptor{}, nil, oci.ErrLayerNotFound)
-		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(repoJSON, nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return("secret", nil)
+		db.On("Exec", ctx, requestRepoTrackingDBQ, "00000000-0000-0000-0000-000000000001").Return(tests.ErrFakeDB)
+		m := NewManager(daemonCfg, db, nil, nil)
 
-		r := &hub.Repository{
-			Kind: hub.Helm,
-			URL:  ociRepoURL,
-		}
-		md, err := m.GetMetadata(r, "")
-		assert.Equal(t, ErrMetadataNotFound, err)
-		assert.Nil(t, md)
-		op.AssertExpectations(t)
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.False(t, trackingRequested)
+		db.AssertExpectations(t)
 	})
 
-	t.Run("remote file (oci): success", func(t *testing.T) {
+	t.Run("repository tracking requested", func(t *testing.T) {
 		t.Parallel()
-		op := &oci.PullerMock{}
-		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "", "").
-			Return(ocispec.Descriptor{}, expectedMetadataYaml, nil)
-		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return(repoJSON, nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return("secret", nil)
+		db.On("Exec", ctx, requestRepoTrackingDBQ, "00000000-0000-0000-0000-000000000001").Return(nil)
+		m := NewManager(daemonCfg, db, nil, nil)
 
-		r := &hub.Repository{
-			Kind: hub.Helm,
-			URL:  ociRepoURL,
-		}
-		md, err := m.GetMetadata(r, "")
-		assert.Equal(t, expectedMetadata, md)
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
 		assert.NoError(t, err)
-		op.AssertExpectations(t)
+		assert.True(t, trackingRequested)
+		db.AssertExpectations(t)
 	})
 
-	t.Run("remote file (oci - with creds): success", func(t *testing.T) {
+	t.Run("repository tracking requested (encrypted push secret)", func(t *testing.T) {
 		t.Parallel()
-		op := &oci.PullerMock{}
-		op.On("PullLayer", mock.Anything, ociRef, MetadataLayerMediaType, "user", "pass").
-			Return(ocispec.Descriptor{}, expectedMetadataYaml, nil)
-		m := NewManager(cfg, nil, nil, nil, WithOCIPuller(op))
-
-		r := &hub.Repository{
-			Kind:     hub.Helm,
-			URL:      ociRepoURL,
-			AuthUser: "user",
-			AuthPass: "pass",
+		kr := newTestKeyring(t)
+		pushSecretEncrypted, err := kr.Encrypt("secret")
+		require.NoError(t, err)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"url": "https://github.com/org1/repo1"
 		}
-		md, err := m.GetMetadata(r, "")
-		assert.Equal(t, expectedMetadata, md)
+		`), nil)
+		db.On("QueryRow", ctx, getRepoPushSecretDBQ, "00000000-0000-0000-0000-000000000001").Return(pushSecretEncrypted, nil)
+		db.On("Exec", ctx, requestRepoTrackingDBQ, "00000000-0000-0000-0000-000000000001").Return(nil)
+		m := NewManager(daemonCfg, db, nil, nil, WithKeyring(kr))
+
+		trackingRequested, err := m.ProcessPushEvent(ctx, "repo1", githubHeaders("secret"), body)
 		assert.NoError(t, err)
-		op.AssertExpectations(t)
+		assert.True(t, trackingRequested)
+		db.AssertExpectations(t)
 	})
 }
 
-func TestGetPackagesDigest(t *testing.T) {
+func TestRegisterTrackingRun(t *testing.T) {
 	ctx := context.Background()
+	run := &hub.TrackingRun{
+		RepositoryID:  repoID,
+		StartedAt:     1592299234,
+		FinishedAt:    1592299294,
+		RemoteDigest:  "digest",
+		PackagesAdded: 1,
+		Errors: []*hub.TrackingRunError{
+			{
+				PackageName:    "pkg1",
+				PackageVersion: "1.0.0",
+				Message:        "error1",
+			},
+		},
+	}
+	runJSON, _ := json.Marshal(run)
 
 	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg string
+			run    *hub.TrackingRun
+		}{
+			{
+				"tracking run not provided",
+				nil,
+			},
+			{
+				"invalid repository id",
+				&hub.TrackingRun{RepositoryID: "invalid"},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil, nil)
+				err := m.RegisterTrackingRun(ctx, tc.run)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("database insert succeeded", func(t *testing.T) {
 		t.Parallel()
-		m := NewManager(cfg, nil, nil, nil)
-		_, err := m.GetPackagesDigest(context.Background(), "invalid")
-		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, registerRepoTrackingRunDBQ, runJSON).Return(nil)
+		m := NewManager(cfg, db, nil, nil)
+
+		err := m.RegisterTrackingRun(ctx, run)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
 	})
 
-	t.Run("database query succeeded", func(t *testing.T) {
+	t.Run("database error", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getRepoPkgsDigestDBQ, "00000000-0000-0000-0000-000000000001").Return([]byte(`
-		{
-			"package1@1.0.0": "digest-package1-1.0.0",
-			"package1@0.0.9": "digest-package1-0.0.9",
-			"package2@1.0.0": "digest-package2-1.0.0",
-			"package2@0.0.9": "digest-package2-0.0.9"
-		}
-		`), nil)
+		db.On("Exec", ctx, registerRepoTrackingRunDBQ, runJSON).Return(tests.ErrFakeDB)
 		m := NewManager(cfg, db, nil, nil)
 
-		pd, err := m.GetPackagesDigest(ctx, "00000000-0000-0000-0000-000000000001")
-		require.NoError(t, err)
-		assert.Len(t, pd, 4)
-		assert.Equal(t, "digest-package1-1.0.0", pd["package1@1.0.0"])
-		assert.Equal(t, "digest-package1-0.0.9", pd["package1@0.0.9"])
-		assert.Equal(t, "digest-package2-1.0.0", pd["package2@1.0.0"])
-		assert.Equal(t, "digest-package2-0.0.9", pd["package2@0.0.9"])
+		err := m.RegisterTrackingRun(ctx, run)
+		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
 	})
 }
 
-func TestGetRemoteDigest(t *testing.T) {
-	ctx := context.Background()
-	helmHTTP := &hub.Repository{
-		Kind: hub.Helm,
-		Name: "repo1",
-		URL:  "https://myrepo.url",
-	}
-	helmOCI := &hub.Repository{
-		Kind: hub.Helm,
-		Name: "repo1",
-		URL:  "oci://myrepo.url/chart",
-	}
+func TestRequestTracking(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	daemonCfg := viper.New()
+	daemonCfg.Set("tracker.daemon.enabled", true)
 
-	t.Run("helm-http: error loading index", func(t *testing.T) {
+	t.Run("user id not found in ctx", func(t *testing.T) {
 		t.Parallel()
-		l := &HelmIndexLoaderMock{}
-		l.On("LoadIndex", helmHTTP).Return(nil, "", tests.ErrFake)
-		m := NewManager(cfg, nil, nil, nil, WithHelmIndexLoader(l))
-
-		digest, err := m.GetRemoteDigest(ctx, helmHTTP)
-		assert.Empty(t, digest)
-		assert.Equal(t, tests.ErrFake, err)
-		l.AssertExpectations(t)
+		m := NewManager(cfg, nil, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.RequestTracking(context.Background(), "repo1")
+		})
 	})
 
-	t.Run("helm-http: success", func(t *testing.T) {
+	t.Run("invalid input", func(t *testing.T) {
 		t.Parallel()
-		l := &HelmIndexLoaderMock{}
-		l.On("LoadIndex", helmHTTP).Return(nil, "digest", nil)
-		m := NewManager(cfg, nil, nil, nil, WithHelmIndexLoader(l))
+		m := NewManager(cfg, nil, nil, nil)
+		err := m.RequestTracking(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
 
-		digest, err := m.GetRemoteDigest(ctx, helmHTTP)
-		assert.Equal(t, "digest", digest)
-		assert.Nil(t, err)
-		l.AssertExpectations(t)
+	t.Run("tracker not running in daemon mode", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil, nil)
+		err := m.RequestTracking(ctx, "repo1")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		assert.True(t, errors.Is(err, errTrackerDaemonNotEnabled))
 	})
 
-	t.Run("helm-oci: error getting tags", func(t *testing.T) {
+	t.Run("authorization failed", func(t *testing.T) {
 		t.Parallel()
-		tg := &oci.TagsGetterMock{}
-		tg.On("Tags", ctx, helmOCI, true).Return(nil, tests.ErrFake)
-		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.UpdateOrganizationRepository,
+		}).Return(tests.ErrFake)
+		m := NewManager(daemonCfg, db, az, nil)
 
-		digest, err := m.GetRemoteDigest(ctx, helmOCI)
-		assert.Empty(t, digest)
+		err := m.RequestTracking(ctx, "repo1")
 		assert.Equal(t, tests.ErrFake, err)
-		tg.AssertExpectations(t)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
 	})
 
-	t.Run("helm-oci: success", func(t *testing.T) {
+	t.Run("repository disabled", func(t *testing.T) {
 		t.Parallel()
-		tg := &oci.TagsGetterMock{}
-		tg.On("Tags", ctx, helmOCI, true).Return([]string{"2.0.0", "1.0.0"}, nil)
-		m := NewManager(cfg, nil, nil, nil, WithOCITagsGetter(tg))
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"disabled": true,
+			"user_alias": "user1"
+		}
+		`), nil)
+		m := NewManager(daemonCfg, db, nil, nil)
 
-		digest, err := m.GetRemoteDigest(ctx, helmOCI)
-		assert.Equal(t, "32b4478532e3fbd46940cfaa0b288bc328817cec9ef38e81c9d19a803bcff285", digest)
-		assert.Nil(t, err)
-		tg.AssertExpectations(t)
+		err := m.RequestTracking(ctx, "repo1")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				util.ErrDBInsufficientPrivilege,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+				{
+					"repository_id": "00000000-0000-0000-0000-000000000001",
+					"name": "repo1",
+					"user_alias": "user1"
+				}
+				`), nil)
+				db.On("Exec", ctx, requestRepoTrackingByOwnerDBQ, "userID", "repo1").Return(tc.dbErr)
+				m := NewManager(daemonCfg, db, nil, nil)
+
+				err := m.RequestTracking(ctx, "repo1")
+				assert.Equal(t, tc.expectedError, err)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("tracking requested successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getRepoByNameDBQ, "repo1", false).Return([]byte(`
+		{
+			"repository_id": "00000000-0000-0000-0000-000000000001",
+			"name": "repo1",
+			"organization_name": "orgName"
+		}
+		`), nil)
+		db.On("Exec", ctx, requestRepoTrackingByOwnerDBQ, "userID", "repo1").Return(nil)
+		az := &authz.AuthorizerMock{}
+		az.On("Authorize", ctx, &hub.AuthorizeInput{
+			OrganizationName: "orgName",
+			UserID:           "userID",
+			Action:           hub.UpdateOrganizationRepository,
+		}).Return(nil)
+		m := NewManager(daemonCfg, db, az, nil)
+
+		err := m.RequestTracking(ctx, "repo1")
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+		az.AssertExpectations(t)
 	})
 }
 
@@ -1783,6 +2820,16 @@ func TestUpdate(t *testing.T) {
 				},
 				nil,
 			},
+			{
+				"invalid tracking interval",
+				&hub.Repository{
+					Kind:             hub.Helm,
+					Name:             "repo1",
+					URL:              "https://repo1.com",
+					TrackingInterval: 30 * 24 * 60 * 60,
+				},
+				nil,
+			},
 		}
 		for _, tc := range testCases {
 			t.Run(tc.errMsg, func(t *testing.T) {
@@ -1971,3 +3018,12 @@ func withRepositoryCloner(rc hub.RepositoryCloner) func(m *Manager) {
 		m.rc = rc
 	}
 }
+
+// newTestKeyring is a helper function that creates a new Keyring instance to
+// be used in tests.
+func newTestKeyring(t *testing.T) *util.Keyring {
+	t.Helper()
+	kr, err := util.NewKeyring([]*util.EncryptionKey{{ID: "key1", Key: "secret1"}})
+	require.NoError(t, err)
+	return kr
+}
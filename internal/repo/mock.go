@@ -2,6 +2,7 @@ package repo
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/stretchr/testify/mock"
@@ -19,6 +20,17 @@ func (m *ClonerMock) CloneRepository(ctx context.Context, r *hub.Repository) (st
 	return args.String(0), args.String(1), args.Error(2)
 }
 
+// SyncRepository implements the RepositoryCloner interface.
+func (m *ClonerMock) SyncRepository(
+	ctx context.Context,
+	r *hub.Repository,
+	sinceCommit string,
+) (string, string, []string, error) {
+	args := m.Called(ctx, r, sinceCommit)
+	changedPaths, _ := args.Get(2).([]string)
+	return args.String(0), args.String(1), changedPaths, args.Error(3)
+}
+
 // ErrorsCollectorMock is mock ErrorsCollector implementation.
 type ErrorsCollectorMock struct {
 	mock.Mock
@@ -75,12 +87,30 @@ func (m *ManagerMock) ClaimOwnership(ctx context.Context, name, orgName string)
 	return args.Error(0)
 }
 
+// ClearTrackingRequest implements the RepositoryManager interface.
+func (m *ManagerMock) ClearTrackingRequest(ctx context.Context, repositoryID string, trackingStartedAt int64) error {
+	args := m.Called(ctx, repositoryID, trackingStartedAt)
+	return args.Error(0)
+}
+
 // Delete implements the RepositoryManager interface.
 func (m *ManagerMock) Delete(ctx context.Context, name string) error {
 	args := m.Called(ctx, name)
 	return args.Error(0)
 }
 
+// DeletePushSecret implements the RepositoryManager interface.
+func (m *ManagerMock) DeletePushSecret(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
+// GeneratePushSecret implements the RepositoryManager interface.
+func (m *ManagerMock) GeneratePushSecret(ctx context.Context, name string) (string, error) {
+	args := m.Called(ctx, name)
+	return args.String(0), args.Error(1)
+}
+
 // GetByID implements the RepositoryManager interface.
 func (m *ManagerMock) GetByID(
 	ctx context.Context,
@@ -120,12 +150,63 @@ func (m *ManagerMock) GetPackagesDigest(
 	return data, args.Error(1)
 }
 
+// GetPackagesPaths implements the RepositoryManager interface.
+func (m *ManagerMock) GetPackagesPaths(
+	ctx context.Context,
+	repositoryID string,
+) (map[string]string, error) {
+	args := m.Called(ctx, repositoryID)
+	data, _ := args.Get(0).(map[string]string)
+	return data, args.Error(1)
+}
+
 // GetRemoteDigest implements the RepositoryManager interface.
 func (m *ManagerMock) GetRemoteDigest(ctx context.Context, r *hub.Repository) (string, error) {
 	args := m.Called(ctx, r)
 	return args.String(0), args.Error(1)
 }
 
+// GetTrackingRunsJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetTrackingRunsJSON(
+	ctx context.Context,
+	name string,
+	p *hub.Pagination,
+) (*hub.JSONQueryResult, error) {
+	args := m.Called(ctx, name, p)
+	data, _ := args.Get(0).(*hub.JSONQueryResult)
+	return data, args.Error(1)
+}
+
+// GetTrackingStatusJSON implements the RepositoryManager interface.
+func (m *ManagerMock) GetTrackingStatusJSON(ctx context.Context, name string) ([]byte, error) {
+	args := m.Called(ctx, name)
+	data, _ := args.Get(0).([]byte)
+	return data, args.Error(1)
+}
+
+// ProcessPushEvent implements the RepositoryManager interface.
+func (m *ManagerMock) ProcessPushEvent(
+	ctx context.Context,
+	name string,
+	h http.Header,
+	body []byte,
+) (bool, error) {
+	args := m.Called(ctx, name, h, body)
+	return args.Bool(0), args.Error(1)
+}
+
+// RegisterTrackingRun implements the RepositoryManager interface.
+func (m *ManagerMock) RegisterTrackingRun(ctx context.Context, run *hub.TrackingRun) error {
+	args := m.Called(ctx, run)
+	return args.Error(0)
+}
+
+// RequestTracking implements the RepositoryManager interface.
+func (m *ManagerMock) RequestTracking(ctx context.Context, name string) error {
+	args := m.Called(ctx, name)
+	return args.Error(0)
+}
+
 // Search implements the RepositoryManager interface.
 func (m *ManagerMock) Search(
 	ctx context.Context,
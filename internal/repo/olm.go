@@ -1,24 +1,51 @@
 package repo
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
 )
 
+// olmConfigsLabel is the label used by OLM catalog images to indicate the
+// location of the file-based catalog in the image filesystem.
+const olmConfigsLabel = "operators.operatorframework.io.index.configs.v1"
+
 // OLMOCIExporter provides a mechanism to export the packages available in an
 // OLM repository stored in an OCI registry.
-type OLMOCIExporter struct{}
+type OLMOCIExporter struct {
+	cfg *viper.Viper
+}
+
+// NewOLMOCIExporter creates a new OLMOCIExporter instance.
+func NewOLMOCIExporter(cfg *viper.Viper) *OLMOCIExporter {
+	return &OLMOCIExporter{
+		cfg: cfg,
+	}
+}
 
 // ExportRepository exports the packages available in a repository stored in a
-// OCI registry using the appregistry manifest format. It returns the temporary
-// directory where the packages will be stored. It's the caller's responsibility
-// to delete it when done.
+// OCI registry. Catalog images using the file-based catalog format are
+// exported natively, whereas for the ones using the legacy sqlite based format
+// the appregistry manifest format is used (the export is done by opm in this
+// case). When the file-based catalog cannot be exported natively, opm is used
+// as well. It returns the temporary directory where the packages will be
+// stored. It's the caller's responsibility to delete it when done.
 func (e *OLMOCIExporter) ExportRepository(ctx context.Context, r *hub.Repository) (string, error) {
 	// Setup temporary directory to store content
 	tmpDir, err := os.MkdirTemp("", "artifact-hub")
@@ -26,8 +53,29 @@ func (e *OLMOCIExporter) ExportRepository(ctx context.Context, r *hub.Repository
 		return "", fmt.Errorf("error creating temp dir: %w", err)
 	}
 
-	// Export repository packages using opm (external tool)
+	// Export file-based catalog when available
 	indexRef := strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix)
+	exported, err := e.exportFileBasedCatalog(ctx, r, indexRef, tmpDir)
+	if err != nil {
+		log.Warn().
+			Err(err).
+			Str("repo", r.Name).
+			Str("ref", indexRef).
+			Msg("error exporting file-based catalog, falling back to opm")
+
+		// Discard any content partially exported
+		if err := os.RemoveAll(tmpDir); err != nil {
+			return "", fmt.Errorf("error cleaning temp dir: %w", err)
+		}
+		if err := os.Mkdir(tmpDir, 0o700); err != nil {
+			return "", fmt.Errorf("error creating temp dir: %w", err)
+		}
+	}
+	if exported {
+		return tmpDir, nil
+	}
+
+	// Export repository packages using opm (external tool)
 	cmd := exec.CommandContext(ctx, "opm", "index", "export", "-i", indexRef, "-f", tmpDir) // #nosec
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
@@ -37,8 +85,74 @@ func (e *OLMOCIExporter) ExportRepository(ctx context.Context, r *hub.Repository
 		"HOME=" + os.Getenv("HOME"),
 	}
 	if err := cmd.Run(); err != nil {
+		_ = os.RemoveAll(tmpDir)
 		return "", fmt.Errorf("error running opm index export (%s): %w: %s", indexRef, err, stderr.String())
 	}
 
 	return tmpDir, nil
 }
+
+// exportFileBasedCatalog extracts the file-based catalog available in the
+// catalog image provided to the destination directory. It returns false when
+// the image does not contain a file-based catalog.
+func (e *OLMOCIExporter) exportFileBasedCatalog(
+	ctx context.Context,
+	r *hub.Repository,
+	indexRef,
+	dst string,
+) (bool, error) {
+	ref, err := name.ParseReference(indexRef)
+	if err != nil {
+		return false, fmt.Errorf("invalid reference: %w", err)
+	}
+	options := oci.PrepareRemoteOptions(ctx, e.cfg, ref, r.AuthUser, r.AuthPass)
+	img, err := remote.Image(ref, options...)
+	if err != nil {
+		return false, fmt.Errorf("error getting image: %w", err)
+	}
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return false, fmt.Errorf("error getting image config: %w", err)
+	}
+	configsDir, ok := cfg.Config.Labels[olmConfigsLabel]
+	if !ok || configsDir == "" {
+		return false, nil
+	}
+	configsDir = strings.Trim(path.Clean("/"+configsDir), "/") + "/"
+
+	// Extract configs directory from the image filesystem
+	fs := mutate.Extract(img)
+	defer fs.Close()
+	tr := tar.NewReader(fs)
+	for {
+		hdr, err := tr.Next()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return false, fmt.Errorf("error reading image filesystem: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		filePath := strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if !strings.HasPrefix(filePath, configsDir) {
+			continue
+		}
+		dstPath := filepath.Join(dst, filepath.FromSlash(strings.TrimPrefix(filePath, configsDir)))
+		if err := os.MkdirAll(filepath.Dir(dstPath), 0o700); err != nil {
+			return false, fmt.Errorf("error creating directory: %w", err)
+		}
+		f, err := os.Create(dstPath)
+		if err != nil {
+			return false, fmt.Errorf("error creating file: %w", err)
+		}
+		if _, err := io.Copy(f, tr); err != nil { // #nosec
+			f.Close()
+			return false, fmt.Errorf("error writing file: %w", err)
+		}
+		f.Close()
+	}
+
+	return true, nil
+}
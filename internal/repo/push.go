@@ -0,0 +1,280 @@
+package repo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	distributionEventsMediaType = "application/vnd.docker.distribution.events.v1+json"
+	cloudEventsMediaType        = "application/cloudevents+json"
+)
+
+var (
+	// errInvalidPushEventSignature indicates that the push event signature or
+	// token does not match the repository push secret.
+	errInvalidPushEventSignature = errors.New("invalid push event signature")
+
+	// errUnsupportedPushEvent indicates that the push event received is not
+	// supported.
+	errUnsupportedPushEvent = errors.New("unsupported push event")
+
+	// cloudEventsPushTypes represents the CloudEvents types sent by the OCI
+	// registries supported when an artifact is pushed.
+	cloudEventsPushTypes = map[string]struct{}{
+		"harbor.artifact.pushed":                  {},
+		"Microsoft.ContainerRegistry.ChartPushed": {},
+		"Microsoft.ContainerRegistry.ImagePushed": {},
+	}
+)
+
+// gitPushEvent represents the subset of fields of a GitHub, GitLab or Gitea
+// push event we are interested in.
+type gitPushEvent struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Project struct {
+		DefaultBranch string `json:"default_branch"`
+	} `json:"project"`
+}
+
+// distributionEnvelope represents an OCI Distribution notifications envelope.
+type distributionEnvelope struct {
+	Events []struct {
+		Action string `json:"action"`
+		Target struct {
+			Repository string `json:"repository"`
+		} `json:"target"`
+	} `json:"events"`
+}
+
+// cloudEvent represents the subset of fields of a CloudEvent in structured
+// mode we are interested in.
+type cloudEvent struct {
+	Type    string          `json:"type"`
+	Subject string          `json:"subject"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// cloudEventData represents the subset of fields of the data of the push
+// CloudEvents sent by Harbor and Azure Container Registry we are interested
+// in.
+type cloudEventData struct {
+	Repository struct {
+		RepoFullName string `json:"repo_full_name"`
+	} `json:"repository"`
+	Target struct {
+		Repository string `json:"repository"`
+	} `json:"target"`
+}
+
+// parsePushEvent verifies the push event provided using the repository push
+// secret and checks if it affects the repository, in which case true will be
+// returned. GitHub, GitLab and Gitea push events are supported, as well as
+// OCI registries push notifications (Distribution notifications and
+// CloudEvents).
+func parsePushEvent(r *hub.Repository, h http.Header, body []byte) (bool, error) {
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	switch {
+	case h.Get("X-Gitea-Event") != "":
+		// Gitea also sends the X-GitHub-Event header, so it must be checked first
+		if !validHMACSignature(r.PushSecret, body, h.Get("X-Gitea-Signature")) {
+			return false, errInvalidPushEventSignature
+		}
+		if h.Get("X-Gitea-Event") != "push" {
+			return false, nil
+		}
+		return gitPushAffectsRepository(r, body)
+	case h.Get("X-GitHub-Event") != "":
+		signature := h.Get("X-Hub-Signature-256")
+		if !strings.HasPrefix(signature, "sha256=") ||
+			!validHMACSignature(r.PushSecret, body, strings.TrimPrefix(signature, "sha256=")) {
+			return false, errInvalidPushEventSignature
+		}
+		if h.Get("X-GitHub-Event") != "push" {
+			return false, nil
+		}
+		return gitPushAffectsRepository(r, body)
+	case h.Get("X-Gitlab-Event") != "":
+		if !validToken(r.PushSecret, h.Get("X-Gitlab-Token")) {
+			return false, errInvalidPushEventSignature
+		}
+		if h.Get("X-Gitlab-Event") != "Push Hook" {
+			return false, nil
+		}
+		return gitPushAffectsRepository(r, body)
+	case mediaType == distributionEventsMediaType:
+		if !validAuthorizationHeader(r.PushSecret, h.Get("Authorization")) {
+			return false, errInvalidPushEventSignature
+		}
+		return distributionPushAffectsRepository(r, body)
+	case mediaType == cloudEventsMediaType, h.Get("Ce-Specversion") != "":
+		if !validAuthorizationHeader(r.PushSecret, h.Get("Authorization")) {
+			return false, errInvalidPushEventSignature
+		}
+		return cloudEventPushAffectsRepository(r, h, body)
+	default:
+		return false, errUnsupportedPushEvent
+	}
+}
+
+// gitPushAffectsRepository checks if the git push event provided affects the
+// repository's git ref (or the default branch when it's not set).
+func gitPushAffectsRepository(r *hub.Repository, body []byte) (bool, error) {
+	var e *gitPushEvent
+	if err := json.Unmarshal(body, &e); err != nil || e == nil {
+		return false, fmt.Errorf("%w: invalid push event payload", errUnsupportedPushEvent)
+	}
+	if r.GitRef != "" {
+		ref := parseGitRef(r.GitRef)
+		if ref.isCommit() {
+			// Repositories pinned to a specific commit are never affected
+			return false, nil
+		}
+		return e.Ref == ref.name.String(), nil
+	}
+	branch := r.Branch
+	if branch == "" {
+		branch = e.Repository.DefaultBranch
+	}
+	if branch == "" {
+		branch = e.Project.DefaultBranch
+	}
+	if branch == "" {
+		return true, nil
+	}
+	return e.Ref == "refs/heads/"+branch, nil
+}
+
+// cloudEventPushAffectsRepository checks if the CloudEvent provided, in binary
+// or structured mode, is a push event that affects the repository.
+func cloudEventPushAffectsRepository(r *hub.Repository, h http.Header, body []byte) (bool, error) {
+	// In binary mode the event attributes are sent in the headers and the
+	// body contains the event data
+	e := &cloudEvent{
+		Type:    h.Get("Ce-Type"),
+		Subject: h.Get("Ce-Subject"),
+		Data:    body,
+	}
+	if e.Type == "" {
+		e = nil
+		if err := json.Unmarshal(body, &e); err != nil || e == nil {
+			return false, fmt.Errorf("%w: invalid cloud event", errUnsupportedPushEvent)
+		}
+	}
+	if _, isPush := cloudEventsPushTypes[e.Type]; !isPush {
+		return false, nil
+	}
+
+	// Check if the repository pushed (as provided by Harbor or ACR in the
+	// event data, or in the event subject) is the repository or one below it
+	var data cloudEventData
+	if len(e.Data) > 0 {
+		if err := json.Unmarshal(e.Data, &data); err != nil {
+			return false, fmt.Errorf("%w: invalid cloud event data", errUnsupportedPushEvent)
+		}
+	}
+	repoPath, err := getOCIRepositoryPath(r)
+	if err != nil {
+		return false, err
+	}
+	for _, pushedRepository := range []string{
+		data.Repository.RepoFullName,
+		data.Target.Repository,
+		trimOCIReference(e.Subject),
+	} {
+		if pushedRepository != "" && ociPushAffectsRepository(repoPath, pushedRepository) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// distributionPushAffectsRepository checks if any of the push events in the
+// Distribution notifications envelope provided affects the repository.
+func distributionPushAffectsRepository(r *hub.Repository, body []byte) (bool, error) {
+	var env *distributionEnvelope
+	if err := json.Unmarshal(body, &env); err != nil || env == nil {
+		return false, fmt.Errorf("%w: invalid distribution notification", errUnsupportedPushEvent)
+	}
+	repoPath, err := getOCIRepositoryPath(r)
+	if err != nil {
+		return false, err
+	}
+	for _, e := range env.Events {
+		if e.Action != "push" {
+			continue
+		}
+		if ociPushAffectsRepository(repoPath, e.Target.Repository) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getOCIRepositoryPath returns the path of the OCI repository provided within
+// its registry.
+func getOCIRepositoryPath(r *hub.Repository) (string, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(u.Path, "/"), nil
+}
+
+// ociPushAffectsRepository checks if a push to the OCI repository provided
+// affects the repository located at the path provided, which happens when it
+// is the same repository or one below it.
+func ociPushAffectsRepository(repoPath, pushedRepository string) bool {
+	return repoPath == "" ||
+		pushedRepository == repoPath ||
+		strings.HasPrefix(pushedRepository, repoPath+"/")
+}
+
+// trimOCIReference removes the tag or digest from the OCI reference provided
+// (i.e. repo:tag or repo@digest), returning just the repository.
+func trimOCIReference(ref string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref
+}
+
+// validHMACSignature checks if the hex encoded signature provided is a valid
+// HMAC-SHA256 signature of the body using the secret provided.
+func validHMACSignature(secret string, body []byte, signature string) bool {
+	signatureBytes, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(signatureBytes, mac.Sum(nil))
+}
+
+// validToken checks if the token provided matches the secret.
+func validToken(secret, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
+
+// validAuthorizationHeader checks if the authorization header provided
+// contains the secret, as a bearer token or as is.
+func validAuthorizationHeader(secret, authorization string) bool {
+	return validToken(secret, strings.TrimPrefix(authorization, "Bearer "))
+}
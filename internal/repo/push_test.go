@@ -0,0 +1,311 @@
+package repo
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePushEvent(t *testing.T) {
+	const secret = "secret"
+	sign := func(body string) string {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write([]byte(body))
+		return hex.EncodeToString(mac.Sum(nil))
+	}
+	gitRepo := &hub.Repository{
+		URL:        "https://github.com/org1/repo1",
+		PushSecret: secret,
+	}
+	ociRepo := &hub.Repository{
+		URL:        "oci://registry.io/ns/charts",
+		PushSecret: secret,
+	}
+	pushMain := `{"ref": "refs/heads/main", "repository": {"default_branch": "main"}}`
+	pushDev := `{"ref": "refs/heads/dev", "repository": {"default_branch": "main"}}`
+	pushTag := `{"ref": "refs/tags/v1.0.0", "repository": {"default_branch": "main"}}`
+	gitlabPushMain := `{"ref": "refs/heads/main", "project": {"default_branch": "main"}}`
+	distributionPush := `{"events": [{"action": "pull", "target": {"repository": "ns/charts/chart1"}}, {"action": "push", "target": {"repository": "ns/charts/chart1"}}]}`
+	distributionPushOther := `{"events": [{"action": "push", "target": {"repository": "ns/other/chart1"}}]}`
+
+	testCases := []struct {
+		desc             string
+		r                *hub.Repository
+		headers          map[string]string
+		body             string
+		expectedAffected bool
+		expectedErr      error
+	}{
+		{
+			"unsupported event",
+			gitRepo,
+			map[string]string{"Content-Type": "application/json"},
+			pushMain,
+			false,
+			errUnsupportedPushEvent,
+		},
+		{
+			"github: invalid signature",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign("other")},
+			pushMain,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"github: signature without prefix",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": sign(pushMain)},
+			pushMain,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"github: ping event",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "ping", "X-Hub-Signature-256": "sha256=" + sign(`{}`)},
+			`{}`,
+			false,
+			nil,
+		},
+		{
+			"github: invalid payload",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(`{`)},
+			`{`,
+			false,
+			errUnsupportedPushEvent,
+		},
+		{
+			"github: push to default branch",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(pushMain)},
+			pushMain,
+			true,
+			nil,
+		},
+		{
+			"github: push to other branch",
+			gitRepo,
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(pushDev)},
+			pushDev,
+			false,
+			nil,
+		},
+		{
+			"github: push to repository branch",
+			&hub.Repository{URL: gitRepo.URL, Branch: "dev", PushSecret: secret},
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(pushDev)},
+			pushDev,
+			true,
+			nil,
+		},
+		{
+			"github: push to repository git ref tag",
+			&hub.Repository{URL: gitRepo.URL, GitRef: "refs/tags/v1.0.0", PushSecret: secret},
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(pushTag)},
+			pushTag,
+			true,
+			nil,
+		},
+		{
+			"github: push to repository pinned to a commit",
+			&hub.Repository{URL: gitRepo.URL, GitRef: "0123456789abcdef0123456789abcdef01234567", PushSecret: secret},
+			map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature-256": "sha256=" + sign(pushMain)},
+			pushMain,
+			false,
+			nil,
+		},
+		{
+			"gitea: invalid signature",
+			gitRepo,
+			map[string]string{"X-Gitea-Event": "push", "X-GitHub-Event": "push", "X-Gitea-Signature": sign("other")},
+			pushMain,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"gitea: push to default branch",
+			gitRepo,
+			map[string]string{"X-Gitea-Event": "push", "X-GitHub-Event": "push", "X-Gitea-Signature": sign(pushMain)},
+			pushMain,
+			true,
+			nil,
+		},
+		{
+			"gitlab: invalid token",
+			gitRepo,
+			map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "invalid"},
+			gitlabPushMain,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"gitlab: tag push event",
+			gitRepo,
+			map[string]string{"X-Gitlab-Event": "Tag Push Hook", "X-Gitlab-Token": secret},
+			`{"ref": "refs/tags/v1.0.0"}`,
+			false,
+			nil,
+		},
+		{
+			"gitlab: push to default branch",
+			gitRepo,
+			map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": secret},
+			gitlabPushMain,
+			true,
+			nil,
+		},
+		{
+			"distribution: invalid token",
+			ociRepo,
+			map[string]string{"Content-Type": distributionEventsMediaType, "Authorization": "Bearer invalid"},
+			distributionPush,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"distribution: push to repository",
+			ociRepo,
+			map[string]string{"Content-Type": distributionEventsMediaType, "Authorization": "Bearer " + secret},
+			distributionPush,
+			true,
+			nil,
+		},
+		{
+			"distribution: push to other repository",
+			ociRepo,
+			map[string]string{"Content-Type": distributionEventsMediaType, "Authorization": "Bearer " + secret},
+			distributionPushOther,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: invalid token",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": "invalid"},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed"}`,
+			false,
+			errInvalidPushEventSignature,
+		},
+		{
+			"cloudevents: structured push event",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed", "data": {"repository": {"repo_full_name": "ns/charts/pkg1"}}}`,
+			true,
+			nil,
+		},
+		{
+			"cloudevents: structured push event to other repository",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed", "data": {"repository": {"repo_full_name": "ns/other/pkg1"}}}`,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: structured push event without repository",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed"}`,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: structured push event with repository in subject",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed", "subject": "ns/charts/pkg1:1.0.0"}`,
+			true,
+			nil,
+		},
+		{
+			"cloudevents: structured push event with invalid data",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed", "data": "invalid"}`,
+			false,
+			errUnsupportedPushEvent,
+		},
+		{
+			"cloudevents: structured delete event",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.deleted", "data": {"repository": {"repo_full_name": "ns/charts/pkg1"}}}`,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: structured event with unsupported type",
+			ociRepo,
+			map[string]string{"Content-Type": cloudEventsMediaType, "Authorization": secret},
+			`{"specversion": "1.0", "type": "harbor.artifact.pushed.failed", "data": {"repository": {"repo_full_name": "ns/charts/pkg1"}}}`,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: binary azure chart push event",
+			ociRepo,
+			map[string]string{
+				"Content-Type":   "application/json",
+				"Ce-Specversion": "1.0",
+				"Ce-Type":        "Microsoft.ContainerRegistry.ChartPushed",
+				"Authorization":  "Bearer " + secret,
+			},
+			`{"target": {"repository": "ns/charts/pkg1", "tag": "1.0.0"}}`,
+			true,
+			nil,
+		},
+		{
+			"cloudevents: binary azure image push event to other repository",
+			ociRepo,
+			map[string]string{
+				"Content-Type":   "application/json",
+				"Ce-Specversion": "1.0",
+				"Ce-Type":        "Microsoft.ContainerRegistry.ImagePushed",
+				"Ce-Subject":     "ns/chartsother:1.0.0",
+				"Authorization":  "Bearer " + secret,
+			},
+			`{"target": {"repository": "ns/chartsother", "tag": "1.0.0"}}`,
+			false,
+			nil,
+		},
+		{
+			"cloudevents: binary push event with repository in subject",
+			ociRepo,
+			map[string]string{
+				"Content-Type":   "application/json",
+				"Ce-Specversion": "1.0",
+				"Ce-Type":        "harbor.artifact.pushed",
+				"Ce-Subject":     "ns/charts/pkg1@sha256:0123456789",
+				"Authorization":  "Bearer " + secret,
+			},
+			`{}`,
+			true,
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			h := http.Header{}
+			for k, v := range tc.headers {
+				h.Set(k, v)
+			}
+			affected, err := parsePushEvent(tc.r, h, []byte(tc.body))
+			if tc.expectedErr != nil {
+				assert.True(t, errors.Is(err, tc.expectedErr))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedAffected, affected)
+		})
+	}
+}
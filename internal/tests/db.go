@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
@@ -178,6 +179,8 @@ func (m *RowMock) Scan(dest ...interface{}) error {
 				*v = e.(int)
 			case *int64:
 				*v = e.(int64)
+			case *time.Time:
+				*v = e.(time.Time)
 			}
 		}
 	}
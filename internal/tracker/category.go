@@ -1,3 +1,5 @@
+//go:build !notensorflow
+
 package tracker
 
 import (
@@ -24,6 +26,12 @@ func NewPackageCategoryClassifierML(modelPath string) *PackageCategoryClassifier
 	}
 }
 
+// newPackageCategoryClassifierML creates a new PackageCategoryClassifierML
+// instance, returning it as a hub.PackageCategoryClassifier.
+func newPackageCategoryClassifierML(modelPath string) (hub.PackageCategoryClassifier, error) {
+	return NewPackageCategoryClassifierML(modelPath), nil
+}
+
 // Predict returns the predicted category according to the model for the
 // package provided. The prediction is based on the package's keywords.
 func (c *PackageCategoryClassifierML) Predict(p *hub.Package) hub.PackageCategory {
@@ -0,0 +1,168 @@
+package tracker
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+)
+
+// getPublisherCategorizedPkgsDBQ represents the query used to get the latest
+// version of the packages in the catalog whose category was provided by the
+// publisher (and not predicted by a classifier).
+const getPublisherCategorizedPkgsDBQ = `
+select coalesce(json_agg(json_build_object(
+	'category', p.package_category_id,
+	'name', p.name,
+	'display_name', s.display_name,
+	'description', s.description,
+	'keywords', s.keywords,
+	'readme', s.readme
+)), '[]')
+from package p
+join snapshot s using (package_id)
+where s.version = p.latest_version
+and p.package_category_id is not null
+and p.package_category_predicted = false
+`
+
+// CategoryClassifierEvaluation represents the result of evaluating a package
+// category classifier against a set of labelled packages.
+type CategoryClassifierEvaluation struct {
+	// Total represents the number of packages evaluated.
+	Total int
+
+	// Correct represents the number of packages whose category was predicted
+	// correctly.
+	Correct int
+
+	// Confusion represents the confusion matrix of the evaluation, indexed by
+	// the expected category and the predicted one.
+	Confusion map[hub.PackageCategory]map[hub.PackageCategory]int
+}
+
+// NewCategoryClassifierEvaluation creates a new CategoryClassifierEvaluation
+// instance.
+func NewCategoryClassifierEvaluation() *CategoryClassifierEvaluation {
+	return &CategoryClassifierEvaluation{
+		Confusion: make(map[hub.PackageCategory]map[hub.PackageCategory]int),
+	}
+}
+
+// Add registers a new prediction in the evaluation.
+func (e *CategoryClassifierEvaluation) Add(expected, predicted hub.PackageCategory) {
+	e.Total++
+	if expected == predicted {
+		e.Correct++
+	}
+	if _, ok := e.Confusion[expected]; !ok {
+		e.Confusion[expected] = make(map[hub.PackageCategory]int)
+	}
+	e.Confusion[expected][predicted]++
+}
+
+// Accuracy returns the ratio of packages whose category was predicted
+// correctly.
+func (e *CategoryClassifierEvaluation) Accuracy() float64 {
+	if e.Total == 0 {
+		return 0
+	}
+	return float64(e.Correct) / float64(e.Total)
+}
+
+// CategoryStats returns the number of packages in the category provided, as
+// well as the precision and recall of the predictions for that category.
+func (e *CategoryClassifierEvaluation) CategoryStats(category hub.PackageCategory) (int, float64, float64) {
+	var total, predicted int
+	for _, count := range e.Confusion[category] {
+		total += count
+	}
+	for _, predictions := range e.Confusion {
+		predicted += predictions[category]
+	}
+	truePositives := e.Confusion[category][category]
+
+	var precision, recall float64
+	if predicted > 0 {
+		precision = float64(truePositives) / float64(predicted)
+	}
+	if total > 0 {
+		recall = float64(truePositives) / float64(total)
+	}
+	return total, precision, recall
+}
+
+// EvaluateCategoryClassifier evaluates the classifier provided against the
+// packages in the catalog whose category was provided by the publisher, using
+// the category as label and the latest version of each package.
+func EvaluateCategoryClassifier(
+	ctx context.Context,
+	db hub.DB,
+	pcc hub.PackageCategoryClassifier,
+) (*CategoryClassifierEvaluation, error) {
+	var pkgs []*hub.Package
+	if err := util.DBQueryUnmarshal(ctx, db, &pkgs, getPublisherCategorizedPkgsDBQ); err != nil {
+		return nil, err
+	}
+
+	e := NewCategoryClassifierEvaluation()
+	for _, p := range pkgs {
+		expected := p.Category
+		p.Category = hub.UnknownCategory
+		e.Add(expected, pcc.Predict(p))
+	}
+
+	return e, nil
+}
+
+// EvaluateCategoryClassifierFromCSV evaluates the classifier provided against
+// the labelled packages read from the reader given. Packages are expected in
+// the format used by the datasets of the ML model (ml/category/data/csv): one
+// package per line, with the expected category label and the package's
+// keywords separated by a semicolon (i.e. 2-database;postgresql,sql).
+func EvaluateCategoryClassifierFromCSV(
+	r io.Reader,
+	pcc hub.PackageCategoryClassifier,
+) (*CategoryClassifierEvaluation, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = ';'
+	cr.FieldsPerRecord = 2
+
+	e := NewCategoryClassifierEvaluation()
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading labelled packages: %w", err)
+		}
+		expected, err := parseCategoryLabel(record[0])
+		if err != nil {
+			return nil, err
+		}
+		p := &hub.Package{
+			Keywords: strings.Split(record[1], ","),
+		}
+		e.Add(expected, pcc.Predict(p))
+	}
+
+	return e, nil
+}
+
+// parseCategoryLabel returns the category corresponding to the label provided
+// (i.e. 2-database).
+func parseCategoryLabel(label string) (hub.PackageCategory, error) {
+	id, _, _ := strings.Cut(label, "-")
+	v, err := strconv.Atoi(id)
+	if err != nil || v < int(hub.UnknownCategory) || v > int(hub.StreamingMessaging) {
+		return hub.UnknownCategory, fmt.Errorf("invalid category label: %s", label)
+	}
+	return hub.PackageCategory(v), nil
+}
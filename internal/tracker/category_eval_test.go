@@ -0,0 +1,135 @@
+package tracker
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateCategoryClassifier(t *testing.T) {
+	ctx := context.Background()
+	pcc, err := newPackageCategoryClassifierRules(&CategoryRules{
+		MinScore: 1,
+		Fields:   CategoryRulesFields{Keywords: 1, Description: 1, Readme: 1},
+		Categories: map[string]map[string]float64{
+			"database":   {"postgresql": 1},
+			"networking": {"ingress": 1},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPublisherCategorizedPkgsDBQ).Return(nil, tests.ErrFakeDB)
+
+		e, err := EvaluateCategoryClassifier(ctx, db, pcc)
+		assert.Nil(t, e)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("evaluation completed successfully", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getPublisherCategorizedPkgsDBQ).Return([]byte(`
+		[
+			{
+				"category": 2,
+				"name": "pkg1",
+				"description": "PostgreSQL database",
+				"keywords": null,
+				"readme": null
+			},
+			{
+				"category": 2,
+				"name": "pkg2",
+				"keywords": ["mysql"]
+			},
+			{
+				"category": 5,
+				"name": "pkg3",
+				"readme": "# Ingress controller"
+			}
+		]
+		`), nil)
+
+		e, err := EvaluateCategoryClassifier(ctx, db, pcc)
+		require.NoError(t, err)
+		assert.Equal(t, 3, e.Total)
+		assert.Equal(t, 2, e.Correct)
+		assert.Equal(t, 1, e.Confusion[hub.Database][hub.Database])
+		assert.Equal(t, 1, e.Confusion[hub.Database][hub.UnknownCategory])
+		assert.Equal(t, 1, e.Confusion[hub.Networking][hub.Networking])
+		db.AssertExpectations(t)
+	})
+}
+
+func TestEvaluateCategoryClassifierFromCSV(t *testing.T) {
+	pcc, err := newPackageCategoryClassifierRules(&CategoryRules{
+		MinScore: 1,
+		Fields:   CategoryRulesFields{Keywords: 1},
+		Categories: map[string]map[string]float64{
+			"database":   {"postgresql": 1},
+			"networking": {"ingress": 1, "mysql": 1},
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("invalid labelled packages", func(t *testing.T) {
+		testCases := []struct {
+			data   string
+			errMsg string
+		}{
+			{
+				"2-database",
+				"error reading labelled packages",
+			},
+			{
+				"database;postgresql",
+				"invalid category label: database",
+			},
+			{
+				"9-other;postgresql",
+				"invalid category label: 9-other",
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				e, err := EvaluateCategoryClassifierFromCSV(strings.NewReader(tc.data), pcc)
+				assert.Nil(t, e)
+				assert.ErrorContains(t, err, tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("evaluation completed successfully", func(t *testing.T) {
+		t.Parallel()
+		data := `0-unknown;bitcoin,blockchain
+2-database;postgresql
+2-database;mysql
+5-networking;ingress
+`
+
+		e, err := EvaluateCategoryClassifierFromCSV(strings.NewReader(data), pcc)
+		require.NoError(t, err)
+		assert.Equal(t, 4, e.Total)
+		assert.Equal(t, 3, e.Correct)
+		assert.InDelta(t, 0.75, e.Accuracy(), 0.001)
+		total, precision, recall := e.CategoryStats(hub.Database)
+		assert.Equal(t, 2, total)
+		assert.InDelta(t, 1.0, precision, 0.001)
+		assert.InDelta(t, 0.5, recall, 0.001)
+		total, precision, recall = e.CategoryStats(hub.Networking)
+		assert.Equal(t, 1, total)
+		assert.InDelta(t, 0.5, precision, 0.001)
+		assert.InDelta(t, 1.0, recall, 0.001)
+		assert.Equal(t, 1, e.Confusion[hub.UnknownCategory][hub.UnknownCategory])
+	})
+}
@@ -0,0 +1,16 @@
+//go:build notensorflow
+
+package tracker
+
+import (
+	"errors"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// newPackageCategoryClassifierML returns an error, as the ML based classifier
+// is not available when the tracker is built without TensorFlow support
+// (notensorflow build tag).
+func newPackageCategoryClassifierML(modelPath string) (hub.PackageCategoryClassifier, error) {
+	return nil, errors.New("ml category classifier not available: tracker built without tensorflow support")
+}
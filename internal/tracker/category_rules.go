@@ -0,0 +1,182 @@
+package tracker
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"gopkg.in/yaml.v3"
+)
+
+// maxReadmeLength represents the maximum length of the readme content that
+// will be considered when classifying a package.
+const maxReadmeLength = 20000
+
+// defaultCategoryRules represents the rules used by the rules based classifier
+// when no rules file is provided.
+//
+//go:embed data/category_rules.yml
+var defaultCategoryRules []byte
+
+// CategoryRules represents the rules used by the rules based package category
+// classifier.
+type CategoryRules struct {
+	MinScore   float64                       `yaml:"minScore"`
+	Fields     CategoryRulesFields           `yaml:"fields"`
+	Categories map[string]map[string]float64 `yaml:"categories"`
+}
+
+// CategoryRulesFields represents the weights applied to the terms found in
+// each of the package's fields.
+type CategoryRulesFields struct {
+	Keywords    float64 `yaml:"keywords"`
+	Name        float64 `yaml:"name"`
+	Description float64 `yaml:"description"`
+	Readme      float64 `yaml:"readme"`
+}
+
+// PackageCategoryClassifierRules classifies packages by category using a set
+// of weighted terms. The score of each category is computed by adding the
+// weights of the category terms found in the package's keywords, name,
+// description and readme, multiplied by the weight of the field they were
+// found in. The category with the highest score is predicted as long as it
+// reaches the minimum score.
+type PackageCategoryClassifierRules struct {
+	minScore   float64
+	fields     CategoryRulesFields
+	categories []*categoryTerms
+}
+
+// categoryTerms represents the weighted terms of a given category.
+type categoryTerms struct {
+	category hub.PackageCategory
+	terms    []*weightedTerm
+}
+
+// weightedTerm represents a normalized term and its weight.
+type weightedTerm struct {
+	term   string
+	weight float64
+}
+
+// NewPackageCategoryClassifierRules creates a new
+// PackageCategoryClassifierRules instance using the rules file provided. When
+// no rules file is provided, the default rules are used.
+func NewPackageCategoryClassifierRules(rulesPath string) (*PackageCategoryClassifierRules, error) {
+	data := defaultCategoryRules
+	if rulesPath != "" {
+		var err error
+		data, err = os.ReadFile(rulesPath)
+		if err != nil {
+			return nil, fmt.Errorf("error reading rules file: %w", err)
+		}
+	}
+	var rules *CategoryRules
+	if err := yaml.Unmarshal(data, &rules); err != nil || rules == nil {
+		return nil, fmt.Errorf("error unmarshaling rules file: %w", err)
+	}
+	return newPackageCategoryClassifierRules(rules)
+}
+
+// newPackageCategoryClassifierRules validates the rules provided and prepares
+// a new PackageCategoryClassifierRules instance from them.
+func newPackageCategoryClassifierRules(rules *CategoryRules) (*PackageCategoryClassifierRules, error) {
+	if rules.MinScore <= 0 {
+		return nil, errors.New("invalid rules: min score must be greater than zero")
+	}
+	c := &PackageCategoryClassifierRules{
+		minScore: rules.MinScore,
+		fields:   rules.Fields,
+	}
+	for name, terms := range rules.Categories {
+		category, err := hub.PackageCategoryFromName(name)
+		if err != nil || category == hub.SkipCategoryPrediction {
+			return nil, fmt.Errorf("invalid rules: invalid category %s", name)
+		}
+		ct := &categoryTerms{category: category}
+		for term, weight := range terms {
+			normalizedTerm := strings.TrimSpace(normalizeText(term))
+			if normalizedTerm == "" {
+				return nil, fmt.Errorf("invalid rules: invalid term in category %s: %s", name, term)
+			}
+			ct.terms = append(ct.terms, &weightedTerm{term: normalizedTerm, weight: weight})
+		}
+		c.categories = append(c.categories, ct)
+	}
+	if len(c.categories) == 0 {
+		return nil, errors.New("invalid rules: no categories found")
+	}
+	slices.SortFunc(c.categories, func(a, b *categoryTerms) int {
+		return int(a.category - b.category)
+	})
+	return c, nil
+}
+
+// Predict returns the predicted category for the package provided according
+// to the classifier rules.
+func (c *PackageCategoryClassifierRules) Predict(p *hub.Package) hub.PackageCategory {
+	if p == nil {
+		return hub.UnknownCategory
+	}
+
+	// Prepare package's fields
+	keywords := make([]string, 0, len(p.Keywords))
+	for _, kw := range p.Keywords {
+		keywords = append(keywords, strings.TrimSpace(normalizeText(kw)))
+	}
+	readme := p.Readme
+	if len(readme) > maxReadmeLength {
+		readme = readme[:maxReadmeLength]
+	}
+	fields := []struct {
+		text   string
+		weight float64
+	}{
+		{" " + strings.Join(keywords, " | ") + " ", c.fields.Keywords},
+		{normalizeText(p.Name + " " + p.DisplayName), c.fields.Name},
+		{normalizeText(p.Description), c.fields.Description},
+		{normalizeText(readme), c.fields.Readme},
+	}
+
+	// Score each category and select the best one
+	predicted := hub.UnknownCategory
+	var maxScore float64
+	var tie bool
+	for _, ct := range c.categories {
+		var score float64
+		for _, t := range ct.terms {
+			for _, f := range fields {
+				if f.weight > 0 && strings.Contains(f.text, " "+t.term+" ") {
+					score += t.weight * f.weight
+				}
+			}
+		}
+		switch {
+		case score > maxScore:
+			predicted = ct.category
+			maxScore = score
+			tie = false
+		case score == maxScore && score > 0:
+			tie = true
+		}
+	}
+	if tie || maxScore < c.minScore {
+		return hub.UnknownCategory
+	}
+	return predicted
+}
+
+// normalizeText returns a normalized version of the text provided: lower case
+// words separated by a single space, with a leading and a trailing space so
+// that terms can be matched as whole words.
+func normalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
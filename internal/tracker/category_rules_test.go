@@ -0,0 +1,204 @@
+package tracker
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPackageCategoryClassifierRules(t *testing.T) {
+	t.Run("default rules loaded successfully", func(t *testing.T) {
+		t.Parallel()
+		c, err := NewPackageCategoryClassifierRules("")
+		require.NoError(t, err)
+		assert.Len(t, c.categories, 8)
+	})
+
+	t.Run("error reading rules file", func(t *testing.T) {
+		t.Parallel()
+		c, err := NewPackageCategoryClassifierRules("testdata/not-found.yml")
+		assert.Nil(t, c)
+		assert.ErrorContains(t, err, "error reading rules file")
+	})
+
+	testCases := []struct {
+		rules       string
+		expectedErr string
+	}{
+		{
+			"minScore: [",
+			"error unmarshaling rules file",
+		},
+		{
+			"minScore: 0",
+			"invalid rules: min score must be greater than zero",
+		},
+		{
+			"minScore: 1",
+			"invalid rules: no categories found",
+		},
+		{
+			"minScore: 1\ncategories:\n  invalid:\n    term: 1",
+			"invalid rules: invalid category invalid",
+		},
+		{
+			"minScore: 1\ncategories:\n  skip-prediction:\n    term: 1",
+			"invalid rules: invalid category skip-prediction",
+		},
+		{
+			"minScore: 1\ncategories:\n  database:\n    '--': 1",
+			"invalid rules: invalid term in category database: --",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.expectedErr, func(t *testing.T) {
+			t.Parallel()
+			rulesPath := filepath.Join(t.TempDir(), "rules.yml")
+			require.NoError(t, os.WriteFile(rulesPath, []byte(tc.rules), 0o600))
+			c, err := NewPackageCategoryClassifierRules(rulesPath)
+			assert.Nil(t, c)
+			assert.ErrorContains(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestPackageCategoryClassifierRulesPredict(t *testing.T) {
+	c, err := newPackageCategoryClassifierRules(&CategoryRules{
+		MinScore: 3,
+		Fields: CategoryRulesFields{
+			Keywords:    3,
+			Name:        2,
+			Description: 1.5,
+			Readme:      0.25,
+		},
+		Categories: map[string]map[string]float64{
+			"database": {
+				"postgresql": 2,
+				"sql":        1,
+			},
+			"monitoring-logging": {
+				"monitoring": 2,
+				"prometheus": 2,
+			},
+			"storage": {
+				"object storage": 2,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		p                *hub.Package
+		expectedCategory hub.PackageCategory
+	}{
+		{
+			nil,
+			hub.UnknownCategory,
+		},
+		{
+			&hub.Package{
+				Name: "pkg1",
+			},
+			hub.UnknownCategory,
+		},
+		{
+			&hub.Package{
+				Name:     "pkg1",
+				Keywords: []string{"PostgreSQL"},
+			},
+			hub.Database,
+		},
+		{
+			&hub.Package{
+				Name:        "prometheus",
+				Description: "Monitoring system and time series database",
+			},
+			hub.MonitoringLogging,
+		},
+		{
+			&hub.Package{
+				Name:        "pkg1",
+				Description: "S3 compatible Object-Storage server",
+			},
+			hub.Storage,
+		},
+		{
+			&hub.Package{
+				Name:        "pkg1",
+				Description: "Uses mysql as backend",
+			},
+			hub.UnknownCategory,
+		},
+		{
+			&hub.Package{
+				Name:   "pkg1",
+				Readme: "postgresql postgresql postgresql",
+			},
+			hub.UnknownCategory,
+		},
+		{
+			&hub.Package{
+				Name:     "pkg1",
+				Keywords: []string{"postgresql", "prometheus"},
+			},
+			hub.UnknownCategory,
+		},
+		{
+			&hub.Package{
+				Name:     "pkg1",
+				Keywords: []string{"postgresqlx"},
+			},
+			hub.UnknownCategory,
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedCategory, c.Predict(tc.p))
+		})
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	testCases := []struct {
+		text         string
+		expectedText string
+	}{
+		{"", "  "},
+		{"Machine-Learning", " machine learning "},
+		{"  CI/CD   pipelines!", " ci cd pipelines "},
+		{"OAuth2 proxy", " oauth2 proxy "},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedText, normalizeText(tc.text))
+		})
+	}
+}
+
+func TestSetupPackageCategoryClassifier(t *testing.T) {
+	t.Run("rules classifier", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.categoryClassifier", "rules")
+		pcc, err := SetupPackageCategoryClassifier(cfg)
+		require.NoError(t, err)
+		assert.IsType(t, &PackageCategoryClassifierRules{}, pcc)
+	})
+
+	t.Run("invalid classifier", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.categoryClassifier", "invalid")
+		pcc, err := SetupPackageCategoryClassifier(cfg)
+		assert.Nil(t, pcc)
+		assert.EqualError(t, err, "invalid category classifier: invalid")
+	})
+}
@@ -21,6 +21,19 @@ const (
 	cloudNativeSecurityHub = "https://github.com/falcosecurity/cloud-native-security-hub/resources/falco"
 )
 
+// SetupPackageCategoryClassifier sets up the package category classifier
+// selected in the configuration provided (ml or rules).
+func SetupPackageCategoryClassifier(cfg *viper.Viper) (hub.PackageCategoryClassifier, error) {
+	switch cfg.GetString("tracker.categoryClassifier") {
+	case "", "ml":
+		return newPackageCategoryClassifierML(cfg.GetString("tracker.categoryModelPath"))
+	case "rules":
+		return NewPackageCategoryClassifierRules(cfg.GetString("tracker.categoryRulesPath"))
+	default:
+		return nil, fmt.Errorf("invalid category classifier: %s", cfg.GetString("tracker.categoryClassifier"))
+	}
+}
+
 // GetRepositories gets the repositories the tracker will process based on the
 // configuration provided:
 //
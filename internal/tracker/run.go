@@ -0,0 +1,75 @@
+package tracker
+
+import (
+	"regexp"
+	"sync"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+// maxRunErrors represents the maximum number of errors recorded per tracking
+// run.
+const maxRunErrors = 100
+
+// packageVersionRE is a regexp used to extract the package name and version
+// from the errors produced while tracking a repository, when available.
+var packageVersionRE = regexp.MustCompile(`package:? (\S+) version:? ([^\s):]+)`)
+
+// runRecorder records the details of a repository tracking run. It wraps the
+// errors collector used during the run, so that the errors produced by the
+// tracker and its sources are recorded before being passed to it.
+type runRecorder struct {
+	ec  hub.ErrorsCollector
+	mu  sync.Mutex
+	run *hub.TrackingRun
+
+	// skipped indicates that the repository has not changed since the last
+	// time it was processed, so the run does not need to be registered.
+	skipped bool
+}
+
+// newRunRecorder creates a new runRecorder instance.
+func newRunRecorder(ec hub.ErrorsCollector, r *hub.Repository, startedAt int64) *runRecorder {
+	return &runRecorder{
+		ec: ec,
+		run: &hub.TrackingRun{
+			RepositoryID: r.RepositoryID,
+			StartedAt:    startedAt,
+		},
+	}
+}
+
+// Append implements the ErrorsCollector interface.
+func (rr *runRecorder) Append(repositoryID, err string) {
+	rr.ec.Append(repositoryID, err)
+	if repositoryID == rr.run.RepositoryID {
+		rr.recordError(err)
+	}
+}
+
+// Flush implements the ErrorsCollector interface.
+func (rr *runRecorder) Flush() {
+	rr.ec.Flush()
+}
+
+// Init implements the ErrorsCollector interface.
+func (rr *runRecorder) Init(repositoryID string) {
+	rr.ec.Init(repositoryID)
+}
+
+// recordError adds the error provided to the run's list of errors, extracting
+// the package name and version from it when possible.
+func (rr *runRecorder) recordError(err string) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	if len(rr.run.Errors) >= maxRunErrors {
+		return
+	}
+	e := &hub.TrackingRunError{Message: err}
+	if m := packageVersionRE.FindStringSubmatch(err); m != nil {
+		e.PackageName = m[1]
+		e.PackageVersion = m[2]
+	}
+	rr.run.Errors = append(rr.run.Errors, e)
+}
@@ -0,0 +1,63 @@
+package tracker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunRecorder(t *testing.T) {
+	r := &hub.Repository{RepositoryID: "repo1"}
+
+	t.Run("errors are recorded and passed to the errors collector", func(t *testing.T) {
+		t.Parallel()
+		ec := &repo.ErrorsCollectorMock{}
+		ec.On("Init", "repo1")
+		ec.On("Append", "repo1", "error1")
+		ec.On("Append", "repo1", "error registering package pkg1 version 1.0.0: error2")
+		ec.On("Append", "repo1", "error preparing package: error3 (package: pkg2 version: 2.0.0)")
+		ec.On("Append", "repo2", "error4")
+		ec.On("Flush")
+		rr := newRunRecorder(ec, r, 1)
+
+		rr.Init("repo1")
+		rr.Append("repo1", "error1")
+		rr.Append("repo1", "error registering package pkg1 version 1.0.0: error2")
+		rr.Append("repo1", "error preparing package: error3 (package: pkg2 version: 2.0.0)")
+		rr.Append("repo2", "error4")
+		rr.Flush()
+		assert.Equal(t, &hub.TrackingRun{
+			RepositoryID: "repo1",
+			StartedAt:    1,
+			Errors: []*hub.TrackingRunError{
+				{
+					Message: "error1",
+				},
+				{
+					PackageName:    "pkg1",
+					PackageVersion: "1.0.0",
+					Message:        "error registering package pkg1 version 1.0.0: error2",
+				},
+				{
+					PackageName:    "pkg2",
+					PackageVersion: "2.0.0",
+					Message:        "error preparing package: error3 (package: pkg2 version: 2.0.0)",
+				},
+			},
+		}, rr.run)
+		ec.AssertExpectations(t)
+	})
+
+	t.Run("errors recorded are limited", func(t *testing.T) {
+		t.Parallel()
+		rr := newRunRecorder(nil, r, 1)
+
+		for i := 0; i < maxRunErrors+10; i++ {
+			rr.recordError(fmt.Sprintf("error%d", i))
+		}
+		assert.Len(t, rr.run.Errors, maxRunErrors)
+	})
+}
@@ -0,0 +1,310 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	// Database queries
+	getDueRepositoriesDBQ = `select get_repositories_due_for_tracking($1::text[], $2::int[])`
+	tryLockRepositoryDBQ  = `select pg_try_advisory_lock($1::integer, hashtext($2::uuid::text))`
+	unlockRepositoryDBQ   = `select pg_advisory_unlock($1::integer, hashtext($2::uuid::text))`
+	isRepositoryDueDBQ    = `
+		select current_timestamp, exists (
+			select 1 from repository
+			where repository_id = $1::uuid
+			and (
+				next_tracking_at is null
+				or next_tracking_at <= current_timestamp
+				or tracking_requested_at is not null
+			)
+		)
+	`
+	updateTrackingScheduleDBQ = `
+		select update_repository_tracking_schedule($1::uuid, $2::text, $3::boolean, $4::integer, $5::integer, $6::timestamptz)
+	`
+
+	// dbTimeout represents the maximum amount of time the database operations
+	// performed once a repository has been tracked (updating its schedule and
+	// releasing its lock) can take.
+	dbTimeout = 10 * time.Second
+
+	// maxConcurrency represents the maximum number of repositories that can
+	// be tracked concurrently. Each repository being tracked holds a
+	// dedicated database connection, so some connections of the pool must be
+	// left available for the tracking itself.
+	maxConcurrency = util.DBMaxConns / 2
+
+	// defaultPollFrequency represents how often the scheduler will check if
+	// there are repositories due for tracking.
+	defaultPollFrequency = 15 * time.Second
+
+	// defaultInterval represents the default interval between trackings of a
+	// repository, used when the repository does not define its own.
+	defaultInterval = 30 * time.Minute
+
+	// defaultMaxBackoffFactor represents the default maximum factor applied
+	// to the tracking interval of repositories that have not changed lately.
+	defaultMaxBackoffFactor = 8
+)
+
+// TrackFn represents a function that tracks the repository provided.
+type TrackFn func(ctx context.Context, r *hub.Repository) error
+
+// dbConn represents a database connection acquired from the pool, used to
+// hold the session level lock of the repositories being tracked.
+type dbConn interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Release()
+	Close(ctx context.Context) error
+}
+
+// poolConn is a wrapper around a connection acquired from the pool that
+// implements the dbConn interface.
+type poolConn struct {
+	*pgxpool.Conn
+}
+
+// Close closes the connection, removing it from the pool.
+func (c *poolConn) Close(ctx context.Context) error {
+	return c.Hijack().Close(ctx)
+}
+
+// Scheduler is in charge of tracking repositories periodically, each of them
+// on its own schedule. Multiple schedulers can run concurrently (i.e. in
+// different tracker replicas), as repositories are locked in the database
+// while they are being tracked to make sure they are never processed twice
+// at the same time.
+type Scheduler struct {
+	db          hub.DB
+	rm          hub.RepositoryManager
+	track       TrackFn
+	acquireConn func(ctx context.Context) (dbConn, error)
+
+	names            []string
+	kinds            []int
+	concurrency      int
+	pollFrequency    time.Duration
+	defaultInterval  time.Duration
+	maxBackoffFactor int
+
+	mu         sync.Mutex
+	inProgress map[string]struct{}
+}
+
+// New creates a new Scheduler instance.
+func New(cfg *viper.Viper, db hub.DB, rm hub.RepositoryManager, track TrackFn) (*Scheduler, error) {
+	s := &Scheduler{
+		db:               db,
+		rm:               rm,
+		track:            track,
+		names:            cfg.GetStringSlice("tracker.repositoriesNames"),
+		concurrency:      cfg.GetInt("tracker.concurrency"),
+		pollFrequency:    defaultPollFrequency,
+		defaultInterval:  defaultInterval,
+		maxBackoffFactor: defaultMaxBackoffFactor,
+		inProgress:       make(map[string]struct{}),
+	}
+	s.acquireConn = func(ctx context.Context) (dbConn, error) {
+		conn, err := s.db.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &poolConn{conn}, nil
+	}
+	for _, kindName := range cfg.GetStringSlice("tracker.repositoriesKinds") {
+		kind, err := hub.GetKindFromName(kindName)
+		if err != nil {
+			return nil, fmt.Errorf("invalid repository kind found in config: %s", kindName)
+		}
+		s.kinds = append(s.kinds, int(kind))
+	}
+	if s.concurrency < 1 {
+		s.concurrency = 1
+	}
+	if s.concurrency > maxConcurrency {
+		log.Warn().Int("concurrency", s.concurrency).Int("max", maxConcurrency).Msg("tracker concurrency capped")
+		s.concurrency = maxConcurrency
+	}
+	if cfg.IsSet("tracker.daemon.pollFrequency") {
+		s.pollFrequency = cfg.GetDuration("tracker.daemon.pollFrequency")
+	}
+	if cfg.IsSet("tracker.daemon.defaultInterval") {
+		s.defaultInterval = cfg.GetDuration("tracker.daemon.defaultInterval")
+	}
+	if cfg.IsSet("tracker.daemon.maxBackoffFactor") {
+		s.maxBackoffFactor = cfg.GetInt("tracker.daemon.maxBackoffFactor")
+	}
+	if s.defaultInterval < time.Second {
+		return nil, fmt.Errorf("invalid default tracking interval: %s", s.defaultInterval)
+	}
+	return s, nil
+}
+
+// Run is the main loop of the scheduler. It checks periodically if there are
+// repositories due for tracking and processes them, until the context
+// provided is done. Before returning, it waits for the repositories being
+// tracked at that moment to finish.
+func (s *Scheduler) Run(ctx context.Context) {
+	limiter := make(chan struct{}, s.concurrency)
+	var wg sync.WaitGroup
+	for {
+		repositoriesIDs, err := s.getDueRepositories(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Error().Err(err).Msg("error getting repositories due for tracking")
+		}
+	L:
+		for _, repositoryID := range repositoriesIDs {
+			if !s.markInProgress(repositoryID) {
+				continue
+			}
+			select {
+			case limiter <- struct{}{}:
+			case <-ctx.Done():
+				s.unmarkInProgress(repositoryID)
+				break L
+			}
+			wg.Add(1)
+			go func(repositoryID string) {
+				defer func() {
+					s.unmarkInProgress(repositoryID)
+					<-limiter
+					wg.Done()
+				}()
+				if err := s.processRepository(ctx, repositoryID); err != nil && ctx.Err() == nil {
+					log.Error().Err(err).Str("repoID", repositoryID).Msg("error processing repository")
+				}
+			}(repositoryID)
+		}
+
+		select {
+		case <-time.After(s.pollFrequency):
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+	}
+}
+
+// getDueRepositories returns the ids of the repositories due for tracking.
+func (s *Scheduler) getDueRepositories(ctx context.Context) ([]string, error) {
+	var repositoriesIDs []string
+	err := util.DBQueryUnmarshal(ctx, s.db, &repositoriesIDs, getDueRepositoriesDBQ, s.names, s.kinds)
+	return repositoriesIDs, err
+}
+
+// processRepository tracks the repository provided and schedules its next
+// tracking. The repository is locked while it's being processed, so if it is
+// already locked (i.e. another replica is processing it) or it's not due for
+// tracking anymore, it'll be skipped. A session level lock is used, held on a
+// dedicated connection, so that no transaction is kept open while the
+// repository is being tracked. When the lock cannot be released, the
+// connection is closed instead of being returned to the pool, so that the lock
+// is not held by a connection that may be reused.
+func (s *Scheduler) processRepository(ctx context.Context, repositoryID string) error {
+	conn, err := s.acquireConn(ctx)
+	if err != nil {
+		return fmt.Errorf("error acquiring database connection: %w", err)
+	}
+
+	// Try to lock the repository
+	var locked bool
+	err = conn.QueryRow(ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).Scan(&locked)
+	if err != nil || !locked {
+		conn.Release()
+		if err != nil {
+			return fmt.Errorf("error locking repository: %w", err)
+		}
+		return nil
+	}
+	defer func() {
+		// The lock is released even if the context provided is done, as the
+		// connection will be returned to the pool
+		unlockCtx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+		defer cancel()
+		_, err := conn.Exec(unlockCtx, unlockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID)
+		if err != nil {
+			log.Error().Err(err).Str("repoID", repositoryID).Msg("error unlocking repository")
+			if err := conn.Close(unlockCtx); err != nil {
+				log.Error().Err(err).Str("repoID", repositoryID).Msg("error closing database connection")
+			}
+			return
+		}
+		conn.Release()
+	}()
+
+	// Check the repository is still due for tracking
+	var trackingStartedAt time.Time
+	var due bool
+	err = conn.QueryRow(ctx, isRepositoryDueDBQ, repositoryID).Scan(&trackingStartedAt, &due)
+	if err != nil {
+		return fmt.Errorf("error checking if repository is due for tracking: %w", err)
+	}
+	if !due {
+		return nil
+	}
+
+	// Track repository
+	r, err := s.rm.GetByID(ctx, repositoryID, true)
+	if err != nil {
+		return fmt.Errorf("error getting repository: %w", err)
+	}
+	if r.Disabled {
+		return nil
+	}
+	previousDigest := r.Digest
+	trackErr := s.track(ctx, r)
+
+	// Schedule next tracking (even if the context provided is done, as the
+	// repository has already been tracked)
+	updateCtx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	_, err = conn.Exec(
+		updateCtx,
+		updateTrackingScheduleDBQ,
+		repositoryID,
+		previousDigest,
+		trackErr == nil,
+		int(s.defaultInterval.Seconds()),
+		s.maxBackoffFactor,
+		trackingStartedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("error updating tracking schedule: %w", err)
+	}
+	return nil
+}
+
+// markInProgress marks the repository provided as being processed by this
+// scheduler. It returns false if the repository was already in progress.
+func (s *Scheduler) markInProgress(repositoryID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.inProgress[repositoryID]; ok {
+		return false
+	}
+	s.inProgress[repositoryID] = struct{}{}
+	return true
+}
+
+// unmarkInProgress removes the repository provided from the list of
+// repositories being processed by this scheduler.
+func (s *Scheduler) unmarkInProgress(repositoryID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.inProgress, repositoryID)
+}
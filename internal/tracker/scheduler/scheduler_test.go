@@ -0,0 +1,419 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/repo"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/util"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const repositoryID = "00000000-0000-0000-0000-000000000001"
+
+var trackingStartedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestNew(t *testing.T) {
+	t.Run("invalid repository kind in config", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.repositoriesKinds", []string{"invalid"})
+
+		s, err := New(cfg, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("invalid default interval in config", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.daemon.defaultInterval", "0s")
+
+		s, err := New(cfg, nil, nil, nil)
+		assert.Error(t, err)
+		assert.Nil(t, s)
+	})
+
+	t.Run("defaults are used when not set in config", func(t *testing.T) {
+		t.Parallel()
+		s, err := New(viper.New(), nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 1, s.concurrency)
+		assert.Equal(t, defaultPollFrequency, s.pollFrequency)
+		assert.Equal(t, defaultInterval, s.defaultInterval)
+		assert.Equal(t, defaultMaxBackoffFactor, s.maxBackoffFactor)
+	})
+
+	t.Run("concurrency capped", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.concurrency", util.DBMaxConns)
+
+		s, err := New(cfg, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, maxConcurrency, s.concurrency)
+	})
+
+	t.Run("config values are used when set", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("tracker.repositoriesNames", []string{"repo1"})
+		cfg.Set("tracker.repositoriesKinds", []string{"helm", "falco"})
+		cfg.Set("tracker.concurrency", 5)
+		cfg.Set("tracker.daemon.pollFrequency", "30s")
+		cfg.Set("tracker.daemon.defaultInterval", "1h")
+		cfg.Set("tracker.daemon.maxBackoffFactor", 4)
+
+		s, err := New(cfg, nil, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"repo1"}, s.names)
+		assert.Equal(t, []int{int(hub.Helm), int(hub.Falco)}, s.kinds)
+		assert.Equal(t, 5, s.concurrency)
+		assert.Equal(t, 30*time.Second, s.pollFrequency)
+		assert.Equal(t, 1*time.Hour, s.defaultInterval)
+		assert.Equal(t, 4, s.maxBackoffFactor)
+	})
+}
+
+func TestRun(t *testing.T) {
+	t.Run("error getting due repositories", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getDueRepositoriesDBQ, []string(nil), []int(nil)).
+			Run(func(args mock.Arguments) { cancel() }).
+			Return(nil, tests.ErrFakeDB)
+		s, _ := New(viper.New(), db, nil, nil)
+
+		s.Run(ctx)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("due repositories are processed", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		r := &hub.Repository{RepositoryID: repositoryID, Digest: "digest"}
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getDueRepositoriesDBQ, []string(nil), []int(nil)).
+			Return([]byte(`["`+repositoryID+`"]`), nil).Once()
+		db.On("QueryRow", ctx, getDueRepositoriesDBQ, []string(nil), []int(nil)).
+			Return([]byte(`[]`), nil)
+		conn := &connMock{}
+		conn.On("QueryRow", ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(true, nil)
+		conn.On("QueryRow", ctx, isRepositoryDueDBQ, repositoryID).
+			Return([]interface{}{trackingStartedAt, true}, nil)
+		conn.On("Exec", mock.Anything, updateTrackingScheduleDBQ, repositoryID, "digest", true, 1800, 8, trackingStartedAt).
+			Run(func(args mock.Arguments) { cancel() }).
+			Return(nil)
+		conn.On("Exec", mock.Anything, unlockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(nil)
+		conn.On("Release").Return()
+		rm := &repo.ManagerMock{}
+		rm.On("GetByID", ctx, repositoryID, true).Return(r, nil)
+		var tracked []string
+		var mu sync.Mutex
+		track := func(ctx context.Context, r *hub.Repository) error {
+			mu.Lock()
+			tracked = append(tracked, r.RepositoryID)
+			mu.Unlock()
+			return nil
+		}
+		s, _ := New(viper.New(), db, rm, track)
+		s.acquireConn = func(ctx context.Context) (dbConn, error) { return conn, nil }
+		s.pollFrequency = 10 * time.Millisecond
+
+		s.Run(ctx)
+		assert.Equal(t, []string{repositoryID}, tracked)
+		db.AssertExpectations(t)
+		conn.AssertExpectations(t)
+		rm.AssertExpectations(t)
+	})
+}
+
+func TestProcessRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("error acquiring database connection", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.s.acquireConn = func(ctx context.Context) (dbConn, error) { return nil, tests.ErrFakeDB }
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error locking repository", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.conn.On("QueryRow", ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(nil, tests.ErrFakeDB)
+		sw.conn.On("Release").Return()
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository locked by another replica", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.conn.On("QueryRow", ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(false, nil)
+		sw.conn.On("Release").Return()
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error checking if repository is due for tracking", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.conn.On("QueryRow", ctx, isRepositoryDueDBQ, repositoryID).Return(nil, tests.ErrFakeDB)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository not due for tracking anymore", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.conn.On("QueryRow", ctx, isRepositoryDueDBQ, repositoryID).
+			Return([]interface{}{trackingStartedAt, false}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error getting repository", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(nil, tests.ErrFakeDB)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository disabled", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(&hub.Repository{
+			RepositoryID: repositoryID,
+			Disabled:     true,
+		}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("tracking failed, next tracking scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.trackErr = tests.ErrFake
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		sw.conn.On("Exec", mock.Anything, updateTrackingScheduleDBQ, repositoryID, "digest", false, 1800, 8, trackingStartedAt).
+			Return(nil)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(&hub.Repository{
+			RepositoryID: repositoryID,
+			Digest:       "digest",
+		}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{repositoryID}, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("error scheduling next tracking", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		sw.conn.On("Exec", mock.Anything, updateTrackingScheduleDBQ, repositoryID, "digest", true, 1800, 8, trackingStartedAt).
+			Return(tests.ErrFakeDB)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(&hub.Repository{
+			RepositoryID: repositoryID,
+			Digest:       "digest",
+		}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		assert.Equal(t, []string{repositoryID}, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository tracked and next tracking scheduled", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		sw.conn.On("Exec", mock.Anything, updateTrackingScheduleDBQ, repositoryID, "digest", true, 1800, 8, trackingStartedAt).
+			Return(nil)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(&hub.Repository{
+			RepositoryID: repositoryID,
+			Digest:       "digest",
+		}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{repositoryID}, sw.tracked)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("repository scheduled and unlocked when the context is done", func(t *testing.T) {
+		t.Parallel()
+		ctx, cancel := context.WithCancel(context.Background())
+		sw := newSchedulerWrapper()
+		sw.trackErr = context.Canceled
+		sw.track = func() { cancel() }
+		sw.expectLock(ctx)
+		sw.expectDue(ctx)
+		var updateCtxErr error
+		sw.conn.On("Exec", mock.Anything, updateTrackingScheduleDBQ, repositoryID, "digest", false, 1800, 8, trackingStartedAt).
+			Run(func(args mock.Arguments) {
+				updateCtxErr = args.Get(0).(context.Context).Err()
+			}).
+			Return(nil)
+		sw.rm.On("GetByID", ctx, repositoryID, true).Return(&hub.Repository{
+			RepositoryID: repositoryID,
+			Digest:       "digest",
+		}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{repositoryID}, sw.tracked)
+		assert.NoError(t, updateCtxErr)
+		assert.NoError(t, sw.unlockCtxErr)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("connection closed when the repository cannot be unlocked", func(t *testing.T) {
+		t.Parallel()
+		sw := newSchedulerWrapper()
+		sw.conn.On("QueryRow", ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(true, nil)
+		sw.conn.On("Exec", mock.Anything, unlockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+			Return(tests.ErrFakeDB)
+		sw.conn.On("Close", mock.Anything).Return(nil)
+		sw.conn.On("QueryRow", ctx, isRepositoryDueDBQ, repositoryID).
+			Return([]interface{}{trackingStartedAt, false}, nil)
+
+		err := sw.s.processRepository(ctx, repositoryID)
+		assert.NoError(t, err)
+		assert.Empty(t, sw.tracked)
+		sw.assertExpectations(t)
+		sw.conn.AssertNotCalled(t, "Release")
+	})
+}
+
+func TestInProgress(t *testing.T) {
+	t.Parallel()
+	s, _ := New(viper.New(), nil, nil, nil)
+
+	assert.True(t, s.markInProgress(repositoryID))
+	assert.False(t, s.markInProgress(repositoryID))
+	s.unmarkInProgress(repositoryID)
+	assert.True(t, s.markInProgress(repositoryID))
+}
+
+type schedulerWrapper struct {
+	db       *tests.DBMock
+	conn     *connMock
+	rm       *repo.ManagerMock
+	s        *Scheduler
+	track    func()
+	trackErr error
+	tracked  []string
+
+	unlockCtxErr error
+}
+
+func newSchedulerWrapper() *schedulerWrapper {
+	sw := &schedulerWrapper{
+		db:   &tests.DBMock{},
+		conn: &connMock{},
+		rm:   &repo.ManagerMock{},
+	}
+	track := func(ctx context.Context, r *hub.Repository) error {
+		sw.tracked = append(sw.tracked, r.RepositoryID)
+		if sw.track != nil {
+			sw.track()
+		}
+		return sw.trackErr
+	}
+	sw.s, _ = New(viper.New(), sw.db, sw.rm, track)
+	sw.s.acquireConn = func(ctx context.Context) (dbConn, error) { return sw.conn, nil }
+	return sw
+}
+
+// expectLock sets up the expectations of the repository being locked and
+// unlocked using the connection acquired.
+func (sw *schedulerWrapper) expectLock(ctx context.Context) {
+	sw.conn.On("QueryRow", ctx, tryLockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+		Return(true, nil)
+	sw.conn.On("Exec", mock.Anything, unlockRepositoryDBQ, util.DBLockKeyTrackRepository, repositoryID).
+		Run(func(args mock.Arguments) {
+			sw.unlockCtxErr = args.Get(0).(context.Context).Err()
+		}).
+		Return(nil)
+	sw.conn.On("Release").Return()
+}
+
+// expectDue sets up the expectations of the repository being due for
+// tracking.
+func (sw *schedulerWrapper) expectDue(ctx context.Context) {
+	sw.conn.On("QueryRow", ctx, isRepositoryDueDBQ, repositoryID).
+		Return([]interface{}{trackingStartedAt, true}, nil)
+}
+
+func (sw *schedulerWrapper) assertExpectations(t *testing.T) {
+	t.Helper()
+
+	sw.db.AssertExpectations(t)
+	sw.conn.AssertExpectations(t)
+	sw.rm.AssertExpectations(t)
+}
+
+// connMock is a mock implementation of the dbConn interface.
+type connMock struct {
+	tests.DBMock
+}
+
+// Release implements the dbConn interface.
+func (m *connMock) Release() {
+	m.Called()
+}
+
+// Close implements the dbConn interface.
+func (m *connMock) Close(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
@@ -0,0 +1,144 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/util"
+)
+
+// ChangelogFile represents the name of the changelog file publishers can
+// provide in the package directory (or in the chart archive). The changelog is
+// expected to follow the Keep a Changelog format (https://keepachangelog.com).
+const ChangelogFile = "CHANGELOG.md"
+
+var (
+	// changelogVersionRE is a regexp used to extract the version from the
+	// changelog release headings (i.e. "## [1.0.0] - 2020-01-01").
+	changelogVersionRE = regexp.MustCompile(`^##\s+\[?v?([^\]\s]+)\]?`)
+
+	// changelogItemRE is a regexp used to match the changelog list items.
+	changelogItemRE = regexp.MustCompile(`^[-*+]\s+(.*)$`)
+
+	// mdLinkRE is a regexp used to match markdown inline links.
+	mdLinkRE = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+
+	// changelogKinds represents the changes sections defined in the Keep a
+	// Changelog format, which are mapped to the corresponding change kinds.
+	changelogKinds = map[string]string{
+		"added":      "added",
+		"changed":    "changed",
+		"deprecated": "deprecated",
+		"removed":    "removed",
+		"fixed":      "fixed",
+		"security":   "security",
+	}
+)
+
+// ParseChangelog parses the Keep a Changelog formatted changelog provided and
+// returns the changes entries of the version requested. The changes sections
+// (Added, Changed, Deprecated, Removed, Fixed and Security) are mapped to the
+// corresponding change kinds. Entries in other sections (e.g. Features or
+// Dependencies) are kept, but no kind is assigned to them. Inline links in the
+// entries are extracted as change links. Changes entries are also validated
+// and normalized. When the version requested cannot be found in the
+// changelog, no changes are returned.
+func ParseChangelog(changelog []byte, version string) ([]*hub.Change, error) {
+	sv, err := semver.NewVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version (semver expected): %w", err)
+	}
+
+	var changes []*hub.Change
+	var inVersion bool
+	var kind string
+	var change *hub.Change
+	scanner := bufio.NewScanner(bytes.NewReader(changelog))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t")
+		switch {
+		case strings.HasPrefix(line, "# "), strings.HasPrefix(line, "## "):
+			if inVersion {
+				return finishChangelogChanges(changes)
+			}
+			if m := changelogVersionRE.FindStringSubmatch(line); m != nil {
+				if hv, err := semver.NewVersion(m[1]); err == nil && hv.Equal(sv) {
+					inVersion = true
+				}
+			}
+		case !inVersion:
+			continue
+		case strings.HasPrefix(line, "### "):
+			kind = changelogKinds[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(line, "### ")))]
+			change = nil
+		case changelogItemRE.MatchString(line):
+			change = &hub.Change{
+				Kind:        kind,
+				Description: changelogItemRE.FindStringSubmatch(line)[1],
+			}
+			changes = append(changes, change)
+		case change != nil && line != "" && (line[0] == ' ' || line[0] == '\t'):
+			// Continuation line of the previous list item
+			change.Description += " " + strings.TrimSpace(line)
+		default:
+			change = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading changelog: %w", err)
+	}
+	return finishChangelogChanges(changes)
+}
+
+// finishChangelogChanges extracts the links from the changes descriptions
+// provided and validates and normalizes the resulting changes entries.
+func finishChangelogChanges(changes []*hub.Change) ([]*hub.Change, error) {
+	for _, change := range changes {
+		for _, m := range mdLinkRE.FindAllStringSubmatch(change.Description, -1) {
+			change.Links = append(change.Links, &hub.Link{
+				Name: m[1],
+				URL:  m[2],
+			})
+		}
+		change.Description = mdLinkRE.ReplaceAllString(change.Description, "$1")
+		if err := pkg.ValidateChange(change); err != nil {
+			return nil, fmt.Errorf("invalid changelog: %w", err)
+		}
+		pkg.NormalizeChange(change)
+	}
+	return changes, nil
+}
+
+// EnrichPackageFromChangelog sets the package changes using the entries
+// available in the changelog provided for the package version. Changes
+// provided explicitly by the publisher take precedence, so the changelog is
+// only used when the package does not have any changes set yet.
+func EnrichPackageFromChangelog(p *hub.Package, changelog []byte) error {
+	if len(p.Changes) > 0 || len(changelog) == 0 {
+		return nil
+	}
+	changes, err := ParseChangelog(changelog, p.Version)
+	if err != nil {
+		return fmt.Errorf("error parsing changelog file: %w", err)
+	}
+	p.Changes = changes
+	return nil
+}
+
+// EnrichPackageFromChangelogFile is a helper that reads the changelog file
+// located in the package path provided, if available, and uses it to enrich
+// the package.
+func EnrichPackageFromChangelogFile(p *hub.Package, pkgPath string) error {
+	changelog, err := util.ReadRegularFile(filepath.Join(pkgPath, ChangelogFile))
+	if err != nil {
+		return nil
+	}
+	return EnrichPackageFromChangelog(p, changelog)
+}
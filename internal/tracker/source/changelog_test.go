@@ -0,0 +1,204 @@
+package source
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testChangelog = []byte(`# Changelog
+
+All notable changes to this project will be documented in this file.
+
+## [Unreleased]
+
+### Added
+
+- Unreleased feature
+
+## [1.1.0] - 2024-02-01
+
+### Added
+
+- Feature 2 ([#2](https://github.com/org/repo/pull/2))
+- Feature 3 spanning
+  several lines
+
+### Fixed
+
+* Issue 1
+
+### Security
+
+- Bump dependency to fix CVE-2024-1234
+
+## [1.0.0] - 2024-01-01
+
+### Added
+
+- Initial release
+
+[Unreleased]: https://github.com/org/repo/compare/v1.1.0...HEAD
+[1.1.0]: https://github.com/org/repo/compare/v1.0.0...v1.1.0
+`)
+
+func TestParseChangelog(t *testing.T) {
+	testCases := []struct {
+		changelog       []byte
+		version         string
+		expectedChanges []*hub.Change
+		expectedErrMsg  string
+	}{
+		{
+			testChangelog,
+			"invalid",
+			nil,
+			"invalid version",
+		},
+		{
+			testChangelog,
+			"2.0.0",
+			nil,
+			"",
+		},
+		{
+			testChangelog,
+			"1.1.0",
+			[]*hub.Change{
+				{
+					Kind:        "added",
+					Description: "Feature 2 (#2)",
+					Links: []*hub.Link{
+						{
+							Name: "#2",
+							URL:  "https://github.com/org/repo/pull/2",
+						},
+					},
+				},
+				{
+					Kind:        "added",
+					Description: "Feature 3 spanning several lines",
+				},
+				{
+					Kind:        "fixed",
+					Description: "Issue 1",
+				},
+				{
+					Kind:        "security",
+					Description: "Bump dependency to fix CVE-2024-1234",
+				},
+			},
+			"",
+		},
+		{
+			testChangelog,
+			"1.0.0",
+			[]*hub.Change{
+				{
+					Kind:        "added",
+					Description: "Initial release",
+				},
+			},
+			"",
+		},
+		{
+			[]byte(`
+## v1.0.0
+
+- Change without section
+`),
+			"1.0.0",
+			[]*hub.Change{
+				{
+					Description: "Change without section",
+				},
+			},
+			"",
+		},
+		{
+			[]byte(`
+## [1.0.0](https://github.com/org/repo/compare/v0.9.0...v1.0.0) (2024-01-01)
+
+### Features
+
+- Feature 1
+
+### Dependencies
+
+- Bump dependency
+
+### Fixed
+
+- Issue 1
+`),
+			"1.0.0",
+			[]*hub.Change{
+				{
+					Description: "Feature 1",
+				},
+				{
+					Description: "Bump dependency",
+				},
+				{
+					Kind:        "fixed",
+					Description: "Issue 1",
+				},
+			},
+			"",
+		},
+	}
+	for i, tc := range testCases {
+		tc := tc
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			changes, err := ParseChangelog(tc.changelog, tc.version)
+			if tc.expectedErrMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrMsg)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedChanges, changes)
+		})
+	}
+}
+
+func TestEnrichPackageFromChangelog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("changes provided explicitly take precedence", func(t *testing.T) {
+		t.Parallel()
+		changes := []*hub.Change{{Description: "Change from metadata"}}
+		p := &hub.Package{Version: "1.0.0", Changes: changes}
+		err := EnrichPackageFromChangelog(p, testChangelog)
+		require.NoError(t, err)
+		assert.Equal(t, changes, p.Changes)
+	})
+
+	t.Run("changes set from changelog", func(t *testing.T) {
+		t.Parallel()
+		p := &hub.Package{Version: "1.0.0"}
+		err := EnrichPackageFromChangelog(p, testChangelog)
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.Change{{Kind: "added", Description: "Initial release"}}, p.Changes)
+	})
+
+	t.Run("changes in unknown sections set without kind", func(t *testing.T) {
+		t.Parallel()
+		p := &hub.Package{Version: "1.0.0"}
+		err := EnrichPackageFromChangelog(p, []byte("## [1.0.0]\n### Other\n- Change\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []*hub.Change{{Description: "Change"}}, p.Changes)
+	})
+
+	t.Run("invalid version", func(t *testing.T) {
+		t.Parallel()
+		p := &hub.Package{Version: "invalid"}
+		err := EnrichPackageFromChangelog(p, testChangelog)
+		assert.ErrorContains(t, err, "error parsing changelog file")
+		assert.Nil(t, p.Changes)
+	})
+}
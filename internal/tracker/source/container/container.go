@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/url"
 	"path"
+	"regexp"
 	"runtime/debug"
 	"slices"
 	"strconv"
@@ -22,6 +23,7 @@ import (
 	"github.com/artifacthub/hub/internal/img"
 	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/repo"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
@@ -86,12 +88,20 @@ var (
 // TrackerSource is a hub.TrackerSource implementation for containers images
 // repositories.
 type TrackerSource struct {
-	i *hub.TrackerSourceInput
+	i  *hub.TrackerSourceInput
+	tg hub.OCITagsGetter
 }
 
 // NewTrackerSource creates a new TrackerSource instance.
-func NewTrackerSource(i *hub.TrackerSourceInput) *TrackerSource {
-	return &TrackerSource{i: i}
+func NewTrackerSource(i *hub.TrackerSourceInput, opts ...func(s *TrackerSource)) *TrackerSource {
+	s := &TrackerSource{i: i}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.tg == nil {
+		s.tg = oci.NewTagsGetter(i.Svc.Cfg)
+	}
+	return s
 }
 
 // GetPackagesAvailable implements the TrackerSource interface.
@@ -108,8 +118,16 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 	if err := json.Unmarshal(s.i.Repository.Data, &data); err != nil {
 		return nil, fmt.Errorf("invalid container image data: %w", err)
 	}
+	tags := data.Tags
+	if data.Discovery != nil {
+		discoveredTags, err := s.discoverTags(data)
+		if err != nil {
+			return nil, fmt.Errorf("error discovering tags: %w", err)
+		}
+		tags = append(tags, discoveredTags...)
+	}
 	var tagsToProcess []string
-	for _, tag := range data.Tags {
+	for _, tag := range tags {
 		p := &hub.Package{
 			Name:    path.Base(s.i.Repository.URL),
 			Version: tag.Name,
@@ -163,6 +181,73 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 	return packagesAvailable, nil
 }
 
+// discoverTags returns the tags available in the registry selected by the
+// tags discovery configuration provided. Tags explicitly listed are not
+// included in the result.
+func (s *TrackerSource) discoverTags(data *hub.ContainerImageData) ([]hub.ContainerImageTag, error) {
+	available, err := s.tg.Tags(s.i.Svc.Ctx, s.i.Repository, data.Discovery.Semver, false)
+	if err != nil {
+		return nil, err
+	}
+	return selectTags(available, data)
+}
+
+// selectTags selects the tags that match the tags discovery configuration
+// provided from the list of tags available. Tags must match some of the
+// include patterns (when provided) and none of the exclude ones. Mutability is
+// inferred from the first include pattern matching the tag.
+func selectTags(available []string, data *hub.ContainerImageData) ([]hub.ContainerImageTag, error) {
+	d := data.Discovery
+
+	// Compile patterns
+	include := make([]*regexp.Regexp, 0, len(d.Include))
+	for _, p := range d.Include {
+		re, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid include pattern %s: %w", p.Pattern, err)
+		}
+		include = append(include, re)
+	}
+	exclude := make([]*regexp.Regexp, 0, len(d.Exclude))
+	for _, p := range d.Exclude {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %s: %w", p, err)
+		}
+		exclude = append(exclude, re)
+	}
+
+	// Select tags
+	limit := repo.MaxDiscoveredContainerImageTags
+	if d.Latest > 0 && d.Latest < limit {
+		limit = d.Latest
+	}
+	var selected []hub.ContainerImageTag
+	for _, tag := range available {
+		if len(selected) == limit {
+			break
+		}
+		if slices.ContainsFunc(data.Tags, func(t hub.ContainerImageTag) bool { return t.Name == tag }) {
+			continue
+		}
+		if slices.ContainsFunc(exclude, func(re *regexp.Regexp) bool { return re.MatchString(tag) }) {
+			continue
+		}
+		if len(include) == 0 {
+			selected = append(selected, hub.ContainerImageTag{Name: tag})
+			continue
+		}
+		for i, re := range include {
+			if re.MatchString(tag) {
+				selected = append(selected, hub.ContainerImageTag{Name: tag, Mutable: d.Include[i].Mutable})
+				break
+			}
+		}
+	}
+
+	return selected, nil
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (s *TrackerSource) warn(err error) {
@@ -0,0 +1,190 @@
+package container
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/tracker/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackerSource(t *testing.T) {
+	t.Run("no tags set up", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.Container,
+				URL:  "oci://registry.io/org/image",
+			},
+			Svc: sw.Svc,
+		}
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+
+	t.Run("error discovering tags", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.Container,
+				URL:  "oci://registry.io/org/image",
+				Data: json.RawMessage(`{"tags": [], "discovery": {"semver": true}}`),
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return(nil, tests.ErrFake)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Nil(t, packages)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("registered immutable tags discovered are not processed again", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.Container,
+				URL:  "oci://registry.io/org/image",
+				Data: json.RawMessage(`{"tags": [], "discovery": {"semver": true, "latest": 2}}`),
+			},
+			PackagesRegistered: map[string]string{
+				"image@2.0.0": "digest2",
+				"image@1.1.0": "digest1",
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"2.0.0", "1.1.0", "1.0.0"}, nil)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			"image@2.0.0": {
+				Name:    "image",
+				Version: "2.0.0",
+				Digest:  hub.HasNotChanged,
+			},
+			"image@1.1.0": {
+				Name:    "image",
+				Version: "1.1.0",
+				Digest:  hub.HasNotChanged,
+			},
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+}
+
+func TestSelectTags(t *testing.T) {
+	available := []string{"2.1.0", "2.0.0-rc.1", "2.0.0", "1.0.0", "latest", "nightly"}
+
+	testCases := []struct {
+		data     *hub.ContainerImageData
+		expected []hub.ContainerImageTag
+	}{
+		{
+			&hub.ContainerImageData{
+				Discovery: &hub.ContainerImageTagsDiscovery{},
+			},
+			[]hub.ContainerImageTag{
+				{Name: "2.1.0"},
+				{Name: "2.0.0-rc.1"},
+				{Name: "2.0.0"},
+				{Name: "1.0.0"},
+				{Name: "latest"},
+				{Name: "nightly"},
+			},
+		},
+		{
+			&hub.ContainerImageData{
+				Tags: []hub.ContainerImageTag{
+					{Name: "latest", Mutable: true},
+				},
+				Discovery: &hub.ContainerImageTagsDiscovery{
+					Exclude: []string{`-rc\.`, `^nightly$`},
+				},
+			},
+			[]hub.ContainerImageTag{
+				{Name: "2.1.0"},
+				{Name: "2.0.0"},
+				{Name: "1.0.0"},
+			},
+		},
+		{
+			&hub.ContainerImageData{
+				Discovery: &hub.ContainerImageTagsDiscovery{
+					Include: []hub.ContainerImageTagPattern{
+						{Pattern: `^2\.`},
+						{Pattern: `^(latest|nightly)$`, Mutable: true},
+					},
+					Exclude: []string{`-rc\.`},
+				},
+			},
+			[]hub.ContainerImageTag{
+				{Name: "2.1.0"},
+				{Name: "2.0.0"},
+				{Name: "latest", Mutable: true},
+				{Name: "nightly", Mutable: true},
+			},
+		},
+		{
+			&hub.ContainerImageData{
+				Discovery: &hub.ContainerImageTagsDiscovery{
+					Semver: true,
+					Latest: 2,
+				},
+			},
+			[]hub.ContainerImageTag{
+				{Name: "2.1.0"},
+				{Name: "2.0.0-rc.1"},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			tags, err := selectTags(available, tc.data)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, tags)
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		data := &hub.ContainerImageData{
+			Discovery: &hub.ContainerImageTagsDiscovery{
+				Exclude: []string{"("},
+			},
+		}
+		_, err := selectTags(available, data)
+		assert.Error(t, err)
+	})
+}
+
+func withOCITagsGetter(tg hub.OCITagsGetter) func(s *TrackerSource) {
+	return func(s *TrackerSource) {
+		s.tg = tg
+	}
+}
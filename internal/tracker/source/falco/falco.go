@@ -3,6 +3,7 @@ package falco
 import (
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -109,21 +110,15 @@ func (s *TrackerSource) preparePackage(r *hub.Repository, md *RulesMetadata, pkg
 	}
 
 	// Prepare source link url whenever possible
-	var repoBaseURL, host, pkgsPath string
-	matches := repo.GitRepoURLRE.FindStringSubmatch(r.URL)
-	if len(matches) >= 3 {
-		repoBaseURL = matches[1]
-		host = matches[2]
-	}
-	if len(matches) == 4 {
-		pkgsPath = strings.TrimSuffix(matches[3], "/")
-	}
 	var sourceURL string
-	switch host {
-	case "github.com":
-		sourceURL = fmt.Sprintf("%s/blob/%s/%s%s", repoBaseURL, repo.GetBranch(r), pkgsPath, pkgPath)
-	case "gitlab.com":
-		sourceURL = fmt.Sprintf("%s/-/blob/%s/%s%s", repoBaseURL, repo.GetBranch(r), pkgsPath, pkgPath)
+	if ws := repo.GetGitWebSource(r); ws != nil {
+		filePath := strings.TrimPrefix(path.Join(ws.Subdir, pkgPath), "/")
+		switch ws.Host {
+		case "github.com":
+			sourceURL = fmt.Sprintf("%s/blob/%s/%s", ws.BaseURL, ws.Ref, filePath)
+		case "gitlab.com":
+			sourceURL = fmt.Sprintf("%s/-/blob/%s/%s", ws.BaseURL, ws.Ref, filePath)
+		}
 	}
 	if sourceURL != "" {
 		p.Links = append(p.Links, &hub.Link{
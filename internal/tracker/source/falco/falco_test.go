@@ -146,4 +146,42 @@ func TestTrackerSource(t *testing.T) {
 		assert.NoError(t, err)
 		sw.AssertExpectations(t)
 	})
+	t.Run("one package returned, repository using git url", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				URL:       "https://gitlab.com/group/subgroup/repo1",
+				GitURL:    "git@gitlab.com:group/subgroup/repo1.git",
+				GitRef:    "refs/tags/v1.0.0",
+				GitSubdir: "path/to/packages",
+			},
+			BasePath: "testdata/path4",
+			Svc:      sw.Svc,
+		}
+		sw.Is.On("DownloadAndSaveImage", sw.Svc.Ctx, logoImageURL).Return("logoImageID", nil)
+
+		// Run test and check expectations
+		p := source.ClonePackage(basePkg)
+		p.Repository = i.Repository
+		p.LogoURL = logoImageURL
+		p.LogoImageID = "logoImageID"
+		p.Links = []*hub.Link{
+			{
+				Name: "source",
+				URL:  "https://gitlab.com/group/subgroup/repo1/-/blob/v1.0.0/path/to/packages/test.yaml",
+			},
+		}
+		p.Data = map[string]interface{}{
+			rulesKey: []*Rule{{Raw: "Falco rules in YAML"}},
+		}
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
 }
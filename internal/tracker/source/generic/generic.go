@@ -12,6 +12,7 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tracker/source"
 	"github.com/artifacthub/hub/internal/util"
 	ignore "github.com/sabhiram/go-gitignore"
 	"gopkg.in/yaml.v3"
@@ -90,20 +91,44 @@ const (
 // TrackerSource is a hub.TrackerSource implementation used by several kinds
 // of repositories.
 type TrackerSource struct {
-	i *hub.TrackerSourceInput
+	i  *hub.TrackerSourceInput
+	tg hub.OCITagsGetter
 }
 
 // NewTrackerSource creates a new TrackerSource instance.
 func NewTrackerSource(i *hub.TrackerSourceInput, opts ...func(s *TrackerSource)) *TrackerSource {
-	return &TrackerSource{i: i}
+	s := &TrackerSource{i: i}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.tg == nil {
+		s.tg = oci.NewTagsGetter(i.Svc.Cfg)
+	}
+	return s
 }
 
 // GetPackagesAvailable implements the TrackerSource interface.
 func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error) {
+	if strings.HasPrefix(s.i.Repository.URL, hub.RepositoryOCIPrefix) {
+		return s.getPackagesAvailableFromOCI()
+	}
+	if s.i.ChangedPaths != nil && s.i.PackagesPaths != nil {
+		if packagesAvailable, ok, err := s.getPackagesAvailableIncrementally(); ok || err != nil {
+			return packagesAvailable, err
+		}
+	}
 	packagesAvailable := make(map[string]*hub.Package)
+	if err := s.walkPackagesDirs(s.i.BasePath, packagesAvailable); err != nil {
+		return nil, err
+	}
 
-	// Walk the path provided looking for available packages
-	err := filepath.Walk(s.i.BasePath, func(pkgPath string, info os.FileInfo, err error) error {
+	return packagesAvailable, nil
+}
+
+// walkPackagesDirs walks the base path provided looking for available
+// packages, adding them to the packages available.
+func (s *TrackerSource) walkPackagesDirs(basePath string, packagesAvailable map[string]*hub.Package) error {
+	return filepath.Walk(basePath, func(pkgPath string, info os.FileInfo, err error) error {
 		// Return ASAP if context is cancelled
 		select {
 		case <-s.i.Svc.Ctx.Done():
@@ -117,64 +142,129 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 			return nil
 		}
 
-		// Get package version metadata
-		md, err := pkg.GetPackageMetadata(
-			s.i.Repository.Kind,
-			filepath.Join(pkgPath, hub.PackageMetadataFile),
-		)
-		if err != nil {
-			if !errors.Is(err, os.ErrNotExist) {
-				s.warn(fmt.Errorf("error getting package metadata (path: %s): %w", pkgPath, err))
+		s.processPackageDir(basePath, pkgPath, packagesAvailable)
+		return nil
+	})
+}
+
+// getPackagesAvailableIncrementally gets the packages available processing
+// only the directories affected by the changed paths provided in the input.
+// The packages registered located in directories that have not changed are
+// returned as they are, marked as not changed. When some of the packages
+// registered is missing its relative path, the packages available cannot be
+// obtained this way and false is returned.
+func (s *TrackerSource) getPackagesAvailableIncrementally() (map[string]*hub.Package, bool, error) {
+	packagesAvailable := make(map[string]*hub.Package)
+
+	// Collect all directories affected by the changes
+	affectedDirs := make(map[string]struct{})
+	for _, changedPath := range s.i.ChangedPaths {
+		for dir := path.Dir(changedPath); ; dir = path.Dir(dir) {
+			affectedDirs[dir] = struct{}{}
+			if dir == "." {
+				break
 			}
-			return nil
 		}
+	}
 
-		// Prepare and store package version
-		p, err := PreparePackage(s.i.Repository, md, pkgPath)
-		if err != nil {
-			s.warn(err)
-			return nil
+	// Keep packages registered located in directories not affected
+	for key := range s.i.PackagesRegistered {
+		relativePath, ok := s.i.PackagesPaths[key]
+		if !ok {
+			return nil, false, nil
 		}
-		p.RelativePath = strings.TrimPrefix(pkgPath, s.i.BasePath)
-		packagesAvailable[pkg.BuildKey(p)] = p
+		dir := strings.TrimPrefix(filepath.ToSlash(relativePath), "/")
+		if dir == "" {
+			dir = "."
+		}
+		if _, ok := affectedDirs[dir]; ok {
+			continue
+		}
+		name, version := pkg.ParseKey(key)
+		packagesAvailable[key] = &hub.Package{
+			Name:       name,
+			Version:    version,
+			Digest:     hub.HasNotChanged,
+			Repository: s.i.Repository,
+		}
+	}
 
-		// Prepare and store logo image when available
-		logoImageID, err := s.prepareLogoImage(md, pkgPath)
-		if err != nil {
-			s.warn(fmt.Errorf("error preparing package %s version %s logo image: %w", md.Name, md.Version, err))
-		} else {
-			p.LogoImageID = logoImageID
+	// Process affected directories
+	for dir := range affectedDirs {
+		// Return ASAP if context is cancelled
+		select {
+		case <-s.i.Svc.Ctx.Done():
+			return nil, false, s.i.Svc.Ctx.Err()
+		default:
 		}
 
-		// Check if the package is signed (for applicable kinds)
-		switch p.Repository.Kind {
-		case hub.Bootc, hub.InspektorGadget, hub.Kubewarden:
-			// We'll consider the package signed if all images are signed
-			signedImages := 0
-			for _, entry := range p.ContainersImages {
-				hasCosignSignature, err := s.i.Svc.Sc.HasCosignSignature(s.i.Svc.Ctx, entry.Image, "", "")
-				if err != nil {
-					s.warn(fmt.Errorf(
-						"error checking package %s version %s image %s signature: %w",
-						md.Name, md.Version, entry.Image, err,
-					))
-				} else if hasCosignSignature {
-					signedImages++
-				}
-			}
-			if len(p.ContainersImages) > 0 && signedImages == len(p.ContainersImages) {
-				p.Signed = true
-				p.Signatures = []string{oci.Cosign}
-			}
+		pkgPath := filepath.Join(s.i.BasePath, filepath.FromSlash(dir))
+		if info, err := os.Stat(pkgPath); err != nil || !info.IsDir() {
+			continue
 		}
+		s.processPackageDir(s.i.BasePath, pkgPath, packagesAvailable)
+	}
 
-		return nil
-	})
+	return packagesAvailable, true, nil
+}
+
+// processPackageDir prepares the package version located in the directory
+// provided, if any, and adds it to the packages available. The package's
+// relative path is computed from the base path provided.
+func (s *TrackerSource) processPackageDir(basePath, pkgPath string, packagesAvailable map[string]*hub.Package) {
+	// Get package version metadata
+	md, err := pkg.GetPackageMetadata(
+		s.i.Repository.Kind,
+		filepath.Join(pkgPath, hub.PackageMetadataFile),
+	)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, os.ErrNotExist) {
+			s.warn(fmt.Errorf("error getting package metadata (path: %s): %w", pkgPath, err))
+		}
+		return
 	}
 
-	return packagesAvailable, nil
+	// Prepare and store package version
+	p, err := PreparePackage(s.i.Repository, md, pkgPath)
+	if err != nil {
+		s.warn(err)
+		return
+	}
+	p.RelativePath = strings.TrimPrefix(pkgPath, basePath)
+	if err := source.EnrichPackageFromChangelogFile(p, pkgPath); err != nil {
+		s.warn(fmt.Errorf("error preparing package %s version %s changes: %w", md.Name, md.Version, err))
+	}
+	packagesAvailable[pkg.BuildKey(p)] = p
+
+	// Prepare and store logo image when available
+	logoImageID, err := s.prepareLogoImage(md, pkgPath)
+	if err != nil {
+		s.warn(fmt.Errorf("error preparing package %s version %s logo image: %w", md.Name, md.Version, err))
+	} else {
+		p.LogoImageID = logoImageID
+	}
+
+	// Check if the package is signed (for applicable kinds)
+	switch p.Repository.Kind {
+	case hub.Bootc, hub.InspektorGadget, hub.Kubewarden:
+		// We'll consider the package signed if all images are signed
+		signedImages := 0
+		for _, entry := range p.ContainersImages {
+			hasCosignSignature, err := s.i.Svc.Sc.HasCosignSignature(s.i.Svc.Ctx, entry.Image, "", "")
+			if err != nil {
+				s.warn(fmt.Errorf(
+					"error checking package %s version %s image %s signature: %w",
+					md.Name, md.Version, entry.Image, err,
+				))
+			} else if hasCosignSignature {
+				signedImages++
+			}
+		}
+		if len(p.ContainersImages) > 0 && signedImages == len(p.ContainersImages) {
+			p.Signed = true
+			p.Signatures = []string{oci.Cosign}
+		}
+	}
 }
 
 // prepareLogoImage processes and stores the logo image provided.
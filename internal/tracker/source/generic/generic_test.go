@@ -337,4 +337,86 @@ func TestTrackerSource(t *testing.T) {
 		assert.NoError(t, err)
 		sw.AssertExpectations(t)
 	})
+
+	t.Run("incremental: only packages in changed directories are processed", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+			},
+			PackagesRegistered: map[string]string{
+				"pkg2@1.0.0": "digest-pkg2",
+				"pkg3@1.0.0": "digest-pkg3",
+			},
+			BasePath: "testdata",
+			Svc:      sw.Svc,
+			ChangedPaths: []string{
+				"path6/policy1.rego",
+				"path10/artifacthub-pkg.yml",
+			},
+			PackagesPaths: map[string]string{
+				"pkg2@1.0.0": "/other/pkg2",
+				"pkg3@1.0.0": "/path10",
+			},
+		}
+		sw.Is.On("SaveImage", sw.Svc.Ctx, imageData).Return("logoImageID", nil)
+
+		// Run test and check expectations
+		p := source.ClonePackage(basePkg)
+		p.Repository = i.Repository
+		p.LogoImageID = "logoImageID"
+		p.Data[OPAPoliciesKey] = map[string]string{
+			"policy1.rego": "policy content\n",
+		}
+		p.RelativePath = "/path6"
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+			"pkg2@1.0.0": {
+				Name:       "pkg2",
+				Version:    "1.0.0",
+				Digest:     hub.HasNotChanged,
+				Repository: i.Repository,
+			},
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+
+	t.Run("incremental: full scan when some package path is not available", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+			},
+			PackagesRegistered: map[string]string{
+				"pkg1@1.0.0": "digest-pkg1",
+			},
+			BasePath:      "testdata/path6",
+			Svc:           sw.Svc,
+			ChangedPaths:  []string{},
+			PackagesPaths: map[string]string{},
+		}
+		sw.Is.On("SaveImage", sw.Svc.Ctx, imageData).Return("logoImageID", nil)
+
+		// Run test and check expectations
+		p := source.ClonePackage(basePkg)
+		p.Repository = i.Repository
+		p.LogoImageID = "logoImageID"
+		p.Data[OPAPoliciesKey] = map[string]string{
+			"policy1.rego": "policy content\n",
+		}
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
 }
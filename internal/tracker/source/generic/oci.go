@@ -0,0 +1,162 @@
+package generic
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+)
+
+const (
+	// PackageContentLayerMediaType represents the media type used for the
+	// layer that contains the package files (including the package metadata
+	// file) in an OCI artifact.
+	PackageContentLayerMediaType = "application/vnd.cncf.artifacthub.package.content.v1.tar+gzip"
+
+	// maxPackageContentSize represents the maximum size of the files that can
+	// be extracted from the package content layer.
+	maxPackageContentSize = 50 * 1024 * 1024
+)
+
+// getPackagesAvailableFromOCI gets the packages available in the OCI
+// repository. Each of the semver tags available in the repository is expected
+// to point to an artifact that contains a package content layer. This layer
+// is extracted and processed the same way a git repository would be, so it can
+// contain one or more package versions.
+func (s *TrackerSource) getPackagesAvailableFromOCI() (map[string]*hub.Package, error) {
+	packagesAvailable := make(map[string]*hub.Package)
+
+	// Get tags available in the repository
+	tags, err := s.tg.Tags(s.i.Svc.Ctx, s.i.Repository, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repository available tags: %w", err)
+	}
+
+	// Process the artifact each tag points to
+	for _, tag := range tags {
+		// Return ASAP if context is cancelled
+		select {
+		case <-s.i.Svc.Ctx.Done():
+			return nil, s.i.Svc.Ctx.Err()
+		default:
+		}
+
+		if err := s.processOCIArtifact(tag, packagesAvailable); err != nil {
+			s.warn(fmt.Errorf("error processing artifact (tag: %s): %w", tag, err))
+		}
+	}
+
+	return packagesAvailable, nil
+}
+
+// processOCIArtifact pulls and extracts the package content layer of the
+// artifact identified by the tag provided, adding the package versions found
+// in it to the packages available.
+func (s *TrackerSource) processOCIArtifact(tag string, packagesAvailable map[string]*hub.Package) error {
+	// Pull package content layer
+	r := s.i.Repository
+	ref := fmt.Sprintf("%s:%s", strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix), tag)
+	desc, data, err := s.i.Svc.Op.PullLayer(s.i.Svc.Ctx, ref, PackageContentLayerMediaType, r.AuthUser, r.AuthPass)
+	if err != nil {
+		return fmt.Errorf("error pulling package content layer: %w", err)
+	}
+
+	// Extract package content
+	tmpDir, err := os.MkdirTemp("", "artifact-hub")
+	if err != nil {
+		return fmt.Errorf("error creating temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractPackageContent(data, tmpDir); err != nil {
+		return fmt.Errorf("error extracting package content: %w", err)
+	}
+
+	// Process package content as it was a git repository
+	artifactPackages := make(map[string]*hub.Package)
+	if err := s.walkPackagesDirs(tmpDir, artifactPackages); err != nil {
+		return err
+	}
+	if len(artifactPackages) == 0 {
+		return errors.New("no packages found in artifact")
+	}
+	for key, p := range artifactPackages {
+		// The layer digest is used as the package digest when none has been
+		// provided in the metadata, so that the package is only registered
+		// again when the artifact content changes
+		if p.Digest == "" {
+			p.Digest = desc.Digest.String()
+		}
+		packagesAvailable[key] = p
+	}
+
+	return nil
+}
+
+// extractPackageContent extracts the package content layer provided (a gzip
+// compressed tarball) in the destination directory. Only directories and
+// regular files are extracted, and entries pointing to locations outside the
+// destination directory are not allowed.
+func extractPackageContent(data []byte, dst string) error {
+	gzr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+
+	var size int64
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(hdr.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("invalid entry in package content: %s", hdr.Name)
+		}
+		target := filepath.Join(dst, name)
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			size += hdr.Size
+			if size > maxPackageContentSize {
+				return errors.New("package content too big")
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := writeFile(target, tr); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeFile creates a file at the path provided with the content read from
+// the reader provided.
+func writeFile(path string, r io.Reader) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,258 @@
+package generic
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/artifacthub/hub/internal/tracker/source"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrackerSourceOCI(t *testing.T) {
+	repoURL := "oci://registry.io/org/policies"
+	pkgMetadata := `
+version: 1.0.0
+name: pkg1
+displayName: Package 1
+createdAt: 2019-06-28T15:23:00Z
+description: Description
+`
+	desc := ocispec.Descriptor{
+		MediaType: PackageContentLayerMediaType,
+		Digest:    digest.FromString("content"),
+	}
+
+	t.Run("error getting repository tags", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return(nil, tests.ErrFake)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Nil(t, packages)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("error pulling package content layer", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"1.0.0"}, nil)
+		sw.Op.On("PullLayer", sw.Svc.Ctx, "registry.io/org/policies:1.0.0", PackageContentLayerMediaType, "", "").
+			Return(ocispec.Descriptor{}, nil, tests.ErrFake)
+		expectedErr := "error processing artifact (tag: 1.0.0): error pulling package content layer: fake error for tests"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("package content contains invalid entries", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		data := buildPackageContent(t, map[string]string{
+			"../artifacthub-pkg.yml": pkgMetadata,
+		})
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"1.0.0"}, nil)
+		sw.Op.On("PullLayer", sw.Svc.Ctx, "registry.io/org/policies:1.0.0", PackageContentLayerMediaType, "", "").
+			Return(desc, data, nil)
+		expectedErr := "error processing artifact (tag: 1.0.0): error extracting package content: invalid entry in package content: ../artifacthub-pkg.yml"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("no packages found in artifact", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.OPA,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		data := buildPackageContent(t, map[string]string{
+			"policy1.rego": "policy content\n",
+		})
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"1.0.0"}, nil)
+		sw.Op.On("PullLayer", sw.Svc.Ctx, "registry.io/org/policies:1.0.0", PackageContentLayerMediaType, "", "").
+			Return(desc, data, nil)
+		expectedErr := "error processing artifact (tag: 1.0.0): no packages found in artifact"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+
+	t.Run("packages returned, no errors", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind:     hub.OPA,
+				URL:      repoURL,
+				AuthUser: "user",
+				AuthPass: "pass",
+			},
+			Svc: sw.Svc,
+		}
+		data1 := buildPackageContent(t, map[string]string{
+			"artifacthub-pkg.yml": pkgMetadata,
+			"policy1.rego":        "policy content\n",
+		})
+		data2 := buildPackageContent(t, map[string]string{
+			"pkg1/artifacthub-pkg.yml": strings.Replace(pkgMetadata, "1.0.0", "0.1.0", 1) + "digest: 0123456789\n",
+			"pkg1/policy1.rego":        "policy content\n",
+		})
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"1.0.0", "0.1.0"}, nil)
+		sw.Op.On("PullLayer", sw.Svc.Ctx, "registry.io/org/policies:1.0.0", PackageContentLayerMediaType, "user", "pass").
+			Return(desc, data1, nil)
+		sw.Op.On("PullLayer", sw.Svc.Ctx, "registry.io/org/policies:0.1.0", PackageContentLayerMediaType, "user", "pass").
+			Return(desc, data2, nil)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
+		require.NoError(t, err)
+		require.Len(t, packages, 2)
+		p1 := packages["pkg1@1.0.0"]
+		require.NotNil(t, p1)
+		assert.Equal(t, desc.Digest.String(), p1.Digest)
+		assert.Equal(t, "", p1.RelativePath)
+		assert.Equal(t, map[string]string{"policy1.rego": "policy content\n"}, p1.Data[OPAPoliciesKey])
+		assert.Equal(t, i.Repository, p1.Repository)
+		p2 := packages["pkg1@0.1.0"]
+		require.NotNil(t, p2)
+		assert.Equal(t, "0123456789", p2.Digest)
+		assert.Equal(t, "/pkg1", p2.RelativePath)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+	})
+}
+
+func TestExtractPackageContent(t *testing.T) {
+	t.Run("package content too big", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gzw)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "big",
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     maxPackageContentSize + 1,
+		}))
+		require.NoError(t, gzw.Close())
+
+		err := extractPackageContent(buf.Bytes(), t.TempDir())
+		assert.EqualError(t, err, "package content too big")
+	})
+
+	t.Run("symlinks are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gzw)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "link",
+			Typeflag: tar.TypeSymlink,
+			Linkname: "/etc/passwd",
+		}))
+		require.NoError(t, tw.Close())
+		require.NoError(t, gzw.Close())
+
+		dst := t.TempDir()
+		err := extractPackageContent(buf.Bytes(), dst)
+		assert.NoError(t, err)
+		assert.NoFileExists(t, dst+"/link")
+	})
+}
+
+func buildPackageContent(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	return buf.Bytes()
+}
+
+func withOCITagsGetter(tg hub.OCITagsGetter) func(s *TrackerSource) {
+	return func(s *TrackerSource) {
+		s.tg = tg
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path"
 	"regexp"
 	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -31,6 +32,7 @@ import (
 	"helm.sh/helm/v3/pkg/chart"
 	"helm.sh/helm/v3/pkg/chart/loader"
 	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
 	helmrepo "helm.sh/helm/v3/pkg/repo"
 )
 
@@ -98,6 +100,7 @@ type TrackerSource struct {
 	i  *hub.TrackerSourceInput
 	il hub.HelmIndexLoader
 	tg hub.OCITagsGetter
+	rl hub.OCIRepositoriesLister
 }
 
 // NewTrackerSource creates a new TrackerSource instance.
@@ -112,6 +115,9 @@ func NewTrackerSource(i *hub.TrackerSourceInput, opts ...func(s *TrackerSource))
 	if s.tg == nil {
 		s.tg = oci.NewTagsGetter(i.Svc.Cfg)
 	}
+	if s.rl == nil {
+		s.rl = oci.NewRepositoriesLister(i.Svc.Cfg, i.Svc.Op)
+	}
 	return s
 }
 
@@ -189,26 +195,27 @@ func (s *TrackerSource) getCharts() (map[string][]*helmrepo.ChartVersion, error)
 			}
 		}
 	case "oci":
-		// Get versions (tags) available in the repository
-		versions, err := s.tg.Tags(s.i.Svc.Ctx, s.i.Repository, true, true)
+		// Get charts and versions (tags) available in the repository
+		ociCharts, err := repo.GetHelmOCICharts(s.i.Svc.Ctx, s.tg, s.rl, s.i.Repository)
 		if err != nil {
 			return nil, fmt.Errorf("error getting repository available versions: %w", err)
 		}
 
 		// Prepare chart versions using the list of versions available
-		name := path.Base(s.i.Repository.URL)
-		for _, version := range versions {
-			// See https://github.com/helm/helm/blob/14d0c13e9eefff5b4a1b511cf50643529692ec94/pkg/registry/client.go#L45C8-L50
-			versionReplacingPlusSign := strings.Replace(version, "+", "_", 1)
-			chartURL := fmt.Sprintf("%s:%s", s.i.Repository.URL, versionReplacingPlusSign)
-
-			charts[name] = append(charts[name], &helmrepo.ChartVersion{
-				Metadata: &chart.Metadata{
-					Name:    name,
-					Version: version,
-				},
-				URLs: []string{chartURL},
-			})
+		for _, c := range ociCharts {
+			for _, version := range c.Versions {
+				// See https://github.com/helm/helm/blob/14d0c13e9eefff5b4a1b511cf50643529692ec94/pkg/registry/client.go#L45C8-L50
+				versionReplacingPlusSign := strings.Replace(version, "+", "_", 1)
+				chartURL := fmt.Sprintf("%s:%s", c.URL, versionReplacingPlusSign)
+
+				charts[c.Name] = append(charts[c.Name], &helmrepo.ChartVersion{
+					Metadata: &chart.Metadata{
+						Name:    c.Name,
+						Version: version,
+					},
+					URLs: []string{chartURL},
+				})
+			}
 		}
 	default:
 		return nil, repo.ErrSchemeNotSupported
@@ -331,6 +338,12 @@ func (s *TrackerSource) preparePackage(chartVersion *helmrepo.ChartVersion) (*hu
 		if err := EnrichPackageFromAnnotations(p, chrt.Metadata.Annotations); err != nil {
 			return nil, fmt.Errorf("error enriching package from annotations: %w", err)
 		}
+
+		// Enrich package with changes from changelog file (when not provided
+		// explicitly using the changes annotation)
+		if err := EnrichPackageFromChangelog(p, chrt); err != nil {
+			s.warn(md, err)
+		}
 	}
 
 	return p, nil
@@ -410,8 +423,16 @@ type LoadChartArchiveOptions struct {
 // LoadChartArchive loads a chart from a remote archive located at the url
 // provided.
 func LoadChartArchive(ctx context.Context, u *url.URL, o *LoadChartArchiveOptions) (*chart.Chart, error) {
-	var r io.Reader
+	data, err := GetChartArchive(ctx, u, o)
+	if err != nil {
+		return nil, err
+	}
+	return loader.LoadArchive(bytes.NewReader(data))
+}
 
+// GetChartArchive gets the content of the remote chart archive located at the
+// url provided.
+func GetChartArchive(ctx context.Context, u *url.URL, o *LoadChartArchiveOptions) ([]byte, error) {
 	switch u.Scheme {
 	case "http", "https":
 		// Get chart content
@@ -440,7 +461,7 @@ func LoadChartArchive(ctx context.Context, u *url.URL, o *LoadChartArchiveOption
 		default:
 			return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
 		}
-		r = resp.Body
+		return io.ReadAll(resp.Body)
 	case "oci":
 		op := o.Op
 		if op == nil {
@@ -458,17 +479,10 @@ func LoadChartArchive(ctx context.Context, u *url.URL, o *LoadChartArchiveOption
 				return nil, err
 			}
 		}
-		r = bytes.NewReader(data)
+		return data, nil
 	default:
 		return nil, repo.ErrSchemeNotSupported
 	}
-
-	// Load chart from reader previously set up
-	chrt, err := loader.LoadArchive(r)
-	if err != nil {
-		return nil, err
-	}
-	return chrt, nil
 }
 
 // EnrichPackageFromChart adds some extra information to the package from the
@@ -486,15 +500,29 @@ func EnrichPackageFromChart(p *hub.Package, chrt *chart.Chart) {
 	// API version
 	p.Data[apiVersionKey] = chrt.Metadata.APIVersion
 
+	// Render chart manifest using the default values
+	manifest, manifestErr := renderManifest(chrt)
+
 	// Containers images
-	imagesRefs, err := extractContainersImages(chrt)
-	if err == nil && len(imagesRefs) > 0 {
-		containersImages := make([]*hub.ContainerImage, 0, len(imagesRefs))
-		for _, imageRef := range imagesRefs {
-			containersImages = append(containersImages, &hub.ContainerImage{Image: imageRef})
+	if manifestErr == nil {
+		imagesRefs := extractContainersImages(manifest)
+		if len(imagesRefs) > 0 {
+			containersImages := make([]*hub.ContainerImage, 0, len(imagesRefs))
+			for _, imageRef := range imagesRefs {
+				containersImages = append(containersImages, &hub.ContainerImage{Image: imageRef})
+			}
+			if err := pkg.ValidateContainersImages(hub.Helm, containersImages); err == nil {
+				p.ContainersImages = containersImages
+			}
 		}
-		if err := pkg.ValidateContainersImages(hub.Helm, containersImages); err == nil {
-			p.ContainersImages = containersImages
+	}
+
+	// Kubernetes APIs deprecations
+	var deprecations []*APIDeprecation
+	if manifestErr == nil {
+		deprecations, _ = findAPIDeprecations(manifest)
+		if len(deprecations) > 0 {
+			p.Data[apiDeprecationsKey] = deprecations
 		}
 	}
 
@@ -513,6 +541,10 @@ func EnrichPackageFromChart(p *hub.Package, chrt *chart.Chart) {
 
 	// Kubernetes version
 	p.Data[kubeVersionKey] = chrt.Metadata.KubeVersion
+	kubeIncompatibleVersions := getKubeIncompatibleVersions(chrt.Metadata.KubeVersion, deprecations)
+	if len(kubeIncompatibleVersions) > 0 {
+		p.Data[kubeIncompatibleVersionsKey] = kubeIncompatibleVersions
+	}
 
 	// License
 	licenseFile := getFile(chrt, "LICENSE")
@@ -561,10 +593,9 @@ func EnrichPackageFromChart(p *hub.Package, chrt *chart.Chart) {
 	p.Data[typeKey] = chrt.Metadata.Type
 }
 
-// extractContainersImages extracts the containers images references found in
-// the manifest generated as a result of Helm dry-run install with the default
-// values.
-func extractContainersImages(chrt *chart.Chart) (images []string, err error) {
+// renderManifest returns the manifest generated as a result of Helm dry-run
+// install with the default values.
+func renderManifest(chrt *chart.Chart) (manifest string, err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("panic running helm dry-run install: %v", r)
@@ -588,12 +619,71 @@ func extractContainersImages(chrt *chart.Chart) (images []string, err error) {
 	install.IncludeCRDs = true
 	install.DependencyUpdate = false
 	release, err := install.Run(chrt, chartutil.Values{})
+	if err != nil {
+		return "", err
+	}
+
+	return release.Manifest, nil
+}
+
+// RenderedTemplate represents a chart template rendered.
+type RenderedTemplate struct {
+	Name     string `json:"name"`
+	Manifest string `json:"manifest"`
+}
+
+// renderChart renders the templates of the chart provided using the values
+// document given. Values are not validated against the chart's schema, as
+// schemas may reference remote documents that would be fetched otherwise.
+func renderChart(chrt *chart.Chart, values []byte) ([]*RenderedTemplate, error) {
+	vals, err := chartutil.ReadValues(values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid values: %w", err)
+	}
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, vals); err != nil {
+		return nil, fmt.Errorf("error processing dependencies: %w", err)
+	}
+	options := chartutil.ReleaseOptions{
+		Name:      "release-name",
+		Namespace: "default",
+		Revision:  1,
+		IsInstall: true,
+	}
+	renderValues, err := chartutil.ToRenderValuesWithSchemaValidation(
+		chrt,
+		vals,
+		options,
+		chartutil.DefaultCapabilities.Copy(),
+		true,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := engine.Engine{}.Render(chrt, renderValues)
 	if err != nil {
 		return nil, err
 	}
 
-	// Extract containers images from release manifest
-	s := bufio.NewScanner(strings.NewReader(release.Manifest))
+	templates := make([]*RenderedTemplate, 0, len(rendered))
+	for name, manifest := range rendered {
+		if strings.TrimSpace(manifest) == "" {
+			continue
+		}
+		templates = append(templates, &RenderedTemplate{
+			Name:     name,
+			Manifest: manifest,
+		})
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// extractContainersImages extracts the containers images references found in
+// the manifest provided.
+func extractContainersImages(manifest string) (images []string) {
+	s := bufio.NewScanner(strings.NewReader(manifest))
 	for s.Scan() {
 		result := containersImagesRE.FindStringSubmatch(s.Text())
 		if result == nil {
@@ -605,7 +695,7 @@ func extractContainersImages(chrt *chart.Chart) (images []string, err error) {
 		}
 	}
 
-	return images, nil
+	return images
 }
 
 // EnrichPackageFromAnnotations adds some extra information to the package from
@@ -792,6 +882,16 @@ func EnrichPackageFromAnnotations(p *hub.Package, annotations map[string]string)
 	return errs.ErrorOrNil()
 }
 
+// EnrichPackageFromChangelog sets the package changes from the changelog file
+// available in the chart archive, if any.
+func EnrichPackageFromChangelog(p *hub.Package, chrt *chart.Chart) error {
+	changelogFile := getFile(chrt, source.ChangelogFile)
+	if changelogFile == nil {
+		return nil
+	}
+	return source.EnrichPackageFromChangelog(p, changelogFile.Data)
+}
+
 // getFile returns the file requested from the provided chart.
 func getFile(chrt *chart.Chart, name string) *chart.File {
 	for _, file := range chrt.Files {
@@ -1,12 +1,16 @@
 package helm
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/httpw"
@@ -64,7 +68,11 @@ func TestTrackerSource(t *testing.T) {
 		Data: map[string]interface{}{
 			apiVersionKey:  "v2",
 			kubeVersionKey: ">= 1.13.0 < 1.15.0",
-			typeKey:        "application",
+			kubeIncompatibleVersionsKey: []string{
+				"1.16", "1.17", "1.18", "1.19", "1.20", "1.21", "1.22", "1.23", "1.24",
+				"1.25", "1.26", "1.27", "1.28", "1.29", "1.30", "1.31", "1.32", "1.33",
+			},
+			typeKey: "application",
 		},
 		Version:    "1.0.0",
 		AppVersion: "1.0.0",
@@ -201,6 +209,30 @@ func TestTrackerSource(t *testing.T) {
 		sw.AssertExpectations(t)
 	})
 
+	t.Run("error listing oci namespace charts", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				URL:  "oci://registry/namespace",
+				Data: json.RawMessage(`{"namespace": true, "listing": "oci://registry/namespace/listing:latest"}`),
+			},
+			Svc: sw.Svc,
+		}
+		rl := &oci.RepositoriesListerMock{}
+		rl.On("Repositories", i.Svc.Ctx, i.Repository, "oci://registry/namespace/listing:latest").
+			Return(nil, tests.ErrFake)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCIRepositoriesLister(rl)).GetPackagesAvailable()
+		assert.Nil(t, packages)
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		rl.AssertExpectations(t)
+		sw.AssertExpectations(t)
+	})
+
 	t.Run("invalid package version", func(t *testing.T) {
 		t.Parallel()
 
@@ -434,6 +466,7 @@ func TestTrackerSource(t *testing.T) {
 
 		// Run test and check expectations
 		p := source.ClonePackage(basePkg)
+		p.Data[kubeIncompatibleVersionsKey] = basePkg.Data[kubeIncompatibleVersionsKey]
 		p.Repository = i.Repository
 		packages, err := NewTrackerSource(i, withIndexLoader(il)).GetPackagesAvailable()
 		assert.Equal(t, map[string]*hub.Package{
@@ -489,6 +522,7 @@ func TestTrackerSource(t *testing.T) {
 
 		// Run test and check expectations
 		p := source.ClonePackage(basePkg)
+		p.Data[kubeIncompatibleVersionsKey] = basePkg.Data[kubeIncompatibleVersionsKey]
 		p.Repository = i.Repository
 		p.LogoURL = logoImageURL
 		p.LogoImageID = "logoImageID"
@@ -526,6 +560,7 @@ func TestTrackerSource(t *testing.T) {
 		// Run test and check expectations
 		packages, err := NewTrackerSource(i, withOCITagsGetter(tg)).GetPackagesAvailable()
 		p := source.ClonePackage(basePkg)
+		p.Data[kubeIncompatibleVersionsKey] = basePkg.Data[kubeIncompatibleVersionsKey]
 		p.ContentURL = "oci://registry/namespace/pkg1:1.0.0"
 		p.Repository = i.Repository
 		p.LogoURL = logoImageURL
@@ -539,6 +574,50 @@ func TestTrackerSource(t *testing.T) {
 		tg.AssertExpectations(t)
 		sw.AssertExpectations(t)
 	})
+
+	t.Run("one package returned, no errors (oci namespace)", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				URL:  "oci://registry/namespace",
+				Data: json.RawMessage(`{"namespace": true}`),
+			},
+			Svc: sw.Svc,
+		}
+		ref := "registry/namespace/pkg1:1.0.0"
+		rl := &oci.RepositoriesListerMock{}
+		rl.On("Repositories", i.Svc.Ctx, i.Repository, "").Return([]string{"pkg1"}, nil)
+		tg := &oci.TagsGetterMock{}
+		tg.On("Tags", i.Svc.Ctx, mock.MatchedBy(func(r *hub.Repository) bool {
+			return r.URL == "oci://registry/namespace/pkg1"
+		}), true).Return([]string{"1.0.0"}, nil)
+		sw.Sc.On("HasCosignSignature", i.Svc.Ctx, ref, "", "").Return(false, nil)
+		data, _ := os.ReadFile("testdata/pkg1-1.0.0.tgz")
+		sw.Op.On("PullLayer", mock.Anything, ref, ChartContentLayerMediaType, "", "").
+			Return(ocispec.Descriptor{}, data, nil)
+		sw.Op.On("PullLayer", mock.Anything, ref, ChartProvenanceLayerMediaType, "", "").
+			Return(ocispec.Descriptor{}, nil, oci.ErrLayerNotFound)
+		sw.Is.On("DownloadAndSaveImage", sw.Svc.Ctx, logoImageURL).Return("logoImageID", nil)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withOCIRepositoriesLister(rl)).GetPackagesAvailable()
+		p := source.ClonePackage(basePkg)
+		p.Data[kubeIncompatibleVersionsKey] = basePkg.Data[kubeIncompatibleVersionsKey]
+		p.ContentURL = "oci://registry/namespace/pkg1:1.0.0"
+		p.Repository = i.Repository
+		p.LogoURL = logoImageURL
+		p.LogoImageID = "logoImageID"
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+		}, packages)
+		assert.NoError(t, err)
+		rl.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		sw.AssertExpectations(t)
+	})
 }
 
 func TestExtractContainersImages(t *testing.T) {
@@ -552,8 +631,9 @@ func TestExtractContainersImages(t *testing.T) {
 		require.NoError(t, err)
 
 		// Extract containers images and check expectations
-		containersImages, err := extractContainersImages(chrt)
+		manifest, err := renderManifest(chrt)
 		require.NoError(t, err)
+		containersImages := extractContainersImages(manifest)
 		assert.Equal(t, []string{
 			"postgres:12",
 			"bitnami/kubectl:1.29",
@@ -574,13 +654,199 @@ func TestExtractContainersImages(t *testing.T) {
 			Values: map[string]interface{}{},
 		}
 
-		containersImages, err := extractContainersImages(chrt)
-		assert.Nil(t, containersImages)
+		manifest, err := renderManifest(chrt)
+		assert.Empty(t, manifest)
 		assert.Error(t, err)
 		assert.True(t, strings.HasPrefix(err.Error(), "panic running helm dry-run install"))
 	})
 }
 
+func TestFindAPIDeprecations(t *testing.T) {
+	t.Run("invalid manifest", func(t *testing.T) {
+		t.Parallel()
+		deprecations, err := findAPIDeprecations("{[")
+		assert.Nil(t, deprecations)
+		assert.Error(t, err)
+	})
+
+	t.Run("deprecated apis found", func(t *testing.T) {
+		t.Parallel()
+		manifest := `
+---
+apiVersion: apps/v1
+kind: Deployment
+---
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+---
+apiVersion: extensions/v1beta1
+kind: Ingress
+---
+apiVersion: policy/v1beta1
+kind: PodDisruptionBudget
+`
+		deprecations, err := findAPIDeprecations(manifest)
+		require.NoError(t, err)
+		assert.Equal(t, []*APIDeprecation{
+			{
+				APIVersion:   "policy/v1beta1",
+				Kind:         "PodDisruptionBudget",
+				DeprecatedIn: "1.21",
+				RemovedIn:    "1.25",
+				Replacement:  "policy/v1",
+			},
+			{
+				APIVersion:   "extensions/v1beta1",
+				Kind:         "Ingress",
+				DeprecatedIn: "1.14",
+				RemovedIn:    "1.22",
+				Replacement:  "networking.k8s.io/v1",
+			},
+		}, deprecations)
+	})
+
+	t.Run("no deprecated apis found", func(t *testing.T) {
+		t.Parallel()
+		deprecations, err := findAPIDeprecations("apiVersion: apps/v1\nkind: Deployment\n")
+		require.NoError(t, err)
+		assert.Nil(t, deprecations)
+	})
+}
+
+func TestGetKubeIncompatibleVersions(t *testing.T) {
+	testCases := []struct {
+		kubeVersion      string
+		deprecations     []*APIDeprecation
+		expectedVersions []string
+	}{
+		{
+			"",
+			nil,
+			nil,
+		},
+		{
+			"invalid",
+			nil,
+			nil,
+		},
+		{
+			">=1.30.0-0",
+			nil,
+			[]string{"1.16", "1.17", "1.18", "1.19", "1.20", "1.21", "1.22", "1.23", "1.24", "1.25", "1.26", "1.27", "1.28", "1.29"},
+		},
+		{
+			"",
+			[]*APIDeprecation{{RemovedIn: "1.29"}, {RemovedIn: "1.32"}},
+			[]string{"1.29", "1.30", "1.31", "1.32", "1.33"},
+		},
+		{
+			">=1.18.0 <1.31.0",
+			[]*APIDeprecation{{RemovedIn: "1.29"}},
+			[]string{"1.16", "1.17", "1.29", "1.30", "1.31", "1.32", "1.33"},
+		},
+	}
+	for i, tc := range testCases {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expectedVersions, getKubeIncompatibleVersions(tc.kubeVersion, tc.deprecations))
+		})
+	}
+}
+
+func TestRenderChart(t *testing.T) {
+	newChart := func(templates map[string]string) *chart.Chart {
+		chrt := &chart.Chart{
+			Metadata: &chart.Metadata{
+				APIVersion: "v2",
+				Name:       "chart",
+				Version:    "1.0.0",
+			},
+			Values: map[string]interface{}{
+				"replicas": 1,
+				"name":     "default",
+			},
+		}
+		for name, data := range templates {
+			chrt.Templates = append(chrt.Templates, &chart.File{Name: name, Data: []byte(data)})
+		}
+		return chrt
+	}
+
+	t.Run("invalid values", func(t *testing.T) {
+		t.Parallel()
+		chrt := newChart(nil)
+		templates, err := renderChart(chrt, []byte("{["))
+		assert.Nil(t, templates)
+		assert.ErrorContains(t, err, "invalid values")
+	})
+
+	t.Run("template error", func(t *testing.T) {
+		t.Parallel()
+		chrt := newChart(map[string]string{
+			"templates/cm.yaml": `{{ required "name is required" .Values.missing }}`,
+		})
+		templates, err := renderChart(chrt, nil)
+		assert.Nil(t, templates)
+		assert.ErrorContains(t, err, "name is required")
+	})
+
+	t.Run("values not validated against schema", func(t *testing.T) {
+		t.Parallel()
+		var requests atomic.Int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			_, _ = w.Write([]byte(`{"type": "string"}`))
+		}))
+		defer ts.Close()
+		chrt := newChart(map[string]string{
+			"templates/cm.yaml": `replicas: {{ .Values.replicas }}`,
+		})
+		chrt.Schema = []byte(fmt.Sprintf(`{
+			"type": "object",
+			"properties": {
+				"replicas": {"type": "integer", "maximum": 1},
+				"name": {"$ref": "%s/schema.json"}
+			}
+		}`, ts.URL))
+		templates, err := renderChart(chrt, []byte("replicas: 2\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []*RenderedTemplate{
+			{
+				Name:     "chart/templates/cm.yaml",
+				Manifest: "replicas: 2",
+			},
+		}, templates)
+		assert.Zero(t, requests.Load())
+	})
+
+	t.Run("chart rendered successfully", func(t *testing.T) {
+		t.Parallel()
+		chrt := newChart(map[string]string{
+			"templates/_helpers.tpl": `{{ define "name" }}{{ .Values.name }}{{ end }}`,
+			"templates/empty.yaml":   `{{ if .Values.disabled }}kind: Secret{{ end }}`,
+			"templates/cm.yaml": `kind: ConfigMap
+name: {{ include "name" . }}
+replicas: {{ .Values.replicas }}
+release: {{ .Release.Name }}
+lookup: {{ lookup "v1" "Secret" "default" "secret" | len }}
+host: {{ getHostByName "artifacthub.io" | quote }}`,
+		})
+		templates, err := renderChart(chrt, []byte("name: custom\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []*RenderedTemplate{
+			{
+				Name: "chart/templates/cm.yaml",
+				Manifest: `kind: ConfigMap
+name: custom
+replicas: 1
+release: release-name
+lookup: 0
+host: ""`,
+			},
+		}, templates)
+	})
+}
+
 func TestEnrichPackageFromAnnotations(t *testing.T) {
 	testCases := []struct {
 		pkg            *hub.Package
@@ -1131,6 +1397,12 @@ func withIndexLoader(il hub.HelmIndexLoader) func(s *TrackerSource) {
 	}
 }
 
+func withOCIRepositoriesLister(rl hub.OCIRepositoriesLister) func(s *TrackerSource) {
+	return func(s *TrackerSource) {
+		s.rl = rl
+	}
+}
+
 func withOCITagsGetter(tg hub.OCITagsGetter) func(s *TrackerSource) {
 	return func(s *TrackerSource) {
 		s.tg = tg
@@ -0,0 +1,169 @@
+package helm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/Masterminds/semver/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	apiDeprecationsKey          = "apiDeprecations"
+	kubeIncompatibleVersionsKey = "kubeIncompatibleVersions"
+
+	// minKubeMinorVersion and maxKubeMinorVersion define the range of
+	// Kubernetes versions (1.x) charts compatibility is checked against.
+	minKubeMinorVersion = 16
+	maxKubeMinorVersion = 33
+)
+
+// APIDeprecation represents a Kubernetes API deprecated or removed in a given
+// Kubernetes version that is used by some of the resources in a chart.
+type APIDeprecation struct {
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	Replacement  string `json:"replacement,omitempty"`
+}
+
+// apiDeprecations contains the Kubernetes APIs deprecated or removed, keyed by
+// the Kubernetes version in which they were removed. For more information
+// please see https://kubernetes.io/docs/reference/using-api/deprecation-guide
+var apiDeprecations = map[string][]*APIDeprecation{
+	"1.16": {
+		{APIVersion: "apps/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta1", Kind: "StatefulSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "DaemonSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "ReplicaSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "apps/v1beta2", Kind: "StatefulSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "DaemonSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "Deployment", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "NetworkPolicy", DeprecatedIn: "1.9", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.10", Replacement: "policy/v1beta1"},
+		{APIVersion: "extensions/v1beta1", Kind: "ReplicaSet", DeprecatedIn: "1.9", Replacement: "apps/v1"},
+	},
+	"1.22": {
+		{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "MutatingWebhookConfiguration", DeprecatedIn: "1.16", Replacement: "admissionregistration.k8s.io/v1"},
+		{APIVersion: "admissionregistration.k8s.io/v1beta1", Kind: "ValidatingWebhookConfiguration", DeprecatedIn: "1.16", Replacement: "admissionregistration.k8s.io/v1"},
+		{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", DeprecatedIn: "1.16", Replacement: "apiextensions.k8s.io/v1"},
+		{APIVersion: "apiregistration.k8s.io/v1beta1", Kind: "APIService", DeprecatedIn: "1.19", Replacement: "apiregistration.k8s.io/v1"},
+		{APIVersion: "authentication.k8s.io/v1beta1", Kind: "TokenReview", DeprecatedIn: "1.19", Replacement: "authentication.k8s.io/v1"},
+		{APIVersion: "authorization.k8s.io/v1beta1", Kind: "LocalSubjectAccessReview", DeprecatedIn: "1.19", Replacement: "authorization.k8s.io/v1"},
+		{APIVersion: "authorization.k8s.io/v1beta1", Kind: "SelfSubjectAccessReview", DeprecatedIn: "1.19", Replacement: "authorization.k8s.io/v1"},
+		{APIVersion: "authorization.k8s.io/v1beta1", Kind: "SubjectAccessReview", DeprecatedIn: "1.19", Replacement: "authorization.k8s.io/v1"},
+		{APIVersion: "certificates.k8s.io/v1beta1", Kind: "CertificateSigningRequest", DeprecatedIn: "1.19", Replacement: "certificates.k8s.io/v1"},
+		{APIVersion: "coordination.k8s.io/v1beta1", Kind: "Lease", DeprecatedIn: "1.19", Replacement: "coordination.k8s.io/v1"},
+		{APIVersion: "extensions/v1beta1", Kind: "Ingress", DeprecatedIn: "1.14", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "networking.k8s.io/v1beta1", Kind: "Ingress", DeprecatedIn: "1.19", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "networking.k8s.io/v1beta1", Kind: "IngressClass", DeprecatedIn: "1.19", Replacement: "networking.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRole", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "ClusterRoleBinding", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "Role", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "rbac.authorization.k8s.io/v1beta1", Kind: "RoleBinding", DeprecatedIn: "1.17", Replacement: "rbac.authorization.k8s.io/v1"},
+		{APIVersion: "scheduling.k8s.io/v1beta1", Kind: "PriorityClass", DeprecatedIn: "1.14", Replacement: "scheduling.k8s.io/v1"},
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIDriver", DeprecatedIn: "1.19", Replacement: "storage.k8s.io/v1"},
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSINode", DeprecatedIn: "1.17", Replacement: "storage.k8s.io/v1"},
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "StorageClass", DeprecatedIn: "1.19", Replacement: "storage.k8s.io/v1"},
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "VolumeAttachment", DeprecatedIn: "1.19", Replacement: "storage.k8s.io/v1"},
+	},
+	"1.25": {
+		{APIVersion: "autoscaling/v2beta1", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.22", Replacement: "autoscaling/v2"},
+		{APIVersion: "batch/v1beta1", Kind: "CronJob", DeprecatedIn: "1.21", Replacement: "batch/v1"},
+		{APIVersion: "discovery.k8s.io/v1beta1", Kind: "EndpointSlice", DeprecatedIn: "1.21", Replacement: "discovery.k8s.io/v1"},
+		{APIVersion: "events.k8s.io/v1beta1", Kind: "Event", DeprecatedIn: "1.19", Replacement: "events.k8s.io/v1"},
+		{APIVersion: "node.k8s.io/v1beta1", Kind: "RuntimeClass", DeprecatedIn: "1.20", Replacement: "node.k8s.io/v1"},
+		{APIVersion: "policy/v1beta1", Kind: "PodDisruptionBudget", DeprecatedIn: "1.21", Replacement: "policy/v1"},
+		{APIVersion: "policy/v1beta1", Kind: "PodSecurityPolicy", DeprecatedIn: "1.21"},
+	},
+	"1.26": {
+		{APIVersion: "autoscaling/v2beta2", Kind: "HorizontalPodAutoscaler", DeprecatedIn: "1.23", Replacement: "autoscaling/v2"},
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "FlowSchema", DeprecatedIn: "1.23", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta1", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.23", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	},
+	"1.27": {
+		{APIVersion: "storage.k8s.io/v1beta1", Kind: "CSIStorageCapacity", DeprecatedIn: "1.24", Replacement: "storage.k8s.io/v1"},
+	},
+	"1.29": {
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "FlowSchema", DeprecatedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta2", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.26", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	},
+	"1.32": {
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "FlowSchema", DeprecatedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+		{APIVersion: "flowcontrol.apiserver.k8s.io/v1beta3", Kind: "PriorityLevelConfiguration", DeprecatedIn: "1.29", Replacement: "flowcontrol.apiserver.k8s.io/v1"},
+	},
+}
+
+// apiDeprecationsIndex indexes the Kubernetes APIs deprecations by api version
+// and kind.
+var apiDeprecationsIndex = func() map[string]*APIDeprecation {
+	index := make(map[string]*APIDeprecation)
+	for removedIn, deprecations := range apiDeprecations {
+		for _, d := range deprecations {
+			d.RemovedIn = removedIn
+			index[d.APIVersion+"/"+d.Kind] = d
+		}
+	}
+	return index
+}()
+
+// findAPIDeprecations returns the deprecated or removed Kubernetes APIs used
+// by the resources in the manifest provided.
+func findAPIDeprecations(manifest string) ([]*APIDeprecation, error) {
+	var deprecations []*APIDeprecation
+	seen := make(map[string]struct{})
+	dec := yaml.NewDecoder(bytes.NewBufferString(manifest))
+	for {
+		var resource struct {
+			APIVersion string `yaml:"apiVersion"`
+			Kind       string `yaml:"kind"`
+		}
+		if err := dec.Decode(&resource); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("error decoding manifest: %w", err)
+		}
+		key := resource.APIVersion + "/" + resource.Kind
+		d, ok := apiDeprecationsIndex[key]
+		if !ok {
+			continue
+		}
+		if _, ok := seen[key]; !ok {
+			deprecations = append(deprecations, d)
+			seen[key] = struct{}{}
+		}
+	}
+	return deprecations, nil
+}
+
+// getKubeIncompatibleVersions returns the Kubernetes versions in which a chart
+// cannot be installed, either because its kubeVersion constraint is not met
+// or because it uses some APIs already removed.
+func getKubeIncompatibleVersions(kubeVersion string, deprecations []*APIDeprecation) []string {
+	var constraint *semver.Constraints
+	if kubeVersion != "" {
+		constraint, _ = semver.NewConstraint(kubeVersion)
+	}
+	var versions []string
+	for minor := minKubeMinorVersion; minor <= maxKubeMinorVersion; minor++ {
+		v := semver.New(1, uint64(minor), 0, "", "")
+		compatible := constraint == nil || constraint.Check(v)
+		for _, d := range deprecations {
+			removedIn, err := semver.NewVersion(d.RemovedIn)
+			if err == nil && !v.LessThan(removedIn) {
+				compatible = false
+				break
+			}
+		}
+		if !compatible {
+			versions = append(versions, "1."+strconv.Itoa(minor))
+		}
+	}
+	return versions
+}
@@ -0,0 +1,204 @@
+package helm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// RenderChartCmd represents the command used to run the chart renderer
+// process. Charts are rendered in a separate process so that rendering can be
+// aborted when it takes too long, as the Helm engine cannot be interrupted.
+const RenderChartCmd = "render-chart"
+
+var (
+	// ErrTooManyRenders indicates that the maximum number of charts being
+	// rendered concurrently has been reached.
+	ErrTooManyRenders = errors.New("too many charts being rendered, please try again later")
+
+	// errRenderOutputTooLarge indicates that the rendered chart output exceeds
+	// the maximum size allowed.
+	errRenderOutputTooLarge = errors.New("rendered chart output too large")
+
+	// errRenderTimeout indicates that the chart could not be rendered within
+	// the time allowed.
+	errRenderTimeout = errors.New("timeout rendering chart")
+)
+
+// renderChartInput represents the input provided to the chart renderer
+// process.
+type renderChartInput struct {
+	Archive   []byte `json:"archive"`
+	Values    []byte `json:"values"`
+	MaxMemory int64  `json:"max_memory"`
+}
+
+// renderChartOutput represents the output produced by the chart renderer
+// process.
+type renderChartOutput struct {
+	Templates []*RenderedTemplate `json:"templates"`
+	Err       string              `json:"error"`
+}
+
+// ChartRendererOptions represents some options that can be provided to set up
+// a ChartRenderer instance.
+type ChartRendererOptions struct {
+	// MaxConcurrency represents the maximum number of charts that can be
+	// rendered concurrently.
+	MaxConcurrency int
+
+	// MaxOutputSize represents the maximum size of the rendered output.
+	MaxOutputSize int64
+
+	// MaxMemory represents the maximum amount of memory the renderer process
+	// can allocate. The renderer process is aborted when it tries to exceed it.
+	// This limit is only enforced on Linux.
+	MaxMemory int64
+
+	// Timeout represents the maximum amount of time rendering a chart can
+	// take. The renderer process is killed when the timeout expires.
+	Timeout time.Duration
+}
+
+// ChartRenderer renders charts templates in separate processes, bounding the
+// number of concurrent renders, the time they can take and the size of the
+// output they can produce.
+type ChartRenderer struct {
+	cmd string
+	o   *ChartRendererOptions
+	sem chan struct{}
+}
+
+// NewChartRenderer creates a new ChartRenderer instance. The renderer process
+// is run by executing the current executable using the RenderChartCmd command,
+// which must be handled by calling ServeRenderChart.
+func NewChartRenderer(o *ChartRendererOptions) *ChartRenderer {
+	cmd, err := os.Executable()
+	if err != nil {
+		cmd = os.Args[0]
+	}
+	return &ChartRenderer{
+		cmd: cmd,
+		o:   o,
+		sem: make(chan struct{}, o.MaxConcurrency),
+	}
+}
+
+// Render renders the templates of the chart archive provided using the values
+// document given, which is merged with the chart's default values. Rendering
+// is done without access to any Kubernetes cluster (lookup always returns
+// empty results), with DNS lookups disabled, without validating the values
+// against the chart's schema and using the default capabilities. The renderer
+// process does not inherit the environment of the current process. Templates
+// that produce no output are omitted. Errors caused by the chart or the values
+// provided wrap hub.ErrInvalidInput.
+func (r *ChartRenderer) Render(ctx context.Context, archive, values []byte) ([]*RenderedTemplate, error) {
+	// Limit the number of concurrent renders
+	select {
+	case r.sem <- struct{}{}:
+		defer func() { <-r.sem }()
+	default:
+		return nil, ErrTooManyRenders
+	}
+
+	// Launch renderer process
+	ctx, cancel := context.WithTimeout(ctx, r.o.Timeout)
+	defer cancel()
+	input, err := json.Marshal(&renderChartInput{
+		Archive:   archive,
+		Values:    values,
+		MaxMemory: r.o.MaxMemory,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.CommandContext(ctx, r.cmd, RenderChartCmd)
+	cmd.Env = []string{}
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = time.Second
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %w", hub.ErrInvalidInput, errRenderTimeout)
+		}
+		return nil, fmt.Errorf("error starting chart renderer: %w", err)
+	}
+
+	// Read renderer output, killing the process if it exceeds the limit
+	data, readErr := io.ReadAll(io.LimitReader(stdout, r.o.MaxOutputSize+1))
+	outputTooLarge := int64(len(data)) > r.o.MaxOutputSize
+	if outputTooLarge {
+		cancel()
+	}
+	waitErr := cmd.Wait()
+	switch {
+	case outputTooLarge:
+		return nil, fmt.Errorf("%w: %w", hub.ErrInvalidInput, errRenderOutputTooLarge)
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return nil, fmt.Errorf("%w: %w", hub.ErrInvalidInput, errRenderTimeout)
+	case readErr != nil:
+		return nil, fmt.Errorf("error reading chart renderer output: %w", readErr)
+	case waitErr != nil:
+		return nil, fmt.Errorf("error running chart renderer: %w", waitErr)
+	}
+
+	// Process renderer output
+	var output *renderChartOutput
+	if err := json.Unmarshal(data, &output); err != nil {
+		return nil, fmt.Errorf("error parsing chart renderer output: %w", err)
+	}
+	if output.Err != "" {
+		return nil, fmt.Errorf("%w: %s", hub.ErrInvalidInput, output.Err)
+	}
+	return output.Templates, nil
+}
+
+// ServeRenderChart renders the chart archive and values read from the reader
+// provided and writes the resulting templates to the writer given. It is
+// expected to be run in the chart renderer process.
+func ServeRenderChart(r io.Reader, w io.Writer) error {
+	var input *renderChartInput
+	if err := json.NewDecoder(r).Decode(&input); err != nil {
+		return fmt.Errorf("error decoding input: %w", err)
+	}
+	if input.MaxMemory > 0 {
+		if err := limitMemory(input.MaxMemory); err != nil {
+			return fmt.Errorf("error limiting memory: %w", err)
+		}
+	}
+	var output renderChartOutput
+	templates, err := loadAndRenderChart(input.Archive, input.Values)
+	if err != nil {
+		output.Err = err.Error()
+	} else {
+		output.Templates = templates
+	}
+	return json.NewEncoder(w).Encode(output)
+}
+
+// loadAndRenderChart loads the chart archive provided and renders its
+// templates using the values document given.
+func loadAndRenderChart(archive, values []byte) (templates []*RenderedTemplate, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic rendering chart: %v", r)
+		}
+	}()
+	chrt, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("error loading chart archive: %w", err)
+	}
+	return renderChart(chrt, values)
+}
@@ -0,0 +1,14 @@
+package helm
+
+import "syscall"
+
+// limitMemory limits the maximum size of the data segment (heap and other
+// private writable mappings) of the current process to the number of bytes
+// provided. The address space is not limited as the Go runtime reserves much
+// more of it than it actually uses.
+func limitMemory(limit int64) error {
+	return syscall.Setrlimit(syscall.RLIMIT_DATA, &syscall.Rlimit{
+		Cur: uint64(limit),
+		Max: uint64(limit),
+	})
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package helm
+
+// limitMemory is a no-op on platforms other than Linux.
+func limitMemory(_ int64) error {
+	return nil
+}
@@ -0,0 +1,160 @@
+package helm
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+func TestMain(m *testing.M) {
+	// Run as a chart renderer process when launched by the chart renderer
+	if len(os.Args) > 1 && os.Args[1] == RenderChartCmd {
+		if err := ServeRenderChart(os.Stdin, os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestChartRenderer(t *testing.T) {
+	chrt := &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: "v2",
+			Name:       "chart",
+			Version:    "1.0.0",
+		},
+		Values: map[string]interface{}{
+			"name": "default",
+		},
+		Templates: []*chart.File{
+			{Name: "templates/cm.yaml", Data: []byte("name: {{ .Values.name }}\n")},
+		},
+	}
+	archivePath, err := chartutil.Save(chrt, t.TempDir())
+	require.NoError(t, err)
+	archive, err := os.ReadFile(filepath.Clean(archivePath))
+	require.NoError(t, err)
+	defaultOptions := &ChartRendererOptions{
+		MaxConcurrency: 1,
+		MaxOutputSize:  1 << 20,
+		MaxMemory:      1 << 30,
+		Timeout:        10 * time.Second,
+	}
+
+	t.Run("too many renders", func(t *testing.T) {
+		t.Parallel()
+		r := NewChartRenderer(&ChartRendererOptions{
+			MaxConcurrency: 0,
+			MaxOutputSize:  defaultOptions.MaxOutputSize,
+			Timeout:        defaultOptions.Timeout,
+		})
+		templates, err := r.Render(t.Context(), archive, nil)
+		assert.Nil(t, templates)
+		assert.ErrorIs(t, err, ErrTooManyRenders)
+	})
+
+	t.Run("timeout rendering chart", func(t *testing.T) {
+		t.Parallel()
+		slowChrt := &chart.Chart{
+			Metadata: chrt.Metadata,
+			Templates: []*chart.File{
+				{
+					Name: "templates/slow.yaml",
+					Data: []byte("{{ range until 100000 }}{{ range until 100000 }}{{ end }}{{ end }}"),
+				},
+			},
+		}
+		slowArchivePath, err := chartutil.Save(slowChrt, t.TempDir())
+		require.NoError(t, err)
+		slowArchive, err := os.ReadFile(filepath.Clean(slowArchivePath))
+		require.NoError(t, err)
+		r := NewChartRenderer(&ChartRendererOptions{
+			MaxConcurrency: defaultOptions.MaxConcurrency,
+			MaxOutputSize:  defaultOptions.MaxOutputSize,
+			Timeout:        time.Second,
+		})
+		start := time.Now()
+		templates, err := r.Render(t.Context(), slowArchive, nil)
+		assert.Less(t, time.Since(start), 5*time.Second)
+		assert.Nil(t, templates)
+		assert.ErrorIs(t, err, hub.ErrInvalidInput)
+		assert.ErrorIs(t, err, errRenderTimeout)
+	})
+
+	t.Run("rendered output too large", func(t *testing.T) {
+		t.Parallel()
+		r := NewChartRenderer(&ChartRendererOptions{
+			MaxConcurrency: defaultOptions.MaxConcurrency,
+			MaxOutputSize:  10,
+			Timeout:        defaultOptions.Timeout,
+		})
+		templates, err := r.Render(t.Context(), archive, nil)
+		assert.Nil(t, templates)
+		assert.ErrorIs(t, err, hub.ErrInvalidInput)
+		assert.ErrorIs(t, err, errRenderOutputTooLarge)
+	})
+
+	t.Run("memory limit exceeded", func(t *testing.T) {
+		if runtime.GOOS != "linux" {
+			t.Skip("memory limit only enforced on linux")
+		}
+		t.Parallel()
+		greedyChrt := &chart.Chart{
+			Metadata: chrt.Metadata,
+			Templates: []*chart.File{
+				{
+					Name: "templates/greedy.yaml",
+					Data: []byte(`{{ $s := "xxxxxxxx" }}{{ range until 28 }}{{ $s = print $s $s }}{{ end }}{{ len $s }}`),
+				},
+			},
+		}
+		greedyArchivePath, err := chartutil.Save(greedyChrt, t.TempDir())
+		require.NoError(t, err)
+		greedyArchive, err := os.ReadFile(filepath.Clean(greedyArchivePath))
+		require.NoError(t, err)
+		r := NewChartRenderer(defaultOptions)
+		templates, err := r.Render(t.Context(), greedyArchive, nil)
+		assert.Nil(t, templates)
+		assert.ErrorContains(t, err, "error running chart renderer")
+	})
+
+	t.Run("invalid chart archive", func(t *testing.T) {
+		t.Parallel()
+		r := NewChartRenderer(defaultOptions)
+		templates, err := r.Render(t.Context(), []byte("invalid"), nil)
+		assert.Nil(t, templates)
+		assert.ErrorIs(t, err, hub.ErrInvalidInput)
+		assert.ErrorContains(t, err, "error loading chart archive")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		t.Parallel()
+		r := NewChartRenderer(defaultOptions)
+		templates, err := r.Render(t.Context(), archive, []byte("{["))
+		assert.Nil(t, templates)
+		assert.ErrorIs(t, err, hub.ErrInvalidInput)
+		assert.ErrorContains(t, err, "invalid values")
+	})
+
+	t.Run("chart rendered successfully", func(t *testing.T) {
+		t.Parallel()
+		r := NewChartRenderer(defaultOptions)
+		templates, err := r.Render(t.Context(), archive, []byte("name: custom\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []*RenderedTemplate{
+			{
+				Name:     "chart/templates/cm.yaml",
+				Manifest: "name: custom\n",
+			},
+		}, templates)
+	})
+}
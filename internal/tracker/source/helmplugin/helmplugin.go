@@ -11,6 +11,7 @@ import (
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/license"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tracker/source"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/hashicorp/go-multierror"
 	"helm.sh/helm/v3/pkg/plugin"
@@ -69,6 +70,9 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 			s.warn(fmt.Errorf("error preparing package %s version %s: %w", md.Name, md.Version, err))
 			return nil
 		}
+		if err := source.EnrichPackageFromChangelogFile(p, pkgPath); err != nil {
+			s.warn(fmt.Errorf("error preparing package %s version %s changes: %w", md.Name, md.Version, err))
+		}
 		packagesAvailable[pkg.BuildKey(p)] = p
 
 		return nil
@@ -1,13 +1,21 @@
 package krew
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"strings"
+	"sync"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/httpw"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/tracker/source"
@@ -34,6 +42,18 @@ const (
 	// Platform keys
 	osKey   = "os"
 	archKey = "arch"
+
+	// Plugin manifest type
+	pluginAPIGroup = "krew.googlecontainertools.github.com"
+	pluginKind     = "Plugin"
+
+	// maxArchiveSize represents the maximum size of the platforms archives
+	// that will be downloaded to verify their checksums.
+	maxArchiveSize = 100 * 1024 * 1024
+
+	// archivesConcurrency represents the maximum number of platforms archives
+	// of a plugin that will be downloaded concurrently.
+	archivesConcurrency = 3
 )
 
 const (
@@ -49,48 +69,85 @@ const (
 var (
 	// errInvalidAnnotation indicates that the annotation provided is not valid.
 	errInvalidAnnotation = errors.New("invalid annotation")
+
+	// errArchiveTooLarge indicates that the platform archive exceeds the
+	// maximum size allowed.
+	errArchiveTooLarge = errors.New("archive too large")
 )
 
+// archiveKey represents the key used to cache the checksums of the platforms
+// archives already downloaded.
+type archiveKey struct {
+	uri    string
+	sha256 string
+}
+
 // TrackerSource is a hub.TrackerSource implementation for Krew plugins
 // repositories.
 type TrackerSource struct {
 	i *hub.TrackerSourceInput
+
+	mu        sync.Mutex
+	checksums map[archiveKey]string
 }
 
 // NewTrackerSource creates a new TrackerSource instance.
 func NewTrackerSource(i *hub.TrackerSourceInput) *TrackerSource {
-	return &TrackerSource{i}
+	return &TrackerSource{
+		i:         i,
+		checksums: make(map[archiveKey]string),
+	}
 }
 
 // GetPackagesAvailable implements the TrackerSource interface.
 func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error) {
 	packagesAvailable := make(map[string]*hub.Package)
 
-	// Iterate over the path provided looking for available packages
-	pluginsPath := filepath.Join(s.i.BasePath, "plugins")
-	pluginManifestFiles, err := os.ReadDir(pluginsPath)
-	if err != nil {
-		return nil, fmt.Errorf("error reading plugins directory: %w", err)
+	// Walk the plugins directory when the repository uses the upstream index
+	// layout. Otherwise walk the whole repository, as custom indexes may have
+	// the plugins manifests organized in nested directories.
+	rootPath := filepath.Join(s.i.BasePath, "plugins")
+	upstreamLayout := true
+	if _, err := os.Stat(rootPath); errors.Is(err, os.ErrNotExist) {
+		rootPath = s.i.BasePath
+		upstreamLayout = false
 	}
-	for _, file := range pluginManifestFiles {
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
 		// Return ASAP if context is cancelled
 		select {
 		case <-s.i.Svc.Ctx.Done():
-			return nil, s.i.Svc.Ctx.Err()
+			return s.i.Svc.Ctx.Err()
 		default:
 		}
 
+		// If an error is raised while visiting a path we skip it, unless it
+		// is the root path
+		if err != nil {
+			if path == rootPath {
+				return fmt.Errorf("error reading plugins directory: %w", err)
+			}
+			return nil
+		}
+		if d.IsDir() {
+			if path != rootPath && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
 		// Only process plugins files
-		if !file.Type().IsRegular() || filepath.Ext(file.Name()) != ".yaml" {
-			continue
+		if !d.Type().IsRegular() || filepath.Ext(d.Name()) != ".yaml" {
+			return nil
+		}
+		if !upstreamLayout && !isPluginManifest(path) {
+			return nil
 		}
 
 		// Get package manifest
-		pluginManifestPath := filepath.Join(pluginsPath, file.Name())
-		manifest, manifestRaw, err := GetManifest(pluginManifestPath)
+		manifest, manifestRaw, err := GetManifest(path)
 		if err != nil {
-			s.warn(fmt.Errorf("error getting package manifest (path: %s): %w", pluginManifestPath, err))
-			continue
+			s.warn(fmt.Errorf("error getting package manifest (path: %s): %w", path, err))
+			return nil
 		}
 
 		// Prepare and store package version
@@ -101,14 +158,146 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 				manifest.Spec.Version,
 				err,
 			))
-			continue
+			return nil
+		}
+		key := pkg.BuildKey(p)
+		if _, ok := packagesAvailable[key]; ok {
+			s.warn(fmt.Errorf("package %s version %s already defined in another manifest (path: %s)", p.Name, p.Version, path))
+			return nil
 		}
-		packagesAvailable[pkg.BuildKey(p)] = p
+
+		// Verify platforms archives of package versions not registered yet
+		bypassDigestCheck := s.i.Svc.Cfg.GetBool("tracker.bypassDigestCheck")
+		if _, ok := s.i.PackagesRegistered[key]; !ok || bypassDigestCheck {
+			if err := s.verifyPlatformsArchives(manifest); err != nil {
+				s.warn(fmt.Errorf("error verifying package %s version %s: %w", p.Name, p.Version, err))
+				return nil
+			}
+		}
+
+		packagesAvailable[key] = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return packagesAvailable, nil
 }
 
+// verifyPlatformsArchives downloads the archives of the plugin's platforms and
+// checks that their sha256 checksums match the ones in the manifest. Platforms
+// whose archive cannot be downloaded are flagged in the tracking errors, but
+// they do not prevent the package from being registered. Archives are only
+// downloaded once per uri and checksum, as they are often shared by several
+// platforms or plugin versions.
+func (s *TrackerSource) verifyPlatformsArchives(manifest *index.Plugin) error {
+	// Get checksums of the archives not downloaded yet
+	limiter := make(chan struct{}, archivesConcurrency)
+	var wg sync.WaitGroup
+	for _, key := range s.getPendingArchives(manifest) {
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func(key archiveKey) {
+			defer func() {
+				<-limiter
+				wg.Done()
+			}()
+			checksum, err := s.getArchiveChecksum(key.uri)
+			if err != nil {
+				s.warn(fmt.Errorf("error getting package %s version %s platform archive (%s): %w",
+					manifest.Name,
+					manifest.Spec.Version,
+					key.uri,
+					err,
+				))
+			}
+			s.mu.Lock()
+			s.checksums[key] = checksum
+			s.mu.Unlock()
+		}(key)
+	}
+	wg.Wait()
+
+	// Check archives checksums match the ones in the manifest
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, platform := range manifest.Spec.Platforms {
+		if platform.URI == "" || platform.Sha256 == "" {
+			continue
+		}
+		checksum := s.checksums[newArchiveKey(platform)]
+		if checksum != "" && !strings.EqualFold(checksum, platform.Sha256) {
+			return fmt.Errorf("sha256 mismatch for platform archive %s (expected: %s, got: %s)",
+				platform.URI,
+				platform.Sha256,
+				checksum,
+			)
+		}
+	}
+	return nil
+}
+
+// getPendingArchives returns the keys of the plugin's platforms archives that
+// haven't been downloaded yet.
+func (s *TrackerSource) getPendingArchives(manifest *index.Plugin) []archiveKey {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []archiveKey
+	seen := make(map[archiveKey]struct{})
+	for _, platform := range manifest.Spec.Platforms {
+		if platform.URI == "" || platform.Sha256 == "" {
+			continue
+		}
+		key := newArchiveKey(platform)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		if _, ok := s.checksums[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// getArchiveChecksum downloads the archive located at the url provided and
+// returns its sha256 checksum. Archives larger than maxArchiveSize are not
+// downloaded completely and an error is returned.
+func (s *TrackerSource) getArchiveChecksum(u string) (string, error) {
+	req, err := httpw.NewRequest("GET", u, nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(s.i.Svc.Ctx)
+	resp, err := s.i.Svc.Hc.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	h := sha256.New()
+	n, err := io.Copy(h, io.LimitReader(resp.Body, maxArchiveSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading archive: %w", err)
+	}
+	if n > maxArchiveSize {
+		return "", errArchiveTooLarge
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// newArchiveKey returns the key of the archive of the platform provided.
+func newArchiveKey(platform index.Platform) archiveKey {
+	return archiveKey{
+		uri:    platform.URI,
+		sha256: strings.ToLower(platform.Sha256),
+	}
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (s *TrackerSource) warn(err error) {
@@ -132,6 +321,19 @@ func GetManifest(pluginManifestPath string) (*index.Plugin, []byte, error) {
 	return manifest, manifestRaw, nil
 }
 
+// isPluginManifest checks if the file provided contains a Krew plugin manifest.
+func isPluginManifest(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var manifest *metav1.TypeMeta
+	if err := yaml.Unmarshal(data, &manifest); err != nil || manifest == nil {
+		return false
+	}
+	return manifest.Kind == pluginKind && strings.HasPrefix(manifest.APIVersion, pluginAPIGroup+"/")
+}
+
 // validateManifest checks if the plugin manifest provided is valid.
 func validateManifest(manifest *index.Plugin) error {
 	var errs *multierror.Error
@@ -1,13 +1,19 @@
 package krew
 
 import (
+	"io"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/httpw"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/tracker/source"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestTrackerSource(t *testing.T) {
@@ -145,4 +151,134 @@ func TestTrackerSource(t *testing.T) {
 		assert.NoError(t, err)
 		sw.AssertExpectations(t)
 	})
+
+	t.Run("custom index with nested manifests, platforms archives verified", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{},
+			BasePath:   "testdata/path5",
+			Svc:        sw.Svc,
+		}
+		req1, _ := httpw.NewRequest("GET", "https://archives.url/plugin1-darwin.tar.gz", nil)
+		sw.Hc.On("Do", req1).Return(nil, tests.ErrFake)
+		req2, _ := httpw.NewRequest("GET", "https://archives.url/plugin1-linux.tar.gz", nil)
+		sw.Hc.On("Do", req2).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader("plugin1-linux")),
+			StatusCode: http.StatusOK,
+		}, nil)
+		req3, _ := httpw.NewRequest("GET", "https://archives.url/plugin2-linux.tar.gz", nil)
+		sw.Hc.On("Do", req3).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader("plugin2-linux")),
+			StatusCode: http.StatusOK,
+		}, nil)
+		expectedErr1 := "error getting package plugin1 version v0.1.0 platform archive (https://archives.url/plugin1-darwin.tar.gz): fake error for tests"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr1).Return()
+		expectedErr2 := "error verifying package plugin2 version 1.0.0: sha256 mismatch for platform archive https://archives.url/plugin2-linux.tar.gz (expected: 0000000000000000000000000000000000000000000000000000000000000000, got: d6e04beb3cad74b9d582031b872bf9485f16faaf847d6cb4ef50c05feb0800e0)"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr2).Return()
+
+		// Run test and check expectations
+		manifestRaw, _ := os.ReadFile("testdata/path5/index-a/plugin1.yaml")
+		p := &hub.Package{
+			Name:        "plugin1",
+			Description: "Test plugin1",
+			Keywords:    []string{"kubernetes", "kubectl", "plugin"},
+			Version:     "0.1.0",
+			Repository:  i.Repository,
+			Data: map[string]interface{}{
+				RawManifestKey: string(manifestRaw),
+				PlatformsKey:   []string{"darwin/amd64", "linux/amd64"},
+			},
+		}
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+
+	t.Run("custom index with nested manifests, registered packages not verified", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{},
+			PackagesRegistered: map[string]string{
+				"plugin1@0.1.0": "",
+				"plugin2@1.0.0": "",
+			},
+			BasePath: "testdata/path5",
+			Svc:      sw.Svc,
+		}
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Len(t, packages, 2)
+		assert.Contains(t, packages, "plugin1@0.1.0")
+		assert.Contains(t, packages, "plugin2@1.0.0")
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+	t.Run("platforms archives shared by several versions downloaded once", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{},
+			BasePath:   "testdata/path6",
+			Svc:        sw.Svc,
+		}
+		req, _ := httpw.NewRequest("GET", "https://archives.url/plugin1.tar.gz", nil)
+		sw.Hc.On("Do", req).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader("plugin1-linux")),
+			StatusCode: http.StatusOK,
+		}, nil).Once()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Len(t, packages, 2)
+		assert.Contains(t, packages, "plugin1@0.1.0")
+		assert.Contains(t, packages, "plugin1@0.2.0")
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+
+	t.Run("platform archive too large", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{},
+			BasePath:   "testdata/path6",
+			Svc:        sw.Svc,
+		}
+		req, _ := httpw.NewRequest("GET", "https://archives.url/plugin1.tar.gz", nil)
+		sw.Hc.On("Do", req).Return(&http.Response{
+			Body:       io.NopCloser(zeroReader{}),
+			StatusCode: http.StatusOK,
+		}, nil).Once()
+		sw.Ec.On("Append", i.Repository.RepositoryID, mock.MatchedBy(func(err string) bool {
+			return strings.HasSuffix(err, "(https://archives.url/plugin1.tar.gz): archive too large")
+		})).Return().Once()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Len(t, packages, 2)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
+}
+
+// zeroReader is an io.Reader that returns an endless stream of zeros.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
 }
@@ -0,0 +1,360 @@
+package olm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/hub"
+	operatorsv1alpha1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// File-based catalogs schemas
+	fbcPackageSchema = "olm.package"
+	fbcChannelSchema = "olm.channel"
+	fbcBundleSchema  = "olm.bundle"
+
+	// File-based catalogs bundle properties types
+	fbcPackageProperty     = "olm.package"
+	fbcBundleObjectProp    = "olm.bundle.object"
+	fbcCSVMetadataProperty = "olm.csv.metadata"
+)
+
+// fbcPackage represents an olm.package blob in a file-based catalog.
+type fbcPackage struct {
+	Name           string `json:"name"`
+	DefaultChannel string `json:"defaultChannel"`
+	Icon           *struct {
+		Data      string `json:"base64data"`
+		MediaType string `json:"mediatype"`
+	} `json:"icon"`
+}
+
+// fbcChannel represents an olm.channel blob in a file-based catalog.
+type fbcChannel struct {
+	Name    string `json:"name"`
+	Package string `json:"package"`
+	Entries []struct {
+		Name     string   `json:"name"`
+		Replaces string   `json:"replaces"`
+		Skips    []string `json:"skips"`
+	} `json:"entries"`
+}
+
+// fbcBundle represents an olm.bundle blob in a file-based catalog.
+type fbcBundle struct {
+	Name       string `json:"name"`
+	Package    string `json:"package"`
+	Image      string `json:"image"`
+	Properties []struct {
+		Type  string          `json:"type"`
+		Value json.RawMessage `json:"value"`
+	} `json:"properties"`
+	RelatedImages []*hub.ContainerImage `json:"relatedImages"`
+}
+
+// catalog represents the content of an OLM file-based catalog. For more
+// information please see:
+// https://olm.operatorframework.io/docs/reference/file-based-catalogs/
+type catalog struct {
+	packages map[string]*fbcPackage
+	channels map[string][]*fbcChannel
+	bundles  []*fbcBundle
+}
+
+// newCatalog creates a new catalog instance.
+func newCatalog() *catalog {
+	return &catalog{
+		packages: make(map[string]*fbcPackage),
+		channels: make(map[string][]*fbcChannel),
+	}
+}
+
+// loadDir loads the file-based catalog blobs available in the files located
+// in the directory provided (subdirectories are not processed).
+func (c *catalog) loadDir(path string) error {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return fmt.Errorf("error reading directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		switch filepath.Ext(entry.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if err := c.loadFile(filepath.Join(path, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadFile loads the file-based catalog blobs available in the file provided.
+// Files that do not contain any file-based catalog blob are ignored.
+func (c *catalog) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("error reading file %s: %w", path, err)
+	}
+	if !bytes.Contains(data, []byte("schema")) || !bytes.Contains(data, []byte("olm.")) {
+		return nil
+	}
+
+	dec := k8syaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var blob json.RawMessage
+		if err := dec.Decode(&blob); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("error decoding file %s: %w", path, err)
+		}
+		var meta struct {
+			Schema string `json:"schema"`
+		}
+		if err := json.Unmarshal(blob, &meta); err != nil {
+			continue
+		}
+		switch meta.Schema {
+		case fbcPackageSchema:
+			p := &fbcPackage{}
+			if err := json.Unmarshal(blob, p); err != nil {
+				return fmt.Errorf("invalid %s blob in file %s: %w", meta.Schema, path, err)
+			}
+			c.packages[p.Name] = p
+		case fbcChannelSchema:
+			ch := &fbcChannel{}
+			if err := json.Unmarshal(blob, ch); err != nil {
+				return fmt.Errorf("invalid %s blob in file %s: %w", meta.Schema, path, err)
+			}
+			c.channels[ch.Package] = append(c.channels[ch.Package], ch)
+		case fbcBundleSchema:
+			b := &fbcBundle{}
+			if err := json.Unmarshal(blob, b); err != nil {
+				return fmt.Errorf("invalid %s blob in file %s: %w", meta.Schema, path, err)
+			}
+			c.bundles = append(c.bundles, b)
+		}
+	}
+	return nil
+}
+
+// getMetadata returns the metadata of all the packages versions (bundles)
+// available in the catalog. Bundles that cannot be processed are skipped and
+// the corresponding error is returned.
+func (c *catalog) getMetadata() ([]*Metadata, []error) {
+	var mds []*Metadata
+	var errs []error
+
+	// Prepare bundles metadata (channels are set once all have been processed)
+	versions := make(map[string]map[string]string) // packageName:bundleName:version
+	for _, b := range c.bundles {
+		md, err := c.getBundleMetadata(b)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error processing bundle %s: %w", b.Name, err))
+			continue
+		}
+		if _, ok := versions[b.Package]; !ok {
+			versions[b.Package] = make(map[string]string)
+		}
+		versions[b.Package][b.Name] = md.Version
+		mds = append(mds, md)
+	}
+
+	// Set channels in bundles metadata
+	channels := make(map[string][]*hub.Channel)
+	for pkgName, pkgChannels := range c.channels {
+		for _, ch := range pkgChannels {
+			if version := getChannelHeadVersion(ch, versions[pkgName]); version != "" {
+				channels[pkgName] = append(channels[pkgName], &hub.Channel{
+					Name:    ch.Name,
+					Version: version,
+				})
+			}
+		}
+		sort.Slice(channels[pkgName], func(i, j int) bool {
+			return channels[pkgName][i].Name < channels[pkgName][j].Name
+		})
+	}
+	for _, md := range mds {
+		md.Channels = channels[md.Name]
+	}
+
+	return mds, errs
+}
+
+// getBundleMetadata returns the metadata of the bundle provided.
+func (c *catalog) getBundleMetadata(b *fbcBundle) (*Metadata, error) {
+	p, ok := c.packages[b.Package]
+	if !ok {
+		return nil, fmt.Errorf("package %s not found", b.Package)
+	}
+
+	// Get csv and version from bundle properties
+	var version string
+	var csv *operatorsv1alpha1.ClusterServiceVersion
+	var csvData []byte
+	for _, prop := range b.Properties {
+		switch prop.Type {
+		case fbcPackageProperty:
+			var value struct {
+				Version string `json:"version"`
+			}
+			if err := json.Unmarshal(prop.Value, &value); err != nil {
+				return nil, fmt.Errorf("invalid %s property: %w", prop.Type, err)
+			}
+			version = value.Version
+		case fbcBundleObjectProp:
+			if csv != nil {
+				continue
+			}
+			var value struct {
+				Data string `json:"data"`
+			}
+			if err := json.Unmarshal(prop.Value, &value); err != nil {
+				return nil, fmt.Errorf("invalid %s property: %w", prop.Type, err)
+			}
+			data, err := base64.StdEncoding.DecodeString(value.Data)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s property: %w", prop.Type, err)
+			}
+			var object struct {
+				Kind string `json:"kind"`
+			}
+			if err := yaml.Unmarshal(data, &object); err != nil || object.Kind != operatorsv1alpha1.ClusterServiceVersionKind {
+				continue
+			}
+			csv = &operatorsv1alpha1.ClusterServiceVersion{}
+			if err := yaml.Unmarshal(data, &csv); err != nil {
+				return nil, fmt.Errorf("error unmarshaling csv: %w", err)
+			}
+			csvData = data
+		}
+	}
+	if csv == nil {
+		var err error
+		csv, csvData, err = getCSVFromMetadataProperty(b, version)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if version == "" && csv.Spec.Version.Version.String() != "0.0.0" {
+		version = csv.Spec.Version.String()
+	}
+
+	// Use package icon when the csv does not provide one
+	if len(csv.Spec.Icon) == 0 && p.Icon != nil && p.Icon.Data != "" {
+		csv.Spec.Icon = []operatorsv1alpha1.Icon{{Data: p.Icon.Data, MediaType: p.Icon.MediaType}}
+	}
+
+	md := &Metadata{
+		Format:             fileBasedCatalog,
+		Name:               b.Package,
+		Version:            version,
+		DefaultChannelName: p.DefaultChannel,
+		CSV:                csv,
+		CSVData:            csvData,
+	}
+	if err := md.validate(); err != nil {
+		return nil, fmt.Errorf("error validating metadata: %w", err)
+	}
+	return md, nil
+}
+
+// getCSVFromMetadataProperty builds a cluster service version from the
+// olm.csv.metadata property of the bundle provided. The bundle related images
+// and the version provided are added to the cluster service version as well.
+func getCSVFromMetadataProperty(b *fbcBundle, version string) (*operatorsv1alpha1.ClusterServiceVersion, []byte, error) {
+	var spec map[string]interface{}
+	for _, prop := range b.Properties {
+		if prop.Type == fbcCSVMetadataProperty {
+			if err := json.Unmarshal(prop.Value, &spec); err != nil {
+				return nil, nil, fmt.Errorf("invalid %s property: %w", prop.Type, err)
+			}
+			break
+		}
+	}
+	if spec == nil {
+		return nil, nil, errors.New("csv not found")
+	}
+
+	metadata := map[string]interface{}{
+		"name":        b.Name,
+		"annotations": spec["annotations"],
+		"labels":      spec["labels"],
+	}
+	delete(spec, "annotations")
+	delete(spec, "labels")
+	if crdDescriptions, ok := spec["crdDescriptions"]; ok {
+		spec["customresourcedefinitions"] = crdDescriptions
+		delete(spec, "crdDescriptions")
+	}
+	var relatedImages []*hub.ContainerImage
+	for _, image := range b.RelatedImages {
+		if image.Image != "" && image.Image != b.Image {
+			relatedImages = append(relatedImages, image)
+		}
+	}
+	spec["relatedImages"] = relatedImages
+	if version != "" {
+		spec["version"] = version
+	}
+
+	csvData, _ := json.Marshal(map[string]interface{}{
+		"apiVersion": operatorsv1alpha1.ClusterServiceVersionAPIVersion,
+		"kind":       operatorsv1alpha1.ClusterServiceVersionKind,
+		"metadata":   metadata,
+		"spec":       spec,
+	})
+	csv := &operatorsv1alpha1.ClusterServiceVersion{}
+	if err := json.Unmarshal(csvData, &csv); err != nil {
+		return nil, nil, fmt.Errorf("invalid %s property: %w", fbcCSVMetadataProperty, err)
+	}
+	return csv, csvData, nil
+}
+
+// getChannelHeadVersion returns the version of the bundle at the head of the
+// channel provided (the one not replaced or skipped by any other entry). When
+// several candidates are found, the highest version is returned.
+func getChannelHeadVersion(ch *fbcChannel, versions map[string]string) string {
+	replaced := make(map[string]struct{})
+	for _, entry := range ch.Entries {
+		if entry.Replaces != "" {
+			replaced[entry.Replaces] = struct{}{}
+		}
+		for _, skip := range entry.Skips {
+			replaced[skip] = struct{}{}
+		}
+	}
+	var headVersion *semver.Version
+	for _, entry := range ch.Entries {
+		if _, ok := replaced[entry.Name]; ok {
+			continue
+		}
+		sv, err := semver.StrictNewVersion(versions[entry.Name])
+		if err != nil {
+			continue
+		}
+		if headVersion == nil || sv.GreaterThan(headVersion) {
+			headVersion = sv
+		}
+	}
+	if headVersion == nil {
+		return ""
+	}
+	return headVersion.String()
+}
@@ -24,8 +24,9 @@ import (
 )
 
 const (
-	bundle          = "bundle"
-	packageManifest = "packageManifest"
+	bundle           = "bundle"
+	fileBasedCatalog = "fileBasedCatalog"
+	packageManifest  = "packageManifest"
 
 	formatKey           = "format"
 	isGlobalOperatorKey = "isGlobalOperator"
@@ -62,6 +63,7 @@ func NewTrackerSource(i *hub.TrackerSourceInput) *TrackerSource {
 // GetPackagesAvailable implements the TrackerSource interface.
 func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error) {
 	packagesAvailable := make(map[string]*hub.Package)
+	fbc := newCatalog()
 
 	// Walk the path provided looking for available packages
 	err := filepath.Walk(s.i.BasePath, func(path string, info os.FileInfo, err error) error {
@@ -85,23 +87,14 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 			return nil
 		}
 		if md == nil {
-			// Not a package path
+			// Not a package path, but it may contain some file-based catalog
+			// blobs that will be processed once all paths have been visited
+			if err := fbc.loadDir(path); err != nil {
+				s.warn(fmt.Errorf("error loading file-based catalog: %w", err))
+			}
 			return nil
 		}
-
-		// Prepare and store package version
-		p, err := PreparePackage(s.i.Repository, md)
-		if err != nil {
-			s.warn(fmt.Errorf("error preparing package %s version %s: %w", md.Name, md.Version, err))
-			return nil
-		}
-		packagesAvailable[pkg.BuildKey(p)] = p
-		logoImageID, err := s.prepareLogoImage(md)
-		if err != nil {
-			s.warn(fmt.Errorf("error preparing package %s version %s logo image: %w", md.Name, md.Version, err))
-		} else {
-			p.LogoImageID = logoImageID
-		}
+		s.addPackage(packagesAvailable, md)
 
 		return nil
 	})
@@ -109,6 +102,15 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 		return nil, err
 	}
 
+	// Add packages defined in file-based catalogs
+	mds, errs := fbc.getMetadata()
+	for _, err := range errs {
+		s.warn(fmt.Errorf("error getting package metadata from file-based catalog: %w", err))
+	}
+	for _, md := range mds {
+		s.addPackage(packagesAvailable, md)
+	}
+
 	setPackagesChannels(packagesAvailable)
 	setPackagesDefaultChannel(packagesAvailable)
 	setPackagesDigest(packagesAvailable)
@@ -116,6 +118,23 @@ func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error)
 	return packagesAvailable, nil
 }
 
+// addPackage prepares a package version using the metadata provided and adds
+// it to the packages available, processing its logo image as well.
+func (s *TrackerSource) addPackage(packagesAvailable map[string]*hub.Package, md *Metadata) {
+	p, err := PreparePackage(s.i.Repository, md)
+	if err != nil {
+		s.warn(fmt.Errorf("error preparing package %s version %s: %w", md.Name, md.Version, err))
+		return
+	}
+	packagesAvailable[pkg.BuildKey(p)] = p
+	logoImageID, err := s.prepareLogoImage(md)
+	if err != nil {
+		s.warn(fmt.Errorf("error preparing package %s version %s logo image: %w", md.Name, md.Version, err))
+	} else {
+		p.LogoImageID = logoImageID
+	}
+}
+
 // prepareLogoImage processes and stores the logo image provided.
 func (s *TrackerSource) prepareLogoImage(md *Metadata) (string, error) {
 	var logoImageID string
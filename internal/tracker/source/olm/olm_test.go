@@ -259,4 +259,48 @@ func TestTrackerSource(t *testing.T) {
 		assert.NoError(t, err)
 		sw.AssertExpectations(t)
 	})
+
+	t.Run("two packages returned (file-based catalog format), no errors", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{},
+			BasePath:   "testdata/path6",
+			Svc:        sw.Svc,
+		}
+		sw.Is.On("SaveImage", sw.Svc.Ctx, imageData).Return("logoImageID", nil)
+
+		// Run test and check expectations
+		p1 := source.ClonePackage(basePkg)
+		p1.Repository = i.Repository
+		p1.LogoImageID = "logoImageID"
+		p1.Channels = []*hub.Channel{
+			{
+				Name:    "alpha",
+				Version: "0.2.0",
+			},
+			{
+				Name:    "stable",
+				Version: "0.1.0",
+			},
+		}
+		p1.DefaultChannel = "stable"
+		p1.Data = map[string]interface{}{
+			formatKey:           "fileBasedCatalog",
+			isGlobalOperatorKey: true,
+		}
+		p1.Digest = "16975040035987170544"
+		p2 := source.ClonePackage(p1)
+		p2.Version = "0.2.0"
+		p2.Digest = "740644373535153787"
+		packages, err := NewTrackerSource(i).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p1): p1,
+			pkg.BuildKey(p2): p2,
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+	})
 }
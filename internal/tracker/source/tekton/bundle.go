@@ -0,0 +1,133 @@
+package tekton
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/spf13/viper"
+)
+
+const (
+	// Annotations used in the layers of Tekton bundles to identify the
+	// resource they contain.
+	bundleAPIVersionAnnotation = "dev.tekton.image.apiVersion"
+	bundleKindAnnotation       = "dev.tekton.image.kind"
+	bundleNameAnnotation       = "dev.tekton.image.name"
+
+	// maxBundleResources represents the maximum number of resources (layers)
+	// a Tekton bundle can contain.
+	maxBundleResources = 20
+
+	// maxBundleResourceSize represents the maximum size of a resource
+	// contained in a Tekton bundle layer.
+	maxBundleResourceSize = 10 * 1024 * 1024
+)
+
+var (
+	// errInvalidBundle indicates that the Tekton bundle is not valid.
+	errInvalidBundle = errors.New("invalid tekton bundle")
+)
+
+// BundleLoader provides a mechanism to load Tekton bundles stored in OCI
+// registries.
+type BundleLoader struct {
+	cfg *viper.Viper
+}
+
+// NewBundleLoader creates a new BundleLoader instance.
+func NewBundleLoader(cfg *viper.Viper) *BundleLoader {
+	return &BundleLoader{cfg: cfg}
+}
+
+// LoadBundle loads the Tekton bundle identified by the tag provided from the
+// OCI repository given. For more information about the Tekton bundles format,
+// please see: https://tekton.dev/docs/pipelines/tekton-bundle-contracts/
+func (l *BundleLoader) LoadBundle(ctx context.Context, r *hub.Repository, tag string) (*hub.TektonBundle, error) {
+	// Get bundle image
+	ref, err := name.ParseReference(fmt.Sprintf("%s:%s", strings.TrimPrefix(r.URL, hub.RepositoryOCIPrefix), tag))
+	if err != nil {
+		return nil, fmt.Errorf("invalid reference: %w", err)
+	}
+	options := oci.PrepareRemoteOptions(ctx, l.cfg, ref, r.AuthUser, r.AuthPass)
+	img, err := remote.Image(ref, options...)
+	if err != nil {
+		return nil, fmt.Errorf("error getting bundle image: %w", err)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("error getting bundle image digest: %w", err)
+	}
+	manifest, err := img.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("error getting bundle image manifest: %w", err)
+	}
+	if len(manifest.Layers) > maxBundleResources {
+		return nil, fmt.Errorf("%w: too many resources (max: %d)", errInvalidBundle, maxBundleResources)
+	}
+
+	// Read bundle resources from image layers
+	bundle := &hub.TektonBundle{
+		Digest:      digest.String(),
+		Annotations: manifest.Annotations,
+	}
+	for _, desc := range manifest.Layers {
+		resource := &hub.TektonBundleResource{
+			APIVersion:  desc.Annotations[bundleAPIVersionAnnotation],
+			Kind:        desc.Annotations[bundleKindAnnotation],
+			Name:        desc.Annotations[bundleNameAnnotation],
+			Annotations: desc.Annotations,
+		}
+		if resource.Kind == "" || resource.Name == "" {
+			return nil, fmt.Errorf("%w: layer %s is missing the tekton annotations", errInvalidBundle, desc.Digest)
+		}
+		layer, err := img.LayerByDigest(desc.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("error getting layer %s: %w", desc.Digest, err)
+		}
+		resource.Data, err = readBundleLayer(layer)
+		if err != nil {
+			return nil, fmt.Errorf("error reading layer %s: %w", desc.Digest, err)
+		}
+		bundle.Resources = append(bundle.Resources, resource)
+	}
+
+	return bundle, nil
+}
+
+// readBundleLayer returns the content of the resource stored in the Tekton
+// bundle layer provided (a tarball containing a single file).
+func readBundleLayer(layer v1.Layer) ([]byte, error) {
+	rc, err := layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	tr := tar.NewReader(rc)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if hdr.Size > maxBundleResourceSize {
+			return nil, errors.New("resource too big")
+		}
+		return io.ReadAll(io.LimitReader(tr, maxBundleResourceSize))
+	}
+	return nil, errors.New("resource not found in layer")
+}
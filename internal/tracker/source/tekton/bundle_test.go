@@ -0,0 +1,63 @@
+package tekton
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBundleLayer(t *testing.T) {
+	t.Run("resource not found in layer", func(t *testing.T) {
+		t.Parallel()
+		layer := static.NewLayer(buildTar(t, nil), types.OCILayer)
+		data, err := readBundleLayer(layer)
+		assert.Nil(t, data)
+		assert.EqualError(t, err, "resource not found in layer")
+	})
+
+	t.Run("resource too big", func(t *testing.T) {
+		t.Parallel()
+		var buf bytes.Buffer
+		tw := tar.NewWriter(&buf)
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     "task1",
+			Typeflag: tar.TypeReg,
+			Size:     maxBundleResourceSize + 1,
+		}))
+		layer := static.NewLayer(buf.Bytes(), types.OCILayer)
+		data, err := readBundleLayer(layer)
+		assert.Nil(t, data)
+		assert.EqualError(t, err, "resource too big")
+	})
+
+	t.Run("resource read successfully", func(t *testing.T) {
+		t.Parallel()
+		layer := static.NewLayer(buildTar(t, map[string]string{"task1": "manifest"}), types.OCILayer)
+		data, err := readBundleLayer(layer)
+		require.NoError(t, err)
+		assert.Equal(t, []byte("manifest"), data)
+	})
+}
+
+func buildTar(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+		}))
+		_, err := tw.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tw.Close())
+	return buf.Bytes()
+}
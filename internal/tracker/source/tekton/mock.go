@@ -0,0 +1,20 @@
+package tekton
+
+import (
+	"context"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/stretchr/testify/mock"
+)
+
+// BundleLoaderMock is a mock hub.TektonBundleLoader implementation.
+type BundleLoaderMock struct {
+	mock.Mock
+}
+
+// LoadBundle implements the hub.TektonBundleLoader interface.
+func (m *BundleLoaderMock) LoadBundle(ctx context.Context, r *hub.Repository, tag string) (*hub.TektonBundle, error) {
+	args := m.Called(ctx, r, tag)
+	bundle, _ := args.Get(0).(*hub.TektonBundle)
+	return bundle, args.Error(1)
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -13,7 +15,9 @@ import (
 	"strings"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/artifacthub/hub/internal/httpw"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
 	"github.com/artifacthub/hub/internal/repo"
 	"github.com/artifacthub/hub/internal/tracker/source"
@@ -80,6 +84,14 @@ const (
 	screenshotsAnnotation     = "artifacthub.io/screenshots"
 	signatureAnnotation       = "tekton.dev/signature"
 
+	// readmeURLAnnotation defines the url of the package's readme file. It
+	// can be set in the Tekton bundle's layers or manifest annotations.
+	readmeURLAnnotation = "io.artifacthub.package.readme-url"
+
+	// maxReadmeSize represents the maximum size of the readme file fetched
+	// from the url set in the readme url annotation.
+	maxReadmeSize = 1024 * 1024
+
 	// examplesPath defines the location of the examples in the package's path.
 	examplesPath = "samples"
 
@@ -98,16 +110,33 @@ var (
 
 // TrackerSource is a hub.TrackerSource implementation for Tekton repositories.
 type TrackerSource struct {
-	i *hub.TrackerSourceInput
+	i  *hub.TrackerSourceInput
+	tg hub.OCITagsGetter
+	bl hub.TektonBundleLoader
 }
 
 // NewTrackerSource creates a new TrackerSource instance.
-func NewTrackerSource(i *hub.TrackerSourceInput) *TrackerSource {
-	return &TrackerSource{i}
+func NewTrackerSource(i *hub.TrackerSourceInput, opts ...func(s *TrackerSource)) *TrackerSource {
+	s := &TrackerSource{i: i}
+	for _, o := range opts {
+		o(s)
+	}
+	if s.tg == nil {
+		s.tg = oci.NewTagsGetter(i.Svc.Cfg)
+	}
+	if s.bl == nil {
+		s.bl = NewBundleLoader(i.Svc.Cfg)
+	}
+	return s
 }
 
 // GetPackagesAvailable implements the TrackerSource interface.
 func (s *TrackerSource) GetPackagesAvailable() (map[string]*hub.Package, error) {
+	// Tekton bundles stored in an OCI registry
+	if strings.HasPrefix(s.i.Repository.URL, hub.RepositoryOCIPrefix) {
+		return s.processBundles()
+	}
+
 	// Get repository's Tekton specific data
 	if s.i.Repository.Data == nil {
 		return nil, errors.New("required repository data field not provided")
@@ -192,6 +221,9 @@ func (s *TrackerSource) processDirBasedCatalog() (map[string]*hub.Package, error
 				s.warn(fmt.Errorf("error preparing package %s version %s: %w", pkgName, v.Name(), err))
 				continue
 			}
+			if err := source.EnrichPackageFromChangelogFile(p, pkgPath); err != nil {
+				s.warn(fmt.Errorf("error preparing package %s version %s changes: %w", pkgName, v.Name(), err))
+			}
 			packagesAvailable[pkg.BuildKey(p)] = p
 		}
 	}
@@ -261,6 +293,9 @@ func (s *TrackerSource) processGitBasedCatalog() (map[string]*hub.Package, error
 				s.warn(fmt.Errorf("error preparing package %s version %s: %w", pkgName, sv.String(), err))
 				continue
 			}
+			if err := source.EnrichPackageFromChangelogFile(p, pkgPath); err != nil {
+				s.warn(fmt.Errorf("error preparing package %s version %s changes: %w", pkgName, sv.String(), err))
+			}
 			packagesAvailable[pkg.BuildKey(p)] = p
 		}
 
@@ -270,6 +305,116 @@ func (s *TrackerSource) processGitBasedCatalog() (map[string]*hub.Package, error
 	return packagesAvailable, nil
 }
 
+// processBundles returns the packages available in the Tekton bundles stored
+// in the OCI repository. Each of the semver tags available in the repository
+// is expected to point to a bundle, which can contain several resources. Only
+// the resources matching the repository kind are processed.
+func (s *TrackerSource) processBundles() (map[string]*hub.Package, error) {
+	packagesAvailable := make(map[string]*hub.Package)
+
+	// Get tags available in the repository
+	tags, err := s.tg.Tags(s.i.Svc.Ctx, s.i.Repository, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repository available tags: %w", err)
+	}
+
+	// Process the bundle each tag points to
+	bypassDigestCheck := s.i.Svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	for _, tag := range tags {
+		// Return ASAP if context is cancelled
+		select {
+		case <-s.i.Svc.Ctx.Done():
+			return nil, s.i.Svc.Ctx.Err()
+		default:
+		}
+
+		sv, err := semver.NewVersion(tag)
+		if err != nil {
+			continue
+		}
+		bundle, err := s.bl.LoadBundle(s.i.Svc.Ctx, s.i.Repository, tag)
+		if err != nil {
+			s.warn(fmt.Errorf("error loading bundle (tag: %s): %w", tag, err))
+			continue
+		}
+		for _, resource := range bundle.Resources {
+			if !strings.EqualFold(resource.Kind, getTektonKind(s.i.Repository.Kind)) {
+				continue
+			}
+
+			// Get package manifest
+			manifest, err := parseManifest(s.i.Repository.Kind, resource.Data)
+			if err != nil {
+				s.warn(fmt.Errorf("error getting package manifest (tag: %s, resource: %s): %w", tag, resource.Name, err))
+				continue
+			}
+
+			// Prepare and store package version
+			p, err := PreparePackage(&PreparePackageInput{
+				R:           s.i.Repository,
+				Tag:         tag,
+				Manifest:    manifest,
+				ManifestRaw: resource.Data,
+				PkgName:     resource.Name,
+				PkgVersion:  sv.String(),
+				Bundle:      true,
+			})
+			if err != nil {
+				s.warn(fmt.Errorf("error preparing package %s version %s: %w", resource.Name, sv.String(), err))
+				continue
+			}
+
+			// Include readme file when the package version is not registered
+			// yet or it has changed
+			digest, ok := s.i.PackagesRegistered[pkg.BuildKey(p)]
+			if !ok || digest != p.Digest || bypassDigestCheck {
+				readmeURL := resource.Annotations[readmeURLAnnotation]
+				if readmeURL == "" {
+					readmeURL = bundle.Annotations[readmeURLAnnotation]
+				}
+				if readmeURL != "" {
+					readme, err := s.getReadme(readmeURL)
+					if err != nil {
+						s.warn(fmt.Errorf("error getting package %s version %s readme: %w", p.Name, p.Version, err))
+					} else {
+						p.Readme = string(readme)
+					}
+				}
+			}
+
+			packagesAvailable[pkg.BuildKey(p)] = p
+		}
+	}
+
+	return packagesAvailable, nil
+}
+
+// getReadme returns the content of the readme file located at the url
+// provided. Readme files larger than maxReadmeSize are rejected.
+func (s *TrackerSource) getReadme(u string) ([]byte, error) {
+	req, err := httpw.NewRequest("GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(s.i.Svc.Ctx)
+	resp, err := s.i.Svc.Hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code received: %d", resp.StatusCode)
+	}
+	readme, err := io.ReadAll(io.LimitReader(resp.Body, maxReadmeSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(readme) > maxReadmeSize {
+		return nil, errors.New("readme too large")
+	}
+	return readme, nil
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (s *TrackerSource) warn(err error) {
@@ -305,6 +450,16 @@ func GetManifest(kind hub.RepositoryKind, pkgName, pkgPath string) (interface{},
 	if err != nil {
 		return nil, nil, err
 	}
+	manifest, err := parseManifest(kind, manifestData)
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, manifestData, nil
+}
+
+// parseManifest parses and validates the manifest data provided, which can be
+// a Tekton task, pipeline or stepaction manifest.
+func parseManifest(kind hub.RepositoryKind, manifestData []byte) (interface{}, error) {
 	var manifest interface{}
 	switch kind {
 	case hub.TektonTask:
@@ -315,12 +470,25 @@ func GetManifest(kind hub.RepositoryKind, pkgName, pkgPath string) (interface{},
 		manifest = &v1alpha.StepAction{}
 	}
 	if err := yaml.Unmarshal(manifestData, &manifest); err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	if err := validateManifest(manifest); err != nil {
-		return nil, nil, fmt.Errorf("error validating manifest: %w", err)
+		return nil, fmt.Errorf("error validating manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// getTektonKind returns the Tekton resource kind of the packages available in
+// the repository kind provided.
+func getTektonKind(kind hub.RepositoryKind) string {
+	switch kind {
+	case hub.TektonPipeline:
+		return "pipeline"
+	case hub.TektonStepAction:
+		return "stepaction"
+	default:
+		return "task"
 	}
-	return manifest, manifestData, nil
 }
 
 // validateManifest checks if the Tekton manifest provided is valid.
@@ -371,6 +539,7 @@ type PreparePackageInput struct {
 	PkgName     string
 	PkgPath     string
 	PkgVersion  string
+	Bundle      bool
 }
 
 // PreparePackage prepares a package version using the package manifest and the
@@ -487,14 +656,19 @@ func PreparePackage(i *PreparePackageInput) (*hub.Package, error) {
 	}
 	p.ContainersImages = containerImages
 
-	// Include content and source links
-	contentURL, sourceURL := prepareContentAndSourceLinks(i)
-	p.ContentURL = contentURL
-	if sourceURL != "" {
-		p.Links = append(p.Links, &hub.Link{
-			Name: "source",
-			URL:  sourceURL,
-		})
+	// Include content and source links (the bundle reference is used as the
+	// content url for packages distributed as Tekton bundles)
+	if i.Bundle {
+		p.ContentURL = fmt.Sprintf("%s:%s", i.R.URL, i.Tag)
+	} else {
+		contentURL, sourceURL := prepareContentAndSourceLinks(i)
+		p.ContentURL = contentURL
+		if sourceURL != "" {
+			p.Links = append(p.Links, &hub.Link{
+				Name: "source",
+				URL:  sourceURL,
+			})
+		}
 	}
 
 	// Include supported platforms
@@ -507,21 +681,21 @@ func PreparePackage(i *PreparePackageInput) (*hub.Package, error) {
 		p.Data[PlatformsKey] = platforms
 	}
 
-	// Include readme file
-	readme, err := util.ReadRegularFile(filepath.Join(i.PkgPath, "README.md"))
-	if err == nil {
-		p.Readme = string(readme)
-	}
-
-	// Include examples files
-	examples, err := generic.GetFilesWithSuffix(".yaml", path.Join(i.PkgPath, examplesPath), nil)
-	if err != nil {
-		if !errors.Is(err, os.ErrNotExist) {
-			return nil, fmt.Errorf("error getting examples files: %w", err)
+	// Include readme and examples files (not available in Tekton bundles)
+	if !i.Bundle {
+		readme, err := util.ReadRegularFile(filepath.Join(i.PkgPath, "README.md"))
+		if err == nil {
+			p.Readme = string(readme)
 		}
-	} else {
-		if len(examples) > 0 {
-			p.Data[ExamplesKey] = examples
+		examples, err := generic.GetFilesWithSuffix(".yaml", path.Join(i.PkgPath, examplesPath), nil)
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				return nil, fmt.Errorf("error getting examples files: %w", err)
+			}
+		} else {
+			if len(examples) > 0 {
+				p.Data[ExamplesKey] = examples
+			}
 		}
 	}
 
@@ -534,36 +708,31 @@ func PreparePackage(i *PreparePackageInput) (*hub.Package, error) {
 }
 
 // prepareContentAndSourceLinks prepares the content and source urls for the
-// git provider identified from the host part in the repository url.
+// git provider identified from the host part in the repository git source.
 func prepareContentAndSourceLinks(i *PreparePackageInput) (string, string) {
-	// Parse repository url
-	var repoBaseURL, host, pkgsPath string
-	matches := repo.GitRepoURLRE.FindStringSubmatch(i.R.URL)
-	if len(matches) >= 3 {
-		repoBaseURL = matches[1]
-		host = matches[2]
-	}
-	if len(matches) == 4 {
-		pkgsPath = strings.TrimSuffix(matches[3], "/")
+	ws := repo.GetGitWebSource(i.R)
+	if ws == nil {
+		return "", ""
 	}
 
 	// Generate content and source url for the corresponding git provider
 	var contentURL, sourceURL string
-	branch := i.Tag
-	if branch == "" {
-		branch = repo.GetBranch(i.R)
+	ref := i.Tag
+	if ref == "" {
+		ref = ws.Ref
 	}
 	pkgRelativePath := strings.TrimPrefix(i.PkgPath, i.BasePath)
-	switch host {
+	filePath := strings.TrimPrefix(path.Join(ws.Subdir, pkgRelativePath, i.PkgName+".yaml"), "/")
+	switch ws.Host {
 	case "bitbucket.org":
-		contentURL = fmt.Sprintf("%s/raw/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
-		sourceURL = fmt.Sprintf("%s/src/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
+		contentURL = fmt.Sprintf("%s/raw/%s/%s", ws.BaseURL, ref, filePath)
+		sourceURL = fmt.Sprintf("%s/src/%s/%s", ws.BaseURL, ref, filePath)
 	case "github.com":
-		contentURL = fmt.Sprintf("%s/raw/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
-		sourceURL = fmt.Sprintf("%s/blob/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
+		contentURL = fmt.Sprintf("%s/raw/%s/%s", ws.BaseURL, ref, filePath)
+		sourceURL = fmt.Sprintf("%s/blob/%s/%s", ws.BaseURL, ref, filePath)
 	case "gitlab.com":
-		contentURL = fmt.Sprintf("%s/-/raw/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
-		sourceURL = fmt.Sprintf("%s/-/blob/%s/%s%s/%s.yaml", repoBaseURL, branch, pkgsPath, pkgRelativePath, i.PkgName)
+		contentURL = fmt.Sprintf("%s/-/raw/%s/%s", ws.BaseURL, ref, filePath)
+		sourceURL = fmt.Sprintf("%s/-/blob/%s/%s", ws.BaseURL, ref, filePath)
 	}
 
 	return contentURL, sourceURL
@@ -1,13 +1,21 @@
 package tekton
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 
+	"github.com/artifacthub/hub/internal/httpw"
 	"github.com/artifacthub/hub/internal/hub"
+	"github.com/artifacthub/hub/internal/oci"
 	"github.com/artifacthub/hub/internal/pkg"
+	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/tracker/source"
 	"github.com/stretchr/testify/assert"
 )
@@ -366,3 +374,324 @@ func TestTrackerSource(t *testing.T) {
 		sw.AssertExpectations(t)
 	})
 }
+
+func TestTrackerSourceBundles(t *testing.T) {
+	repoURL := "oci://registry.io/org/tasks"
+	taskManifest := []byte(`apiVersion: tekton.dev/v1
+kind: Task
+metadata:
+  name: task1
+  labels:
+    app.kubernetes.io/version: "0.1.0"
+  annotations:
+    tekton.dev/displayName: Task 1
+spec:
+  description: Test task
+  steps:
+    - name: step1
+      image: registry.io/image1:1.0.0
+`)
+	bundle := &hub.TektonBundle{
+		Digest: "sha256:bundle",
+		Annotations: map[string]string{
+			readmeURLAnnotation: "https://readme.url/README.md",
+		},
+		Resources: []*hub.TektonBundleResource{
+			{
+				APIVersion: "v1",
+				Kind:       "task",
+				Name:       "task1",
+				Data:       taskManifest,
+			},
+			{
+				APIVersion: "v1",
+				Kind:       "pipeline",
+				Name:       "pipeline1",
+				Data:       []byte("invalid"),
+			},
+		},
+	}
+
+	t.Run("error getting repository tags", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return(nil, tests.ErrFake)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Nil(t, packages)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+
+	t.Run("error loading bundle", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"0.1.0"}, nil)
+		bl.On("LoadBundle", sw.Svc.Ctx, i.Repository, "0.1.0").Return(nil, tests.ErrFake)
+		expectedErr := "error loading bundle (tag: 0.1.0): fake error for tests"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+
+	t.Run("version mismatch between tag and manifest", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"0.2.0"}, nil)
+		bl.On("LoadBundle", sw.Svc.Ctx, i.Repository, "0.2.0").Return(bundle, nil)
+		expectedErr := "error preparing package task1 version 0.2.0: version mismatch (0.1.0 != 0.2.0)"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+
+	t.Run("one package returned, readme fetched", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"0.1.0"}, nil)
+		bl.On("LoadBundle", sw.Svc.Ctx, i.Repository, "0.1.0").Return(bundle, nil)
+		req, _ := httpw.NewRequest("GET", "https://readme.url/README.md", nil)
+		sw.Hc.On("Do", req).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader("# Task 1")),
+			StatusCode: http.StatusOK,
+		}, nil)
+
+		// Run test and check expectations
+		p := &hub.Package{
+			Name:        "task1",
+			DisplayName: "Task 1",
+			Description: "Test task",
+			Keywords:    []string{"tekton", "task", ""},
+			Version:     "0.1.0",
+			Digest:      fmt.Sprintf("%x", sha256.Sum256(taskManifest)),
+			Readme:      "# Task 1",
+			ContentURL:  "oci://registry.io/org/tasks:0.1.0",
+			Repository:  i.Repository,
+			ContainersImages: []*hub.ContainerImage{
+				{
+					Image: "registry.io/image1:1.0.0",
+				},
+			},
+			Data: map[string]interface{}{
+				PipelinesMinVersionKey: "",
+				RawManifestKey:         string(taskManifest),
+				TasksKey:               []map[string]interface{}(nil),
+			},
+		}
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.Equal(t, map[string]*hub.Package{
+			pkg.BuildKey(p): p,
+		}, packages)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+
+	t.Run("one package returned, readme too large", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"0.1.0"}, nil)
+		bl.On("LoadBundle", sw.Svc.Ctx, i.Repository, "0.1.0").Return(bundle, nil)
+		req, _ := httpw.NewRequest("GET", "https://readme.url/README.md", nil)
+		sw.Hc.On("Do", req).Return(&http.Response{
+			Body:       io.NopCloser(strings.NewReader(strings.Repeat("#", maxReadmeSize+1))),
+			StatusCode: http.StatusOK,
+		}, nil)
+		expectedErr := "error getting package task1 version 0.1.0 readme: readme too large"
+		sw.Ec.On("Append", i.Repository.RepositoryID, expectedErr).Return()
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.Len(t, packages, 1)
+		assert.Empty(t, packages["task1@0.1.0"].Readme)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+
+	t.Run("one package returned, already registered so readme not fetched", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		sw := source.NewTestsServicesWrapper()
+		tg := &oci.TagsGetterMock{}
+		bl := &BundleLoaderMock{}
+		i := &hub.TrackerSourceInput{
+			Repository: &hub.Repository{
+				Kind: hub.TektonTask,
+				URL:  repoURL,
+			},
+			PackagesRegistered: map[string]string{
+				"task1@0.1.0": fmt.Sprintf("%x", sha256.Sum256(taskManifest)),
+			},
+			Svc: sw.Svc,
+		}
+		tg.On("Tags", sw.Svc.Ctx, i.Repository, true).Return([]string{"0.1.0"}, nil)
+		bl.On("LoadBundle", sw.Svc.Ctx, i.Repository, "0.1.0").Return(bundle, nil)
+
+		// Run test and check expectations
+		packages, err := NewTrackerSource(i, withOCITagsGetter(tg), withBundleLoader(bl)).GetPackagesAvailable()
+		assert.Len(t, packages, 1)
+		assert.Empty(t, packages["task1@0.1.0"].Readme)
+		assert.NoError(t, err)
+		sw.AssertExpectations(t)
+		tg.AssertExpectations(t)
+		bl.AssertExpectations(t)
+	})
+}
+
+func TestPrepareContentAndSourceLinks(t *testing.T) {
+	testCases := []struct {
+		desc               string
+		i                  *PreparePackageInput
+		expectedContentURL string
+		expectedSourceURL  string
+	}{
+		{
+			"repository url",
+			&PreparePackageInput{
+				R:        &hub.Repository{URL: "https://github.com/user/repo/path"},
+				BasePath: "/tmp/repo/path",
+				PkgName:  "task1",
+				PkgPath:  "/tmp/repo/path/task1/0.1",
+			},
+			"https://github.com/user/repo/raw/master/path/task1/0.1/task1.yaml",
+			"https://github.com/user/repo/blob/master/path/task1/0.1/task1.yaml",
+		},
+		{
+			"git url, git ref and git subdirectory",
+			&PreparePackageInput{
+				R: &hub.Repository{
+					URL:       "https://gitlab.com/group/subgroup/repo",
+					GitURL:    "https://gitlab.com/group/subgroup/repo.git",
+					GitRef:    "main",
+					GitSubdir: "path",
+				},
+				BasePath: "/tmp/repo/path",
+				PkgName:  "task1",
+				PkgPath:  "/tmp/repo/path/task1/0.1",
+			},
+			"https://gitlab.com/group/subgroup/repo/-/raw/main/path/task1/0.1/task1.yaml",
+			"https://gitlab.com/group/subgroup/repo/-/blob/main/path/task1/0.1/task1.yaml",
+		},
+		{
+			"git url, git based versioning",
+			&PreparePackageInput{
+				R: &hub.Repository{
+					GitURL: "git@bitbucket.org:user/repo.git",
+				},
+				Tag:      "v0.1.0",
+				BasePath: "/tmp/repo",
+				PkgName:  "task1",
+				PkgPath:  "/tmp/repo/task1",
+			},
+			"https://bitbucket.org/user/repo/raw/v0.1.0/task1/task1.yaml",
+			"https://bitbucket.org/user/repo/src/v0.1.0/task1/task1.yaml",
+		},
+		{
+			"unknown git host",
+			&PreparePackageInput{
+				R: &hub.Repository{
+					GitURL: "https://gitea.example.com/group/repo.git",
+				},
+				BasePath: "/tmp/repo",
+				PkgName:  "task1",
+				PkgPath:  "/tmp/repo/task1",
+			},
+			"",
+			"",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			t.Parallel()
+			contentURL, sourceURL := prepareContentAndSourceLinks(tc.i)
+			assert.Equal(t, tc.expectedContentURL, contentURL)
+			assert.Equal(t, tc.expectedSourceURL, sourceURL)
+		})
+	}
+}
+
+func withBundleLoader(bl hub.TektonBundleLoader) func(s *TrackerSource) {
+	return func(s *TrackerSource) {
+		s.bl = bl
+	}
+}
+
+func withOCITagsGetter(tg hub.OCITagsGetter) func(s *TrackerSource) {
+	return func(s *TrackerSource) {
+		s.tg = tg
+	}
+}
@@ -1,11 +1,13 @@
 package tracker
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/pkg"
@@ -19,6 +21,7 @@ type Tracker struct {
 	svc    *hub.TrackerServices
 	r      *hub.Repository
 	logger zerolog.Logger
+	rr     *runRecorder
 }
 
 // New creates a new Tracker instance.
@@ -30,31 +33,69 @@ func New(svc *hub.TrackerServices, r *hub.Repository, logger zerolog.Logger) *Tr
 	}
 }
 
-// Run initializes the tracking of the repository provided.
+// Run initializes the tracking of the repository provided. A record of the
+// run is registered once it's done, unless the repository has not changed
+// since the last time it was processed. When the repository tracking was
+// requested, the request is cleared once it has been tracked successfully.
 func (t *Tracker) Run() error {
+	t.rr = newRunRecorder(t.svc.Ec, t.r, time.Now().Unix())
+	err := t.track()
+	if !t.rr.skipped {
+		t.registerRun(err)
+	}
+	if err == nil && t.r.TrackingRequested {
+		err := t.svc.Rm.ClearTrackingRequest(context.Background(), t.r.RepositoryID, t.rr.run.StartedAt)
+		if err != nil {
+			t.logger.Warn().Err(fmt.Errorf("error clearing tracking request: %w", err)).Send()
+		}
+	}
+	return err
+}
+
+// track tracks the packages available in the repository, registering and
+// unregistering them as needed.
+func (t *Tracker) track() error {
 	// Check if repository has been updated since last time it was processed
 	remoteDigest, err := t.svc.Rm.GetRemoteDigest(t.svc.Ctx, t.r)
 	if err != nil {
 		return fmt.Errorf("error getting repository remote digest: %w", err)
 	}
-	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck")
+	t.rr.run.RemoteDigest = remoteDigest
+	bypassDigestCheck := t.svc.Cfg.GetBool("tracker.bypassDigestCheck") || t.r.TrackingRequested
 	if remoteDigest != "" && t.r.Digest == remoteDigest && !bypassDigestCheck {
+		t.rr.skipped = true
 		return nil
 	}
 
 	// Initialize logs for this repository in the errors collector
 	t.logger.Debug().Msg("tracking repository")
-	t.svc.Ec.Init(t.r.RepositoryID)
+	t.rr.Init(t.r.RepositoryID)
 
-	// Clone repository when applicable and get its metadata
-	tmpDir, packagesPath, err := t.cloneRepository()
-	if err != nil {
-		return fmt.Errorf("error cloning repository: %w", err)
+	// Clone repository when applicable and get its metadata. When the local
+	// copy of the repository cannot be kept in the cache, a full clone of the
+	// repository is done instead
+	var repoDir, packagesPath string
+	var changedPaths []string
+	synced := false
+	if t.svc.Cfg.GetBool("tracker.incrementalTracking") && repo.IsGitRepository(t.r) {
+		repoDir, packagesPath, changedPaths, err = t.syncRepository(bypassDigestCheck)
+		switch {
+		case err == nil:
+			synced = true
+		case !errors.Is(err, repo.ErrCacheDirNotAvailable):
+			return fmt.Errorf("error syncing repository: %w", err)
+		}
 	}
-	if tmpDir != "" {
-		defer os.RemoveAll(tmpDir)
+	if !synced {
+		repoDir, packagesPath, err = t.cloneRepository()
+		if err != nil {
+			return fmt.Errorf("error cloning repository: %w", err)
+		}
+		if repoDir != "" {
+			defer os.RemoveAll(repoDir)
+		}
 	}
-	basePath := filepath.Join(tmpDir, packagesPath)
+	basePath := filepath.Join(repoDir, packagesPath)
 	md, err := t.svc.Rm.GetMetadata(t.r, basePath)
 	if err != nil && !errors.Is(err, repo.ErrMetadataNotFound) {
 		t.warn(fmt.Errorf("error getting repository metadata: %w", err))
@@ -66,6 +107,16 @@ func (t *Tracker) Run() error {
 		return fmt.Errorf("error getting packages registered: %w", err)
 	}
 
+	// Load the packages registered relative paths when the repository can be
+	// processed incrementally
+	var packagesPaths map[string]string
+	if changedPaths != nil {
+		packagesPaths, err = t.svc.Rm.GetPackagesPaths(t.svc.Ctx, t.r.RepositoryID)
+		if err != nil {
+			return fmt.Errorf("error getting packages paths: %w", err)
+		}
+	}
+
 	// Get packages available in repository
 	i := &hub.TrackerSourceInput{
 		Repository:         t.r,
@@ -75,13 +126,15 @@ func (t *Tracker) Run() error {
 		Svc: &hub.TrackerSourceServices{
 			Ctx:    t.svc.Ctx,
 			Cfg:    t.svc.Cfg,
-			Ec:     t.svc.Ec,
+			Ec:     t.rr,
 			Hc:     t.svc.Hc,
 			Op:     t.svc.Op,
 			Is:     t.svc.Is,
 			Sc:     t.svc.Sc,
 			Logger: t.logger,
 		},
+		ChangedPaths:  changedPaths,
+		PackagesPaths: packagesPaths,
 	}
 	source := t.svc.SetupTrackerSource(i)
 	packagesAvailable, err := source.GetPackagesAvailable()
@@ -106,6 +159,7 @@ func (t *Tracker) Run() error {
 
 		// Check if this package should be ignored
 		if shouldIgnorePackage(md, p.Name, p.Version) {
+			t.rr.run.PackagesIgnored++
 			continue
 		}
 
@@ -113,6 +167,7 @@ func (t *Tracker) Run() error {
 		switch p.Category {
 		case hub.UnknownCategory:
 			p.Category = t.svc.Pcc.Predict(p)
+			p.CategoryPredicted = true
 		case hub.SkipCategoryPrediction:
 			p.Category = hub.UnknownCategory
 		}
@@ -121,6 +176,10 @@ func (t *Tracker) Run() error {
 		t.logger.Debug().Str("name", p.Name).Str("v", p.Version).Msg("registering package")
 		if err := t.svc.Pm.Register(t.svc.Ctx, p); err != nil {
 			t.warn(fmt.Errorf("error registering package %s version %s: %w", p.Name, p.Version, err))
+		} else if ok {
+			t.rr.run.PackagesUpdated++
+		} else {
+			t.rr.run.PackagesAdded++
 		}
 	}
 
@@ -146,6 +205,8 @@ func (t *Tracker) Run() error {
 				}
 				if err := t.svc.Pm.Unregister(t.svc.Ctx, p); err != nil {
 					t.warn(fmt.Errorf("error unregistering package %s version %s: %w", name, version, err))
+				} else {
+					t.rr.run.PackagesRemoved++
 				}
 			}
 		}
@@ -172,53 +233,69 @@ func (t *Tracker) cloneRepository() (string, string, error) {
 	var tmpDir, packagesPath string
 	var err error
 
-	switch t.r.Kind {
-	case
-		hub.Container,
-		hub.Helm,
-		hub.Kagent:
-		// These repositories are not cloned
-	case hub.OLM:
-		if strings.HasPrefix(t.r.URL, hub.RepositoryOCIPrefix) {
-			tmpDir, err = t.svc.Oe.ExportRepository(t.svc.Ctx, t.r)
-		} else {
-			tmpDir, packagesPath, err = t.svc.Rc.CloneRepository(t.svc.Ctx, t.r)
-		}
-	case
-		hub.ArgoTemplate,
-		hub.Backstage,
-		hub.Bootc,
-		hub.CoreDNS,
-		hub.Falco,
-		hub.Gatekeeper,
-		hub.Headlamp,
-		hub.HelmPlugin,
-		hub.InspektorGadget,
-		hub.KCL,
-		hub.KedaScaler,
-		hub.Keptn,
-		hub.KnativeClientPlugin,
-		hub.Krew,
-		hub.KubeArmor,
-		hub.Kubewarden,
-		hub.Kyverno,
-		hub.Meshery,
-		hub.OPA,
-		hub.OpenCost,
-		hub.Radius,
-		hub.TBAction,
-		hub.TektonPipeline,
-		hub.TektonTask,
-		hub.TektonStepAction:
+	switch {
+	case t.r.Kind == hub.OLM && strings.HasPrefix(t.r.URL, hub.RepositoryOCIPrefix):
+		tmpDir, err = t.svc.Oe.ExportRepository(t.svc.Ctx, t.r)
+	case repo.IsGitRepository(t.r):
 		tmpDir, packagesPath, err = t.svc.Rc.CloneRepository(t.svc.Ctx, t.r)
 	}
 
 	return tmpDir, packagesPath, err
 }
 
+// syncRepository updates the local copy of the git repository provided to the
+// tracker instance kept in the cache. In addition to the local copy path and
+// the path where the packages are located, it returns the paths (relative to
+// the packages path) of the files that have changed since the last time the
+// repository was processed. When a full scan of the repository is required,
+// nil changed paths are returned.
+func (t *Tracker) syncRepository(bypassDigestCheck bool) (string, string, []string, error) {
+	repoDir, packagesPath, changedPaths, err := t.svc.Rc.SyncRepository(t.svc.Ctx, t.r, t.r.Digest)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	// A full scan is required when the changes are not available, when it has
+	// been explicitly requested or when the last tracking produced errors, as
+	// some packages may need to be processed again
+	if changedPaths == nil || bypassDigestCheck || t.r.LastTrackingErrors != "" {
+		return repoDir, packagesPath, nil, nil
+	}
+
+	// Keep only the changes in the packages path. Changes to the repository
+	// metadata file (i.e. ignore entries) also require a full scan
+	pkgsChangedPaths := make([]string, 0, len(changedPaths))
+	for _, p := range changedPaths {
+		if packagesPath != "" {
+			if !strings.HasPrefix(p, packagesPath+"/") {
+				continue
+			}
+			p = strings.TrimPrefix(p, packagesPath+"/")
+		}
+		if strings.HasPrefix(p, hub.RepositoryMetadataFile+".") {
+			return repoDir, packagesPath, nil, nil
+		}
+		pkgsChangedPaths = append(pkgsChangedPaths, p)
+	}
+
+	return repoDir, packagesPath, pkgsChangedPaths, nil
+}
+
+// registerRun registers the record of the tracking run, including the error
+// that made it fail if any.
+func (t *Tracker) registerRun(err error) {
+	if err != nil {
+		t.rr.recordError(err.Error())
+	}
+	t.rr.run.FinishedAt = time.Now().Unix()
+	if err := t.svc.Rm.RegisterTrackingRun(context.Background(), t.rr.run); err != nil {
+		t.logger.Warn().Err(fmt.Errorf("error registering tracking run: %w", err)).Send()
+	}
+}
+
 // warn is a helper that sends the error provided to the errors collector and
 // logs it as a warning.
 func (t *Tracker) warn(err error) {
 	t.logger.Warn().Err(err).Send()
-	t.svc.Ec.Append(t.r.RepositoryID, err.Error())
+	t.rr.Append(t.r.RepositoryID, err.Error())
 }
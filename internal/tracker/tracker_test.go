@@ -16,6 +16,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestTracker(t *testing.T) {
@@ -52,6 +53,9 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r, zerolog.Nop()).Run()
 		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Equal(t, []*hub.TrackingRunError{
+			{Message: "error getting repository remote digest: fake error for tests"},
+		}, sw.run.Errors)
 		sw.assertExpectations(t)
 	})
 
@@ -68,6 +72,7 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Nil(t, sw.run)
 		sw.assertExpectations(t)
 	})
 
@@ -92,6 +97,27 @@ func TestTracker(t *testing.T) {
 		sw.assertExpectations(t)
 	})
 
+	t.Run("repository has not been updated, but its tracking was requested", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup services and expectations
+		r := &hub.Repository{
+			RepositoryID:      "repo1",
+			Digest:            "digest",
+			TrackingRequested: true,
+		}
+		sw := newServicesWrapper()
+		sw.rm.On("GetRemoteDigest", sw.svc.Ctx, r).Return(r.Digest, nil)
+		sw.ec.On("Init", r.RepositoryID)
+		sw.rm.On("GetMetadata", r, "").Return(nil, nil)
+		sw.rm.On("GetPackagesDigest", sw.svc.Ctx, r.RepositoryID).Return(nil, tests.ErrFake)
+
+		// Run test and check expectations
+		err := New(sw.svc, r, zerolog.Nop()).Run()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		sw.assertExpectations(t)
+	})
+
 	t.Run("error cloning or exporting repository", func(t *testing.T) {
 		repositories := []*hub.Repository{
 			{
@@ -207,6 +233,10 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 0, sw.run.PackagesAdded)
+		assert.Equal(t, []*hub.TrackingRunError{
+			{PackageName: "pkg1", PackageVersion: "1.0.0", Message: expectedErr},
+		}, sw.run.Errors)
 		sw.assertExpectations(t)
 	})
 
@@ -276,6 +306,7 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 1, sw.run.PackagesIgnored)
 		sw.assertExpectations(t)
 	})
 
@@ -298,6 +329,11 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, r1.RepositoryID, sw.run.RepositoryID)
+		assert.Equal(t, 1, sw.run.PackagesAdded)
+		assert.Equal(t, 0, sw.run.PackagesUpdated)
+		assert.Empty(t, sw.run.Errors)
+		assert.True(t, p.CategoryPredicted)
 		sw.assertExpectations(t)
 	})
 
@@ -324,6 +360,8 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 0, sw.run.PackagesAdded)
+		assert.Equal(t, 1, sw.run.PackagesUpdated)
 		sw.assertExpectations(t)
 	})
 
@@ -418,6 +456,7 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, 1, sw.run.PackagesRemoved)
 		sw.assertExpectations(t)
 	})
 
@@ -489,8 +528,144 @@ func TestTracker(t *testing.T) {
 		// Run test and check expectations
 		err := New(sw.svc, r1, zerolog.Nop()).Run()
 		assert.Nil(t, err)
+		assert.Equal(t, "digest", sw.run.RemoteDigest)
+		sw.assertExpectations(t)
+	})
+
+	t.Run("incremental tracking: error syncing repository", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			RepositoryID: "repo2",
+			Kind:         hub.OPA,
+			URL:          "https://github.com/org1/repo1/pkgs",
+			Digest:       "commit1",
+		}
+
+		// Setup services and expectations
+		sw := newServicesWrapper()
+		sw.svc.Cfg.Set("tracker.incrementalTracking", true)
+		sw.rm.On("GetRemoteDigest", sw.svc.Ctx, r).Return("commit2", nil)
+		sw.ec.On("Init", r.RepositoryID)
+		sw.rc.On("SyncRepository", sw.svc.Ctx, r, "commit1").Return("", "", nil, tests.ErrFake)
+
+		// Run test and check expectations
+		err := New(sw.svc, r, zerolog.Nop()).Run()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		sw.assertExpectations(t)
+	})
+
+	t.Run("incremental tracking: cache dir not available, repository cloned instead", func(t *testing.T) {
+		t.Parallel()
+		r := &hub.Repository{
+			RepositoryID: "repo2",
+			Kind:         hub.OPA,
+			URL:          "https://github.com/org1/repo1/pkgs",
+			Digest:       "commit1",
+		}
+
+		// Setup services and expectations
+		sw := newServicesWrapper()
+		sw.svc.Cfg.Set("tracker.incrementalTracking", true)
+		sw.rm.On("GetRemoteDigest", sw.svc.Ctx, r).Return("commit2", nil)
+		sw.ec.On("Init", r.RepositoryID)
+		sw.rc.On("SyncRepository", sw.svc.Ctx, r, "commit1").Return("", "", nil, repo.ErrCacheDirNotAvailable)
+		sw.rc.On("CloneRepository", sw.svc.Ctx, r).Return("", "", tests.ErrFake)
+
+		// Run test and check expectations
+		err := New(sw.svc, r, zerolog.Nop()).Run()
+		assert.True(t, errors.Is(err, tests.ErrFake))
+		assert.Contains(t, err.Error(), "error cloning repository")
 		sw.assertExpectations(t)
 	})
+
+	t.Run("incremental tracking: changes provided to source when available", func(t *testing.T) {
+		packagesPaths := map[string]string{
+			"pkg1@1.0.0": "/pkg1",
+		}
+		testCases := []struct {
+			desc                 string
+			r                    *hub.Repository
+			changedPaths         []string
+			expectedChangedPaths []string
+		}{
+			{
+				"changes not available",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1"},
+				nil,
+				nil,
+			},
+			{
+				"last tracking produced errors",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1", LastTrackingErrors: "error"},
+				[]string{"pkgs/pkg1/policy.rego"},
+				nil,
+			},
+			{
+				"tracking requested",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1", TrackingRequested: true},
+				[]string{"pkgs/pkg1/policy.rego"},
+				nil,
+			},
+			{
+				"repository metadata file changed",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1"},
+				[]string{"pkgs/pkg1/policy.rego", "pkgs/artifacthub-repo.yml"},
+				nil,
+			},
+			{
+				"only changes in packages path are provided",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1"},
+				[]string{"pkgs/pkg1/policy.rego", "other/artifacthub-repo.yml", "README.md"},
+				[]string{"pkg1/policy.rego"},
+			},
+			{
+				"no changes in packages path",
+				&hub.Repository{Kind: hub.OPA, Digest: "commit1"},
+				[]string{"README.md"},
+				[]string{},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.desc, func(t *testing.T) {
+				t.Parallel()
+
+				// Setup services and expectations
+				sw := newServicesWrapper()
+				sw.svc.Cfg.Set("tracker.incrementalTracking", true)
+				var input *hub.TrackerSourceInput
+				sw.svc.SetupTrackerSource = func(i *hub.TrackerSourceInput) hub.TrackerSource {
+					input = i
+					return sw.src
+				}
+				sw.rm.On("GetRemoteDigest", sw.svc.Ctx, tc.r).Return("commit2", nil)
+				sw.ec.On("Init", tc.r.RepositoryID)
+				sw.rc.On("SyncRepository", sw.svc.Ctx, tc.r, "commit1").Return("/repo", "pkgs", tc.changedPaths, nil)
+				sw.rm.On("GetMetadata", tc.r, "/repo/pkgs").Return(nil, nil)
+				sw.rm.On("GetPackagesDigest", sw.svc.Ctx, tc.r.RepositoryID).Return(nil, nil)
+				if tc.expectedChangedPaths != nil {
+					sw.rm.On("GetPackagesPaths", sw.svc.Ctx, tc.r.RepositoryID).Return(packagesPaths, nil)
+				}
+				sw.src.On("GetPackagesAvailable").Return(map[string]*hub.Package{}, nil)
+				sw.rm.On("UpdateDigest", sw.svc.Ctx, tc.r.RepositoryID, "commit2").Return(nil)
+				if tc.r.TrackingRequested {
+					sw.rm.On("ClearTrackingRequest", context.Background(), tc.r.RepositoryID, mock.Anything).
+						Return(nil)
+				}
+
+				// Run test and check expectations
+				err := New(sw.svc, tc.r, zerolog.Nop()).Run()
+				assert.Nil(t, err)
+				assert.Equal(t, "/repo/pkgs", input.BasePath)
+				assert.Equal(t, tc.expectedChangedPaths, input.ChangedPaths)
+				if tc.expectedChangedPaths != nil {
+					assert.Equal(t, packagesPaths, input.PackagesPaths)
+				} else {
+					assert.Nil(t, input.PackagesPaths)
+				}
+				sw.assertExpectations(t)
+			})
+		}
+	})
 }
 
 type servicesWrapper struct {
@@ -504,6 +679,7 @@ type servicesWrapper struct {
 	pcc *PackageCategoryClassifierMock
 	src *source.Mock
 	svc *hub.TrackerServices
+	run *hub.TrackingRun
 }
 
 func newServicesWrapper() *servicesWrapper {
@@ -536,7 +712,7 @@ func newServicesWrapper() *servicesWrapper {
 	}
 
 	// Setup services wrapper and return it
-	return &servicesWrapper{
+	sw := &servicesWrapper{
 		rm:  rm,
 		pm:  pm,
 		rc:  rc,
@@ -548,6 +724,13 @@ func newServicesWrapper() *servicesWrapper {
 		src: src,
 		svc: svc,
 	}
+
+	// Keep track of the tracking run registered, if any
+	rm.On("RegisterTrackingRun", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sw.run = args.Get(1).(*hub.TrackingRun)
+	}).Return(nil).Maybe()
+
+	return sw
 }
 
 func (sw *servicesWrapper) assertExpectations(t *testing.T) {
@@ -15,12 +15,14 @@ import (
 
 	_ "embed" // Used by templates
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
 	"github.com/jackc/pgx/v4"
 	"github.com/pquerna/otp"
 	"github.com/pquerna/otp/totp"
+	"github.com/rs/zerolog/log"
 	"github.com/satori/uuid"
 	"github.com/spf13/viper"
 	pwvalidator "github.com/wagslane/go-password-validator"
@@ -31,23 +33,29 @@ const (
 	// Database queries
 	approveSessionDBQ            = `select approve_session($1::text, $2::text)`
 	checkUserAliasAvailDBQ       = `select user_id from "user" where alias = $1::text`
-	checkUserCredsDBQ            = `select user_id, password from "user" where email = $1 and password is not null and email_verified = true` //#nosec
+	checkUserCredsDBQ            = `select u.user_id, u.password, coalesce(l.locked_until > current_timestamp, false) from "user" u left join login_lockout l on l.user_id = u.user_id and l.client = $2 where u.email = $1 and u.password is not null and u.email_verified = true` //#nosec
 	deleteSessionDBQ             = `delete from session where session_id = $1`
 	deleteUserDBQ                = `select delete_user($1::uuid, $2::text)`
 	disableTFADBQ                = `update "user" set tfa_enabled = false, tfa_url = null, tfa_recovery_codes = null where user_id = $1 and tfa_enabled = true`
 	enableTFADBQ                 = `update "user" set tfa_enabled = true where user_id = $1`
 	getSessionDBQ                = `select user_id, floor(extract(epoch from created_at)), approved from session where session_id = $1`
 	getTFAConfigDBQ              = `select get_user_tfa_config($1::uuid)`
+	getUserAuditLogDBQ           = `select * from get_user_audit_log($1::uuid, $2::jsonb)`
 	getUserEmailDBQ              = `select email from "user" where user_id = $1`
 	getUserIDFromEmailDBQ        = `select user_id from "user" where email = $1`
-	getUserIDFromSessionIDDBQ    = `select user_id from session where session_id = $1`
+	getUserIDFromLDAPDNDBQ       = `select user_id from "user" where ldap_dn = $1`
+	getSessionUserDBQ            = `select s.user_id, coalesce(host(s.ip), ''), coalesce(l.locked_until > current_timestamp, false) from session s left join login_lockout l on l.user_id = s.user_id and l.client = coalesce(host(s.ip), '') where s.session_id = $1`
 	getUserPasswordDBQ           = `select password from "user" where user_id = $1 and password is not null`
 	getUserProfileDBQ            = `select get_user_profile($1::uuid)`
+	linkLDAPAccountDBQ           = `update "user" set ldap_dn = $2 where user_id = $1`
 	registerPasswordResetCodeDBQ = `select register_password_reset_code($1::text, $2::text)`
 	registerSessionDBQ           = `select register_session($1::jsonb)`
 	registerUserDBQ              = `select register_user($1::jsonb)`
 	registerDeleteUserCodeDBQ    = `select register_delete_user_code($1::uuid, $2::text)`
+	registerFailedLoginDBQ       = `select register_failed_login_attempt($1::uuid, $2::text, $3::integer, $4::integer)`
+	resetFailedLoginsDBQ         = `delete from login_lockout where user_id = $1 and client = $2`
 	resetUserPasswordDBQ         = `select reset_user_password($1::text, $2::text)`
+	syncUserOrgsMembershipsDBQ   = `select sync_user_organizations_memberships($1::uuid, $2::jsonb)`
 	updateTFAInfoDBQ             = `update "user" set tfa_url = $2, tfa_recovery_codes = $3 where user_id = $1`
 	updateUserPasswordDBQ        = `select update_user_password($1::uuid, $2::text, $3::text)`
 	updateUserProfileDBQ         = `select update_user_profile($1::uuid, $2::jsonb)`
@@ -66,7 +74,8 @@ const (
 type templateID int
 
 const (
-	confirmUserDeletionEmail templateID = iota
+	accountLockedEmail templateID = iota
+	confirmUserDeletionEmail
 	passwordResetEmail
 	passwordResetSuccessEmail
 	tfaDisabledEmail
@@ -76,6 +85,9 @@ const (
 )
 
 var (
+	//go:embed template/account_locked_email.tmpl
+	accountLockedEmailTmpl string
+
 	//go:embed template/confirm_user_deletion_email.tmpl
 	confirmUserDeletionEmailTmpl string
 
@@ -118,6 +130,14 @@ var (
 	// database when the password reset code is not valid.
 	errInvalidPasswordResetCodeDB = errors.New("ERROR: invalid password reset code (SQLSTATE P0001)")
 
+	// errLDAPAccountAlreadyLinked indicates that the LDAP account provided is
+	// already linked to a different user.
+	errLDAPAccountAlreadyLinked = fmt.Errorf("%w: %s", hub.ErrInvalidInput, "ldap account already linked to a different user")
+
+	// errLDAPAccountAlreadyLinkedDB represents the error returned from the
+	// database when the LDAP account is already linked to a different user.
+	errLDAPAccountAlreadyLinkedDB = errors.New(`ERROR: duplicate key value violates unique constraint "user_ldap_dn_key" (SQLSTATE 23505)`)
+
 	// errInvalidTFAPasscode indicates that the TFA passcode provided is not
 	// valid.
 	errInvalidTFAPasscode = fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid passcode")
@@ -141,6 +161,7 @@ func NewManager(cfg *viper.Viper, db hub.DB, es hub.EmailSender) *Manager {
 		db:  db,
 		es:  es,
 		tmpl: map[templateID]*template.Template{
+			accountLockedEmail:        template.Must(template.New("").Parse(email.BaseTmpl + accountLockedEmailTmpl)),
 			confirmUserDeletionEmail:  template.Must(template.New("").Parse(email.BaseTmpl + confirmUserDeletionEmailTmpl)),
 			passwordResetEmail:        template.Must(template.New("").Parse(email.BaseTmpl + passwordResetEmailTmpl)),
 			passwordResetSuccessEmail: template.Must(template.New("").Parse(email.BaseTmpl + passwordResetSuccessEmailTmpl)),
@@ -162,12 +183,18 @@ func (m *Manager) ApproveSession(ctx context.Context, sessionID, passcode string
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "passcode not provided")
 	}
 
-	// Get id of the user the session belongs to
-	var userID string
-	err := m.db.QueryRow(ctx, getUserIDFromSessionIDDBQ, hash(sessionID)).Scan(&userID)
+	// Get id of the user the session belongs to and the client that created
+	// it. Sessions created from clients for which the account is locked cannot
+	// be approved.
+	var userID, client string
+	var locked bool
+	err := m.db.QueryRow(ctx, getSessionUserDBQ, hash(sessionID)).Scan(&userID, &client, &locked)
 	if err != nil {
 		return err
 	}
+	if locked {
+		return errInvalidTFAPasscode
+	}
 
 	// Get TFA config from database
 	var c *hub.TFAConfig
@@ -182,6 +209,9 @@ func (m *Manager) ApproveSession(ctx context.Context, sessionID, passcode string
 	}
 	validRecoveryCodeProvided := isValidRecoveryCode(c.RecoveryCodes, passcode)
 	if !totp.Validate(passcode, key.Secret()) && !validRecoveryCodeProvided {
+		if err := m.registerFailedLoginAttempt(ctx, userID, client); err != nil {
+			return err
+		}
 		return errInvalidTFAPasscode
 	}
 
@@ -229,11 +259,14 @@ func (m *Manager) CheckAvailability(ctx context.Context, resourceKind, value str
 	return available, err
 }
 
-// CheckCredentials checks if the credentials provided are valid.
+// CheckCredentials checks if the credentials provided are valid. When the
+// account has been temporarily locked for the client provided after too many
+// failed login attempts, the credentials are considered invalid.
 func (m *Manager) CheckCredentials(
 	ctx context.Context,
 	email,
-	password string,
+	password,
+	client string,
 ) (*hub.CheckCredentialsOutput, error) {
 	// Validate input
 	if email == "" {
@@ -245,7 +278,8 @@ func (m *Manager) CheckCredentials(
 
 	// Get password for email provided from database
 	var userID, hashedPassword string
-	err := m.db.QueryRow(ctx, checkUserCredsDBQ, email).Scan(&userID, &hashedPassword)
+	var locked bool
+	err := m.db.QueryRow(ctx, checkUserCredsDBQ, email, client).Scan(&userID, &hashedPassword, &locked)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return &hub.CheckCredentialsOutput{Valid: false}, nil
@@ -253,16 +287,34 @@ func (m *Manager) CheckCredentials(
 		return nil, err
 	}
 
-	// Check if the password provided is valid
+	// Check if the password provided is valid. This is done even when the
+	// account is locked, so that locked accounts cannot be told apart from
+	// the response time.
 	err = bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+
+	// Check if the account has been temporarily locked for this client
+	if locked {
+		return &hub.CheckCredentialsOutput{Valid: false}, nil
+	}
+
 	if err != nil {
+		if err := m.registerFailedLoginAttempt(ctx, userID, client); err != nil {
+			return nil, err
+		}
 		return &hub.CheckCredentialsOutput{Valid: false}, nil
 	}
 
+	// Reset the failed login attempts counter
+	if m.cfg.GetInt("server.accountLockout.maxFailedAttempts") > 0 {
+		if _, err := m.db.Exec(ctx, resetFailedLoginsDBQ, userID, client); err != nil {
+			return nil, err
+		}
+	}
+
 	return &hub.CheckCredentialsOutput{
 		Valid:  true,
 		UserID: userID,
-	}, err
+	}, nil
 }
 
 // CheckSession checks if the user session provided is valid.
@@ -377,8 +429,17 @@ func (m *Manager) DisableTFA(ctx context.Context, passcode string) error {
 		return errInvalidTFAPasscode
 	}
 
-	// Set TFA as disabled in the database
-	if _, err := m.db.Exec(ctx, disableTFADBQ, userID); err != nil {
+	// Set TFA as disabled in the database and register audit event
+	err = util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, disableTFADBQ, userID); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionTFADisabled,
+			UserID: userID,
+		})
+	})
+	if err != nil {
 		return err
 	}
 
@@ -430,8 +491,17 @@ func (m *Manager) EnableTFA(ctx context.Context, passcode string) error {
 		return errInvalidTFAPasscode
 	}
 
-	// Set TFA as enabled in the database
-	if _, err := m.db.Exec(ctx, enableTFADBQ, userID); err != nil {
+	// Set TFA as enabled in the database and register audit event
+	err = util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, enableTFADBQ, userID); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionTFAEnabled,
+			UserID: userID,
+		})
+	})
+	if err != nil {
 		return err
 	}
 
@@ -458,6 +528,21 @@ func (m *Manager) EnableTFA(ctx context.Context, passcode string) error {
 	return nil
 }
 
+// GetAuditLogJSON returns the audit log entries of the user doing the request
+// that match the input provided as a json object.
+func (m *Manager) GetAuditLogJSON(ctx context.Context, input *hub.GetAuditLogInput) (*hub.JSONQueryResult, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if err := audit.ValidateGetLogInput(input); err != nil {
+		return nil, err
+	}
+
+	// Get audit log entries from database
+	inputJSON, _ := json.Marshal(input)
+	return util.DBQueryJSONWithPagination(ctx, m.db, getUserAuditLogDBQ, userID, inputJSON)
+}
+
 // GetProfile returns the profile of the user doing the request.
 func (m *Manager) GetProfile(ctx context.Context) (*hub.User, error) {
 	dataJSON, err := m.GetProfileJSON(ctx)
@@ -499,6 +584,44 @@ func (m *Manager) GetUserID(ctx context.Context, email string) (string, error) {
 	return userID, nil
 }
 
+// GetUserIDFromLDAPDN returns the id of the user linked to the LDAP entry
+// with the DN provided.
+func (m *Manager) GetUserIDFromLDAPDN(ctx context.Context, dn string) (string, error) {
+	// Validate input
+	if dn == "" {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "dn not provided")
+	}
+
+	// Get user id from database
+	var userID string
+	err := m.db.QueryRow(ctx, getUserIDFromLDAPDNDBQ, dn).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return userID, nil
+}
+
+// LinkLDAPAccount links the user doing the request to the LDAP entry with the
+// DN provided, allowing him to log in using his LDAP credentials.
+func (m *Manager) LinkLDAPAccount(ctx context.Context, dn string) error {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if dn == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "dn not provided")
+	}
+
+	// Link ldap account in database
+	_, err := m.db.Exec(ctx, linkLDAPAccountDBQ, userID, dn)
+	if err != nil && err.Error() == errLDAPAccountAlreadyLinkedDB.Error() {
+		return errLDAPAccountAlreadyLinked
+	}
+	return err
+}
+
 // RegisterDeleteUserCode registers a code that allows the user doing the
 // request to initiate the process to delete his account. A link containing the
 // code will be emailed to the user.
@@ -604,7 +727,19 @@ func (m *Manager) RegisterSession(ctx context.Context, session *hub.Session) (*h
 	session.SessionID = sessionIDHashed
 	sessionJSON, _ := json.Marshal(session)
 	var approved bool
-	err := m.db.QueryRow(ctx, registerSessionDBQ, sessionJSON).Scan(&approved)
+	err := util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if err := tx.QueryRow(ctx, registerSessionDBQ, sessionJSON).Scan(&approved); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionLogin,
+			UserID: session.UserID,
+			Details: map[string]string{
+				"ip":         session.IP,
+				"user_agent": session.UserAgent,
+			},
+		})
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -697,9 +832,22 @@ func (m *Manager) ResetPassword(ctx context.Context, code, newPassword string) e
 		return err
 	}
 
-	// Reset user password in database
+	// Reset user password in database and register audit event
 	var userEmail string
-	err = m.db.QueryRow(ctx, resetUserPasswordDBQ, hash(code), string(newHashed)).Scan(&userEmail)
+	err = util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, resetUserPasswordDBQ, hash(code), string(newHashed)).Scan(&userEmail)
+		if err != nil {
+			return err
+		}
+		var userID string
+		if err := tx.QueryRow(ctx, getUserIDFromEmailDBQ, userEmail).Scan(&userID); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionPasswordReset,
+			UserID: userID,
+		})
+	})
 	if err != nil {
 		if err.Error() == errInvalidPasswordResetCodeDB.Error() {
 			return ErrInvalidPasswordResetCode
@@ -781,6 +929,37 @@ func (m *Manager) SetupTFA(ctx context.Context) ([]byte, error) {
 	return json.Marshal(output)
 }
 
+// SyncOrganizationsMemberships synchronizes the memberships of the user
+// provided to the organizations listed, as well as the roles the user has on
+// them. This is used to keep organizations memberships in sync with the groups
+// the user belongs to in an external identity provider.
+func (m *Manager) SyncOrganizationsMemberships(
+	ctx context.Context,
+	userID string,
+	memberships []*hub.OrganizationMembershipSync,
+) error {
+	// Validate input
+	if userID == "" {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "user id not provided")
+	}
+	if _, err := uuid.FromString(userID); err != nil {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid user id")
+	}
+	for _, ms := range memberships {
+		if ms.OrganizationName == "" {
+			return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "organization name not provided")
+		}
+	}
+	if len(memberships) == 0 {
+		return nil
+	}
+
+	// Sync organizations memberships in database
+	membershipsJSON, _ := json.Marshal(memberships)
+	_, err := m.db.Exec(ctx, syncUserOrgsMembershipsDBQ, userID, membershipsJSON)
+	return err
+}
+
 // UpdatePassword updates the user password in the database.
 func (m *Manager) UpdatePassword(ctx context.Context, old, new string) error {
 	userID := ctx.Value(hub.UserIDKey).(string)
@@ -813,9 +992,16 @@ func (m *Manager) UpdatePassword(ctx context.Context, old, new string) error {
 		return err
 	}
 
-	// Update user password in database
-	_, err = m.db.Exec(ctx, updateUserPasswordDBQ, userID, oldHashed, string(newHashed))
-	return err
+	// Update user password in database and register audit event
+	return util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, updateUserPasswordDBQ, userID, oldHashed, string(newHashed)); err != nil {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionPasswordUpdated,
+			UserID: userID,
+		})
+	})
 }
 
 // UpdateProfile updates the user profile in the database.
@@ -868,6 +1054,84 @@ func (m *Manager) VerifyPasswordResetCode(ctx context.Context, code string) erro
 	return err
 }
 
+// registerFailedLoginAttempt registers a failed login attempt for the user
+// provided from the client given when account lockout is enabled. When the
+// maximum number of consecutive failed attempts from the client is reached,
+// the account is temporarily locked for that client and the user is notified
+// by email.
+func (m *Manager) registerFailedLoginAttempt(ctx context.Context, userID, client string) error {
+	maxFailedAttempts := m.cfg.GetInt("server.accountLockout.maxFailedAttempts")
+	if maxFailedAttempts <= 0 {
+		return nil
+	}
+	lockoutDuration := m.cfg.GetDuration("server.accountLockout.duration")
+
+	// Register failed attempt, locking the account if needed (and registering
+	// the corresponding audit event)
+	var locked bool
+	err := util.DBTransact(ctx, m.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			registerFailedLoginDBQ,
+			userID,
+			client,
+			maxFailedAttempts,
+			int(lockoutDuration.Seconds()),
+		).Scan(&locked)
+		if err != nil || !locked {
+			return err
+		}
+		return audit.RegisterEvent(ctx, tx, &hub.AuditEvent{
+			Action: hub.AuditActionAccountLocked,
+			UserID: userID,
+			Details: map[string]string{
+				"client":   client,
+				"duration": lockoutDuration.String(),
+			},
+		})
+	})
+	if err != nil || !locked {
+		return err
+	}
+
+	// Notify user by email that the account has been locked. Errors are only
+	// logged, as the failed login attempt has already been registered.
+	if m.es != nil {
+		if err := m.notifyAccountLocked(ctx, userID, client, lockoutDuration); err != nil {
+			log.Error().Err(err).Str("userID", userID).Msg("error notifying account lockout")
+		}
+	}
+
+	return nil
+}
+
+// notifyAccountLocked sends an email to the user provided notifying that the
+// account has been temporarily locked for the client given.
+func (m *Manager) notifyAccountLocked(
+	ctx context.Context,
+	userID,
+	client string,
+	lockoutDuration time.Duration,
+) error {
+	var userEmail string
+	if err := m.db.QueryRow(ctx, getUserEmailDBQ, userID).Scan(&userEmail); err != nil {
+		return err
+	}
+	templateData := baseTemplateData(m.cfg)
+	templateData["Client"] = client
+	templateData["Duration"] = lockoutDuration.String()
+	var emailBody bytes.Buffer
+	if err := m.tmpl[accountLockedEmail].Execute(&emailBody, templateData); err != nil {
+		return err
+	}
+	emailData := &email.Data{
+		To:      userEmail,
+		Subject: "Account temporarily locked",
+		Body:    emailBody.Bytes(),
+	}
+	return m.es.SendEmail(emailData)
+}
+
 // hash is a helper function that creates a sha512 hash of the text provided.
 func hash(text string) string {
 	return fmt.Sprintf("%x", sha512.Sum512([]byte(text)))
@@ -8,6 +8,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/artifacthub/hub/internal/audit"
 	"github.com/artifacthub/hub/internal/email"
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
@@ -21,11 +22,16 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var cfg *viper.Viper
+var cfg, lockoutCfg *viper.Viper
 
 func init() {
 	cfg = viper.New()
 	cfg.Set("theme.siteName", "Artifact Hub")
+
+	lockoutCfg = viper.New()
+	lockoutCfg.Set("theme.siteName", "Artifact Hub")
+	lockoutCfg.Set("server.accountLockout.maxFailedAttempts", 3)
+	lockoutCfg.Set("server.accountLockout.duration", 30*time.Minute)
 }
 
 func TestApproveSession(t *testing.T) {
@@ -76,7 +82,7 @@ func TestApproveSession(t *testing.T) {
 	t.Run("error getting user id from session", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDFromSessionIDDBQ, hashedSessionID).Return("", tests.ErrFakeDB)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hashedSessionID).Return(nil, tests.ErrFakeDB)
 		m := NewManager(cfg, db, nil)
 
 		err := m.ApproveSession(ctx, sessionID, "123456")
@@ -84,10 +90,21 @@ func TestApproveSession(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("account locked", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", true}, nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.Equal(t, errInvalidTFAPasscode, err)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("error getting 2fa config from database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDFromSessionIDDBQ, hash(sessionID)).Return("userID", nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(nil, tests.ErrFakeDB)
 		m := NewManager(cfg, db, nil)
 
@@ -99,7 +116,7 @@ func TestApproveSession(t *testing.T) {
 	t.Run("invalid passcode provided", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDFromSessionIDDBQ, hash(sessionID)).Return("userID", nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
 		m := NewManager(cfg, db, nil)
 
@@ -108,10 +125,49 @@ func TestApproveSession(t *testing.T) {
 		db.AssertExpectations(t)
 	})
 
+	t.Run("invalid passcode provided, error registering failed attempt", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
+		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		tx.On("QueryRow", ctx, registerFailedLoginDBQ, "userID", "client", 3, 1800).Return(nil, tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(lockoutCfg, db, nil)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("invalid passcode provided, account locked and user notified", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
+		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		tx.On("QueryRow", ctx, registerFailedLoginDBQ, "userID", "client", 3, 1800).Return(true, nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
+		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
+		es := &email.SenderMock{}
+		es.On("SendEmail", mock.Anything).Return(nil)
+		m := NewManager(lockoutCfg, db, es)
+
+		err := m.ApproveSession(ctx, sessionID, "123456")
+		assert.Equal(t, errInvalidTFAPasscode, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		es.AssertExpectations(t)
+	})
+
 	t.Run("session approved successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDFromSessionIDDBQ, hash(sessionID)).Return("userID", nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
 		db.On("Exec", ctx, approveSessionDBQ, hashedSessionID, "").Return(nil)
 		m := NewManager(cfg, db, nil)
@@ -125,7 +181,7 @@ func TestApproveSession(t *testing.T) {
 	t.Run("session approved successfully (using valid recovery code)", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, getUserIDFromSessionIDDBQ, hash(sessionID)).Return("userID", nil)
+		db.On("QueryRow", ctx, getSessionUserDBQ, hash(sessionID)).Return([]interface{}{"userID", "client", false}, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
 		db.On("Exec", ctx, approveSessionDBQ, hashedSessionID, code1).Return(nil)
 		m := NewManager(cfg, db, nil)
@@ -233,7 +289,7 @@ func TestCheckCredentials(t *testing.T) {
 			t.Run(tc.errMsg, func(t *testing.T) {
 				t.Parallel()
 				m := NewManager(cfg, nil, nil)
-				_, err := m.CheckCredentials(ctx, tc.email, tc.password)
+				_, err := m.CheckCredentials(ctx, tc.email, tc.password, "client")
 				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
 				assert.Contains(t, err.Error(), tc.errMsg)
 			})
@@ -243,10 +299,10 @@ func TestCheckCredentials(t *testing.T) {
 	t.Run("credentials provided not found in database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, checkUserCredsDBQ, "email").Return(nil, pgx.ErrNoRows)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return(nil, pgx.ErrNoRows)
 		m := NewManager(cfg, db, nil)
 
-		output, err := m.CheckCredentials(ctx, "email", "pass")
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
 		assert.NoError(t, err)
 		assert.False(t, output.Valid)
 		assert.Empty(t, output.UserID)
@@ -256,23 +312,37 @@ func TestCheckCredentials(t *testing.T) {
 	t.Run("error getting credentials from database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, checkUserCredsDBQ, "email").Return(nil, tests.ErrFakeDB)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return(nil, tests.ErrFakeDB)
 		m := NewManager(cfg, db, nil)
 
-		output, err := m.CheckCredentials(ctx, "email", "pass")
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, output)
 		db.AssertExpectations(t)
 	})
 
+	t.Run("account locked, valid credentials provided", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), true}, nil)
+		m := NewManager(lockoutCfg, db, nil)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
+		assert.NoError(t, err)
+		assert.False(t, output.Valid)
+		assert.Empty(t, output.UserID)
+		db.AssertExpectations(t)
+	})
+
 	t.Run("invalid credentials provided", func(t *testing.T) {
 		t.Parallel()
 		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, checkUserCredsDBQ, "email").Return([]interface{}{"userID", string(pw)}, nil)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
 		m := NewManager(cfg, db, nil)
 
-		output, err := m.CheckCredentials(ctx, "email", "pass2")
+		output, err := m.CheckCredentials(ctx, "email", "pass2", "client")
 		assert.NoError(t, err)
 		assert.False(t, output.Valid)
 		assert.Empty(t, output.UserID)
@@ -283,10 +353,98 @@ func TestCheckCredentials(t *testing.T) {
 		t.Parallel()
 		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, checkUserCredsDBQ, "email").Return([]interface{}{"userID", string(pw)}, nil)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
 		m := NewManager(cfg, db, nil)
 
-		output, err := m.CheckCredentials(ctx, "email", "pass")
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
+		assert.NoError(t, err)
+		assert.True(t, output.Valid)
+		assert.Equal(t, "userID", output.UserID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials provided, error registering failed attempt", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
+		tx.On("QueryRow", ctx, registerFailedLoginDBQ, "userID", "client", 3, 1800).Return(nil, tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(lockoutCfg, db, nil)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass2", "client")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, output)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials provided, failed attempt registered", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
+		tx.On("QueryRow", ctx, registerFailedLoginDBQ, "userID", "client", 3, 1800).Return(false, nil)
+		tx.On("Commit", ctx).Return(nil)
+		m := NewManager(lockoutCfg, db, nil)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass2", "client")
+		assert.NoError(t, err)
+		assert.False(t, output.Valid)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("invalid credentials provided, account locked, error sending email", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
+		tx.On("QueryRow", ctx, registerFailedLoginDBQ, "userID", "client", 3, 1800).Return(true, nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
+		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
+		es := &email.SenderMock{}
+		es.On("SendEmail", mock.Anything).Return(email.ErrFakeSenderFailure)
+		m := NewManager(lockoutCfg, db, es)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass2", "client")
+		assert.NoError(t, err)
+		assert.False(t, output.Valid)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+		es.AssertExpectations(t)
+	})
+
+	t.Run("valid credentials provided, error resetting failed attempts", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
+		db.On("Exec", ctx, resetFailedLoginsDBQ, "userID", "client").Return(tests.ErrFakeDB)
+		m := NewManager(lockoutCfg, db, nil)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, output)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("valid credentials provided, failed attempts reset", func(t *testing.T) {
+		t.Parallel()
+		pw, _ := bcrypt.GenerateFromPassword([]byte("pass"), bcrypt.DefaultCost)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, checkUserCredsDBQ, "email", "client").Return([]interface{}{"userID", string(pw), false}, nil)
+		db.On("Exec", ctx, resetFailedLoginsDBQ, "userID", "client").Return(nil)
+		m := NewManager(lockoutCfg, db, nil)
+
+		output, err := m.CheckCredentials(ctx, "email", "pass", "client")
 		assert.NoError(t, err)
 		assert.True(t, output.Valid)
 		assert.Equal(t, "userID", output.UserID)
@@ -568,21 +726,47 @@ func TestDisableTFA(t *testing.T) {
 	t.Run("error setting 2fa as disabled in the database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		tx.On("Exec", ctx, disableTFADBQ, "userID").Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		passcode, _ := totp.GenerateCode(key.Secret(), time.Now())
+		err := m.DisableTFA(ctx, passcode)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, disableTFADBQ, "userID").Return(tests.ErrFakeDB)
+		tx.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		passcode, _ := totp.GenerateCode(key.Secret(), time.Now())
 		err := m.DisableTFA(ctx, passcode)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("error sending 2fa enabled email notification", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
 		es := &email.SenderMock{}
 		es.On("SendEmail", mock.Anything).Return(email.ErrFakeSenderFailure)
@@ -592,13 +776,18 @@ func TestDisableTFA(t *testing.T) {
 		err := m.DisableTFA(ctx, passcode)
 		assert.Equal(t, email.ErrFakeSenderFailure, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("2fa disabled successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
 		es := &email.SenderMock{}
 		es.On("SendEmail", mock.Anything).Return(nil)
@@ -608,18 +797,24 @@ func TestDisableTFA(t *testing.T) {
 		err := m.DisableTFA(ctx, passcode)
 		assert.Nil(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("2fa disabled successfully (using valid recovery code)", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, disableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		err := m.DisableTFA(ctx, code1)
 		assert.Nil(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 }
 
@@ -677,21 +872,47 @@ func TestEnableTFA(t *testing.T) {
 	t.Run("error setting 2fa as enabled in the database", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
+		tx.On("Exec", ctx, enableTFADBQ, "userID").Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		passcode, _ := totp.GenerateCode(key.Secret(), time.Now())
+		err := m.EnableTFA(ctx, passcode)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, enableTFADBQ, "userID").Return(tests.ErrFakeDB)
+		tx.On("Exec", ctx, enableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		passcode, _ := totp.GenerateCode(key.Secret(), time.Now())
 		err := m.EnableTFA(ctx, passcode)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("error sending 2fa enabled email notification", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, enableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, enableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
 		es := &email.SenderMock{}
 		es.On("SendEmail", mock.Anything).Return(email.ErrFakeSenderFailure)
@@ -701,13 +922,18 @@ func TestEnableTFA(t *testing.T) {
 		err := m.EnableTFA(ctx, passcode)
 		assert.Equal(t, email.ErrFakeSenderFailure, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("2fa enabled successfully", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getTFAConfigDBQ, "userID").Return(tfaConfigJSON, nil)
-		db.On("Exec", ctx, enableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, enableTFADBQ, "userID").Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		db.On("QueryRow", ctx, getUserEmailDBQ, "userID").Return("email", nil)
 		es := &email.SenderMock{}
 		es.On("SendEmail", mock.Anything).Return(nil)
@@ -717,6 +943,60 @@ func TestEnableTFA(t *testing.T) {
 		err := m.EnableTFA(ctx, passcode)
 		assert.Nil(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+}
+
+func TestGetAuditLogJSON(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	input := &hub.GetAuditLogInput{
+		Actions: []hub.AuditAction{hub.AuditActionLogin},
+		Limit:   10,
+		Offset:  1,
+	}
+	inputJSON, _ := json.Marshal(input)
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetAuditLogJSON(context.Background(), input)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		_, err := m.GetAuditLogJSON(ctx, &hub.GetAuditLogInput{
+			Actions: []hub.AuditAction{"invalid"},
+		})
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserAuditLogDBQ, "userID", inputJSON).
+			Return([]interface{}{[]byte("dataJSON"), 1}, nil)
+		m := NewManager(cfg, db, nil)
+
+		result, err := m.GetAuditLogJSON(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("dataJSON"), result.Data)
+		assert.Equal(t, 1, result.TotalCount)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserAuditLogDBQ, "userID", inputJSON).Return(nil, tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		result, err := m.GetAuditLogJSON(ctx, input)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, result)
+		db.AssertExpectations(t)
 	})
 }
 
@@ -847,6 +1127,107 @@ func TestGetUserID(t *testing.T) {
 	})
 }
 
+func TestGetUserIDFromLDAPDN(t *testing.T) {
+	ctx := context.Background()
+	dn := "uid=user1,dc=example,dc=com"
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		_, err := m.GetUserIDFromLDAPDN(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserIDFromLDAPDNDBQ, dn).Return("userID", nil)
+		m := NewManager(cfg, db, nil)
+
+		userID, err := m.GetUserIDFromLDAPDN(ctx, dn)
+		assert.NoError(t, err)
+		assert.Equal(t, "userID", userID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("user not found", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserIDFromLDAPDNDBQ, dn).Return("", pgx.ErrNoRows)
+		m := NewManager(cfg, db, nil)
+
+		userID, err := m.GetUserIDFromLDAPDN(ctx, dn)
+		assert.Equal(t, ErrNotFound, err)
+		assert.Empty(t, userID)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getUserIDFromLDAPDNDBQ, dn).Return("", tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		userID, err := m.GetUserIDFromLDAPDN(ctx, dn)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Empty(t, userID)
+		db.AssertExpectations(t)
+	})
+}
+
+func TestLinkLDAPAccount(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+	dn := "uid=user1,dc=example,dc=com"
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		assert.Panics(t, func() {
+			_ = m.LinkLDAPAccount(context.Background(), dn)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(cfg, nil, nil)
+		err := m.LinkLDAPAccount(ctx, "")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, linkLDAPAccountDBQ, "userID", dn).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.LinkLDAPAccount(ctx, dn)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, linkLDAPAccountDBQ, "userID", dn).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.LinkLDAPAccount(ctx, dn)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("ldap account already linked to a different user", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, linkLDAPAccountDBQ, "userID", dn).Return(errLDAPAccountAlreadyLinkedDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.LinkLDAPAccount(ctx, dn)
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+		db.AssertExpectations(t)
+	})
+}
+
 func TestRegisterDeleteUserCode(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 
@@ -1001,7 +1382,10 @@ func TestRegisterSession(t *testing.T) {
 	t.Run("database error", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(nil, tests.ErrFakeDB)
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(nil, tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		sIN := &hub.Session{UserID: userID}
@@ -1009,12 +1393,35 @@ func TestRegisterSession(t *testing.T) {
 		assert.Equal(t, tests.ErrFakeDB, err)
 		assert.Nil(t, sOUT)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(true, nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		sIN := &hub.Session{UserID: userID}
+		sOUT, err := m.RegisterSession(ctx, sIN)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		assert.Nil(t, sOUT)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("successful session registration", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
-		db.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(true, nil)
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, registerSessionDBQ, mock.Anything).Return(true, nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		sIN := &hub.Session{
@@ -1028,6 +1435,7 @@ func TestRegisterSession(t *testing.T) {
 		assert.Equal(t, userID, sOUT.UserID)
 		assert.True(t, sOUT.Approved)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 }
 
@@ -1186,16 +1594,37 @@ func TestResetPassword(t *testing.T) {
 			t.Run(tc.dbErr.Error(), func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
-				db.On("QueryRow", ctx, resetUserPasswordDBQ, codeHashed, mock.Anything).Return("", tc.dbErr)
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
+				tx.On("QueryRow", ctx, resetUserPasswordDBQ, codeHashed, mock.Anything).Return("", tc.dbErr)
+				tx.On("Rollback", ctx).Return(nil)
 				m := NewManager(cfg, db, nil)
 
 				err := m.ResetPassword(ctx, code, newPassword)
 				assert.Equal(t, tc.expectedErr, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
 			})
 		}
 	})
 
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		tx.On("QueryRow", ctx, resetUserPasswordDBQ, codeHashed, mock.Anything).Return("email", nil)
+		tx.On("QueryRow", ctx, getUserIDFromEmailDBQ, "email").Return("userID", nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.ResetPassword(ctx, code, newPassword)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
 	t.Run("successful password reset in database", func(t *testing.T) {
 		testCases := []struct {
 			description         string
@@ -1214,7 +1643,12 @@ func TestResetPassword(t *testing.T) {
 			t.Run(tc.description, func(t *testing.T) {
 				t.Parallel()
 				db := &tests.DBMock{}
-				db.On("QueryRow", ctx, resetUserPasswordDBQ, codeHashed, mock.Anything).Return("email", nil)
+				tx := &tests.TXMock{}
+				db.On("Begin", ctx).Return(tx, nil)
+				tx.On("QueryRow", ctx, resetUserPasswordDBQ, codeHashed, mock.Anything).Return("email", nil)
+				tx.On("QueryRow", ctx, getUserIDFromEmailDBQ, "email").Return("userID", nil)
+				tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+				tx.On("Commit", ctx).Return(nil)
 				es := &email.SenderMock{}
 				es.On("SendEmail", mock.Anything).Return(tc.emailSenderResponse)
 				m := NewManager(cfg, db, es)
@@ -1222,6 +1656,7 @@ func TestResetPassword(t *testing.T) {
 				err := m.ResetPassword(ctx, code, newPassword)
 				assert.Equal(t, tc.emailSenderResponse, err)
 				db.AssertExpectations(t)
+				tx.AssertExpectations(t)
 				es.AssertExpectations(t)
 			})
 		}
@@ -1287,6 +1722,84 @@ func TestSetupTFA(t *testing.T) {
 	})
 }
 
+func TestSyncOrganizationsMemberships(t *testing.T) {
+	ctx := context.Background()
+	userID := "00000000-0000-0000-0000-000000000001"
+	memberships := []*hub.OrganizationMembershipSync{
+		{
+			OrganizationName: "org1",
+			Member:           true,
+			Roles:            map[string]bool{"owner": true},
+		},
+	}
+	membershipsJSON, _ := json.Marshal(memberships)
+
+	t.Run("invalid input", func(t *testing.T) {
+		testCases := []struct {
+			errMsg      string
+			userID      string
+			memberships []*hub.OrganizationMembershipSync
+		}{
+			{
+				"user id not provided",
+				"",
+				nil,
+			},
+			{
+				"invalid user id",
+				"invalid",
+				nil,
+			},
+			{
+				"organization name not provided",
+				userID,
+				[]*hub.OrganizationMembershipSync{{Member: true}},
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.errMsg, func(t *testing.T) {
+				t.Parallel()
+				m := NewManager(cfg, nil, nil)
+				err := m.SyncOrganizationsMemberships(ctx, tc.userID, tc.memberships)
+				assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+				assert.Contains(t, err.Error(), tc.errMsg)
+			})
+		}
+	})
+
+	t.Run("no memberships provided", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		m := NewManager(cfg, db, nil)
+
+		err := m.SyncOrganizationsMemberships(ctx, userID, nil)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database query succeeded", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, syncUserOrgsMembershipsDBQ, userID, membershipsJSON).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SyncOrganizationsMemberships(ctx, userID, memberships)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, syncUserOrgsMembershipsDBQ, userID, membershipsJSON).Return(tests.ErrFakeDB)
+		m := NewManager(cfg, db, nil)
+
+		err := m.SyncOrganizationsMemberships(ctx, userID, memberships)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestUpdatePassword(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 	oldHashed, _ := bcrypt.GenerateFromPassword([]byte("old"), bcrypt.DefaultCost)
@@ -1358,26 +1871,52 @@ func TestUpdatePassword(t *testing.T) {
 	t.Run("database error updating password", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getUserPasswordDBQ, "userID").Return(string(oldHashed), nil)
-		db.On("Exec", ctx, updateUserPasswordDBQ, "userID", mock.Anything, mock.Anything).
+		tx.On("Exec", ctx, updateUserPasswordDBQ, "userID", mock.Anything, mock.Anything).
 			Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
+		m := NewManager(cfg, db, nil)
+
+		err := m.UpdatePassword(ctx, "old", new)
+		assert.Equal(t, tests.ErrFakeDB, err)
+		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
+	})
+
+	t.Run("error registering audit event", func(t *testing.T) {
+		t.Parallel()
+		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
+		db.On("QueryRow", ctx, getUserPasswordDBQ, "userID").Return(string(oldHashed), nil)
+		tx.On("Exec", ctx, updateUserPasswordDBQ, "userID", mock.Anything, mock.Anything).Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(tests.ErrFakeDB)
+		tx.On("Rollback", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		err := m.UpdatePassword(ctx, "old", new)
 		assert.Equal(t, tests.ErrFakeDB, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 
 	t.Run("successful password update", func(t *testing.T) {
 		t.Parallel()
 		db := &tests.DBMock{}
+		tx := &tests.TXMock{}
+		db.On("Begin", ctx).Return(tx, nil)
 		db.On("QueryRow", ctx, getUserPasswordDBQ, "userID").Return(string(oldHashed), nil)
-		db.On("Exec", ctx, updateUserPasswordDBQ, "userID", mock.Anything, mock.Anything).Return(nil)
+		tx.On("Exec", ctx, updateUserPasswordDBQ, "userID", mock.Anything, mock.Anything).Return(nil)
+		tx.On("Exec", ctx, audit.RegisterEventDBQ, mock.Anything).Return(nil)
+		tx.On("Commit", ctx).Return(nil)
 		m := NewManager(cfg, db, nil)
 
 		err := m.UpdatePassword(ctx, "old", new)
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
+		tx.AssertExpectations(t)
 	})
 }
 
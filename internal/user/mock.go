@@ -29,9 +29,10 @@ func (m *ManagerMock) CheckAvailability(ctx context.Context, resourceKind, value
 func (m *ManagerMock) CheckCredentials(
 	ctx context.Context,
 	email,
-	password string,
+	password,
+	client string,
 ) (*hub.CheckCredentialsOutput, error) {
-	args := m.Called(ctx, email, password)
+	args := m.Called(ctx, email, password, client)
 	data, _ := args.Get(0).(*hub.CheckCredentialsOutput)
 	return data, args.Error(1)
 }
@@ -71,6 +72,16 @@ func (m *ManagerMock) EnableTFA(ctx context.Context, passcode string) error {
 	return args.Error(0)
 }
 
+// GetAuditLogJSON implements the UserManager interface.
+func (m *ManagerMock) GetAuditLogJSON(
+	ctx context.Context,
+	input *hub.GetAuditLogInput,
+) (*hub.JSONQueryResult, error) {
+	args := m.Called(ctx, input)
+	data, _ := args.Get(0).(*hub.JSONQueryResult)
+	return data, args.Error(1)
+}
+
 // GetProfile implements the UserManager interface.
 func (m *ManagerMock) GetProfile(ctx context.Context) (*hub.User, error) {
 	args := m.Called(ctx)
@@ -91,6 +102,18 @@ func (m *ManagerMock) GetUserID(ctx context.Context, email string) (string, erro
 	return args.String(0), args.Error(1)
 }
 
+// GetUserIDFromLDAPDN implements the UserManager interface.
+func (m *ManagerMock) GetUserIDFromLDAPDN(ctx context.Context, dn string) (string, error) {
+	args := m.Called(ctx, dn)
+	return args.String(0), args.Error(1)
+}
+
+// LinkLDAPAccount implements the UserManager interface.
+func (m *ManagerMock) LinkLDAPAccount(ctx context.Context, dn string) error {
+	args := m.Called(ctx, dn)
+	return args.Error(0)
+}
+
 // RegisterDeleteUserCode implements the UserManager interface.
 func (m *ManagerMock) RegisterDeleteUserCode(ctx context.Context) error {
 	args := m.Called(ctx)
@@ -129,6 +152,16 @@ func (m *ManagerMock) SetupTFA(ctx context.Context) ([]byte, error) {
 	return data, args.Error(1)
 }
 
+// SyncOrganizationsMemberships implements the UserManager interface.
+func (m *ManagerMock) SyncOrganizationsMemberships(
+	ctx context.Context,
+	userID string,
+	memberships []*hub.OrganizationMembershipSync,
+) error {
+	args := m.Called(ctx, userID, memberships)
+	return args.Error(0)
+}
+
 // UpdatePassword implements the UserManager interface.
 func (m *ManagerMock) UpdatePassword(ctx context.Context, old, new string) error {
 	args := m.Called(ctx, old, new)
@@ -0,0 +1,287 @@
+package util
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/artifacthub/hub/internal/hub"
+	"github.com/spf13/viper"
+)
+
+// encryptedValuePrefix represents the prefix used in the values encrypted
+// using a Keyring. The id of the key used to encrypt the value follows it.
+const encryptedValuePrefix = "enc:v1:"
+
+var (
+	// ErrEncryptionKeyNotProvided indicates that no key to encrypt some data
+	// has been provided.
+	ErrEncryptionKeyNotProvided = errors.New("encryption key not provided")
+
+	// ErrEncryptionKeyNotFound indicates that the key used to encrypt some
+	// data is not available in the keyring.
+	ErrEncryptionKeyNotFound = errors.New("encryption key not found")
+
+	// ErrInvalidEncryptedData indicates that the encrypted data provided is
+	// not valid or could not be decrypted with the key provided.
+	ErrInvalidEncryptedData = errors.New("invalid encrypted data")
+)
+
+// EncryptionKey represents a key used to encrypt sensitive data.
+type EncryptionKey struct {
+	ID  string `mapstructure:"id"`
+	Key string `mapstructure:"key"`
+}
+
+// Keyring provides envelope encryption for sensitive data stored in the
+// database. Each value is encrypted with its own random data key, which is
+// encrypted (wrapped) using the active key of the keyring. The id of the key
+// used is stored along with the value, so that keys can be rotated: values
+// encrypted with previous keys can still be decrypted as long as their keys
+// remain available in the keyring.
+type Keyring struct {
+	active *EncryptionKey
+	keys   map[string]*EncryptionKey
+}
+
+// NewKeyring creates a new Keyring instance with the keys provided. The first
+// key will be the active one, used to encrypt new data.
+func NewKeyring(keys []*EncryptionKey) (*Keyring, error) {
+	kr := &Keyring{
+		keys: make(map[string]*EncryptionKey, len(keys)),
+	}
+	for _, k := range keys {
+		if k.ID == "" || strings.Contains(k.ID, ":") {
+			return nil, fmt.Errorf("invalid encryption key id: %q", k.ID)
+		}
+		if k.Key == "" {
+			return nil, fmt.Errorf("encryption key %s not provided", k.ID)
+		}
+		if _, ok := kr.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicated encryption key id: %s", k.ID)
+		}
+		kr.keys[k.ID] = k
+	}
+	if len(keys) > 0 {
+		kr.active = keys[0]
+	}
+	return kr, nil
+}
+
+// SetupKeyring creates a new Keyring instance using the encryption keys
+// defined in the configuration provided (db.encryptionKeys).
+func SetupKeyring(cfg *viper.Viper) (*Keyring, error) {
+	var keys []*EncryptionKey
+	if err := cfg.UnmarshalKey("db.encryptionKeys", &keys); err != nil {
+		return nil, fmt.Errorf("invalid encryption keys: %w", err)
+	}
+	return NewKeyring(keys)
+}
+
+// Enabled checks if the keyring has an active key that can be used to
+// encrypt data.
+func (kr *Keyring) Enabled() bool {
+	return kr != nil && kr.active != nil
+}
+
+// Encrypt encrypts the string provided using the active key.
+func (kr *Keyring) Encrypt(s string) (string, error) {
+	if !kr.Enabled() {
+		return "", ErrEncryptionKeyNotProvided
+	}
+
+	// Encrypt value using a new data key
+	dataKey := make([]byte, 32)
+	if _, err := rand.Read(dataKey); err != nil {
+		return "", fmt.Errorf("error generating data key: %w", err)
+	}
+	data, err := seal(dataKey, []byte(s))
+	if err != nil {
+		return "", err
+	}
+
+	// Wrap data key using the active key
+	kek := sha256.Sum256([]byte(kr.active.Key))
+	wrappedDataKey, err := seal(kek[:], dataKey)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s:%s:%s",
+		encryptedValuePrefix,
+		kr.active.ID,
+		base64.StdEncoding.EncodeToString(wrappedDataKey),
+		base64.StdEncoding.EncodeToString(data),
+	), nil
+}
+
+// Decrypt decrypts the string provided, previously encrypted using any of the
+// keys in the keyring. Values that are not encrypted are returned as is.
+func (kr *Keyring) Decrypt(s string) (string, error) {
+	if !IsEncrypted(s) {
+		return s, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(s, encryptedValuePrefix), ":")
+	if len(parts) != 3 {
+		return "", ErrInvalidEncryptedData
+	}
+	var k *EncryptionKey
+	if kr != nil {
+		k = kr.keys[parts[0]]
+	}
+	if k == nil {
+		return "", fmt.Errorf("%w: %s", ErrEncryptionKeyNotFound, parts[0])
+	}
+
+	// Unwrap data key
+	wrappedDataKey, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", ErrInvalidEncryptedData
+	}
+	kek := sha256.Sum256([]byte(k.Key))
+	dataKey, err := open(kek[:], wrappedDataKey)
+	if err != nil {
+		return "", err
+	}
+
+	// Decrypt value
+	data, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", ErrInvalidEncryptedData
+	}
+	plaintext, err := open(dataKey, data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// NeedsReencryption checks if the value provided should be encrypted again,
+// because it is not encrypted yet or was encrypted using a key other than the
+// active one.
+func (kr *Keyring) NeedsReencryption(s string) bool {
+	if s == "" || !kr.Enabled() {
+		return false
+	}
+	return !strings.HasPrefix(s, encryptedValuePrefix+kr.active.ID+":")
+}
+
+// IsEncrypted checks if the value provided has been encrypted using a Keyring.
+func IsEncrypted(s string) bool {
+	return strings.HasPrefix(s, encryptedValuePrefix)
+}
+
+// seal encrypts the data provided using AES-256-GCM with the key provided.
+// The nonce used is prepended to the result.
+func seal(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("error generating nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, data, nil), nil
+}
+
+// open decrypts the data provided, previously encrypted using seal.
+func open(key, data []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrInvalidEncryptedData
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrInvalidEncryptedData
+	}
+	return plaintext, nil
+}
+
+// newGCM returns an AES-256-GCM cipher using the key provided.
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedDBColumn represents a database column that stores encrypted
+// values.
+type encryptedDBColumn struct {
+	table    string
+	idColumn string
+	column   string
+}
+
+// encryptedDBColumns contains the database columns that store values
+// encrypted using a Keyring.
+var encryptedDBColumns = []encryptedDBColumn{
+	{"repository", "repository_id", "auth_user"},
+	{"repository", "repository_id", "auth_pass"},
+	{"repository", "repository_id", "auth_ssh_key"},
+	{"repository", "repository_id", "auth_github_app_key"},
+	{"repository", "repository_id", "push_secret"},
+	{"webhook", "webhook_id", "secret"},
+}
+
+// ReencryptDBSecrets encrypts again using the active key of the keyring
+// provided all the secrets stored in the database that are not encrypted yet
+// or were encrypted using a different key. It returns the number of values
+// that were re-encrypted.
+func ReencryptDBSecrets(ctx context.Context, db hub.DB, kr *Keyring) (int, error) {
+	if !kr.Enabled() {
+		return 0, ErrEncryptionKeyNotProvided
+	}
+
+	var count int
+	for _, c := range encryptedDBColumns {
+		// Get values stored in column
+		var rows []struct {
+			ID    string `json:"id"`
+			Value string `json:"value"`
+		}
+		getValuesDBQ := fmt.Sprintf(`
+			select coalesce(json_agg(json_build_object('id', %[1]s, 'value', %[2]s)), '[]')
+			from %[3]s where %[2]s is not null
+		`, c.idColumn, c.column, c.table)
+		if err := DBQueryUnmarshal(ctx, db, &rows, getValuesDBQ); err != nil {
+			return count, fmt.Errorf("error getting %s.%s values: %w", c.table, c.column, err)
+		}
+
+		// Re-encrypt the values that need it
+		updateValueDBQ := fmt.Sprintf(
+			`update %[1]s set %[2]s = $2 where %[3]s = $1 and %[2]s = $3`,
+			c.table, c.column, c.idColumn,
+		)
+		for _, row := range rows {
+			if !kr.NeedsReencryption(row.Value) {
+				continue
+			}
+			plaintext, err := kr.Decrypt(row.Value)
+			if err != nil {
+				return count, fmt.Errorf("error decrypting %s.%s (id: %s): %w", c.table, c.column, row.ID, err)
+			}
+			encrypted, err := kr.Encrypt(plaintext)
+			if err != nil {
+				return count, err
+			}
+			if _, err := db.Exec(ctx, updateValueDBQ, row.ID, encrypted, row.Value); err != nil {
+				return count, fmt.Errorf("error updating %s.%s (id: %s): %w", c.table, c.column, row.ID, err)
+			}
+			count++
+		}
+	}
+	return count, nil
+}
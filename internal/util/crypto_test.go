@@ -0,0 +1,185 @@
+package util
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/artifacthub/hub/internal/tests"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid keys", func(t *testing.T) {
+		t.Parallel()
+		testCases := [][]*EncryptionKey{
+			{{ID: "", Key: "key1"}},
+			{{ID: "key:1", Key: "key1"}},
+			{{ID: "key1", Key: ""}},
+			{{ID: "key1", Key: "key1"}, {ID: "key1", Key: "key2"}},
+		}
+		for _, keys := range testCases {
+			_, err := NewKeyring(keys)
+			assert.Error(t, err)
+		}
+	})
+
+	t.Run("keyring setup from config", func(t *testing.T) {
+		t.Parallel()
+		cfg := viper.New()
+		cfg.Set("db.encryptionKeys", []map[string]interface{}{
+			{"id": "key2", "key": "secret2"},
+			{"id": "key1", "key": "secret1"},
+		})
+		kr, err := SetupKeyring(cfg)
+		require.NoError(t, err)
+		assert.True(t, kr.Enabled())
+		assert.Equal(t, "key2", kr.active.ID)
+		assert.Len(t, kr.keys, 2)
+	})
+
+	t.Run("encryption key not provided", func(t *testing.T) {
+		t.Parallel()
+		kr, err := NewKeyring(nil)
+		require.NoError(t, err)
+		assert.False(t, kr.Enabled())
+		_, err = kr.Encrypt("secret")
+		assert.True(t, errors.Is(err, ErrEncryptionKeyNotProvided))
+		assert.False(t, kr.NeedsReencryption("secret"))
+	})
+
+	t.Run("string encrypted and decrypted successfully", func(t *testing.T) {
+		t.Parallel()
+		kr, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+
+		encrypted, err := kr.Encrypt("secret")
+		require.NoError(t, err)
+		assert.True(t, IsEncrypted(encrypted))
+		assert.True(t, strings.HasPrefix(encrypted, "enc:v1:key1:"))
+		assert.NotContains(t, encrypted, "secret")
+		assert.False(t, kr.NeedsReencryption(encrypted))
+		decrypted, err := kr.Decrypt(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", decrypted)
+	})
+
+	t.Run("not encrypted values are returned as is", func(t *testing.T) {
+		t.Parallel()
+		kr, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+
+		decrypted, err := kr.Decrypt("plaintext")
+		require.NoError(t, err)
+		assert.Equal(t, "plaintext", decrypted)
+		assert.True(t, kr.NeedsReencryption("plaintext"))
+	})
+
+	t.Run("values encrypted with previous keys can be decrypted", func(t *testing.T) {
+		t.Parallel()
+		kr1, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		kr2, err := NewKeyring([]*EncryptionKey{{ID: "key2", Key: "secret2"}, {ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+
+		encrypted, err := kr1.Encrypt("secret")
+		require.NoError(t, err)
+		assert.True(t, kr2.NeedsReencryption(encrypted))
+		decrypted, err := kr2.Decrypt(encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", decrypted)
+	})
+
+	t.Run("decryption fails when key is not available", func(t *testing.T) {
+		t.Parallel()
+		kr1, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		kr2, err := NewKeyring([]*EncryptionKey{{ID: "key2", Key: "secret2"}})
+		require.NoError(t, err)
+
+		encrypted, err := kr1.Encrypt("secret")
+		require.NoError(t, err)
+		_, err = kr2.Decrypt(encrypted)
+		assert.True(t, errors.Is(err, ErrEncryptionKeyNotFound))
+		var nilKeyring *Keyring
+		_, err = nilKeyring.Decrypt(encrypted)
+		assert.True(t, errors.Is(err, ErrEncryptionKeyNotFound))
+	})
+
+	t.Run("decryption fails when data is not valid", func(t *testing.T) {
+		t.Parallel()
+		kr1, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		kr2, err := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "other"}})
+		require.NoError(t, err)
+
+		encrypted, err := kr1.Encrypt("secret")
+		require.NoError(t, err)
+		_, err = kr2.Decrypt(encrypted)
+		assert.True(t, errors.Is(err, ErrInvalidEncryptedData))
+		_, err = kr1.Decrypt("enc:v1:key1:invalid")
+		assert.True(t, errors.Is(err, ErrInvalidEncryptedData))
+	})
+}
+
+func TestReencryptDBSecrets(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("encryption key not provided", func(t *testing.T) {
+		t.Parallel()
+		_, err := ReencryptDBSecrets(ctx, nil, nil)
+		assert.True(t, errors.Is(err, ErrEncryptionKeyNotProvided))
+	})
+
+	t.Run("error getting values", func(t *testing.T) {
+		t.Parallel()
+		kr, _ := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, mock.Anything).Return(nil, tests.ErrFakeDB)
+
+		_, err := ReencryptDBSecrets(ctx, db, kr)
+		assert.True(t, errors.Is(err, tests.ErrFakeDB))
+		db.AssertExpectations(t)
+	})
+
+	t.Run("values re-encrypted successfully", func(t *testing.T) {
+		t.Parallel()
+		kr1, _ := NewKeyring([]*EncryptionKey{{ID: "key1", Key: "secret1"}})
+		kr2, _ := NewKeyring([]*EncryptionKey{{ID: "key2", Key: "secret2"}, {ID: "key1", Key: "secret1"}})
+		encryptedKey1, _ := kr1.Encrypt("pass2")
+		encryptedKey2, _ := kr2.Encrypt("pass3")
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, mock.MatchedBy(func(q string) bool {
+			return strings.Contains(q, "'value', auth_pass)")
+		})).Return([]byte(`[
+			{"id": "repo1", "value": "pass1"},
+			{"id": "repo2", "value": "`+encryptedKey1+`"},
+			{"id": "repo3", "value": "`+encryptedKey2+`"}
+		]`), nil)
+		db.On("QueryRow", ctx, mock.Anything).Return([]byte(`[]`), nil)
+		reencrypted := make(map[string]string)
+		db.On("Exec", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				reencrypted[args.String(2)] = args.String(3)
+			}).
+			Return(nil)
+
+		count, err := ReencryptDBSecrets(ctx, db, kr2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+		require.Len(t, reencrypted, 2)
+		for id, expectedValue := range map[string]string{"repo1": "pass1", "repo2": "pass2"} {
+			assert.True(t, strings.HasPrefix(reencrypted[id], "enc:v1:key2:"))
+			value, err := kr2.Decrypt(reencrypted[id])
+			require.NoError(t, err)
+			assert.Equal(t, expectedValue, value)
+		}
+		db.AssertExpectations(t)
+	})
+}
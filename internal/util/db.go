@@ -20,6 +20,14 @@ import (
 // DBLockKeyUpdatePackagesViews represents the lock key used when updating
 const DBLockKeyUpdatePackagesViews = 1
 
+// DBLockKeyTrackRepository represents the lock key used, along with the
+// repository id, to prevent a repository from being tracked concurrently.
+const DBLockKeyTrackRepository = 2
+
+// DBMaxConns represents the maximum number of connections in the database
+// pool.
+const DBMaxConns = 50
+
 // ErrDBInsufficientPrivilege indicates that the user does not have the
 // required privilege to perform the operation.
 var ErrDBInsufficientPrivilege = errors.New("ERROR: insufficient_privilege (SQLSTATE 42501)")
@@ -38,7 +46,7 @@ func SetupDB(cfg *viper.Viper) (*pgxpool.Pool, error) {
 	if err != nil {
 		return nil, err
 	}
-	poolConfig.MaxConns = 50
+	poolConfig.MaxConns = DBMaxConns
 	poolConfig.MaxConnLifetime = 30 * time.Minute
 	poolConfig.HealthCheckPeriod = 30 * time.Second
 	poolConfig.ConnConfig.Logger = zerologadapter.NewLogger(log.Logger)
@@ -0,0 +1,175 @@
+package valuesdiff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Kinds of changes detected between two versions of a chart's values or
+// values schema.
+const (
+	Added          = "added"
+	Removed        = "removed"
+	DefaultChanged = "default_changed"
+	TypeChanged    = "type_changed"
+)
+
+// Change represents a change detected at a given path between two versions of
+// a chart's values or values schema.
+type Change struct {
+	Path string      `json:"path"`
+	Kind string      `json:"kind"`
+	From interface{} `json:"from,omitempty"`
+	To   interface{} `json:"to,omitempty"`
+}
+
+// Values returns the changes between the values provided. Nested objects are
+// compared recursively, whereas any other value (including arrays) is compared
+// as a whole.
+func Values(from, to map[string]interface{}) []*Change {
+	changes := make([]*Change, 0)
+	return diffValues(changes, "", from, to)
+}
+
+// diffValues appends to the list of changes provided the ones detected between
+// the objects provided, located at the given path.
+func diffValues(changes []*Change, path string, from, to map[string]interface{}) []*Change {
+	for _, key := range sortedKeys(from, to) {
+		fromValue, inFrom := from[key]
+		toValue, inTo := to[key]
+		keyPath := joinPath(path, key)
+		switch {
+		case !inTo:
+			changes = append(changes, &Change{Path: keyPath, Kind: Removed, From: fromValue})
+		case !inFrom:
+			changes = append(changes, &Change{Path: keyPath, Kind: Added, To: toValue})
+		default:
+			fromObject, fromIsObject := fromValue.(map[string]interface{})
+			toObject, toIsObject := toValue.(map[string]interface{})
+			switch {
+			case fromIsObject && toIsObject:
+				changes = diffValues(changes, keyPath, fromObject, toObject)
+			case typeOf(fromValue) != typeOf(toValue):
+				changes = append(changes, &Change{Path: keyPath, Kind: TypeChanged, From: fromValue, To: toValue})
+			case !reflect.DeepEqual(fromValue, toValue):
+				changes = append(changes, &Change{Path: keyPath, Kind: DefaultChanged, From: fromValue, To: toValue})
+			}
+		}
+	}
+	return changes
+}
+
+// Schemas returns the changes between the values JSON schemas provided. The
+// properties defined in the schemas are compared recursively, looking for
+// properties added or removed as well as for changes in their types or
+// default values. Array items are represented in the path using [].
+func Schemas(from, to []byte) ([]*Change, error) {
+	fromSchema, err := parseSchema(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from schema: %w", err)
+	}
+	toSchema, err := parseSchema(to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to schema: %w", err)
+	}
+	changes := make([]*Change, 0)
+	return diffSchemas(changes, "", fromSchema, toSchema), nil
+}
+
+// diffSchemas appends to the list of changes provided the ones detected
+// between the schemas provided, located at the given path.
+func diffSchemas(changes []*Change, path string, from, to map[string]interface{}) []*Change {
+	// Type and default value
+	if path != "" {
+		if !reflect.DeepEqual(from["type"], to["type"]) {
+			changes = append(changes, &Change{Path: path, Kind: TypeChanged, From: from["type"], To: to["type"]})
+		}
+		if !reflect.DeepEqual(from["default"], to["default"]) {
+			changes = append(changes, &Change{Path: path, Kind: DefaultChanged, From: from["default"], To: to["default"]})
+		}
+	}
+
+	// Properties
+	fromProperties, _ := from["properties"].(map[string]interface{})
+	toProperties, _ := to["properties"].(map[string]interface{})
+	for _, key := range sortedKeys(fromProperties, toProperties) {
+		fromProperty, inFrom := fromProperties[key].(map[string]interface{})
+		toProperty, inTo := toProperties[key].(map[string]interface{})
+		keyPath := joinPath(path, key)
+		switch {
+		case inFrom && !inTo:
+			changes = append(changes, &Change{Path: keyPath, Kind: Removed, From: fromProperty["type"]})
+		case !inFrom && inTo:
+			changes = append(changes, &Change{Path: keyPath, Kind: Added, To: toProperty["type"]})
+		case inFrom && inTo:
+			changes = diffSchemas(changes, keyPath, fromProperty, toProperty)
+		}
+	}
+
+	// Array items
+	fromItems, fromHasItems := from["items"].(map[string]interface{})
+	toItems, toHasItems := to["items"].(map[string]interface{})
+	if fromHasItems && toHasItems {
+		changes = diffSchemas(changes, path+"[]", fromItems, toItems)
+	}
+
+	return changes
+}
+
+// parseSchema parses the JSON schema provided. An empty schema is returned
+// when no data is provided.
+func parseSchema(data []byte) (map[string]interface{}, error) {
+	schema := make(map[string]interface{})
+	if len(data) == 0 {
+		return schema, nil
+	}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// joinPath returns the path of the key provided in the parent path given.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// sortedKeys returns the sorted union of the keys of the maps provided.
+func sortedKeys(m1, m2 map[string]interface{}) []string {
+	keys := make([]string, 0, len(m1)+len(m2))
+	for k := range m1 {
+		keys = append(keys, k)
+	}
+	for k := range m2 {
+		if _, ok := m1[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// typeOf returns the JSON type of the value provided.
+func typeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case int, int64, uint64, float64:
+		return "number"
+	default:
+		return reflect.TypeOf(v).String()
+	}
+}
@@ -0,0 +1,190 @@
+package valuesdiff
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValues(t *testing.T) {
+	t.Run("no changes", func(t *testing.T) {
+		t.Parallel()
+		values := map[string]interface{}{
+			"replicas": float64(1),
+			"image": map[string]interface{}{
+				"tag": "1.0.0",
+			},
+		}
+		assert.Empty(t, Values(values, values))
+		assert.Empty(t, Values(nil, nil))
+	})
+
+	t.Run("some changes", func(t *testing.T) {
+		t.Parallel()
+		from := map[string]interface{}{
+			"replicas": float64(1),
+			"image": map[string]interface{}{
+				"repository": "repo/img",
+				"tag":        "1.0.0",
+				"pullPolicy": "Always",
+			},
+			"ports":     []interface{}{float64(80)},
+			"resources": nil,
+			"debug":     false,
+		}
+		to := map[string]interface{}{
+			"replicas": "1",
+			"image": map[string]interface{}{
+				"repository": "repo/img",
+				"tag":        "2.0.0",
+			},
+			"ports":     []interface{}{float64(80), float64(443)},
+			"resources": map[string]interface{}{},
+			"debug":     false,
+			"service": map[string]interface{}{
+				"type": "ClusterIP",
+			},
+		}
+		assert.Equal(t, []*Change{
+			{
+				Path: "image.pullPolicy",
+				Kind: Removed,
+				From: "Always",
+			},
+			{
+				Path: "image.tag",
+				Kind: DefaultChanged,
+				From: "1.0.0",
+				To:   "2.0.0",
+			},
+			{
+				Path: "ports",
+				Kind: DefaultChanged,
+				From: []interface{}{float64(80)},
+				To:   []interface{}{float64(80), float64(443)},
+			},
+			{
+				Path: "replicas",
+				Kind: TypeChanged,
+				From: float64(1),
+				To:   "1",
+			},
+			{
+				Path: "resources",
+				Kind: TypeChanged,
+				To:   map[string]interface{}{},
+			},
+			{
+				Path: "service",
+				Kind: Added,
+				To: map[string]interface{}{
+					"type": "ClusterIP",
+				},
+			},
+		}, Values(from, to))
+	})
+}
+
+func TestSchemas(t *testing.T) {
+	t.Run("invalid from schema", func(t *testing.T) {
+		t.Parallel()
+		changes, err := Schemas([]byte("{["), nil)
+		assert.Nil(t, changes)
+		assert.ErrorContains(t, err, "invalid from schema")
+	})
+
+	t.Run("invalid to schema", func(t *testing.T) {
+		t.Parallel()
+		changes, err := Schemas(nil, []byte("{["))
+		assert.Nil(t, changes)
+		assert.ErrorContains(t, err, "invalid to schema")
+	})
+
+	t.Run("no schemas", func(t *testing.T) {
+		t.Parallel()
+		changes, err := Schemas(nil, nil)
+		require.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("some changes", func(t *testing.T) {
+		t.Parallel()
+		from := []byte(`{
+			"type": "object",
+			"properties": {
+				"replicas": {"type": "integer", "default": 1},
+				"image": {
+					"type": "object",
+					"properties": {
+						"tag": {"type": "string"},
+						"pullPolicy": {"type": "string", "default": "Always"}
+					}
+				},
+				"ports": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"properties": {
+							"port": {"type": "integer"}
+						}
+					}
+				}
+			}
+		}`)
+		to := []byte(`{
+			"type": "object",
+			"properties": {
+				"replicas": {"type": ["integer", "string"], "default": 2},
+				"image": {
+					"type": "object",
+					"properties": {
+						"tag": {"type": "string"}
+					}
+				},
+				"ports": {
+					"type": "array",
+					"items": {
+						"type": "object",
+						"properties": {
+							"port": {"type": "integer"},
+							"protocol": {"type": "string"}
+						}
+					}
+				},
+				"debug": {"type": "boolean"}
+			}
+		}`)
+		changes, err := Schemas(from, to)
+		require.NoError(t, err)
+		assert.Equal(t, []*Change{
+			{
+				Path: "debug",
+				Kind: Added,
+				To:   "boolean",
+			},
+			{
+				Path: "image.pullPolicy",
+				Kind: Removed,
+				From: "string",
+			},
+			{
+				Path: "ports[].protocol",
+				Kind: Added,
+				To:   "string",
+			},
+			{
+				Path: "replicas",
+				Kind: TypeChanged,
+				From: "integer",
+				To:   []interface{}{"integer", "string"},
+			},
+			{
+				Path: "replicas",
+				Kind: DefaultChanged,
+				From: float64(1),
+				To:   float64(2),
+			},
+		}, changes)
+	})
+}
@@ -3,12 +3,14 @@ package webhook
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/url"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/satori/uuid"
 )
 
@@ -20,19 +22,37 @@ const (
 	getOrgWebhooksDBQ             = `select * from get_org_webhooks($1::uuid, $2::text, $3::int, $4::int)`
 	getUserWebhooksDBQ            = `select * from get_user_webhooks($1::uuid, $2::int, $3::int)`
 	getWebhookDBQ                 = `select get_webhook($1::uuid, $2::uuid)`
+	getWebhookSecretDBQ           = `select coalesce(secret, '') from webhook where webhook_id = $2 and user_has_access_to_webhook($1::uuid, $2::uuid)`
 	updateWebhookDBQ              = `select update_webhook($1::uuid, $2::jsonb)`
 )
 
+// keepSecret represents the value returned in place of the webhooks secrets.
+// When provided on updates, the current secret is kept unchanged.
+const keepSecret = "="
+
 // Manager provides an API to manage webhooks.
 type Manager struct {
 	db hub.DB
+	kr *util.Keyring
 }
 
 // NewManager creates a new Manager instance.
-func NewManager(db hub.DB) *Manager {
-	return &Manager{
+func NewManager(db hub.DB, opts ...func(m *Manager)) *Manager {
+	m := &Manager{
 		db: db,
 	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+// WithKeyring allows providing the Keyring used to encrypt the webhooks
+// secrets for a Manager instance.
+func WithKeyring(kr *util.Keyring) func(m *Manager) {
+	return func(m *Manager) {
+		m.kr = kr
+	}
 }
 
 // Add adds the provided webhook to the database.
@@ -50,6 +70,9 @@ func (m *Manager) Add(ctx context.Context, orgName string, wh *hub.Webhook) erro
 	if err != nil || u.Scheme == "" || u.Host == "" {
 		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid url")
 	}
+	if wh.Secret == keepSecret {
+		return fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid secret")
+	}
 	if _, err := template.New("").Parse(wh.Template); err != nil {
 		return fmt.Errorf("%w: %s %w", hub.ErrInvalidInput, "invalid template", err)
 	}
@@ -66,7 +89,11 @@ func (m *Manager) Add(ctx context.Context, orgName string, wh *hub.Webhook) erro
 	}
 
 	// Add webhook to the database
-	whJSON, _ := json.Marshal(wh)
+	whToStore, err := m.encryptSecret(wh)
+	if err != nil {
+		return err
+	}
+	whJSON, _ := json.Marshal(whToStore)
 	_, err = m.db.Exec(ctx, addWebhookDBQ, userID, orgName, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
@@ -111,6 +138,31 @@ func (m *Manager) GetJSON(ctx context.Context, webhookID string) ([]byte, error)
 	return dataJSON, nil
 }
 
+// GetSecret returns the decrypted secret of the webhook provided. The user
+// doing the request must have access to the webhook.
+func (m *Manager) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	userID := ctx.Value(hub.UserIDKey).(string)
+
+	// Validate input
+	if _, err := uuid.FromString(webhookID); err != nil {
+		return "", fmt.Errorf("%w: %s", hub.ErrInvalidInput, "invalid webhook id")
+	}
+
+	// Get webhook secret from database and decrypt it
+	var secret string
+	if err := m.db.QueryRow(ctx, getWebhookSecretDBQ, userID, webhookID).Scan(&secret); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", hub.ErrInsufficientPrivilege
+		}
+		return "", err
+	}
+	secret, err := m.kr.Decrypt(secret)
+	if err != nil {
+		return "", fmt.Errorf("error decrypting secret: %w", err)
+	}
+	return secret, nil
+}
+
 // GetOwnedByOrgJSON returns the webhooks belonging to the provided organization
 // as a json array.
 func (m *Manager) GetOwnedByOrgJSON(
@@ -195,10 +247,29 @@ func (m *Manager) Update(ctx context.Context, wh *hub.Webhook) error {
 	}
 
 	// Update webhook in database
-	whJSON, _ := json.Marshal(wh)
+	whToStore, err := m.encryptSecret(wh)
+	if err != nil {
+		return err
+	}
+	whJSON, _ := json.Marshal(whToStore)
 	_, err = m.db.Exec(ctx, updateWebhookDBQ, userID, whJSON)
 	if err != nil && err.Error() == util.ErrDBInsufficientPrivilege.Error() {
 		return hub.ErrInsufficientPrivilege
 	}
 	return err
 }
+
+// encryptSecret returns a copy of the webhook provided with its secret
+// encrypted, ready to be stored in the database. The secret is stored as
+// provided when encryption is not enabled.
+func (m *Manager) encryptSecret(wh *hub.Webhook) (*hub.Webhook, error) {
+	whCopy := *wh
+	if m.kr.Enabled() && whCopy.Secret != "" && whCopy.Secret != keepSecret {
+		encrypted, err := m.kr.Encrypt(whCopy.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting secret: %w", err)
+		}
+		whCopy.Secret = encrypted
+	}
+	return &whCopy, nil
+}
@@ -2,12 +2,14 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/artifacthub/hub/internal/hub"
 	"github.com/artifacthub/hub/internal/tests"
 	"github.com/artifacthub/hub/internal/util"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -64,6 +66,15 @@ func TestAdd(t *testing.T) {
 					URL:  "invalidurl",
 				},
 			},
+			{
+				"invalid secret",
+				"org1",
+				&hub.Webhook{
+					Name:   "webhook",
+					URL:    "http://webhook1.url",
+					Secret: "=",
+				},
+			},
 			{
 				"invalid template",
 				"org1",
@@ -153,6 +164,27 @@ func TestAdd(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+
+	t.Run("add webhook with secret encrypted succeeded", func(t *testing.T) {
+		t.Parallel()
+		kr, err := util.NewKeyring([]*util.EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		whWithSecret := *wh
+		whWithSecret.Secret = "very"
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, addWebhookDBQ, "userID", "orgName", mock.MatchedBy(func(whJSON []byte) bool {
+			var whStored *hub.Webhook
+			_ = json.Unmarshal(whJSON, &whStored)
+			secret, err := kr.Decrypt(whStored.Secret)
+			return err == nil && util.IsEncrypted(whStored.Secret) && secret == "very"
+		})).Return(nil)
+		m := NewManager(db, WithKeyring(kr))
+
+		err = m.Add(ctx, "orgName", &whWithSecret)
+		assert.NoError(t, err)
+		assert.Equal(t, "very", whWithSecret.Secret)
+		db.AssertExpectations(t)
+	})
 }
 
 func TestDelete(t *testing.T) {
@@ -273,6 +305,70 @@ func TestGetJSON(t *testing.T) {
 	})
 }
 
+func TestGetSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
+
+	t.Run("user id not found in ctx", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		assert.Panics(t, func() {
+			_, _ = m.GetSecret(context.Background(), validUUID)
+		})
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		t.Parallel()
+		m := NewManager(nil)
+		_, err := m.GetSecret(ctx, "invalid")
+		assert.True(t, errors.Is(err, hub.ErrInvalidInput))
+	})
+
+	t.Run("database error", func(t *testing.T) {
+		testCases := []struct {
+			dbErr         error
+			expectedError error
+		}{
+			{
+				tests.ErrFakeDB,
+				tests.ErrFakeDB,
+			},
+			{
+				pgx.ErrNoRows,
+				hub.ErrInsufficientPrivilege,
+			},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.dbErr.Error(), func(t *testing.T) {
+				t.Parallel()
+				db := &tests.DBMock{}
+				db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return(nil, tc.dbErr)
+				m := NewManager(db)
+
+				secret, err := m.GetSecret(ctx, validUUID)
+				assert.Equal(t, tc.expectedError, err)
+				assert.Empty(t, secret)
+				db.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("encrypted secret returned decrypted", func(t *testing.T) {
+		t.Parallel()
+		kr, err := util.NewKeyring([]*util.EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		encryptedSecret, err := kr.Encrypt("very")
+		require.NoError(t, err)
+		db := &tests.DBMock{}
+		db.On("QueryRow", ctx, getWebhookSecretDBQ, "userID", validUUID).Return(encryptedSecret, nil)
+		m := NewManager(db, WithKeyring(kr))
+
+		secret, err := m.GetSecret(ctx, validUUID)
+		assert.NoError(t, err)
+		assert.Equal(t, "very", secret)
+		db.AssertExpectations(t)
+	})
+}
+
 func TestGetOwnedByOrgJSON(t *testing.T) {
 	ctx := context.WithValue(context.Background(), hub.UserIDKey, "userID")
 	p := &hub.Pagination{Limit: 10, Offset: 1}
@@ -575,4 +671,22 @@ func TestUpdate(t *testing.T) {
 		assert.NoError(t, err)
 		db.AssertExpectations(t)
 	})
+	t.Run("update webhook keeping current secret succeeded", func(t *testing.T) {
+		t.Parallel()
+		kr, err := util.NewKeyring([]*util.EncryptionKey{{ID: "key1", Key: "secret1"}})
+		require.NoError(t, err)
+		whWithSecret := *wh
+		whWithSecret.Secret = "="
+		db := &tests.DBMock{}
+		db.On("Exec", ctx, updateWebhookDBQ, "userID", mock.MatchedBy(func(whJSON []byte) bool {
+			var whStored *hub.Webhook
+			_ = json.Unmarshal(whJSON, &whStored)
+			return whStored.Secret == "="
+		})).Return(nil)
+		m := NewManager(db, WithKeyring(kr))
+
+		err = m.Update(ctx, &whWithSecret)
+		assert.NoError(t, err)
+		db.AssertExpectations(t)
+	})
 }
@@ -42,6 +42,12 @@ func (m *ManagerMock) GetOwnedByUserJSON(ctx context.Context, p *hub.Pagination)
 	return data, args.Error(1)
 }
 
+// GetSecret implements the WebhookManager interface.
+func (m *ManagerMock) GetSecret(ctx context.Context, webhookID string) (string, error) {
+	args := m.Called(ctx, webhookID)
+	return args.String(0), args.Error(1)
+}
+
 // GetSubscribedTo implements the WebhookManager interface.
 func (m *ManagerMock) GetSubscribedTo(ctx context.Context, e *hub.Event) ([]*hub.Webhook, error) {
 	args := m.Called(ctx, e)
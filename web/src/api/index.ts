@@ -453,6 +453,14 @@ class API_CLASS {
       displayName: 'display_name',
       authUser: 'auth_user',
       authPass: 'auth_pass',
+      authSshKey: 'auth_ssh_key',
+      authSshKnownHosts: 'auth_ssh_known_hosts',
+      authGithubAppId: 'auth_github_app_id',
+      authGithubAppInstallationId: 'auth_github_app_installation_id',
+      authGithubAppKey: 'auth_github_app_key',
+      gitUrl: 'git_url',
+      gitRef: 'git_ref',
+      gitSubdir: 'git_subdir',
       scannerDisabled: 'scanner_disabled',
     });
     return this.apiFetch({
@@ -481,6 +489,14 @@ class API_CLASS {
       displayName: 'display_name',
       authUser: 'auth_user',
       authPass: 'auth_pass',
+      authSshKey: 'auth_ssh_key',
+      authSshKnownHosts: 'auth_ssh_known_hosts',
+      authGithubAppId: 'auth_github_app_id',
+      authGithubAppInstallationId: 'auth_github_app_installation_id',
+      authGithubAppKey: 'auth_github_app_key',
+      gitUrl: 'git_url',
+      gitRef: 'git_ref',
+      gitSubdir: 'git_subdir',
       scannerDisabled: 'scanner_disabled',
     });
     return this.apiFetch({
@@ -773,7 +789,11 @@ class API_CLASS {
   }
 
   public triggerWebhookTest(webhook: TestWebhook): Promise<string | null> {
-    const formattedWebhook = renameKeysInObject(webhook, { contentType: 'content_type', eventKinds: 'event_kinds' });
+    const formattedWebhook = renameKeysInObject(webhook, {
+      webhookId: 'webhook_id',
+      contentType: 'content_type',
+      eventKinds: 'event_kinds',
+    });
 
     return this.apiFetch({
       url: `${this.API_BASE_URL}/webhooks/test`,
@@ -40,6 +40,9 @@ const repoMock1: Repository = {
   displayName: 'Repo test',
   url: 'https://github.com/repo/test',
   branch: '',
+  gitUrl: '',
+  gitRef: '',
+  gitSubdir: '',
   disabled: false,
   scannerDisabled: false,
   authUser: null,
@@ -682,6 +685,11 @@ describe('Repository Modal - repositories section', () => {
                 displayName: 'Repo test1',
                 authUser: '=',
                 authPass: '=',
+                authSshKey: '=',
+                authSshKnownHosts: '=',
+                authGithubAppId: '=',
+                authGithubAppInstallationId: '=',
+                authGithubAppKey: '=',
               },
               undefined
             );
@@ -862,6 +870,11 @@ describe('Repository Modal - repositories section', () => {
                 displayName: 'Repo test1',
                 authUser: null,
                 authPass: '=',
+                authSshKey: '=',
+                authSshKnownHosts: '=',
+                authGithubAppId: '=',
+                authGithubAppInstallationId: '=',
+                authGithubAppKey: '=',
               },
               undefined
             );
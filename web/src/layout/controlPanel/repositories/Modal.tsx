@@ -14,6 +14,8 @@ import API from '../../../api';
 import { AppCtx } from '../../../context/AppCtx';
 import {
   ContainerTag,
+  ContainerTagPattern,
+  ContainerTagsDiscovery,
   ErrorKind,
   RefInputField,
   Repository,
@@ -28,6 +30,7 @@ import ExternalLink from '../../common/ExternalLink';
 import InputField from '../../common/InputField';
 import Modal from '../../common/Modal';
 import styles from './Modal.module.css';
+import TagsDiscovery from './TagsDiscovery';
 import TagsList from './TagsList';
 
 interface FormValidation {
@@ -86,6 +89,14 @@ const RepositoryModal = (props: Props) => {
 
   const [containerTags, setContainerTags] = useState<ContainerTag[]>(prepareTags());
   const [repeatedTagNames, setRepeatedTagNames] = useState<boolean>(false);
+  const [tagsDiscovery, setTagsDiscovery] = useState<ContainerTagsDiscovery | null>(
+    props.repository && props.repository.data && props.repository.data.discovery
+      ? props.repository.data.discovery
+      : null
+  );
+  const [helmNamespace, setHelmNamespace] = useState<boolean>(
+    props.repository && props.repository.data && props.repository.data.namespace ? true : false
+  );
   const [versioning, setVersioning] = useState<VersioningOption>(
     props.repository && props.repository.data && props.repository.data.versioning
       ? props.repository.data.versioning
@@ -186,12 +197,49 @@ const RepositoryModal = (props: Props) => {
           authPass: !isUndefined(props.repository) && props.repository.private && !resetFields ? '=' : authPass,
         };
 
+        // Git source settings are only available for git based repositories
+        if (formData.has('gitUrl')) {
+          repository = {
+            ...repository,
+            gitUrl: formData.get('gitUrl') as string,
+            gitRef: formData.get('gitRef') as string,
+            gitSubdir: formData.get('gitSubdir') as string,
+          };
+        }
+
+        // Keep git authentication settings (SSH key, GitHub App) unchanged
+        if (!isUndefined(props.repository) && props.repository.private && !resetFields) {
+          repository = {
+            ...repository,
+            authSshKey: '=',
+            authSshKnownHosts: '=',
+            authGithubAppId: '=',
+            authGithubAppInstallationId: '=',
+            authGithubAppKey: '=',
+          };
+        }
+
         if (selectedKind === RepositoryKind.Container) {
           const cleanTags = containerTags.filter((tag: ContainerTag) => tag.name !== '');
           const readyTags = cleanTags.map((tag: ContainerTag) => ({ name: tag.name, mutable: tag.mutable }));
           repository.data = {
             tags: readyTags,
           };
+          if (!isNull(tagsDiscovery)) {
+            repository.data.discovery = {
+              ...tagsDiscovery,
+              include: tagsDiscovery.include.filter((item: ContainerTagPattern) => item.pattern !== ''),
+              exclude: compact(tagsDiscovery.exclude),
+            };
+          }
+        }
+
+        if (selectedKind === RepositoryKind.Helm && helmNamespace) {
+          const listing = formData.get('listing') as string;
+          repository.data = {
+            namespace: true,
+            ...(listing !== '' ? { listing: listing } : {}),
+          };
         }
 
         if (
@@ -681,6 +729,29 @@ const RepositoryModal = (props: Props) => {
       case RepositoryKind.Kagent:
         return undefined;
       case RepositoryKind.OLM:
+      case RepositoryKind.ArgoTemplate:
+      case RepositoryKind.Backstage:
+      case RepositoryKind.Bootc:
+      case RepositoryKind.CoreDNS:
+      case RepositoryKind.Falco:
+      case RepositoryKind.Gatekeeper:
+      case RepositoryKind.Headlamp:
+      case RepositoryKind.InspektorGadget:
+      case RepositoryKind.KCL:
+      case RepositoryKind.KedaScaler:
+      case RepositoryKind.Keptn:
+      case RepositoryKind.KnativeClientPlugin:
+      case RepositoryKind.KubeArmor:
+      case RepositoryKind.Kubewarden:
+      case RepositoryKind.Kyverno:
+      case RepositoryKind.MesheryDesign:
+      case RepositoryKind.OPA:
+      case RepositoryKind.OpenCost:
+      case RepositoryKind.RadiusRecipe:
+      case RepositoryKind.TBAction:
+      case RepositoryKind.TektonPipeline:
+      case RepositoryKind.TektonStepAction:
+      case RepositoryKind.TektonTask:
         return `^((https?://)|${OCI_PREFIX}).*`;
       case RepositoryKind.Container:
         return `^${OCI_PREFIX}.*`;
@@ -970,6 +1041,59 @@ const RepositoryModal = (props: Props) => {
 
             {getAdditionalInfo()}
 
+            {selectedKind === RepositoryKind.Helm && (
+              <div className="mt-4 mb-3">
+                <div className="form-check form-switch ps-0">
+                  <label htmlFor="helmNamespace" className={`form-check-label fw-bold ${styles.label}`}>
+                    Track all charts in namespace
+                  </label>{' '}
+                  <input
+                    id="helmNamespace"
+                    type="checkbox"
+                    className="form-check-input position-absolute ms-2"
+                    value="true"
+                    role="switch"
+                    onChange={() => setHelmNamespace(!helmNamespace)}
+                    checked={helmNamespace}
+                  />
+                </div>
+
+                <div className="form-text text-muted mt-2">
+                  Use this switch when the url provided (<span className="fw-bold">oci://registry/namespace</span>)
+                  points to a registry namespace containing multiple charts.
+                </div>
+
+                {helmNamespace && (
+                  <div className="mt-3">
+                    <InputField
+                      type="text"
+                      label="Listing"
+                      name="listing"
+                      placeholder={`${OCI_PREFIX}registry/namespace/listing:latest`}
+                      pattern={`^${OCI_PREFIX}.*`}
+                      invalidText={{
+                        default: 'Please enter a valid OCI reference',
+                        patternMismatch: 'Please enter a valid OCI reference',
+                      }}
+                      additionalInfo={
+                        <small className="text-muted text-break mt-1">
+                          <p className="mb-0">
+                            Optional reference to an artifact listing the charts available in the namespace. When not
+                            provided, the registry catalog will be used.
+                          </p>
+                        </small>
+                      }
+                      value={
+                        !isUndefined(props.repository) && props.repository.data && props.repository.data.listing
+                          ? props.repository.data.listing
+                          : ''
+                      }
+                    />
+                  </div>
+                )}
+              </div>
+            )}
+
             {selectedKind === RepositoryKind.Container && (
               <TagsList
                 tags={containerTags}
@@ -979,6 +1103,10 @@ const RepositoryModal = (props: Props) => {
               />
             )}
 
+            {selectedKind === RepositoryKind.Container && (
+              <TagsDiscovery discovery={tagsDiscovery} setDiscovery={setTagsDiscovery} />
+            )}
+
             {[
               RepositoryKind.Falco,
               RepositoryKind.OLM,
@@ -1023,6 +1151,58 @@ const RepositoryModal = (props: Props) => {
                   }
                   value={!isUndefined(props.repository) && props.repository.branch ? props.repository.branch : ''}
                 />
+
+                <InputField
+                  type="text"
+                  label="Git URL"
+                  name="gitUrl"
+                  placeholder="https://gitea.example.com/group/subgroup/repo.git"
+                  additionalInfo={
+                    <small className="text-muted text-break mt-1">
+                      <p className="mb-0">
+                        Git remote url (https, ssh or scp-like). Use it when your repository is not hosted in GitHub,
+                        GitLab or Bitbucket, or its url does not follow the <span className="fw-bold">org/repo</span>{' '}
+                        format. When it is provided, the url above is not used to locate the git repository.
+                      </p>
+                    </small>
+                  }
+                  value={!isUndefined(props.repository) && props.repository.gitUrl ? props.repository.gitUrl : ''}
+                />
+
+                <InputField
+                  type="text"
+                  label="Git ref"
+                  name="gitRef"
+                  placeholder="main"
+                  additionalInfo={
+                    <small className="text-muted text-break mt-1">
+                      <p className="mb-0">
+                        Branch name, tag (<span className="fw-bold">refs/tags/v1.0.0</span>) or commit hash to track.
+                        It takes precedence over the branch. When a git url is provided and neither is set, the remote
+                        default branch is used.
+                      </p>
+                    </small>
+                  }
+                  value={!isUndefined(props.repository) && props.repository.gitRef ? props.repository.gitRef : ''}
+                />
+
+                <InputField
+                  type="text"
+                  label="Git subdirectory"
+                  name="gitSubdir"
+                  placeholder="path/to/packages"
+                  additionalInfo={
+                    <small className="text-muted text-break mt-1">
+                      <p className="mb-0">
+                        Path in the git repository where your packages are located. Only used when a git url is
+                        provided.
+                      </p>
+                    </small>
+                  }
+                  value={
+                    !isUndefined(props.repository) && props.repository.gitSubdir ? props.repository.gitSubdir : ''
+                  }
+                />
               </div>
             )}
 
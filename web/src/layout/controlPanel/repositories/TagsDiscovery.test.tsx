@@ -0,0 +1,99 @@
+import { render, screen } from '@testing-library/react';
+import userEvent from '@testing-library/user-event';
+
+import TagsDiscovery, { DEFAULT_DISCOVERY } from './TagsDiscovery';
+
+const setDiscoveryMock = jest.fn();
+
+const defaultProps = {
+  discovery: {
+    include: [
+      { pattern: '^v[0-9.]+$', mutable: false },
+      { pattern: '^latest$', mutable: true },
+    ],
+    exclude: ['-rc'],
+    semver: false,
+    latest: 0,
+  },
+  setDiscovery: setDiscoveryMock,
+};
+
+describe('TagsDiscovery', () => {
+  afterEach(() => {
+    jest.resetAllMocks();
+  });
+
+  describe('Render', () => {
+    it('renders component', () => {
+      render(<TagsDiscovery {...defaultProps} />);
+
+      expect(screen.getByText('Discover tags from registry')).toBeInTheDocument();
+      expect(screen.getByRole('button', { name: 'Add include pattern' })).toBeInTheDocument();
+      expect(screen.getByRole('button', { name: 'Add exclude pattern' })).toBeInTheDocument();
+      expect(screen.getByDisplayValue('^v[0-9.]+$')).toBeInTheDocument();
+      expect(screen.getByDisplayValue('^latest$')).toBeInTheDocument();
+      expect(screen.getByDisplayValue('-rc')).toBeInTheDocument();
+
+      const switches = screen.getAllByRole('switch');
+      expect(switches).toHaveLength(4);
+      expect(switches[0]).toBeChecked();
+      expect(switches[1]).not.toBeChecked();
+      expect(switches[2]).toBeChecked();
+      expect(switches[3]).not.toBeChecked();
+      expect(screen.getByRole('spinbutton')).toBeDisabled();
+    });
+
+    it('renders only discovery switch when disabled', () => {
+      render(<TagsDiscovery discovery={null} setDiscovery={setDiscoveryMock} />);
+
+      expect(screen.getAllByRole('switch')).toHaveLength(1);
+      expect(screen.getByRole('switch')).not.toBeChecked();
+      expect(screen.queryByRole('button', { name: 'Add include pattern' })).toBeNull();
+    });
+
+    it('enables discovery', async () => {
+      render(<TagsDiscovery discovery={null} setDiscovery={setDiscoveryMock} />);
+
+      await userEvent.click(screen.getByRole('switch'));
+
+      expect(setDiscoveryMock).toHaveBeenCalledTimes(1);
+      expect(setDiscoveryMock).toHaveBeenCalledWith(DEFAULT_DISCOVERY);
+    });
+
+    it('adds include pattern', async () => {
+      render(<TagsDiscovery {...defaultProps} />);
+
+      await userEvent.click(screen.getByRole('button', { name: 'Add include pattern' }));
+
+      expect(setDiscoveryMock).toHaveBeenCalledTimes(1);
+      expect(setDiscoveryMock).toHaveBeenCalledWith({
+        ...defaultProps.discovery,
+        include: [...defaultProps.discovery.include, { pattern: '', mutable: false }],
+      });
+    });
+
+    it('deletes exclude pattern', async () => {
+      render(<TagsDiscovery {...defaultProps} />);
+
+      await userEvent.click(screen.getByRole('button', { name: 'Delete exclude pattern' }));
+
+      expect(setDiscoveryMock).toHaveBeenCalledTimes(1);
+      expect(setDiscoveryMock).toHaveBeenCalledWith({ ...defaultProps.discovery, exclude: [] });
+    });
+
+    it('updates include pattern mutability', async () => {
+      render(<TagsDiscovery {...defaultProps} />);
+
+      await userEvent.click(screen.getAllByRole('switch')[1]);
+
+      expect(setDiscoveryMock).toHaveBeenCalledTimes(1);
+      expect(setDiscoveryMock).toHaveBeenCalledWith({
+        ...defaultProps.discovery,
+        include: [
+          { pattern: '^v[0-9.]+$', mutable: true },
+          { pattern: '^latest$', mutable: true },
+        ],
+      });
+    });
+  });
+});
@@ -0,0 +1,237 @@
+import isNull from 'lodash/isNull';
+import { ChangeEvent, Dispatch, MouseEvent as ReactMouseEvent, SetStateAction } from 'react';
+import { HiPlus } from 'react-icons/hi';
+import { MdClose } from 'react-icons/md';
+
+import { ContainerTagsDiscovery } from '../../../types';
+import InputField from '../../common/InputField';
+import styles from './TagsList.module.css';
+
+interface Props {
+  discovery: ContainerTagsDiscovery | null;
+  setDiscovery: Dispatch<SetStateAction<ContainerTagsDiscovery | null>>;
+}
+
+type PatternsKind = 'include' | 'exclude';
+
+export const DEFAULT_DISCOVERY: ContainerTagsDiscovery = { include: [], exclude: [], semver: true, latest: 5 };
+const MAX_PATTERNS = 10;
+const MAX_LATEST = 50;
+
+const TagsDiscovery = (props: Props) => {
+  const { discovery } = props;
+  const patternsNumber = isNull(discovery) ? 0 : discovery.include.length + discovery.exclude.length;
+
+  const updateDiscovery = (changes: Partial<ContainerTagsDiscovery>) => {
+    props.setDiscovery({ ...discovery!, ...changes });
+  };
+
+  const addPattern = (kind: PatternsKind) => {
+    if (kind === 'include') {
+      updateDiscovery({ include: [...discovery!.include, { pattern: '', mutable: false }] });
+    } else {
+      updateDiscovery({ exclude: [...discovery!.exclude, ''] });
+    }
+  };
+
+  const deletePattern = (kind: PatternsKind, index: number) => {
+    if (kind === 'include') {
+      const include = [...discovery!.include];
+      include.splice(index, 1);
+      updateDiscovery({ include: include });
+    } else {
+      const exclude = [...discovery!.exclude];
+      exclude.splice(index, 1);
+      updateDiscovery({ exclude: exclude });
+    }
+  };
+
+  const onUpdateInclude = (index: number, field: 'pattern' | 'mutable', value?: string) => {
+    const include = [...discovery!.include];
+    if (field === 'pattern') {
+      include[index] = { ...include[index], pattern: value as string };
+    } else {
+      include[index] = { ...include[index], mutable: !include[index].mutable };
+    }
+    updateDiscovery({ include: include });
+  };
+
+  const onUpdateExclude = (index: number, value: string) => {
+    const exclude = [...discovery!.exclude];
+    exclude[index] = value;
+    updateDiscovery({ exclude: exclude });
+  };
+
+  const renderDeleteBtn = (kind: PatternsKind, index: number) => (
+    <div className={`position-relative text-end ${styles.btnWrapper}`}>
+      <button
+        className={`btn btn-danger btn-sm ms-auto p-0 position-relative lh-1 ${styles.btn}`}
+        type="button"
+        onClick={(event: ReactMouseEvent<HTMLButtonElement, MouseEvent>) => {
+          event.preventDefault();
+          event.stopPropagation();
+          deletePattern(kind, index);
+        }}
+        aria-label={`Delete ${kind} pattern`}
+      >
+        <MdClose />
+      </button>
+    </div>
+  );
+
+  const renderLabel = (kind: PatternsKind, label: string) => (
+    <label className={`form-check-label fw-bold mb-2 ${styles.label}`}>
+      {label}
+      <button
+        type="button"
+        className={`btn btn-primary btn-sm ms-2 p-0 position-relative lh-1 ${styles.btn} ${styles.inTitle}`}
+        onClick={() => addPattern(kind)}
+        disabled={patternsNumber >= MAX_PATTERNS}
+        aria-label={`Add ${kind} pattern`}
+      >
+        <HiPlus />
+      </button>
+    </label>
+  );
+
+  return (
+    <div className="mb-4">
+      <div className="form-check form-switch ps-0 mb-2">
+        <label htmlFor="tagsDiscovery" className={`form-check-label fw-bold ${styles.label}`}>
+          Discover tags from registry
+        </label>
+        <input
+          id="tagsDiscovery"
+          type="checkbox"
+          className="form-check-input position-absolute ms-2"
+          role="switch"
+          value="true"
+          checked={!isNull(discovery)}
+          onChange={() => props.setDiscovery(isNull(discovery) ? { ...DEFAULT_DISCOVERY } : null)}
+        />
+      </div>
+
+      <div className="form-text text-muted mt-0 mb-3">
+        When enabled, tags available in the registry will be discovered automatically and processed in addition to the
+        ones listed above. Up to <span className="fw-bold">{MAX_LATEST}</span> tags will be discovered.
+      </div>
+
+      {!isNull(discovery) && (
+        <>
+          <div className="mb-3">
+            {renderLabel('include', 'Include patterns')}
+            {discovery.include.map((item, idx: number) => (
+              <div
+                className="d-flex flex-row align-items-stretch justify-content-between"
+                key={`include_${item.pattern}_${idx}`}
+              >
+                <InputField
+                  className="flex-grow-1"
+                  type="text"
+                  name={`include_${idx}`}
+                  autoComplete="off"
+                  value={item.pattern}
+                  placeholder="Regular expression"
+                  onBlur={(e: ChangeEvent<HTMLInputElement>) => {
+                    onUpdateInclude(idx, 'pattern', e.target.value);
+                  }}
+                  smallBottomMargin
+                />
+
+                <div className="d-flex flex-row align-items-center mb-3 ms-3 flex-nowrap">
+                  <div className={`ms-2 me-5 position-relative ${styles.inputWrapper}`}>
+                    <div className="form-check form-switch ps-0">
+                      <label htmlFor={`include_mutable_${idx}`} className={`form-check-label fw-bold ${styles.label}`}>
+                        Mutable
+                      </label>
+                      <input
+                        id={`include_mutable_${idx}`}
+                        type="checkbox"
+                        className="form-check-input position-absolute ms-2"
+                        role="switch"
+                        value="true"
+                        checked={item.mutable}
+                        onChange={() => {
+                          onUpdateInclude(idx, 'mutable');
+                        }}
+                      />
+                    </div>
+                  </div>
+                  {renderDeleteBtn('include', idx)}
+                </div>
+              </div>
+            ))}
+            <div className="form-text text-muted mt-0">
+              Tags must match some of these patterns to be selected (all tags are selected when none is provided).
+              Tags will inherit the mutability of the first pattern they match.
+            </div>
+          </div>
+
+          <div className="mb-3">
+            {renderLabel('exclude', 'Exclude patterns')}
+            {discovery.exclude.map((pattern: string, idx: number) => (
+              <div
+                className="d-flex flex-row align-items-stretch justify-content-between"
+                key={`exclude_${pattern}_${idx}`}
+              >
+                <InputField
+                  className="flex-grow-1"
+                  type="text"
+                  name={`exclude_${idx}`}
+                  autoComplete="off"
+                  value={pattern}
+                  placeholder="Regular expression"
+                  onBlur={(e: ChangeEvent<HTMLInputElement>) => {
+                    onUpdateExclude(idx, e.target.value);
+                  }}
+                  smallBottomMargin
+                />
+                <div className="d-flex flex-row align-items-center mb-3 ms-3 flex-nowrap">
+                  {renderDeleteBtn('exclude', idx)}
+                </div>
+              </div>
+            ))}
+            <div className="form-text text-muted mt-0">Tags matching any of these patterns will be skipped.</div>
+          </div>
+
+          <div className="d-flex flex-row align-items-start">
+            <div className="form-check form-switch ps-0 mt-4 me-5">
+              <label htmlFor="tagsDiscoverySemver" className={`form-check-label fw-bold ${styles.label}`}>
+                Only semver tags
+              </label>
+              <input
+                id="tagsDiscoverySemver"
+                type="checkbox"
+                className="form-check-input position-absolute ms-2"
+                role="switch"
+                value="true"
+                checked={discovery.semver}
+                onChange={() => updateDiscovery({ semver: !discovery.semver, latest: 0 })}
+              />
+            </div>
+
+            <InputField
+              type="number"
+              label="Latest versions"
+              name="tagsDiscoveryLatest"
+              value={discovery.latest.toString()}
+              min={0}
+              max={MAX_LATEST}
+              disabled={!discovery.semver}
+              onChange={(e: ChangeEvent<HTMLInputElement>) => {
+                updateDiscovery({ latest: parseInt(e.target.value) || 0 });
+              }}
+              additionalInfo={
+                <small className="text-muted text-break mt-1">
+                  <p className="mb-0">Number of most recent versions to list (0 means no limit).</p>
+                </small>
+              }
+            />
+          </div>
+        </>
+      )}
+    </div>
+  );
+};
+
+export default TagsDiscovery;
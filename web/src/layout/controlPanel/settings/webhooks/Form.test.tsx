@@ -680,8 +680,10 @@ describe('WebhookForm', () => {
       await waitFor(() => {
         expect(API.triggerWebhookTest).toHaveBeenCalledTimes(1);
         expect(API.triggerWebhookTest).toHaveBeenCalledWith({
+          webhookId: mockWebhook.webhookId,
           url: mockWebhook.url,
           eventKinds: mockWebhook.eventKinds,
+          secret: mockWebhook.secret,
         });
       });
 
@@ -763,8 +765,10 @@ describe('WebhookForm', () => {
         await waitFor(() => {
           expect(API.triggerWebhookTest).toHaveBeenCalledTimes(1);
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
+            webhookId: mockWebhook.webhookId,
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            secret: mockWebhook.secret,
           });
         });
 
@@ -795,8 +799,10 @@ describe('WebhookForm', () => {
         await waitFor(() => {
           expect(API.triggerWebhookTest).toHaveBeenCalledTimes(1);
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
+            webhookId: mockWebhook.webhookId,
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            secret: mockWebhook.secret,
           });
         });
 
@@ -826,8 +832,10 @@ describe('WebhookForm', () => {
         await waitFor(() => {
           expect(API.triggerWebhookTest).toHaveBeenCalledTimes(1);
           expect(API.triggerWebhookTest).toHaveBeenCalledWith({
+            webhookId: mockWebhook.webhookId,
             url: mockWebhook.url,
             eventKinds: mockWebhook.eventKinds,
+            secret: mockWebhook.secret,
           });
         });
 
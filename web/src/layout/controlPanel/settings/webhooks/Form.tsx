@@ -159,9 +159,14 @@ const WebhookForm = (props: Props) => {
   }
 
   const triggerTest = () => {
-    if (!isNull(currentTestWebhook)) {
+    if (!isNull(currentTestWebhook) && form.current) {
       cleanApiError();
-      triggerWebhookTest(currentTestWebhook);
+      const formData = new FormData(form.current);
+      triggerWebhookTest({
+        ...currentTestWebhook,
+        webhookId: !isUndefined(props.webhook) ? props.webhook.webhookId : undefined,
+        secret: (formData.get('secret') as string) || undefined,
+      });
     }
   };
 
@@ -377,6 +382,8 @@ const WebhookForm = (props: Props) => {
             <div className="form-text text-muted mb-2 mt-0">
               If you provide a secret, we'll send it to you in the <span className="fw-bold">X-ArtifactHub-Secret</span>{' '}
               header on each request. This will allow you to validate that the request comes from ArtifactHub.
+              Secrets are stored encrypted and are not displayed once saved: leave the value{' '}
+              <span className="fw-bold">=</span> to keep the current secret.
             </div>
             <div className="d-flex">
               <div className="col-md-8">
@@ -26,6 +26,7 @@ import Dependencies from './Dependencies';
 import styles from './Details.module.css';
 import Flavors from './Flavors';
 import Keywords from './Keywords';
+import KubernetesCompatibility from './KubernetesCompatibility';
 import Last30DaysViews from './Last30DaysViews';
 import LastYearActivity from './LastYearActivity';
 import License from './License';
@@ -179,6 +180,11 @@ const Details = (props: Props) => {
                         </p>
                       </div>
                     )}
+
+                    <KubernetesCompatibility
+                      incompatibleVersions={props.package.data.kubeIncompatibleVersions}
+                      apiDeprecations={props.package.data.apiDeprecations}
+                    />
                   </>
                 )}
               </>
@@ -0,0 +1,54 @@
+import { render, screen } from '@testing-library/react';
+
+import KubernetesCompatibility, { groupVersions } from './KubernetesCompatibility';
+
+const defaultProps = {
+  incompatibleVersions: ['1.16', '1.17', '1.25', '1.26', '1.27', '1.33'],
+  apiDeprecations: [
+    {
+      apiVersion: 'policy/v1beta1',
+      kind: 'PodDisruptionBudget',
+      deprecatedIn: '1.21',
+      removedIn: '1.25',
+      replacement: 'policy/v1',
+    },
+    {
+      apiVersion: 'policy/v1beta1',
+      kind: 'PodSecurityPolicy',
+      deprecatedIn: '1.21',
+      removedIn: '1.25',
+    },
+  ],
+};
+
+describe('KubernetesCompatibility', () => {
+  afterEach(() => {
+    jest.resetAllMocks();
+  });
+
+  describe('Render', () => {
+    it('renders component', () => {
+      render(<KubernetesCompatibility {...defaultProps} />);
+
+      expect(screen.getByText('Incompatible Kubernetes versions')).toBeInTheDocument();
+      expect(screen.getByTestId('kubeIncompatibleVersions')).toHaveTextContent('1.16 - 1.17, 1.25 - 1.27, 1.33');
+      expect(screen.getByText('Deprecated APIs')).toBeInTheDocument();
+      expect(screen.getAllByRole('listitem')).toHaveLength(2);
+      expect(screen.getByText('PodDisruptionBudget')).toBeInTheDocument();
+      expect(screen.getByText('Removed in 1.25 (use policy/v1)')).toBeInTheDocument();
+    });
+
+    it('does not render component when there is no data', () => {
+      const { container } = render(<KubernetesCompatibility incompatibleVersions={[]} />);
+      expect(container).toBeEmptyDOMElement();
+    });
+  });
+
+  describe('groupVersions', () => {
+    it('groups consecutive versions', () => {
+      expect(groupVersions([])).toEqual([]);
+      expect(groupVersions(['1.20'])).toEqual(['1.20']);
+      expect(groupVersions(['1.20', '1.21', '1.23'])).toEqual(['1.20 - 1.21', '1.23']);
+    });
+  });
+});
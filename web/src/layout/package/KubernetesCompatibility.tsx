@@ -0,0 +1,80 @@
+import isUndefined from 'lodash/isUndefined';
+
+import { APIDeprecation } from '../../types';
+import SmallTitle from '../common/SmallTitle';
+import styles from './KubernetesCompatibility.module.css';
+
+interface Props {
+  incompatibleVersions?: string[];
+  apiDeprecations?: APIDeprecation[];
+}
+
+// Groups consecutive Kubernetes versions into ranges (i.e. 1.25 - 1.33)
+export const groupVersions = (versions: string[]): string[] => {
+  const ranges: string[] = [];
+  let start: string | null = null;
+  let prev: string | null = null;
+
+  const getMinor = (version: string): number => parseInt(version.split('.')[1]);
+  const closeRange = () => {
+    if (start !== null && prev !== null) {
+      ranges.push(start === prev ? start : `${start} - ${prev}`);
+    }
+  };
+
+  versions.forEach((version: string) => {
+    if (prev === null || getMinor(version) !== getMinor(prev) + 1) {
+      closeRange();
+      start = version;
+    }
+    prev = version;
+  });
+  closeRange();
+
+  return ranges;
+};
+
+const KubernetesCompatibility = (props: Props) => {
+  const hasIncompatibleVersions = !isUndefined(props.incompatibleVersions) && props.incompatibleVersions.length > 0;
+  const hasAPIDeprecations = !isUndefined(props.apiDeprecations) && props.apiDeprecations.length > 0;
+
+  if (!hasIncompatibleVersions && !hasAPIDeprecations) return null;
+
+  return (
+    <>
+      {hasIncompatibleVersions && (
+        <div>
+          <SmallTitle text="Incompatible Kubernetes versions" />
+          <p data-testid="kubeIncompatibleVersions" className={`text-break ${styles.text}`}>
+            {groupVersions(props.incompatibleVersions!).join(', ')}
+          </p>
+        </div>
+      )}
+
+      {hasAPIDeprecations && (
+        <div>
+          <SmallTitle text="Deprecated APIs" id="api-deprecations-list" />
+          <div className="mb-3" role="list" aria-describedby="api-deprecations-list">
+            {props.apiDeprecations!.map((deprecation: APIDeprecation) => (
+              <div
+                key={`${deprecation.apiVersion}/${deprecation.kind}`}
+                className={`text-break pb-1 ${styles.text}`}
+                role="listitem"
+              >
+                <div className="text-truncate">
+                  <span className="fw-bold">{deprecation.kind}</span> ({deprecation.apiVersion})
+                </div>
+                <small className="text-muted">
+                  Removed in {deprecation.removedIn}
+                  {deprecation.replacement && <> (use {deprecation.replacement})</>}
+                </small>
+              </div>
+            ))}
+          </div>
+        </div>
+      )}
+    </>
+  );
+};
+
+export default KubernetesCompatibility;
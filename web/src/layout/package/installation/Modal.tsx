@@ -198,6 +198,7 @@ const InstallationModal = (props: Props) => {
                             case InstallMethodKind.Tekton:
                               return (
                                 <TektonInstall
+                                  name={method.props.name}
                                   contentUrl={method.props.contentUrl!}
                                   isPrivate={method.props.isPrivate}
                                   repository={method.props.repository!}
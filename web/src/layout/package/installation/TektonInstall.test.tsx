@@ -62,5 +62,21 @@ describe('TektonInstall', () => {
         expect(await screen.findByText('kubectl apply -f PIPELINE_RAW_YAML_URL')).toBeInTheDocument();
       });
     });
+
+    it('renders tekton bundle', async () => {
+      render(
+        <TektonInstall
+          {...defaultProps}
+          name="task1"
+          contentUrl="oci://registry.io/org/tasks:0.1.0"
+          repository={{ ...repo, url: 'oci://registry.io/org/tasks' }}
+        />
+      );
+
+      expect(screen.getByText('Install the task:')).toBeInTheDocument();
+      expect(
+        await screen.findByText('tkn bundle list registry.io/org/tasks:0.1.0 task task1 -o yaml | kubectl apply -f -')
+      ).toBeInTheDocument();
+    });
   });
 });
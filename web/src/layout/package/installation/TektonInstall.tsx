@@ -5,6 +5,7 @@ import CommandBlock from './CommandBlock';
 import styles from './ContentInstall.module.css';
 
 interface Props {
+  name?: string;
   contentUrl?: string;
   isPrivate?: boolean;
   repository: Repository;
@@ -35,9 +36,15 @@ const TektonInstall = (props: Props) => {
     }
   }
 
+  // Packages distributed as Tekton bundles use the bundle reference as content url
+  const isBundle = !isUndefined(url) && url.startsWith('oci://');
+  const command = isBundle
+    ? `tkn bundle list ${url!.replace('oci://', '')} ${type} ${props.name || ''} -o yaml | kubectl apply -f -`
+    : `kubectl apply -f ${url}`;
+
   return (
     <div className="mt-3">
-      <CommandBlock command={`kubectl apply -f ${url}`} title={`Install the ${type}:`} />
+      <CommandBlock command={command} title={`Install the ${type}:`} />
 
       {props.isPrivate && (
         <div className={`alert alert-warning my-4 ${styles.alert}`} role="alert">
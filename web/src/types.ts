@@ -96,6 +96,9 @@ export interface Repository {
   displayName?: string | null;
   url: string;
   branch?: string | null;
+  gitUrl?: string | null;
+  gitRef?: string | null;
+  gitSubdir?: string | null;
   organizationName?: string | null;
   organizationDisplayName?: string | null;
   userAlias?: string | null;
@@ -110,11 +113,19 @@ export interface Repository {
   private?: boolean;
   authUser?: string | null;
   authPass?: string | null;
+  authSshKey?: string | null;
+  authSshKnownHosts?: string | null;
+  authGithubAppId?: string | null;
+  authGithubAppInstallationId?: string | null;
+  authGithubAppKey?: string | null;
   disabled?: boolean;
   scannerDisabled?: boolean;
   data?: {
     tags?: ContainerTag[];
+    discovery?: ContainerTagsDiscovery;
     versioning?: VersioningOption;
+    namespace?: boolean;
+    listing?: string;
   };
 }
 
@@ -242,6 +253,14 @@ export interface Dependency {
   artifacthubRepositoryName?: string;
 }
 
+export interface APIDeprecation {
+  apiVersion: string;
+  kind: string;
+  deprecatedIn: string;
+  removedIn: string;
+  replacement?: string;
+}
+
 export interface Recommendation {
   url: string;
 }
@@ -269,6 +288,8 @@ export interface PackageData {
   apiVersion?: string;
   type?: HelmChartType;
   kubeVersion?: string;
+  kubeIncompatibleVersions?: string[];
+  apiDeprecations?: APIDeprecation[];
   policy?: string;
   [KeptnData.Version]?: string;
   [KeptnData.Kind]?: string;
@@ -511,17 +532,17 @@ export interface Subscription {
 }
 
 export interface TestWebhook {
+  webhookId?: string;
   url: string;
   contentType?: string | null;
   template?: string | null;
+  secret?: string;
   eventKinds: EventKind[];
 }
 
 export interface Webhook extends TestWebhook {
-  webhookId?: string;
   name: string;
   description?: string;
-  secret?: string;
   active: boolean;
   packages: Package[];
   lastNotifications?: null | WebhookNotification[];
@@ -594,6 +615,7 @@ export enum AuthorizerAction {
   DeleteOrganizationMember = 'deleteOrganizationMember',
   DeleteOrganizationRepository = 'deleteOrganizationRepository',
   GetAuthorizationPolicy = 'getAuthorizationPolicy',
+  GetOrganizationAuditLog = 'getOrganizationAuditLog',
   TransferOrganizationRepository = 'transferOrganizationRepository',
   UpdateAuthorizationPolicy = 'updateAuthorizationPolicy',
   UpdateOrganization = 'updateOrganization',
@@ -855,6 +877,18 @@ export interface ContainerTag {
   id?: string;
 }
 
+export interface ContainerTagPattern {
+  pattern: string;
+  mutable: boolean;
+}
+
+export interface ContainerTagsDiscovery {
+  include: ContainerTagPattern[];
+  exclude: string[];
+  semver: boolean;
+  latest: number;
+}
+
 export interface TemplatesQuery {
   template?: string;
   compareTo?: string;
@@ -274,6 +274,7 @@ const getInstallMethods = (props: PackageInfo): InstallMethodOutput => {
             title: 'Kubectl',
             kind: InstallMethodKind.Tekton,
             props: {
+              name: pkg.name,
               contentUrl: pkg.contentUrl,
               repository: pkg.repository,
               isPrivate: pkg.repository!.private,